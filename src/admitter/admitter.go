@@ -9,6 +9,14 @@ type Admitter interface {
 	Admit(config *types.ProcessConfig) bool
 }
 
+// ProjectAdmitter is an Admitter that decides with the whole project in view,
+// e.g. to admit the dependencies of the admitted processes. Prepare is called
+// before the processes of the project are admitted.
+type ProjectAdmitter interface {
+	Admitter
+	Prepare(p *types.Project)
+}
+
 // ApplyToProject removes every process rejected by one of the admitters and
 // prunes the remaining processes' dependencies on the removed ones, so that
 // a process selected e.g. by --namespace can start without waiting for an
@@ -16,6 +24,11 @@ type Admitter interface {
 // before admission, so the pruning only ever drops edges that point at
 // excluded processes.
 func ApplyToProject(p *types.Project, admitters []Admitter) {
+	for _, adm := range admitters {
+		if projectAdm, ok := adm.(ProjectAdmitter); ok {
+			projectAdm.Prepare(p)
+		}
+	}
 	removed := false
 	for name, proc := range p.Processes {
 		for _, adm := range admitters {
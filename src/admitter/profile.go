@@ -0,0 +1,63 @@
+package admitter
+
+import (
+	"slices"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+// ProfileAdmitter admits the processes without profiles, the processes with
+// one of the active profiles or requested by name, and the dependencies of the
+// admitted processes, whatever their profiles.
+type ProfileAdmitter struct {
+	Profiles []string
+	// Requested are the processes requested on the command line, which run
+	// even if none of their profiles is active.
+	Requested []string
+
+	admitted map[string]bool
+}
+
+func (a *ProfileAdmitter) Prepare(p *types.Project) {
+	a.admitted = map[string]bool{}
+	var pending []types.ProcessConfig
+	for _, proc := range p.Processes {
+		if a.isSelected(&proc) {
+			pending = append(pending, proc)
+		}
+	}
+	for len(pending) > 0 {
+		proc := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if a.admitted[proc.ReplicaName] {
+			continue
+		}
+		a.admitted[proc.ReplicaName] = true
+		for dep := range proc.DependsOn {
+			for _, depProc := range p.Processes {
+				if depProc.ReplicaName == dep || depProc.Name == dep {
+					pending = append(pending, depProc)
+				}
+			}
+		}
+	}
+}
+
+func (a *ProfileAdmitter) isSelected(proc *types.ProcessConfig) bool {
+	if len(proc.Profiles) == 0 {
+		return true
+	}
+	if slices.Contains(a.Requested, proc.Name) || slices.Contains(a.Requested, proc.ReplicaName) {
+		return true
+	}
+	return slices.ContainsFunc(proc.Profiles, func(profile string) bool {
+		return slices.Contains(a.Profiles, profile)
+	})
+}
+
+func (a *ProfileAdmitter) Admit(proc *types.ProcessConfig) bool {
+	if a.admitted == nil {
+		return a.isSelected(proc)
+	}
+	return a.admitted[proc.ReplicaName]
+}
@@ -0,0 +1,104 @@
+package admitter
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func profilesProject() *types.Project {
+	return &types.Project{
+		Processes: types.Processes{
+			"api": {Name: "api", ReplicaName: "api",
+				DependsOn: types.DependsOnConfig{"db": {}}},
+			"db": {Name: "db", ReplicaName: "db", Profiles: []string{"db"}},
+			"mock-0": {Name: "mock", ReplicaName: "mock-0", Profiles: []string{"offline"},
+				DependsOn: types.DependsOnConfig{"fixtures": {}}},
+			"mock-1": {Name: "mock", ReplicaName: "mock-1", Profiles: []string{"offline"},
+				DependsOn: types.DependsOnConfig{"fixtures": {}}},
+			"fixtures": {Name: "fixtures", ReplicaName: "fixtures", Profiles: []string{"seed"}},
+			"debugger": {Name: "debugger", ReplicaName: "debugger", Profiles: []string{"debug", "dev"}},
+		},
+	}
+}
+
+func TestProfileAdmitter(t *testing.T) {
+	tests := []struct {
+		name      string
+		profiles  []string
+		requested []string
+		want      []string
+	}{
+		{
+			name: "no profiles",
+			want: []string{"api", "db"},
+		},
+		{
+			name:     "profile with dependencies",
+			profiles: []string{"offline"},
+			want:     []string{"api", "db", "fixtures", "mock-0", "mock-1"},
+		},
+		{
+			name:     "one of the profiles",
+			profiles: []string{"dev"},
+			want:     []string{"api", "db", "debugger"},
+		},
+		{
+			name:     "unknown profile",
+			profiles: []string{"prod"},
+			want:     []string{"api", "db"},
+		},
+		{
+			name:      "requested process",
+			requested: []string{"mock"},
+			want:      []string{"api", "db", "fixtures", "mock-0", "mock-1"},
+		},
+		{
+			name:      "requested replica",
+			requested: []string{"debugger"},
+			want:      []string{"api", "db", "debugger"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := profilesProject()
+			ApplyToProject(project, []Admitter{&ProfileAdmitter{Profiles: tt.profiles, Requested: tt.requested}})
+			var got []string
+			for name := range project.Processes {
+				got = append(got, name)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("admitted processes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfileAdmitter_WithNamespaces(t *testing.T) {
+	project := profilesProject()
+	ApplyToProject(project, []Admitter{
+		&NamespaceAdmitter{EnabledNamespaces: []string{}},
+		&ProfileAdmitter{Profiles: []string{"offline"}},
+	})
+	mock := project.Processes["mock-0"]
+	if _, ok := mock.DependsOn["fixtures"]; !ok {
+		t.Error("the dependency of an admitted process should be kept")
+	}
+
+	project = profilesProject()
+	proc := project.Processes["mock-0"]
+	proc.Namespace = types.Namespaces{"mocks"}
+	project.Processes["mock-0"] = proc
+	ApplyToProject(project, []Admitter{
+		&NamespaceAdmitter{EnabledNamespaces: []string{"mocks"}},
+		&ProfileAdmitter{Profiles: []string{"offline"}},
+	})
+	if _, ok := project.Processes["fixtures"]; ok {
+		t.Error("a dependency outside of the namespaces should be removed")
+	}
+	if _, ok := project.Processes["mock-0"].DependsOn["fixtures"]; ok {
+		t.Error("the dependency on a removed process should be pruned")
+	}
+}
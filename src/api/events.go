@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/gin-gonic/gin"
+)
+
+// @Schemes
+// @Id				GetEvents
+// @Description	Retrieves process lifecycle events from the event journal, oldest first
+// @Tags			Project
+// @Summary		Get events
+// @Produce		json
+// @Param			process	query		string				false	"Process name"
+// @Param			since	query		string				false	"Duration relative to now (1h) or RFC 3339 time"
+// @Param			after	query		int					false	"Only events recorded after this sequence number"
+// @Param			limit	query		int					false	"Only the most recent events"
+// @Success		200		{object}	types.JournalEvents	"Events"
+// @Failure		400		{object}	map[string]string
+// @Router			/events [get]
+func (api *PcApi) GetEvents(c *gin.Context) {
+	query, err := parseJournalQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	events, err := api.project.GetEvents(query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if events == nil {
+		events = []types.JournalEvent{}
+	}
+	c.JSON(http.StatusOK, types.JournalEvents{Events: events})
+}
+
+func parseJournalQuery(c *gin.Context) (types.JournalQuery, error) {
+	query := types.JournalQuery{Process: c.Query("process")}
+	var err error
+	if query.Since, err = types.ParseJournalSince(c.Query("since"), time.Now()); err != nil {
+		return query, err
+	}
+	if after := c.Query("after"); after != "" {
+		if query.AfterSeq, err = strconv.ParseUint(after, 10, 64); err != nil {
+			return query, err
+		}
+	}
+	if limit := c.Query("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			return query, err
+		}
+	}
+	return query, nil
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestGetEvents(t *testing.T) {
+	var got types.JournalQuery
+	mock := &mockProject{
+		getEventsFn: func(query types.JournalQuery) ([]types.JournalEvent, error) {
+			got = query
+			return []types.JournalEvent{{Seq: 8, Process: "web", Type: types.JournalEventRestart, Reason: "restart requested"}}, nil
+		},
+	}
+	r := setupRouter(mock)
+	w := performRequest(r, http.MethodGet, "/events?process=web&since=2026-01-01T12:00:00Z&after=7&limit=5", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	want := types.JournalQuery{
+		Process:  "web",
+		Since:    time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		AfterSeq: 7,
+		Limit:    5,
+	}
+	if !got.Since.Equal(want.Since) || got.Process != want.Process || got.AfterSeq != want.AfterSeq || got.Limit != want.Limit {
+		t.Errorf("query = %+v, want %+v", got, want)
+	}
+	data, ok := parseJSON(t, w)["data"].([]any)
+	if !ok || len(data) != 1 {
+		t.Fatalf("expected one event, got %s", w.Body.String())
+	}
+	if ev := data[0].(map[string]any); ev["reason"] != "restart requested" {
+		t.Errorf("unexpected event %v", ev)
+	}
+}
+
+func TestGetEvents_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		err  error
+	}{
+		{"invalid since", "/events?since=yesterday", nil},
+		{"invalid after", "/events?after=-1", nil},
+		{"invalid limit", "/events?limit=all", nil},
+		{"journal disabled", "/events", errors.New("the event journal is disabled")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockProject{
+				getEventsFn: func(types.JournalQuery) ([]types.JournalEvent, error) {
+					return nil, tt.err
+				},
+			}
+			w := performRequest(setupRouter(mock), http.MethodGet, tt.path, "")
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", w.Code)
+			}
+		})
+	}
+}
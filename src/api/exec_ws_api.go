@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+// @Schemes
+// @Id                    ExecProcess
+// @Summary               Run a command in the environment of a process
+// @Description           Upgrades HTTP to WebSocket and runs the command with the environment, env_file and working directory of the process. Messages in both directions are api.ExecMessage: the client sends the stdin and the terminal size changes, the server the output and the exit code.
+// @Tags                  Process
+// @Produce               json
+// @Param                 name    query   string   true  "Process Name"
+// @Param                 command query   []string true  "Executable and arguments" collectionFormat(multi)
+// @Param                 tty     query   bool     false "Run the command in a pseudo-terminal"
+// @Param                 rows    query   int      false "Rows of the pseudo-terminal"
+// @Param                 cols    query   int      false "Columns of the pseudo-terminal"
+// @Success               101 "Switching Protocols"
+// @Failure               400 {object} api.ErrorResponse
+// @Router                /process/exec/ws [get]
+func (api *PcApi) HandleExec(c *gin.Context) {
+	name := c.Query("name")
+	req := types.ExecRequest{
+		Command: c.QueryArray("command"),
+		Tty:     c.Query("tty") == "true",
+	}
+	var err error
+	if req.Size.Rows, err = parseTerminalDim(c.Query("rows")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Size.Cols, err = parseTerminalDim(c.Query("cols")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err = req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err = api.project.GetProcessInfo(name); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer ws.Close()
+
+	// The command is killed when the client goes away.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stdin, stdinWriter := io.Pipe()
+	resize := make(chan types.TerminalSize, 1)
+	go readExecInput(ws, stdinWriter, resize, cancel)
+
+	var mtx sync.Mutex
+	code, err := api.project.ExecProcess(ctx, name, &req, &types.ExecStreams{
+		Stdin:  stdin,
+		Stdout: &execWsWriter{ws: ws, mtx: &mtx, stream: ExecStreamStdout},
+		Stderr: &execWsWriter{ws: ws, mtx: &mtx, stream: ExecStreamStderr},
+		Resize: resize,
+	})
+	last := ExecMessage{ExitCode: &code}
+	if err != nil {
+		last = ExecMessage{Error: err.Error()}
+	}
+	mtx.Lock()
+	defer mtx.Unlock()
+	if err = ws.WriteJSON(last); err != nil {
+		log.Err(err).Msgf("Failed to send the exit code of the exec in %s", name)
+		return
+	}
+	_ = ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
+
+func parseTerminalDim(value string) (uint16, error) {
+	if value == "" {
+		return 0, nil
+	}
+	dim, err := strconv.ParseUint(value, 10, 16)
+	return uint16(dim), err
+}
+
+// readExecInput forwards the stdin and the terminal size changes sent by the
+// client, until the connection is closed.
+func readExecInput(ws *websocket.Conn, stdin *io.PipeWriter, resize chan types.TerminalSize, cancel context.CancelFunc) {
+	defer cancel()
+	for {
+		var msg ExecMessage
+		if err := ws.ReadJSON(&msg); err != nil {
+			_ = stdin.CloseWithError(err)
+			return
+		}
+		if len(msg.Data) > 0 {
+			if _, err := stdin.Write(msg.Data); err != nil {
+				log.Debug().Err(err).Msg("Exec stdin closed")
+			}
+		}
+		if msg.EOF {
+			_ = stdin.Close()
+		}
+		if msg.Resize != nil {
+			// Only the last size matters.
+			select {
+			case <-resize:
+			default:
+			}
+			resize <- *msg.Resize
+		}
+	}
+}
+
+type execWsWriter struct {
+	ws     *websocket.Conn
+	mtx    *sync.Mutex
+	stream string
+}
+
+func (w *execWsWriter) Write(p []byte) (int, error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if err := w.ws.WriteJSON(ExecMessage{Stream: w.stream, Data: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	metricsPrefix      = "process_compose_"
+	metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// metricsStatuses are the process statuses exported by the status metric, one
+// series each, so that a status change is a value change rather than a new
+// series.
+var metricsStatuses = []string{
+	types.ProcessStateDisabled,
+	types.ProcessStateForeground,
+	types.ProcessStatePending,
+	types.ProcessStateRunning,
+	types.ProcessStateLaunching,
+	types.ProcessStateLaunched,
+	types.ProcessStateRestarting,
+	types.ProcessStateCrashLoopBackOff,
+	types.ProcessStateTerminating,
+	types.ProcessStateCompleted,
+	types.ProcessStateSkipped,
+	types.ProcessStateError,
+	types.ProcessStateScheduled,
+	types.ProcessStateWatching,
+}
+
+// @Schemes
+// @Id				GetMetrics
+// @Description	Retrieves project and process metrics in the Prometheus text exposition format
+// @Tags			Project
+// @Summary		Get metrics
+// @Produce		plain
+// @Success		200	{string}	string	"Metrics"
+// @Failure		500	{object}	map[string]string
+// @Router			/metrics [get]
+func (api *PcApi) GetMetrics(c *gin.Context) {
+	project, err := api.project.GetProjectState(false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	states, err := api.project.GetProcessesState()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	var processes []types.ProcessState
+	if states != nil {
+		processes = states.States
+	}
+	c.Data(http.StatusOK, metricsContentType, []byte(formatMetrics(project, processes)))
+}
+
+// formatMetrics renders the project and process states. Samples of the same
+// metric must be contiguous, so every metric is written for all processes
+// before moving on to the next one.
+func formatMetrics(project *types.ProjectState, states []types.ProcessState) string {
+	m := &metricsWriter{}
+
+	m.family("up_time_seconds", "gauge", "Time since the project started.")
+	m.sample("up_time_seconds", nil, project.UpTime.Seconds())
+	m.family("processes", "gauge", "Number of processes in the project.")
+	m.sample("processes", nil, float64(project.ProcessNum))
+	m.family("running_processes", "gauge", "Number of running processes.")
+	m.sample("running_processes", nil, float64(project.RunningProcessNum))
+
+	m.family("process_status", "gauge", "Current process status, 1 for the status the process is in.")
+	for i := range states {
+		for _, status := range metricsStatuses {
+			m.sample("process_status", processLabels(&states[i], "status", status), boolValue(states[i].Status == status))
+		}
+	}
+	m.processGauge(states, "process_running", "Whether the process is running.", func(s *types.ProcessState) float64 {
+		return boolValue(s.IsRunning)
+	})
+	m.processGauge(states, "process_age_seconds", "Time since the process started.", func(s *types.ProcessState) float64 {
+		return s.Age.Seconds()
+	})
+	m.processGauge(states, "process_exit_code", "Exit code of the last process run.", func(s *types.ProcessState) float64 {
+		return float64(s.ExitCode)
+	})
+	m.processGauge(states, "process_cpu_percent", "Process CPU usage, in percent of a single core.", func(s *types.ProcessState) float64 {
+		return s.CPU
+	})
+	m.processGauge(states, "process_memory_bytes", "Process resident memory.", func(s *types.ProcessState) float64 {
+		return float64(s.Mem)
+	})
+	m.processCounter(states, "process_restarts_total", "Number of times the process was restarted.", func(s *types.ProcessState) float64 {
+		return float64(s.Restarts)
+	})
+	m.processCounter(states, "process_oom_kills_total", "Number of times the process was killed by the OOM killer.", func(s *types.ProcessState) float64 {
+		return float64(s.OOMKills)
+	})
+
+	m.family("process_ready", "gauge", "Whether the readiness probe reports the process ready. Only exported for processes with a readiness probe.")
+	for i := range states {
+		if states[i].HasHealthProbe {
+			m.sample("process_ready", processLabels(&states[i]), boolValue(states[i].Health == types.ProcessHealthReady))
+		}
+	}
+	m.family("process_readiness_seconds", "gauge", "Time it took the process to become ready after it started.")
+	for i := range states {
+		s := &states[i]
+		if s.ProcessStartTime != nil && s.ProcessReadyTime != nil {
+			m.sample("process_readiness_seconds", processLabels(s), s.ProcessReadyTime.Sub(*s.ProcessStartTime).Seconds())
+		}
+	}
+
+	m.family("process_probe_checks_total", "counter", "Number of readiness and liveness checks, by result.")
+	for i := range states {
+		stats := states[i].ProbeStats
+		for _, check := range []struct {
+			probe, result string
+			count         int64
+		}{
+			{"readiness", "success", stats.ReadinessSuccesses},
+			{"readiness", "failure", stats.ReadinessFailures},
+			{"liveness", "success", stats.LivenessSuccesses},
+			{"liveness", "failure", stats.LivenessFailures},
+		} {
+			if check.count > 0 {
+				m.sample("process_probe_checks_total", processLabels(&states[i], "probe", check.probe, "result", check.result), float64(check.count))
+			}
+		}
+	}
+
+	m.family("process_scheduled_runs_total", "counter", "Number of scheduled runs that were due, by whether they started.")
+	for i := range states {
+		s := &states[i]
+		if s.NextRunTime == nil && s.ScheduledRuns == 0 && s.ScheduledRunsSkipped == 0 {
+			continue
+		}
+		m.sample("process_scheduled_runs_total", processLabels(s, "result", "started"), float64(s.ScheduledRuns))
+		m.sample("process_scheduled_runs_total", processLabels(s, "result", "skipped"), float64(s.ScheduledRunsSkipped))
+	}
+
+	return m.String()
+}
+
+type metricsWriter struct {
+	strings.Builder
+}
+
+func (m *metricsWriter) family(name, typ, help string) {
+	fmt.Fprintf(m, "# HELP %s%s %s\n# TYPE %s%s %s\n", metricsPrefix, name, help, metricsPrefix, name, typ)
+}
+
+// sample writes a single series. labels are name/value pairs.
+func (m *metricsWriter) sample(name string, labels []string, value float64) {
+	m.WriteString(metricsPrefix + name)
+	if len(labels) > 0 {
+		m.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				m.WriteByte(',')
+			}
+			m.WriteString(labels[i] + `="` + escapeLabelValue(labels[i+1]) + `"`)
+		}
+		m.WriteByte('}')
+	}
+	m.WriteByte(' ')
+	m.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	m.WriteByte('\n')
+}
+
+func (m *metricsWriter) processGauge(states []types.ProcessState, name, help string, value func(*types.ProcessState) float64) {
+	m.processMetric(states, name, "gauge", help, value)
+}
+
+func (m *metricsWriter) processCounter(states []types.ProcessState, name, help string, value func(*types.ProcessState) float64) {
+	m.processMetric(states, name, "counter", help, value)
+}
+
+func (m *metricsWriter) processMetric(states []types.ProcessState, name, typ, help string, value func(*types.ProcessState) float64) {
+	m.family(name, typ, help)
+	for i := range states {
+		m.sample(name, processLabels(&states[i]), value(&states[i]))
+	}
+}
+
+func processLabels(state *types.ProcessState, extra ...string) []string {
+	return append([]string{"process", state.Name, "namespace", strings.Join(state.Namespace, ",")}, extra...)
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestGetMetrics(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	ready := start.Add(1500 * time.Millisecond)
+	next := start.Add(time.Hour)
+	mock := &mockProject{
+		getProjectStateFn: func(bool) (*types.ProjectState, error) {
+			return &types.ProjectState{UpTime: time.Minute, ProcessNum: 2, RunningProcessNum: 1}, nil
+		},
+		getProcessesStateFn: func() (*types.ProcessesState, error) {
+			return &types.ProcessesState{States: []types.ProcessState{
+				{
+					Name:             "web",
+					Namespace:        types.Namespaces{"default"},
+					Status:           types.ProcessStateRunning,
+					IsRunning:        true,
+					Age:              90 * time.Second,
+					Restarts:         2,
+					Mem:              1024,
+					CPU:              12.5,
+					HasHealthProbe:   true,
+					Health:           types.ProcessHealthReady,
+					ProcessStartTime: &start,
+					ProcessReadyTime: &ready,
+					ProbeStats:       types.ProbeStats{ReadinessSuccesses: 7, ReadinessFailures: 1},
+				},
+				{
+					Name:                 `cron "job"`,
+					Namespace:            types.Namespaces{"batch"},
+					Status:               types.ProcessStateCompleted,
+					ExitCode:             3,
+					Health:               types.ProcessHealthUnknown,
+					NextRunTime:          &next,
+					ScheduledRuns:        4,
+					ScheduledRunsSkipped: 1,
+				},
+			}}, nil
+		},
+	}
+	r := setupRouter(mock)
+	w := performRequest(r, http.MethodGet, "/metrics", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	body := w.Body.String()
+	want := []string{
+		"# TYPE process_compose_up_time_seconds gauge",
+		"process_compose_up_time_seconds 60",
+		"process_compose_running_processes 1",
+		`process_compose_process_status{process="web",namespace="default",status="Running"} 1`,
+		`process_compose_process_status{process="web",namespace="default",status="Completed"} 0`,
+		`process_compose_process_running{process="web",namespace="default"} 1`,
+		`process_compose_process_age_seconds{process="web",namespace="default"} 90`,
+		`process_compose_process_cpu_percent{process="web",namespace="default"} 12.5`,
+		`process_compose_process_memory_bytes{process="web",namespace="default"} 1024`,
+		"# TYPE process_compose_process_restarts_total counter",
+		`process_compose_process_restarts_total{process="web",namespace="default"} 2`,
+		`process_compose_process_ready{process="web",namespace="default"} 1`,
+		`process_compose_process_readiness_seconds{process="web",namespace="default"} 1.5`,
+		`process_compose_process_probe_checks_total{process="web",namespace="default",probe="readiness",result="success"} 7`,
+		`process_compose_process_probe_checks_total{process="web",namespace="default",probe="readiness",result="failure"} 1`,
+		`process_compose_process_exit_code{process="cron \"job\"",namespace="batch"} 3`,
+		`process_compose_process_scheduled_runs_total{process="cron \"job\"",namespace="batch",result="started"} 4`,
+		`process_compose_process_scheduled_runs_total{process="cron \"job\"",namespace="batch",result="skipped"} 1`,
+	}
+	for _, line := range want {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("missing line %q in:\n%s", line, body)
+		}
+	}
+	unwanted := []string{
+		`process_compose_process_ready{process="cron \"job\""`,
+		`process_compose_process_readiness_seconds{process="cron \"job\""`,
+		`probe="liveness"`,
+		`process_compose_process_scheduled_runs_total{process="web"`,
+	}
+	for _, fragment := range unwanted {
+		if strings.Contains(body, fragment) {
+			t.Errorf("unexpected %q in:\n%s", fragment, body)
+		}
+	}
+}
+
+func TestGetMetrics_Error(t *testing.T) {
+	mock := &mockProject{
+		getProcessesStateFn: func() (*types.ProcessesState, error) {
+			return nil, errors.New("internal error")
+		},
+	}
+	r := setupRouter(mock)
+	w := performRequest(r, http.MethodGet, "/metrics", "")
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", w.Code)
+	}
+}
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"os"
+	"time"
 
 	"github.com/f1bonacc1/process-compose/src/pclog"
 	"github.com/f1bonacc1/process-compose/src/types"
@@ -39,9 +41,16 @@ type mockProject struct {
 	truncateProcessLogsFn   func(string) error
 	getProcessPtyFn         func(string) *os.File
 	getFullProcessEnvFn     func(*types.ProcessConfig) []string
+	getLaunchEnvFn          func(*types.ProcessConfig) []string
+	execProcessFn           func(string, *types.ExecRequest, *types.ExecStreams) (int, error)
 	getDependencyGraphFn    func() (*types.DependencyGraph, error)
 	sendSignalFn            func(string, int) error
 	sendProcessKeysFn       func(string, string) error
+	getEventsFn             func(types.JournalQuery) ([]types.JournalEvent, error)
+	getResourceHistoryFn    func(string, time.Time) ([]types.ResourceHistory, error)
+	getProcessTreeFn        func(string) (*types.ProcessTree, error)
+	signalProcessTreeFn     func(string, int, int) error
+	saveProjectStateFn      func() (string, error)
 }
 
 func (m *mockProject) ShutDownProject() error {
@@ -268,6 +277,20 @@ func (m *mockProject) GetFullProcessEnvironment(proc *types.ProcessConfig) []str
 	return nil
 }
 
+func (m *mockProject) GetProcessLaunchEnvironment(proc *types.ProcessConfig) []string {
+	if m.getLaunchEnvFn != nil {
+		return m.getLaunchEnvFn(proc)
+	}
+	return nil
+}
+
+func (m *mockProject) ExecProcess(_ context.Context, name string, req *types.ExecRequest, streams *types.ExecStreams) (int, error) {
+	if m.execProcessFn != nil {
+		return m.execProcessFn(name, req, streams)
+	}
+	return 0, nil
+}
+
 func (m *mockProject) GetDependencyGraph() (*types.DependencyGraph, error) {
 	if m.getDependencyGraphFn != nil {
 		return m.getDependencyGraphFn()
@@ -275,5 +298,40 @@ func (m *mockProject) GetDependencyGraph() (*types.DependencyGraph, error) {
 	return nil, nil
 }
 
+func (m *mockProject) GetEvents(query types.JournalQuery) ([]types.JournalEvent, error) {
+	if m.getEventsFn != nil {
+		return m.getEventsFn(query)
+	}
+	return nil, nil
+}
+
+func (m *mockProject) GetResourceHistory(name string, since time.Time) ([]types.ResourceHistory, error) {
+	if m.getResourceHistoryFn != nil {
+		return m.getResourceHistoryFn(name, since)
+	}
+	return nil, nil
+}
+
+func (m *mockProject) GetProcessTree(name string) (*types.ProcessTree, error) {
+	if m.getProcessTreeFn != nil {
+		return m.getProcessTreeFn(name)
+	}
+	return nil, nil
+}
+
+func (m *mockProject) SignalProcessTree(name string, pid int, sig int) error {
+	if m.signalProcessTreeFn != nil {
+		return m.signalProcessTreeFn(name, pid, sig)
+	}
+	return nil
+}
+
+func (m *mockProject) SaveProjectState() (string, error) {
+	if m.saveProjectStateFn != nil {
+		return m.saveProjectStateFn()
+	}
+	return "", nil
+}
+
 func (m *mockProject) RegisterStateObserver(_ types.StateObserver)   {}
 func (m *mockProject) UnregisterStateObserver(_ types.StateObserver) {}
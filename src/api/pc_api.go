@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -186,15 +187,9 @@ func (api *PcApi) StopProcess(c *gin.Context) {
 // @Router			/process/signal/{name}/{signal} [patch]
 func (api *PcApi) SendSignal(c *gin.Context) {
 	name := c.Param("name")
-	sig, err := strconv.Atoi(c.Param("signal"))
+	sig, err := parseSignal(c.Param("signal"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid signal: " + err.Error()})
-		return
-	}
-
-	const maxSignal = 22
-	if sig < 0 || sig > maxSignal {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid signal: " + strconv.Itoa(sig) + ". Must be between 0 and " + strconv.Itoa(maxSignal)})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -207,6 +202,19 @@ func (api *PcApi) SendSignal(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"name": name})
 }
 
+func parseSignal(param string) (int, error) {
+	sig, err := strconv.Atoi(param)
+	if err != nil {
+		return 0, errors.New("invalid signal: " + err.Error())
+	}
+
+	const maxSignal = 22
+	if sig < 0 || sig > maxSignal {
+		return 0, errors.New("invalid signal: " + strconv.Itoa(sig) + ". Must be between 0 and " + strconv.Itoa(maxSignal))
+	}
+	return sig, nil
+}
+
 // @Schemes
 // @Id				StopProcesses
 // @Description	Sends kill signal to the processes list
@@ -558,6 +566,24 @@ func (api *PcApi) GetProjectState(c *gin.Context) {
 	c.JSON(http.StatusOK, state)
 }
 
+// @Schemes
+// @Id				SaveProjectState
+// @Description	Saves the runtime state of the project (replicas, restarts, stopped processes) to the state file
+// @Tags			Project
+// @Summary		Save project state
+// @Produce		json
+// @Success		200	{object}	map[string]string	"State File Path"
+// @Failure		400	{object}	map[string]string
+// @Router			/project/state/save [post]
+func (api *PcApi) SaveProjectState(c *gin.Context) {
+	path, err := api.project.SaveProjectState()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"path": path})
+}
+
 // @Schemes
 // @Id				ReloadProject
 // @Description	Reload project state from config
@@ -587,6 +613,9 @@ func (api *PcApi) ReloadProject(c *gin.Context) {
 // @Tags            Graph
 // @Summary         Get dependency graph
 // @Produce         json
+// @Produce         image/svg+xml
+// @Produce         plain
+// @Param           format query string false "Output format: json (default), svg, dot or mermaid"
 // @Success         200 {object} types.DependencyGraph
 // @Failure         400 {object} map[string]string
 // @Router          /graph [get]
@@ -596,5 +625,16 @@ func (api *PcApi) GetDependencyGraph(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, graph)
+	switch format := c.Query("format"); format {
+	case "", "json":
+		c.JSON(http.StatusOK, graph)
+	case "svg":
+		c.Data(http.StatusOK, "image/svg+xml", []byte(graph.ToSVG()))
+	case "dot":
+		c.String(http.StatusOK, graph.ToDOT())
+	case "mermaid":
+		c.String(http.StatusOK, graph.ToMermaid())
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown graph format " + format})
+	}
 }
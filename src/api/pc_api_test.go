@@ -968,6 +968,37 @@ func TestGetProjectState_Error(t *testing.T) {
 	}
 }
 
+// --- SaveProjectState ---
+
+func TestSaveProjectState_Success(t *testing.T) {
+	mock := &mockProject{
+		saveProjectStateFn: func() (string, error) {
+			return "/tmp/state.json", nil
+		},
+	}
+	r := setupRouter(mock)
+	w := performRequest(r, http.MethodPost, "/project/state/save", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "/tmp/state.json") {
+		t.Errorf("expected the state file path in the response, got %s", w.Body.String())
+	}
+}
+
+func TestSaveProjectState_Failure(t *testing.T) {
+	mock := &mockProject{
+		saveProjectStateFn: func() (string, error) {
+			return "", errors.New("the state file is disabled")
+		},
+	}
+	r := setupRouter(mock)
+	w := performRequest(r, http.MethodPost, "/project/state/save", "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
 // --- ReloadProject ---
 
 func TestReloadProject_Success(t *testing.T) {
@@ -1024,6 +1055,44 @@ func TestGetDependencyGraph_Success(t *testing.T) {
 	}
 }
 
+func TestGetDependencyGraph_Formats(t *testing.T) {
+	mock := &mockProject{
+		getDependencyGraphFn: func() (*types.DependencyGraph, error) {
+			return types.BuildDependencyGraph(types.Processes{
+				"db":  {Name: "db", ReplicaName: "db"},
+				"api": {Name: "api", ReplicaName: "api", DependsOn: types.DependsOnConfig{"db": {Condition: types.ProcessConditionHealthy}}},
+			}), nil
+		},
+	}
+	tests := []struct {
+		format      string
+		wantCode    int
+		wantType    string
+		wantContent string
+	}{
+		{format: "json", wantCode: http.StatusOK, wantType: "application/json", wantContent: `"nodes"`},
+		{format: "svg", wantCode: http.StatusOK, wantType: "image/svg+xml", wantContent: `data-process="api"`},
+		{format: "dot", wantCode: http.StatusOK, wantType: "text/plain", wantContent: `"api" -> "db" [label="healthy"];`},
+		{format: "mermaid", wantCode: http.StatusOK, wantType: "text/plain", wantContent: "api --> db"},
+		{format: "png", wantCode: http.StatusBadRequest, wantType: "application/json", wantContent: "unknown graph format png"},
+	}
+	r := setupRouter(mock)
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			w := performRequest(r, http.MethodGet, "/graph?format="+tt.format, "")
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d", tt.wantCode, w.Code)
+			}
+			if !strings.HasPrefix(w.Header().Get("Content-Type"), tt.wantType) {
+				t.Errorf("expected content type %s, got %s", tt.wantType, w.Header().Get("Content-Type"))
+			}
+			if !strings.Contains(w.Body.String(), tt.wantContent) {
+				t.Errorf("expected the body to contain %s, got %s", tt.wantContent, w.Body.String())
+			}
+		})
+	}
+}
+
 func TestGetDependencyGraph_Error(t *testing.T) {
 	mock := &mockProject{
 		getDependencyGraphFn: func() (*types.DependencyGraph, error) {
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Schemes
+// @Id				GetProcessTree
+// @Description	Retrieves the OS processes spawned by the process, with their resource usage and open ports
+// @Tags			Process
+// @Summary		Get process tree
+// @Produce		json
+// @Param			name	path		string				true	"Process Name"
+// @Success		200		{object}	types.ProcessTree	"Process Tree"
+// @Failure		400		{object}	map[string]string
+// @Router			/process/tree/{name} [get]
+func (api *PcApi) GetProcessTree(c *gin.Context) {
+	name := c.Param("name")
+
+	tree, err := api.project.GetProcessTree(name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tree)
+}
+
+// @Schemes
+// @Id				SignalProcessTree
+// @Description	Sends a POSIX signal to a single OS process of the process tree
+// @Tags			Process
+// @Summary		Signal a process descendant
+// @Produce		json
+// @Param			name	path		string				true	"Process Name"
+// @Param			pid		path		int					true	"PID of the process or of one of its descendants"
+// @Param			signal	path		int					true	"Signal Number"
+// @Success		200		{object}	api.NameResponse	"Signaled Process Name"
+// @Failure		400		{object}	map[string]string
+// @Router			/process/tree/signal/{name}/{pid}/{signal} [patch]
+func (api *PcApi) SignalProcessTree(c *gin.Context) {
+	name := c.Param("name")
+	pid, err := strconv.Atoi(c.Param("pid"))
+	if err != nil || pid <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pid: " + c.Param("pid")})
+		return
+	}
+	sig, err := parseSignal(c.Param("signal"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = api.project.SignalProcessTree(name, pid, sig)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"name": name})
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestGetProcessTree(t *testing.T) {
+	mock := &mockProject{
+		getProcessTreeFn: func(name string) (*types.ProcessTree, error) {
+			return &types.ProcessTree{
+				Name: name,
+				Root: types.ProcessTreeNode{
+					Pid:     100,
+					Command: "sh -c npm start",
+					Children: []types.ProcessTreeNode{
+						{Pid: 101, Command: "node server.js", TcpPorts: []uint16{3000}},
+					},
+				},
+			}, nil
+		},
+	}
+	w := performRequest(setupRouter(mock), http.MethodGet, "/process/tree/web", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := parseJSON(t, w)
+	if resp["name"] != "web" {
+		t.Errorf("name = %v, want web", resp["name"])
+	}
+	children := resp["root"].(map[string]any)["children"].([]any)
+	child := children[0].(map[string]any)
+	if child["pid"] != 101.0 || child["tcp_ports"].([]any)[0] != 3000.0 {
+		t.Errorf("unexpected child %v", child)
+	}
+}
+
+func TestSignalProcessTree(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		err      error
+		wantCode int
+		wantPid  int
+		wantSig  int
+	}{
+		{"descendant", "/process/tree/signal/web/101/15", nil, http.StatusOK, 101, 15},
+		{"invalid pid", "/process/tree/signal/web/abc/15", nil, http.StatusBadRequest, 0, 0},
+		{"invalid signal", "/process/tree/signal/web/101/99", nil, http.StatusBadRequest, 0, 0},
+		{"not a descendant", "/process/tree/signal/web/1/15", errors.New("pid 1 is not a descendant of process web"), http.StatusBadRequest, 1, 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPid, gotSig int
+			mock := &mockProject{
+				signalProcessTreeFn: func(name string, pid, sig int) error {
+					gotPid, gotSig = pid, sig
+					return tt.err
+				},
+			}
+			w := performRequest(setupRouter(mock), http.MethodPatch, tt.path, "")
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+			if gotPid != tt.wantPid || gotSig != tt.wantSig {
+				t.Errorf("signaled pid %d with %d, want pid %d with %d", gotPid, gotSig, tt.wantPid, tt.wantSig)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/gin-gonic/gin"
+)
+
+// @Schemes
+// @Id				GetResourceHistory
+// @Description	Retrieves the CPU, memory and restart samples of the processes, oldest first
+// @Tags			Process
+// @Summary		Get resource history
+// @Produce		json
+// @Param			process	query		string					false	"Process name"
+// @Param			since	query		string					false	"Duration relative to now (10m) or RFC 3339 time"
+// @Success		200		{object}	types.ResourceHistories	"Resource History"
+// @Failure		400		{object}	map[string]string
+// @Router			/resources [get]
+func (api *PcApi) GetResourceHistory(c *gin.Context) {
+	since, err := types.ParseJournalSince(c.Query("since"), time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	histories, err := api.project.GetResourceHistory(c.Query("process"), since)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, types.ResourceHistories{Histories: histories})
+}
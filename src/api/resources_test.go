@@ -0,0 +1,63 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestGetResourceHistory(t *testing.T) {
+	var gotName string
+	var gotSince time.Time
+	mock := &mockProject{
+		getResourceHistoryFn: func(name string, since time.Time) ([]types.ResourceHistory, error) {
+			gotName, gotSince = name, since
+			return []types.ResourceHistory{{
+				Name:    "web",
+				Samples: []types.ResourceSample{{Mem: 1024, CPU: 12.5, Restarts: 2}},
+			}}, nil
+		},
+	}
+	w := performRequest(setupRouter(mock), http.MethodGet, "/resources?process=web&since=2026-01-01T12:00:00Z", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if gotName != "web" || !gotSince.Equal(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("query = %q since %v", gotName, gotSince)
+	}
+	data, ok := parseJSON(t, w)["data"].([]any)
+	if !ok || len(data) != 1 {
+		t.Fatalf("expected one history, got %s", w.Body.String())
+	}
+	samples := data[0].(map[string]any)["samples"].([]any)
+	if sample := samples[0].(map[string]any); sample["mem"] != 1024.0 || sample["cpu"] != 12.5 || sample["restarts"] != 2.0 {
+		t.Errorf("unexpected sample %v", sample)
+	}
+}
+
+func TestGetResourceHistory_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		err  error
+	}{
+		{"invalid since", "/resources?since=yesterday", nil},
+		{"unknown process", "/resources?process=nope", errors.New("no such process: nope")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockProject{
+				getResourceHistoryFn: func(string, time.Time) ([]types.ResourceHistory, error) {
+					return nil, tt.err
+				},
+			}
+			w := performRequest(setupRouter(mock), http.MethodGet, tt.path, "")
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", w.Code)
+			}
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/f1bonacc1/process-compose/src/config"
 	_ "github.com/f1bonacc1/process-compose/src/docs"
@@ -13,15 +15,44 @@ import (
 )
 
 // TokenAuthMiddleware enforces API access using an auth token if configured.
+// Browsers, which can't set the token header, send the token in the cookie
+// set by the web UI login page, the only page served without a token.
+//
+// The browsers send the cookie with the requests of the other sites on the
+// same host as well, such as another port of localhost, so the cookie only
+// authenticates the requests that change nothing, and the others when they
+// come from the web UI.
 func TokenAuthMiddleware(token string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if c.Request.URL.Path == uiLoginPath {
+			c.Next()
+			return
+		}
 		reqToken := c.GetHeader(config.TokenHeader)
-		if reqToken != token {
+		if reqToken == "" {
+			reqToken, _ = c.Cookie(config.TokenCookie)
+			if reqToken != "" && !isSafeMethod(c.Request.Method) &&
+				c.GetHeader(config.UIRequestHeader) == "" && !isSameOrigin(c) {
+				log.Error().
+					Str("client_ip", c.ClientIP()).
+					Str("method", c.Request.Method).
+					Str("path", c.Request.URL.Path).
+					Msg("rejected cross-origin request authenticated by the web UI cookie")
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+		}
+		if !isValidToken(reqToken, token) {
 			log.Error().
 				Str("client_ip", c.ClientIP()).
 				Str("method", c.Request.Method).
 				Str("path", c.Request.URL.Path).
 				Msg("failed login attempt: invalid or missing token")
+			if c.Request.Method == http.MethodGet && strings.HasPrefix(c.Request.URL.Path, uiPath) {
+				c.Redirect(http.StatusFound, uiLoginPath)
+				c.Abort()
+				return
+			}
 			c.AbortWithStatus(http.StatusUnauthorized)
 			return
 		}
@@ -29,6 +60,23 @@ func TokenAuthMiddleware(token string) gin.HandlerFunc {
 	}
 }
 
+// isValidToken compares the tokens in constant time, so that the response
+// time doesn't tell how much of the token was guessed.
+func isValidToken(reqToken, token string) bool {
+	return subtle.ConstantTimeCompare([]byte(reqToken), []byte(token)) == 1
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// isSameOrigin reports whether the request was sent by a page served by this
+// server, according to its Origin header.
+func isSameOrigin(c *gin.Context) bool {
+	origin, err := url.Parse(c.GetHeader("Origin"))
+	return err == nil && origin.Host != "" && origin.Host == c.Request.Host
+}
+
 // InitRoutes initialize routing information
 func InitRoutes(useLogger bool, handler *PcApi) *gin.Engine {
 	r := gin.New()
@@ -63,6 +111,8 @@ func InitRoutes(useLogger bool, handler *PcApi) *gin.Engine {
 	r.GET("/process/info/:name", handler.GetProcessInfo)
 	r.POST("/process", handler.UpdateProcess)
 	r.GET("/process/ports/:name", handler.GetProcessPorts)
+	r.GET("/process/tree/:name", handler.GetProcessTree)
+	r.PATCH("/process/tree/signal/:name/:pid/:signal", handler.SignalProcessTree)
 	r.GET("/process/logs/:name/:endOffset/:limit", handler.GetProcessLogs)
 	r.DELETE("/process/logs/:name", handler.TruncateProcessLogs)
 	r.PATCH("/process/stop/:name", handler.StopProcess)
@@ -76,6 +126,7 @@ func InitRoutes(useLogger bool, handler *PcApi) *gin.Engine {
 	r.POST("/project/configuration", handler.ReloadProject)
 	r.GET("/project/name", handler.GetProjectName)
 	r.GET("/project/state", handler.GetProjectState)
+	r.POST("/project/state/save", handler.SaveProjectState)
 	r.POST("/namespace/start/:name", handler.StartNamespace)
 	r.POST("/namespace/stop/:name", handler.StopNamespace)
 	r.POST("/namespace/restart/:name", handler.RestartNamespace)
@@ -83,7 +134,12 @@ func InitRoutes(useLogger bool, handler *PcApi) *gin.Engine {
 	r.PATCH("/process/scale/:name/:scale", handler.ScaleProcess)
 	r.GET("/process/logs/ws", handler.HandleLogsStream)
 	r.GET("/process/states/ws", handler.HandleStatesStream)
+	r.GET("/process/exec/ws", handler.HandleExec)
 	r.GET("/graph", handler.GetDependencyGraph)
+	r.GET("/metrics", handler.GetMetrics)
+	r.GET("/events", handler.GetEvents)
+	r.GET("/resources", handler.GetResourceHistory)
+	initWebUIRoutes(r, authToken)
 
 	return r
 }
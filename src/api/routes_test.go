@@ -46,4 +46,76 @@ func TestTokenAuthMiddleware(t *testing.T) {
 			t.Errorf("Expected status code %d, got %d", http.StatusOK, w.Code)
 		}
 	})
+
+	t.Run("valid token cookie", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+		req.AddCookie(&http.Cookie{Name: config.TokenCookie, Value: "valid-token-1234567890"})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, w.Code)
+		}
+	})
+
+	t.Run("invalid token cookie", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+		req.AddCookie(&http.Cookie{Name: config.TokenCookie, Value: "wrong-token"})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, w.Code)
+		}
+	})
+}
+
+func TestTokenAuthMiddleware_CookieOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(TokenAuthMiddleware("valid-token-1234567890"))
+	r.POST("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name     string
+		header   string
+		origin   string
+		cookie   string
+		wantCode int
+	}{
+		{name: "cookie without header or origin", cookie: "valid-token-1234567890", wantCode: http.StatusForbidden},
+		{name: "cookie from another origin", cookie: "valid-token-1234567890", origin: "http://localhost:3000", wantCode: http.StatusForbidden},
+		{name: "cookie from the same origin", cookie: "valid-token-1234567890", origin: "http://localhost:8080", wantCode: http.StatusOK},
+		{name: "cookie with the ui header", cookie: "valid-token-1234567890", header: "1", wantCode: http.StatusOK},
+		{name: "invalid cookie with the ui header", cookie: "wrong-token", header: "1", wantCode: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "http://localhost:8080/test", nil)
+			req.AddCookie(&http.Cookie{Name: config.TokenCookie, Value: tt.cookie})
+			if tt.header != "" {
+				req.Header.Set(config.UIRequestHeader, tt.header)
+			}
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantCode {
+				t.Errorf("Expected status code %d, got %d", tt.wantCode, w.Code)
+			}
+		})
+	}
+
+	t.Run("token header from another origin", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "http://localhost:8080/test", nil)
+		req.Header.Set(config.TokenHeader, "valid-token-1234567890")
+		req.Header.Set("Origin", "http://localhost:3000")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, w.Code)
+		}
+	})
 }
@@ -1,8 +1,20 @@
 package api
 
+import (
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/pclog"
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
 type LogMessage struct {
 	Message     string `json:"message"`
 	ProcessName string `json:"process_name"`
+	// Time is the time the line was received, when the logs of several
+	// processes are merged.
+	Time time.Time `json:"time,omitzero"`
+	// Record is the parsed message, for a process with a log_format.
+	Record *pclog.LogRecord `json:"record,omitempty"`
 }
 
 // NameResponse represents a simple response containing a process name.
@@ -33,3 +45,22 @@ type ErrorResponse struct {
 type SendKeysRequest struct {
 	Keys string `json:"keys"`
 }
+
+// ExecMessage is a message of the exec WebSocket. The client sends the stdin
+// of the command in Data, EOF once its stdin is closed and Resize when its
+// terminal is resized. The server sends the output of the command in Data,
+// from its Stream, and a last message with the ExitCode, or the Error that
+// prevented the command from running.
+type ExecMessage struct {
+	Stream   string              `json:"stream,omitempty"`
+	Data     []byte              `json:"data,omitempty"`
+	EOF      bool                `json:"eof,omitempty"`
+	Resize   *types.TerminalSize `json:"resize,omitempty"`
+	ExitCode *int                `json:"exit_code,omitempty"`
+	Error    string              `json:"error,omitempty"`
+}
+
+const (
+	ExecStreamStdout = "stdout"
+	ExecStreamStderr = "stderr"
+)
@@ -0,0 +1,325 @@
+"use strict";
+
+// The web dashboard of Process Compose. It only uses the REST and WebSocket
+// API; when a token is configured, the browser sends it in the cookie set by
+// the login page.
+(() => {
+  const refreshInterval = 2000;
+  const reconnectDelay = 2000;
+  const logTail = 1000;
+  const maxLogLines = 5000;
+  const graphRefreshDelay = 300;
+  const ansiEscape = /\x1b\[[0-9;?]*[ -\/]*[@-~]/g;
+  const idleStatuses = ["Pending", "Disabled", "Skipped", "Foreground", "Scheduled"];
+
+  const processes = new Map();
+  let selected = "";
+  let logSocket = null;
+  let view = "processes";
+  let graphTimer = null;
+  let errorTimer = null;
+
+  const $ = (id) => document.getElementById(id);
+
+  function wsURL(path) {
+    const scheme = location.protocol === "https:" ? "wss:" : "ws:";
+    return `${scheme}//${location.host}${path}`;
+  }
+
+  function processPath(...segments) {
+    return segments.map((s) => encodeURIComponent(s)).join("/");
+  }
+
+  async function request(method, path) {
+    const resp = await fetch(path, {
+      method,
+      // Required by the server for the requests authenticated by the cookie.
+      headers: { Accept: "application/json", "X-PC-UI-Request": "1" },
+    });
+    if (resp.status === 401) {
+      location.assign("login");
+      throw new Error("Unauthorized");
+    }
+    const type = resp.headers.get("Content-Type") || "";
+    const body = type.includes("application/json") ? await resp.json() : await resp.text();
+    if (!resp.ok) {
+      throw new Error((body && body.error) || `${method} ${path} failed with ${resp.status}`);
+    }
+    return body;
+  }
+
+  function showError(message) {
+    const banner = $("error");
+    banner.textContent = message;
+    banner.hidden = false;
+    clearTimeout(errorTimer);
+    errorTimer = setTimeout(() => (banner.hidden = true), 8000);
+  }
+
+  // Processes
+
+  async function refreshProcesses() {
+    try {
+      const states = await request("GET", "/processes");
+      processes.clear();
+      for (const state of states.data || []) {
+        processes.set(state.name, state);
+      }
+      renderProcesses();
+    } catch (err) {
+      setConnection(false);
+    }
+  }
+
+  function formatNamespace(namespace) {
+    return Array.isArray(namespace) ? namespace.join(", ") : namespace || "";
+  }
+
+  function formatMem(state) {
+    return state.is_running && state.mem > 0 ? `${(state.mem / (1024 * 1024)).toFixed(1)} MiB` : "-";
+  }
+
+  function formatCPU(state) {
+    return state.is_running ? `${state.cpu.toFixed(1)}%` : "-";
+  }
+
+  function formatExitCode(state) {
+    return state.is_running || idleStatuses.includes(state.status) ? "-" : String(state.exit_code);
+  }
+
+  function cell(row, text, className) {
+    const td = row.insertCell();
+    td.textContent = text;
+    if (className) {
+      td.className = className;
+    }
+    return td;
+  }
+
+  function actionButton(label, enabled, action) {
+    const button = document.createElement("button");
+    button.type = "button";
+    button.textContent = label;
+    button.disabled = !enabled;
+    button.addEventListener("click", (ev) => {
+      ev.stopPropagation();
+      action();
+    });
+    return button;
+  }
+
+  function renderProcesses() {
+    const filter = $("filter").value.trim().toLowerCase();
+    const tbody = $("processes").tBodies[0];
+    tbody.replaceChildren();
+    const names = [...processes.keys()].sort();
+    for (const name of names) {
+      const state = processes.get(name);
+      const namespace = formatNamespace(state.namespace);
+      if (filter && !name.toLowerCase().includes(filter) && !namespace.toLowerCase().includes(filter)) {
+        continue;
+      }
+      const row = tbody.insertRow();
+      row.classList.toggle("selected", name === selected);
+      row.addEventListener("click", () => selectProcess(name));
+      cell(row, name);
+      cell(row, namespace);
+      cell(row, state.status, `status-${state.status}`);
+      cell(row, state.is_ready, state.is_ready === "Not Ready" ? "health-not-ready" : "");
+      cell(row, state.pid ? String(state.pid) : "-", "num");
+      cell(row, state.is_running ? state.system_time : "-");
+      cell(row, String(state.restarts), "num");
+      cell(row, formatExitCode(state), "num");
+      cell(row, formatMem(state), "num");
+      cell(row, formatCPU(state), "num");
+      const actions = cell(row, "", "actions");
+      actions.append(
+        actionButton("Start", !state.is_running, () => runAction("POST", `/process/start/${processPath(name)}`)),
+        actionButton("Stop", state.is_running, () => runAction("PATCH", `/process/stop/${processPath(name)}`)),
+        actionButton("Restart", true, () => runAction("POST", `/process/restart/${processPath(name)}`)),
+        actionButton("Scale", true, () => scaleProcess(name)),
+      );
+    }
+  }
+
+  async function runAction(method, path) {
+    try {
+      await request(method, path);
+    } catch (err) {
+      showError(err.message);
+    }
+    refreshProcesses();
+  }
+
+  async function scaleProcess(name) {
+    let current = 1;
+    try {
+      const info = await request("GET", `/process/info/${processPath(name)}`);
+      current = info.replicas || 1;
+    } catch (err) {
+      showError(err.message);
+      return;
+    }
+    const answer = prompt(`Number of replicas of ${name}:`, String(current));
+    if (answer === null) {
+      return;
+    }
+    const scale = Number.parseInt(answer, 10);
+    if (!Number.isInteger(scale) || scale < 1) {
+      showError(`Invalid number of replicas: ${answer}`);
+      return;
+    }
+    runAction("PATCH", `/process/scale/${processPath(name, String(scale))}`);
+  }
+
+  // Live state
+
+  function setConnection(connected) {
+    const status = $("connection");
+    status.textContent = connected ? "Connected" : "Disconnected";
+    status.classList.toggle("connected", connected);
+    status.classList.toggle("disconnected", !connected);
+  }
+
+  function subscribeStates() {
+    const socket = new WebSocket(wsURL("/process/states/ws"));
+    socket.addEventListener("open", () => setConnection(true));
+    socket.addEventListener("message", (msg) => {
+      const ev = JSON.parse(msg.data);
+      processes.set(ev.state.name, ev.state);
+      renderProcesses();
+      scheduleGraphRefresh();
+    });
+    socket.addEventListener("close", () => {
+      setConnection(false);
+      setTimeout(subscribeStates, reconnectDelay);
+    });
+  }
+
+  // Logs
+
+  function selectProcess(name) {
+    if (name === selected) {
+      return;
+    }
+    selected = name;
+    history.replaceState(null, "", `#${encodeURIComponent(name)}`);
+    $("logs-process").textContent = name;
+    renderProcesses();
+    markGraphSelection();
+    streamLogs();
+  }
+
+  function streamLogs() {
+    if (logSocket) {
+      logSocket.close();
+    }
+    $("logs").replaceChildren();
+    if (!selected) {
+      return;
+    }
+    const query = new URLSearchParams({ name: selected, offset: String(logTail), follow: "true" });
+    const socket = new WebSocket(wsURL(`/process/logs/ws?${query}`));
+    socket.addEventListener("message", (msg) => appendLog(JSON.parse(msg.data).message));
+    socket.addEventListener("close", () => {
+      // reconnect, unless another process was selected meanwhile
+      if (logSocket === socket) {
+        logSocket = null;
+        setTimeout(() => {
+          if (!logSocket) {
+            streamLogs();
+          }
+        }, reconnectDelay);
+      }
+    });
+    logSocket = socket;
+  }
+
+  function appendLog(message) {
+    const logs = $("logs");
+    logs.append(message.replace(ansiEscape, "") + "\n");
+    while (logs.childNodes.length > maxLogLines) {
+      logs.firstChild.remove();
+    }
+    if ($("follow").checked) {
+      logs.scrollTop = logs.scrollHeight;
+    }
+  }
+
+  // Dependency graph
+
+  function scheduleGraphRefresh() {
+    if (view !== "graph" || graphTimer) {
+      return;
+    }
+    graphTimer = setTimeout(() => {
+      graphTimer = null;
+      refreshGraph();
+    }, graphRefreshDelay);
+  }
+
+  async function refreshGraph() {
+    let svg;
+    try {
+      svg = await request("GET", "/graph?format=svg");
+    } catch (err) {
+      showError(err.message);
+      return;
+    }
+    const graph = $("graph");
+    graph.innerHTML = svg;
+    const nodes = graph.querySelectorAll("g.process");
+    if (nodes.length === 0) {
+      graph.textContent = "No process depends on another one.";
+      return;
+    }
+    for (const node of nodes) {
+      node.addEventListener("click", () => selectProcess(node.dataset.process));
+    }
+    markGraphSelection();
+  }
+
+  function markGraphSelection() {
+    for (const node of $("graph").querySelectorAll("g.process")) {
+      node.classList.toggle("selected", node.dataset.process === selected);
+    }
+  }
+
+  function showView(name) {
+    view = name;
+    for (const tab of document.querySelectorAll(".tabs button")) {
+      tab.classList.toggle("active", tab.dataset.view === name);
+    }
+    $("processes-view").hidden = name !== "processes";
+    $("graph-view").hidden = name !== "graph";
+    $("filter").hidden = name !== "processes";
+    if (name === "graph") {
+      refreshGraph();
+    }
+  }
+
+  async function init() {
+    for (const tab of document.querySelectorAll(".tabs button")) {
+      tab.addEventListener("click", () => showView(tab.dataset.view));
+    }
+    $("filter").addEventListener("input", renderProcesses);
+    $("clear-logs").addEventListener("click", () => $("logs").replaceChildren());
+    $("error").addEventListener("click", () => ($("error").hidden = true));
+
+    try {
+      const project = await request("GET", "/project/name");
+      $("project").textContent = project.projectName;
+      document.title = `${project.projectName} - Process Compose`;
+    } catch (err) {
+      showError(err.message);
+    }
+    await refreshProcesses();
+    setInterval(refreshProcesses, refreshInterval);
+    subscribeStates();
+    if (location.hash) {
+      selectProcess(decodeURIComponent(location.hash.slice(1)));
+    }
+  }
+
+  init();
+})();
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+
+	"github.com/f1bonacc1/process-compose/src/config"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	uiPath      = "/ui"
+	uiLoginPath = "/ui/login"
+)
+
+// uiFiles is the web dashboard. It has no build step: the pages and their
+// assets are served as they are.
+//
+//go:embed ui
+var uiFiles embed.FS
+
+// initWebUIRoutes serves the web dashboard at /ui. The dashboard is a single
+// page built on the REST and WebSocket API, so it is protected by the same
+// token, which browsers send in a cookie set by the login page.
+func initWebUIRoutes(r *gin.Engine, authToken string) {
+	assets, err := fs.Sub(uiFiles, "ui/assets")
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load the web UI assets")
+	}
+	r.GET(uiPath, func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, uiPath+"/")
+	})
+	r.GET(uiPath+"/", serveUIPage("ui/index.html"))
+	r.StaticFS(uiPath+"/assets", http.FS(assets))
+	r.GET(uiLoginPath, func(c *gin.Context) {
+		if authToken == "" {
+			c.Redirect(http.StatusFound, uiPath+"/")
+			return
+		}
+		serveUIPage("ui/login.html")(c)
+	})
+	r.POST(uiLoginPath, func(c *gin.Context) {
+		// The login is a form, which can't set a header: an Origin, which the
+		// browsers send with every form, has to be this server, so that other
+		// sites can't log the browser in.
+		if c.GetHeader("Origin") != "" && !isSameOrigin(c) {
+			log.Error().
+				Str("client_ip", c.ClientIP()).
+				Str("origin", c.GetHeader("Origin")).
+				Msg("rejected cross-origin web UI login attempt")
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		if authToken == "" || !isValidToken(c.PostForm("token"), authToken) {
+			log.Error().
+				Str("client_ip", c.ClientIP()).
+				Msg("failed web UI login attempt: invalid token")
+			c.Redirect(http.StatusSeeOther, uiLoginPath+"?failed")
+			return
+		}
+		// Strict, so that other sites can't act on the API on behalf of the
+		// logged-in browser.
+		c.SetSameSite(http.SameSiteStrictMode)
+		c.SetCookie(config.TokenCookie, authToken, 0, "/", "", false, true)
+		c.Redirect(http.StatusSeeOther, uiPath+"/")
+	})
+}
+
+func serveUIPage(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		page, err := uiFiles.ReadFile(name)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+	}
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/config"
+)
+
+const testUIToken = "valid-token-1234567890"
+
+func TestWebUI_NoToken(t *testing.T) {
+	t.Setenv(config.EnvVarApiToken, "")
+	t.Setenv(config.EnvVarApiTokenPath, "")
+	r := setupRouter(&mockProject{})
+
+	tests := []struct {
+		name         string
+		path         string
+		wantCode     int
+		wantLocation string
+		wantContent  string
+	}{
+		{name: "root", path: "/ui", wantCode: http.StatusMovedPermanently, wantLocation: "/ui/"},
+		{name: "index", path: "/ui/", wantCode: http.StatusOK, wantContent: `<script src="assets/app.js">`},
+		{name: "script", path: "/ui/assets/app.js", wantCode: http.StatusOK, wantContent: "/process/states/ws"},
+		{name: "style", path: "/ui/assets/style.css", wantCode: http.StatusOK},
+		{name: "missing asset", path: "/ui/assets/missing.js", wantCode: http.StatusNotFound},
+		{name: "login", path: "/ui/login", wantCode: http.StatusFound, wantLocation: "/ui/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performRequest(r, http.MethodGet, tt.path, "")
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d", tt.wantCode, w.Code)
+			}
+			if tt.wantLocation != "" && w.Header().Get("Location") != tt.wantLocation {
+				t.Errorf("expected a redirect to %s, got %s", tt.wantLocation, w.Header().Get("Location"))
+			}
+			if !strings.Contains(w.Body.String(), tt.wantContent) {
+				t.Errorf("expected the body to contain %s", tt.wantContent)
+			}
+		})
+	}
+}
+
+func TestWebUI_Token(t *testing.T) {
+	t.Setenv(config.EnvVarApiToken, testUIToken)
+	r := setupRouter(&mockProject{})
+
+	t.Run("page redirects to login", func(t *testing.T) {
+		w := performRequest(r, http.MethodGet, "/ui/", "")
+		if w.Code != http.StatusFound || w.Header().Get("Location") != uiLoginPath {
+			t.Errorf("expected a redirect to %s, got %d %s", uiLoginPath, w.Code, w.Header().Get("Location"))
+		}
+	})
+
+	t.Run("api is unauthorized", func(t *testing.T) {
+		w := performRequest(r, http.MethodGet, "/processes", "")
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", w.Code)
+		}
+	})
+
+	t.Run("login page", func(t *testing.T) {
+		w := performRequest(r, http.MethodGet, uiLoginPath, "")
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `name="token"`) {
+			t.Errorf("expected the login form, got %d", w.Code)
+		}
+	})
+
+	login := func(token string) *httptest.ResponseRecorder {
+		form := url.Values{"token": {token}}
+		req, _ := http.NewRequest(http.MethodPost, uiLoginPath, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("invalid login", func(t *testing.T) {
+		w := login("wrong-token")
+		if w.Code != http.StatusSeeOther || w.Header().Get("Location") != uiLoginPath+"?failed" {
+			t.Errorf("expected a redirect to the login page, got %d %s", w.Code, w.Header().Get("Location"))
+		}
+		if len(w.Result().Cookies()) != 0 {
+			t.Errorf("expected no cookie, got %v", w.Result().Cookies())
+		}
+	})
+
+	t.Run("cross-origin login", func(t *testing.T) {
+		form := url.Values{"token": {testUIToken}}
+		req, _ := http.NewRequest(http.MethodPost, "http://localhost:8080"+uiLoginPath, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Origin", "http://localhost:3000")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden || len(w.Result().Cookies()) != 0 {
+			t.Errorf("expected the login to be rejected, got %d %v", w.Code, w.Result().Cookies())
+		}
+	})
+
+	t.Run("valid login", func(t *testing.T) {
+		w := login(testUIToken)
+		if w.Code != http.StatusSeeOther || w.Header().Get("Location") != uiPath+"/" {
+			t.Fatalf("expected a redirect to the dashboard, got %d %s", w.Code, w.Header().Get("Location"))
+		}
+		cookies := w.Result().Cookies()
+		if len(cookies) != 1 {
+			t.Fatalf("expected the token cookie, got %v", cookies)
+		}
+		cookie := cookies[0]
+		if cookie.Name != config.TokenCookie || cookie.Value != testUIToken || !cookie.HttpOnly || cookie.SameSite != http.SameSiteStrictMode {
+			t.Errorf("unexpected cookie %+v", cookie)
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, "/ui/", nil)
+		req.AddCookie(cookie)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("expected the dashboard with the cookie, got %d", w.Code)
+		}
+	})
+}
@@ -4,13 +4,13 @@ import (
 	"errors"
 	"net"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/f1bonacc1/process-compose/src/app"
 	"github.com/f1bonacc1/process-compose/src/pclog"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
@@ -25,9 +25,10 @@ var upgrader = websocket.Upgrader{}
 // @Description           Upgrades HTTP to WebSocket and streams JSON log messages. Each message is api.LogMessage.
 // @Tags                  Process
 // @Produce               json
-// @Param                 name   query   string true  "Comma-separated process names to stream"
+// @Param                 name   query   string true  "Comma-separated process names to stream. The logs of several processes are merged in the order they were received"
 // @Param                 offset query   int    true  "Offset from the end of the log"
 // @Param                 follow query   bool   false "If true, continue streaming new lines"
+// @Param                 filter query   string false "Only stream the lines of processes with a log_format that match this filter (e.g. level>=warn request_id=abc)"
 // @Success               101 "Switching Protocols"
 // @Failure               400 {object} api.ErrorResponse
 // @Router                /process/logs/ws [get]
@@ -40,6 +41,11 @@ func (api *PcApi) HandleLogsStream(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	filter, err := pclog.ParseLogFilter(c.Query("filter"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -47,123 +53,245 @@ func (api *PcApi) HandleLogsStream(c *gin.Context) {
 	}
 
 	done := make(chan struct{})
-	wsWriteMtx := &sync.Mutex{}
 	if follow {
 		go handleIncoming(ws, done)
 	}
-	for _, processName := range processNames {
-		if processName == "" {
-			continue
-		}
-		logChan := make(chan LogMessage, 256)
-		chanCloseMtx := &sync.Mutex{}
-		isChannelClosed := false
-		closeLogChan := func() {
-			chanCloseMtx.Lock()
-			defer chanCloseMtx.Unlock()
-			if !isChannelClosed {
-				close(logChan)
-				isChannelClosed = true
+	processNames = slices.DeleteFunc(processNames, func(name string) bool { return name == "" })
+	switch len(processNames) {
+	case 0:
+		_ = ws.Close()
+	case 1:
+		api.streamLogs(ws, processNames[0], endOffset, follow, filter, done)
+	default:
+		api.streamMergedLogs(ws, processNames, endOffset, follow, filter, done)
+	}
+}
+
+// streamLogs streams the logs of a single process.
+func (api *PcApi) streamLogs(
+	ws *websocket.Conn,
+	processName string,
+	endOffset int,
+	follow bool,
+	filter *pclog.LogFilter,
+	done chan struct{},
+) {
+	queue := newLogQueue(processName)
+	parser := api.getLogParser(processName)
+	connector := pclog.NewConnector(
+		func(messages []string) {
+			for _, message := range messages {
+				if msg, ok := newLogMessage(processName, message, parser, filter); ok {
+					queue.enqueue(msg)
+				}
 			}
-		}
-		dropped := &atomic.Uint64{}
-		warnedOnce := &atomic.Bool{}
-		enqueue := func(msg LogMessage) bool {
-			chanCloseMtx.Lock()
-			defer chanCloseMtx.Unlock()
-			if isChannelClosed {
-				return false
+			if !follow {
+				queue.close()
 			}
-			select {
-			case logChan <- msg:
-			default:
-				dropped.Add(1)
-				if warnedOnce.CompareAndSwap(false, true) {
-					log.Warn().Str("process", processName).Msg("ws subscriber backpressured; dropping log lines")
-				}
+		},
+		func(message string) (n int, err error) {
+			msg, ok := newLogMessage(processName, message, parser, filter)
+			if ok && !queue.enqueue(msg) {
+				return 0, nil
 			}
-			return true
+			return len(message), nil
+		},
+		endOffset)
+	unsubscribe := func() {
+		if err := api.project.UnSubscribeLogger(processName, connector); err != nil {
+			log.Err(err).Msg("Failed to unsubscribe from logger")
 		}
-		connector := pclog.NewConnector(
-			func(messages []string) {
-				for _, message := range messages {
-					msg := LogMessage{
-						Message:     message,
-						ProcessName: processName,
-					}
-					enqueue(msg)
-				}
-				if !follow {
-					closeLogChan()
-				}
-			},
-			func(message string) (n int, err error) {
-				msg := LogMessage{
-					Message:     message,
-					ProcessName: processName,
-				}
-				if !enqueue(msg) {
-					return 0, nil
-				}
-				return len(message), nil
-			},
-			endOffset)
-		go api.handleLog(ws, processName, connector, logChan, done, wsWriteMtx, dropped, closeLogChan)
+	}
+	if err := api.project.GetLogsAndSubscribe(processName, connector); err != nil {
+		log.Err(err).Msg("Failed to subscribe to logger")
+	}
+	go handleLog(ws, queue, unsubscribe, done)
+}
 
-		err = api.project.GetLogsAndSubscribe(processName, connector)
-		if err != nil {
+// streamMergedLogs streams the logs of several processes as a single stream
+// ordered by the time the lines were received, such as `docker compose logs`.
+func (api *PcApi) streamMergedLogs(
+	ws *websocket.Conn,
+	processNames []string,
+	endOffset int,
+	follow bool,
+	filter *pclog.LogFilter,
+	done chan struct{},
+) {
+	queue := newLogQueue(strings.Join(processNames, ","))
+	parsers := make(map[string]*pclog.LogParser, len(processNames))
+	merger := pclog.NewLogMerger(endOffset, func(line pclog.MergedLogLine) {
+		if msg, ok := newLogMessage(line.ProcessName, line.Message, parsers[line.ProcessName], filter); ok {
+			msg.Time = line.Time
+			queue.enqueue(msg)
+		}
+	})
+	observers := make(map[string]pclog.LogObserver, len(processNames))
+	for _, processName := range processNames {
+		parsers[processName] = api.getLogParser(processName)
+		observer := merger.Observer(processName)
+		if err := api.project.GetLogsAndSubscribe(processName, observer); err != nil {
 			log.Err(err).Msg("Failed to subscribe to logger")
-			return
+			continue
+		}
+		observers[processName] = observer
+	}
+	merger.Start()
+	if !follow {
+		queue.close()
+	}
+	unsubscribe := func() {
+		for name, observer := range observers {
+			if err := api.project.UnSubscribeLogger(name, observer); err != nil {
+				log.Err(err).Msg("Failed to unsubscribe from logger")
+			}
+		}
+	}
+	go handleLog(ws, queue, unsubscribe, done)
+}
+
+// logQueue holds the log messages of a ws subscriber until they are written.
+// The messages queued before the writer starts, the buffered lines the
+// subscriber asked for, are all kept. The messages that follow are dropped
+// when the subscriber can't keep up.
+type logQueue struct {
+	name     string
+	messages chan LogMessage
+	mx       sync.Mutex
+	closed   bool
+	started  bool
+	backlog  []LogMessage
+	dropped  atomic.Uint64
+	warned   atomic.Bool
+}
+
+func newLogQueue(name string) *logQueue {
+	return &logQueue{
+		name:     name,
+		messages: make(chan LogMessage, 256),
+	}
+}
+
+// enqueue reports false once the queue is closed.
+func (q *logQueue) enqueue(msg LogMessage) bool {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+	if q.closed {
+		return false
+	}
+	if !q.started {
+		q.backlog = append(q.backlog, msg)
+		return true
+	}
+	select {
+	case q.messages <- msg:
+	default:
+		q.dropped.Add(1)
+		if q.warned.CompareAndSwap(false, true) {
+			log.Warn().Str("process", q.name).Msg("ws subscriber backpressured; dropping log lines")
 		}
 	}
+	return true
+}
+
+// start returns the backlog to write first. Messages enqueued from now on
+// may be dropped.
+func (q *logQueue) start() []LogMessage {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+	q.started = true
+	backlog := q.backlog
+	q.backlog = nil
+	return backlog
+}
+
+func (q *logQueue) close() {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+	if !q.closed {
+		close(q.messages)
+		q.closed = true
+	}
+}
 
+// getLogParser returns the parser of the log_format of a process, or nil.
+func (api *PcApi) getLogParser(name string) *pclog.LogParser {
+	config, err := api.project.GetProcessInfo(name)
+	if err != nil {
+		return nil
+	}
+	parser, err := pclog.NewLogParser(config.LogFormat, config.LogRegex)
+	if err != nil {
+		log.Err(err).Str("process", name).Msg("Invalid log format")
+		return nil
+	}
+	return parser
 }
 
-func (api *PcApi) handleLog(
+// newLogMessage parses a log line, and reports whether the filter selects it.
+func newLogMessage(name, message string, parser *pclog.LogParser, filter *pclog.LogFilter) (LogMessage, bool) {
+	msg := LogMessage{
+		Message:     message,
+		ProcessName: name,
+	}
+	rec, parsed := parser.Parse(message)
+	if parsed {
+		msg.Record = &rec
+	}
+	return msg, filter.Match(&rec, parsed)
+}
+
+func handleLog(
 	ws *websocket.Conn,
-	procName string,
-	connector *pclog.Connector,
-	logChan chan LogMessage,
+	queue *logQueue,
+	unsubscribe func(),
 	done chan struct{},
-	wsWriteMtx *sync.Mutex,
-	dropped *atomic.Uint64,
-	closeLogChan func(),
 ) {
 	defer func() {
-		if count := dropped.Load(); count > 0 {
-			log.Warn().Str("process", procName).Uint64("dropped", count).
+		if count := queue.dropped.Load(); count > 0 {
+			log.Warn().Str("process", queue.name).Uint64("dropped", count).
 				Msg("ws subscriber disconnected after dropped lines")
 		}
 	}()
-	defer func(project app.IProject, name string, observer pclog.LogObserver) {
-		err := project.UnSubscribeLogger(name, observer)
-		if err != nil {
-			log.Err(err).Msg("Failed to unsubscribe from logger")
-		}
-	}(api.project, procName, connector)
+	defer unsubscribe()
 	defer ws.Close()
+	// This is the only writer of the ws.Conn: the logs of several processes
+	// are merged into a single queue.
+	write := func(msg *LogMessage) bool {
+		_ = ws.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		if err := ws.WriteJSON(msg); err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Err(err).Msg("Failed to write to socket")
+			}
+			return false
+		}
+		return true
+	}
+	for _, msg := range queue.start() {
+		select {
+		case <-done:
+			log.Warn().Msg("Socket closed remotely")
+			queue.close()
+			return
+		default:
+		}
+		if !write(&msg) {
+			queue.close()
+			return
+		}
+	}
 	for {
 		select {
-		case msg, open := <-logChan:
+		case msg, open := <-queue.messages:
 			if !open {
 				return
 			}
-			// Serialize writes per ws.Conn. Multiple process streams share the
-			// same connection when the request contains comma-separated names.
-			wsWriteMtx.Lock()
-			_ = ws.SetWriteDeadline(time.Now().Add(10 * time.Second))
-			err := ws.WriteJSON(&msg)
-			wsWriteMtx.Unlock()
-			if err != nil {
-				if errors.Is(err, net.ErrClosed) {
-					return
-				}
-				log.Err(err).Msg("Failed to write to socket")
+			if !write(&msg) {
 				return
 			}
 		case <-done:
 			log.Warn().Msg("Socket closed remotely")
-			closeLogChan()
+			queue.close()
 			return
 		}
 
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/pclog"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/gorilla/websocket"
+)
+
+// newLogsServer serves the logs of the given buffers from a mock project.
+func newLogsServer(t *testing.T, buffers map[string]*pclog.ProcessLogBuffer, configs map[string]*types.ProcessConfig) *httptest.Server {
+	t.Helper()
+	mock := &mockProject{
+		getLogsAndSubscribeFn: func(name string, observer pclog.LogObserver) error {
+			buf, ok := buffers[name]
+			if !ok {
+				return errors.New("no such process")
+			}
+			buf.GetLogsAndSubscribe(observer)
+			return nil
+		},
+		unSubscribeLoggerFn: func(name string, observer pclog.LogObserver) error {
+			buffers[name].UnSubscribe(observer)
+			return nil
+		},
+		getProcessInfoFn: func(name string) (*types.ProcessConfig, error) {
+			if config, ok := configs[name]; ok {
+				return config, nil
+			}
+			return &types.ProcessConfig{Name: name}, nil
+		},
+	}
+	srv := httptest.NewServer(setupRouter(mock))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// readLogs reads the log messages of a non-following stream until the server
+// closes it.
+func readLogs(t *testing.T, srv *httptest.Server, query neturl.Values) []LogMessage {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/process/logs/ws?" + query.Encode()
+	ws, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer resp.Body.Close()
+	defer ws.Close()
+	_ = ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var messages []LogMessage
+	for {
+		var msg LogMessage
+		if err := ws.ReadJSON(&msg); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseAbnormalClosure) {
+				t.Fatalf("ReadJSON() error = %v", err)
+			}
+			return messages
+		}
+		messages = append(messages, msg)
+	}
+}
+
+func TestHandleLogsStream_Merged(t *testing.T) {
+	buffers := map[string]*pclog.ProcessLogBuffer{
+		"web": pclog.NewLogBuffer(10),
+		"db":  pclog.NewLogBuffer(10),
+	}
+	for _, line := range []struct{ proc, msg string }{
+		{"web", "web 1"}, {"db", "db 1"}, {"db", "db 2"}, {"web", "web 2"},
+	} {
+		buffers[line.proc].Write(line.msg)
+		time.Sleep(time.Millisecond)
+	}
+	srv := newLogsServer(t, buffers, nil)
+
+	messages := readLogs(t, srv, neturl.Values{"name": {"web,db"}, "offset": {"10"}, "follow": {"false"}})
+	want := []string{"web 1", "db 1", "db 2", "web 2"}
+	if len(messages) != len(want) {
+		t.Fatalf("got %d messages, want %d: %+v", len(messages), len(want), messages)
+	}
+	for i, msg := range messages {
+		if msg.Message != want[i] {
+			t.Errorf("message %d = %q, want %q", i, msg.Message, want[i])
+		}
+		if proc := strings.Fields(want[i])[0]; msg.ProcessName != proc {
+			t.Errorf("message %d process = %q, want %q", i, msg.ProcessName, proc)
+		}
+		if msg.Time.IsZero() {
+			t.Errorf("message %d has no time", i)
+		}
+	}
+}
+
+func TestHandleLogsStream_LargeBacklog(t *testing.T) {
+	// Well above the capacity of the queue of the live lines
+	const lines = 1000
+	buffers := map[string]*pclog.ProcessLogBuffer{
+		"web": pclog.NewLogBuffer(lines),
+		"db":  pclog.NewLogBuffer(lines),
+	}
+	for i := range lines {
+		buffers["web"].Write(fmt.Sprintf("web %d", i))
+		buffers["db"].Write(fmt.Sprintf("db %d", i))
+	}
+	srv := newLogsServer(t, buffers, nil)
+
+	tests := []struct {
+		name  string
+		procs string
+		want  int
+	}{
+		{name: "single", procs: "web", want: lines},
+		{name: "merged", procs: "web,db", want: 2 * lines},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := readLogs(t, srv, neturl.Values{"name": {tt.procs}, "offset": {strconv.Itoa(lines)}, "follow": {"false"}})
+			if len(messages) != tt.want {
+				t.Fatalf("got %d messages, want %d", len(messages), tt.want)
+			}
+			if last := messages[len(messages)-1].Message; !strings.HasSuffix(last, strconv.Itoa(lines-1)) {
+				t.Errorf("last message = %q, want the last buffered line", last)
+			}
+		})
+	}
+}
+
+func TestHandleLogsStream_Filter(t *testing.T) {
+	buffers := map[string]*pclog.ProcessLogBuffer{"api": pclog.NewLogBuffer(10)}
+	buffers["api"].Write(`{"level":"info","msg":"started"}`)
+	buffers["api"].Write(`{"level":"error","msg":"failed","request_id":"abc"}`)
+	buffers["api"].Write("panic: failed")
+	configs := map[string]*types.ProcessConfig{"api": {Name: "api", LogFormat: types.LogFormatJSON}}
+	srv := newLogsServer(t, buffers, configs)
+
+	messages := readLogs(t, srv, neturl.Values{"name": {"api"}, "offset": {"10"}, "filter": {"level>=warn"}})
+	if len(messages) != 1 {
+		t.Fatalf("got %d messages, want 1: %+v", len(messages), messages)
+	}
+	rec := messages[0].Record
+	if rec == nil || rec.Level != pclog.LogLevelError || rec.Message != "failed" || rec.Fields["request_id"] != "abc" {
+		t.Errorf("record = %+v, want the parsed error line", rec)
+	}
+
+	url := srv.URL + "/process/logs/ws?" + neturl.Values{"name": {"api"}, "offset": {"10"}, "filter": {"level>=loud"}}.Encode()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid filter status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
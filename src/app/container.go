@@ -0,0 +1,104 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/container"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+)
+
+var containerNameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+func (p *Process) getContainerCommander() command.Commander {
+	conf := p.procConf.Container
+	client, err := container.NewClient(conf.Host)
+	if err != nil {
+		// The host is validated with the configuration.
+		log.Err(err).Msgf("Invalid container host for %s, using the default", p.getName())
+		client, _ = container.NewClient("")
+	}
+	return command.BuildContainerCommand(client, p.getContainerName(), buildContainerSpec(p.procConf), conf.Pull)
+}
+
+// getContainerName returns container.name, or pc-<project>-<replica>. Without
+// project name, the name of the working directory of process-compose is used.
+func (p *Process) getContainerName() string {
+	if p.procConf.Container.Name != "" {
+		return p.procConf.Container.Name
+	}
+	project := p.projectName
+	if project == "" {
+		if cwd, err := os.Getwd(); err == nil {
+			project = filepath.Base(cwd)
+		}
+	}
+	name := strings.Join([]string{"pc", project, p.procConf.ReplicaName}, "-")
+	return strings.Trim(containerNameInvalidChars.ReplaceAllString(name, "_"), "_.-")
+}
+
+// buildContainerSpec translates the container configuration of the process
+// into a create request. The environment is set by the process starter.
+func buildContainerSpec(proc *types.ProcessConfig) container.CreateRequest {
+	conf := proc.Container
+	spec := container.CreateRequest{
+		Image:      conf.Image,
+		Cmd:        conf.Command,
+		Entrypoint: conf.Entrypoint,
+		User:       conf.User,
+		Labels: map[string]string{
+			container.LabelManaged: "true",
+			container.LabelProcess: proc.ReplicaName,
+		},
+		HostConfig: container.HostConfig{
+			Binds:       append([]string(nil), conf.Volumes...),
+			NetworkMode: conf.Network,
+		},
+	}
+	for _, portSpec := range conf.Ports {
+		port, err := types.ParseContainerPort(portSpec)
+		if err != nil {
+			// The ports are validated with the configuration.
+			continue
+		}
+		if spec.ExposedPorts == nil {
+			spec.ExposedPorts = map[string]struct{}{}
+			spec.HostConfig.PortBindings = map[string][]container.PortBinding{}
+		}
+		spec.ExposedPorts[port.Key()] = struct{}{}
+		spec.HostConfig.PortBindings[port.Key()] = append(spec.HostConfig.PortBindings[port.Key()], container.PortBinding{
+			HostIP:   port.HostIP,
+			HostPort: port.HostPort,
+		})
+	}
+	return spec
+}
+
+// getContainerPorts reports the host ports the container is published on, as
+// the engine forwards them rather than the container process listening on them.
+func (p *Process) getContainerPorts(ports *types.ProcessPorts) error {
+	publisher, ok := p.command.(interface {
+		PublishedPorts() (tcp, udp []uint16, err error)
+	})
+	if !ok {
+		return nil
+	}
+	tcp, udp, err := publisher.PublishedPorts()
+	if err != nil {
+		log.Err(err).Msgf("failed to get the published ports of %s", p.getName())
+		return err
+	}
+	for _, port := range tcp {
+		p.logOpenPort("TCP", port)
+	}
+	for _, port := range udp {
+		p.logOpenPort("UDP", port)
+	}
+	ports.TcpPorts = append(ports.TcpPorts, tcp...)
+	ports.UdpPorts = append(ports.UdpPorts, udp...)
+	return nil
+}
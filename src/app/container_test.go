@@ -0,0 +1,154 @@
+package app
+
+import (
+	"runtime"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/container"
+	"github.com/f1bonacc1/process-compose/src/container/containertest"
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func newContainerRunner(t *testing.T, engine *containertest.Engine, containers map[string]*types.ContainerConfig) *ProjectRunner {
+	t.Helper()
+	processes := types.Processes{}
+	for name, conf := range containers {
+		conf.Host = engine.Host()
+		processes[name] = types.ProcessConfig{
+			Name:           name,
+			ReplicaName:    name,
+			Container:      conf,
+			Environment:    []string{"PROC_VAR=proc"},
+			ShutDownParams: types.ShutDownParams{Signal: int(syscall.SIGTERM)},
+		}
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{
+		project: &types.Project{
+			Name:        "demo",
+			Processes:   processes,
+			Environment: []string{"GLOBAL_VAR=global"},
+			ShellConfig: command.DefaultShellConfig(),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return runner
+}
+
+func newContainerEngine(t *testing.T, images ...string) *containertest.Engine {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake engine listens on a unix socket")
+	}
+	engine, err := containertest.NewEngine(t.TempDir(), images...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(engine.Close)
+	return engine
+}
+
+func TestContainerProcess_Lifecycle(t *testing.T) {
+	engine := newContainerEngine(t, "alpine:3")
+	runner := newContainerRunner(t, engine, map[string]*types.ContainerConfig{
+		"job":  {Image: "alpine:3", Command: []string{"env"}, Environment: []string{"CONTAINER_VAR=container", "PROC_VAR=override"}},
+		"fail": {Image: "alpine:3", Command: []string{"fail", "boom", "3"}},
+		"web":  {Image: "nginx:1.27", Ports: []string{"8080:80", "9000/udp"}, Volumes: []string{"./html:/html:ro", "data:/data"}},
+	})
+	done := make(chan struct{})
+	go func() {
+		_ = runner.Run()
+		close(done)
+	}()
+	waitForProcessState(t, runner, "job", types.ProcessStateCompleted, 5*time.Second)
+	waitForProcessState(t, runner, "fail", types.ProcessStateCompleted, 5*time.Second)
+	waitForProcessState(t, runner, "web", types.ProcessStateRunning, 5*time.Second)
+
+	logs, _ := runner.GetProcessLog("job", 0, 0)
+	for _, want := range []string{"PC_PROC_NAME=job", "GLOBAL_VAR=global", "CONTAINER_VAR=container"} {
+		if !slices.Contains(logs, want) {
+			t.Errorf("job environment %q misses %s", logs, want)
+		}
+	}
+	if i, j := slices.Index(logs, "PROC_VAR=proc"), slices.Index(logs, "PROC_VAR=override"); i < 0 || j < i {
+		t.Errorf("container environment doesn't override the process environment: %q", logs)
+	}
+	for _, line := range logs {
+		if strings.HasPrefix(line, "PATH=") {
+			t.Errorf("the container inherited the system environment: %q", logs)
+		}
+	}
+	job, ok := engine.Container("pc-demo-job")
+	if !ok || !job.Removed || job.Spec.Labels[container.LabelManaged] != "true" || job.Spec.Labels[container.LabelProcess] != "job" {
+		t.Errorf("job container = %+v, want a removed managed container", job)
+	}
+
+	state, _ := runner.GetProcessState("fail")
+	if state.ExitCode != 3 {
+		t.Errorf("fail exit code = %d, want 3", state.ExitCode)
+	}
+	if logs, _ = runner.GetProcessLog("fail", 0, 0); !slices.Contains(logs, "boom") {
+		t.Errorf("fail logs = %q, want boom", logs)
+	}
+
+	web, _ := engine.Container("pc-demo-web")
+	if engine.Pulls() != 1 || web.Spec.Image != "nginx:1.27" {
+		t.Errorf("pulls = %d, image = %s, want nginx pulled once", engine.Pulls(), web.Spec.Image)
+	}
+	if !strings.HasSuffix(web.Spec.HostConfig.Binds[0], "/html:/html:ro") || !strings.HasPrefix(web.Spec.HostConfig.Binds[0], "/") || web.Spec.HostConfig.Binds[1] != "data:/data" {
+		t.Errorf("binds = %q, want an absolute bind mount and a named volume", web.Spec.HostConfig.Binds)
+	}
+	ports, err := runner.GetProcessPorts("web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ports.TcpPorts, []uint16{8080}) || !slices.Equal(ports.UdpPorts, []uint16{32768}) {
+		t.Errorf("ports = %v/tcp %v/udp, want the published ports", ports.TcpPorts, ports.UdpPorts)
+	}
+
+	if err = runner.StopProcess("web"); err != nil {
+		t.Fatal(err)
+	}
+	waitForProcessState(t, runner, "web", types.ProcessStateCompleted, 5*time.Second)
+	web, _ = engine.Container("pc-demo-web")
+	if !slices.Equal(web.Signals, []string{"15"}) || !web.Removed {
+		t.Errorf("web container = %+v, want stopped with SIGTERM and removed", web)
+	}
+	if state, _ = runner.GetProcessState("web"); state.ExitCode != 143 {
+		t.Errorf("web exit code = %d, want 143", state.ExitCode)
+	}
+	_ = runner.ShutDownProject()
+	<-done
+}
+
+func TestContainerProcess_StaleContainer(t *testing.T) {
+	engine := newContainerEngine(t, "alpine:3")
+	engine.AddContainer("pc-demo-managed", map[string]string{container.LabelManaged: "true"})
+	engine.AddContainer("pc-demo-foreign", nil)
+	runner := newContainerRunner(t, engine, map[string]*types.ContainerConfig{
+		"managed": {Image: "alpine:3", Command: []string{"echo", "replaced"}},
+		"foreign": {Image: "alpine:3", Command: []string{"echo", "replaced"}, Pull: container.PullNever},
+	})
+	done := make(chan struct{})
+	go func() {
+		_ = runner.Run()
+		close(done)
+	}()
+	waitForProcessState(t, runner, "managed", types.ProcessStateCompleted, 5*time.Second)
+	waitForProcessState(t, runner, "foreign", types.ProcessStateError, 5*time.Second)
+	_ = runner.ShutDownProject()
+	<-done
+
+	if logs, _ := runner.GetProcessLog("managed", 0, 0); !slices.Contains(logs, "replaced") {
+		t.Errorf("managed logs = %q, want the new container output", logs)
+	}
+	if foreign, _ := engine.Container("pc-demo-foreign"); foreign.Removed {
+		t.Error("a container not managed by process-compose was removed")
+	}
+}
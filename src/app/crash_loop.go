@@ -0,0 +1,40 @@
+package app
+
+import (
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+// crashLoopDetector tells a crash loop from the occasional failure. It keeps
+// the times of the recent failures of a process, within the sliding window of
+// its crash_loop configuration.
+type crashLoopDetector struct {
+	failures []time.Time
+}
+
+// recordFailure records a failure at now and reports whether the process is
+// crash looping: it failed at least MaxFailures times within the window. A nil
+// config never detects a crash loop.
+func (d *crashLoopDetector) recordFailure(config *types.CrashLoopConfig, now time.Time) bool {
+	if config == nil || config.MaxFailures <= 0 {
+		return false
+	}
+	window := time.Duration(config.WindowSeconds) * time.Second
+	recent := d.failures[:0]
+	for _, failure := range d.failures {
+		if now.Sub(failure) < window {
+			recent = append(recent, failure)
+		}
+	}
+	d.failures = append(recent, now)
+	if len(d.failures) > config.MaxFailures {
+		d.failures = d.failures[len(d.failures)-config.MaxFailures:]
+	}
+	return len(d.failures) >= config.MaxFailures
+}
+
+// reset forgets the recorded failures.
+func (d *crashLoopDetector) reset() {
+	d.failures = d.failures[:0]
+}
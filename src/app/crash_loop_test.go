@@ -0,0 +1,46 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestCrashLoopDetector(t *testing.T) {
+	config := &types.CrashLoopConfig{MaxFailures: 3, WindowSeconds: 10}
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		config   *types.CrashLoopConfig
+		failures []time.Duration
+		want     bool
+	}{
+		{"no config", nil, []time.Duration{0, time.Second, 2 * time.Second}, false},
+		{"below the threshold", config, []time.Duration{0, time.Second}, false},
+		{"threshold within the window", config, []time.Duration{0, time.Second, 2 * time.Second}, true},
+		{"failures spread over more than the window", config, []time.Duration{0, 6 * time.Second, 12 * time.Second}, false},
+		{"old failures slide out", config, []time.Duration{0, 11 * time.Second, 12 * time.Second, 13 * time.Second}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d crashLoopDetector
+			var got bool
+			for _, offset := range tt.failures {
+				got = d.recordFailure(tt.config, base.Add(offset))
+			}
+			if got != tt.want {
+				t.Errorf("recordFailure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var d crashLoopDetector
+	for i := range 3 {
+		d.recordFailure(config, base.Add(time.Duration(i)*time.Second))
+	}
+	d.reset()
+	if d.recordFailure(config, base.Add(3*time.Second)) {
+		t.Error("recordFailure() after reset() reports a crash loop")
+	}
+}
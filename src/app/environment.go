@@ -13,12 +13,16 @@ import (
 func buildProcessEnvironment(
 	proc *types.ProcessConfig,
 	globalEnv []string,
+	secretEnv []string,
 	dotEnvVars map[string]string,
 ) []string {
 	env := []string{
 		"PC_PROC_NAME=" + proc.Name,
 		EnvReplicaNum + "=" + strconv.Itoa(proc.ReplicaNum),
 	}
+	for name, port := range proc.AssignedPorts {
+		env = append(env, types.PortEnvName(name)+"="+strconv.Itoa(port))
+	}
 
 	// .env variables and system environment MUST come BEFORE YAML configurations
 	// so that explicit YAML configs can override both .env and system defaults.
@@ -26,16 +30,22 @@ func buildProcessEnvironment(
 	// 1. .env file variables (baseline defaults)
 	// 2. System environment (os.Environ - can override .env from shell)
 	// 3. Global YAML environment section (explicit config overrides)
-	// 4. Local process env_file variables
-	// 5. Local process YAML environment section (highest - process-specific overrides)
+	// 4. Project secrets
+	// 5. Local process env_file variables
+	// 6. Local process YAML environment section (highest - process-specific overrides)
+	// 7. Container environment section, for container processes
+	// Containers don't inherit the system environment: they run in their own.
 	if dotEnvVars != nil && !proc.DisableDotEnv {
 		for k, v := range dotEnvVars {
 			env = append(env, k+"="+v)
 		}
 	}
 
-	env = append(env, os.Environ()...)
+	if proc.Container == nil {
+		env = append(env, os.Environ()...)
+	}
 	env = append(env, globalEnv...)
+	env = append(env, secretEnv...)
 
 	if proc.EnvFile != "" {
 		envFile := proc.EnvFile
@@ -53,5 +63,8 @@ func buildProcessEnvironment(
 	}
 
 	env = append(env, proc.Environment...)
+	if proc.Container != nil {
+		env = append(env, proc.Container.Environment...)
+	}
 	return env
 }
@@ -1,9 +1,15 @@
 package app
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/f1bonacc1/process-compose/src/pclog"
+	"github.com/f1bonacc1/process-compose/src/secrets"
 	"github.com/f1bonacc1/process-compose/src/types"
 )
 
@@ -13,9 +19,11 @@ func TestBuildProcessEnvironment(t *testing.T) {
 		ReplicaNum:    1,
 		Environment:   []string{"PROC_ENV=proc_val", "COMMON_ENV=proc_override"},
 		DisableDotEnv: false,
+		AssignedPorts: map[string]int{"http": 8080},
 	}
 
-	globalEnv := []string{"GLOBAL_ENV=global_val", "COMMON_ENV=global_val"}
+	globalEnv := []string{"GLOBAL_ENV=global_val", "COMMON_ENV=global_val", "TOKEN=global_val"}
+	secretEnv := []string{"TOKEN=secret_val", "COMMON_ENV=secret_val"}
 	dotEnvVars := map[string]string{
 		"DOTENV_VAR": "dotenv_val",
 		"COMMON_ENV": "dotenv_val",
@@ -24,7 +32,7 @@ func TestBuildProcessEnvironment(t *testing.T) {
 	// Set a system env var to test inheritance
 	t.Setenv("SYSTEM_ENV", "system_val")
 
-	env := buildProcessEnvironment(proc, globalEnv, dotEnvVars)
+	env := buildProcessEnvironment(proc, globalEnv, secretEnv, dotEnvVars)
 
 	envMap := make(map[string]string)
 	for _, e := range env {
@@ -40,9 +48,11 @@ func TestBuildProcessEnvironment(t *testing.T) {
 	}{
 		{"PC_PROC_NAME", "test-proc"},
 		{EnvReplicaNum, "1"},
+		{"PC_PORT_HTTP", "8080"},
 		{"DOTENV_VAR", "dotenv_val"},
 		{"SYSTEM_ENV", "system_val"},
 		{"GLOBAL_ENV", "global_val"},
+		{"TOKEN", "secret_val"},
 		{"PROC_ENV", "proc_val"},
 		{"COMMON_ENV", "proc_override"},
 	}
@@ -63,7 +73,7 @@ func TestBuildProcessEnvironment_DisableDotEnv(t *testing.T) {
 	}
 	dotEnvVars := map[string]string{"DOTENV_VAR": "dotenv_val"}
 
-	env := buildProcessEnvironment(proc, nil, dotEnvVars)
+	env := buildProcessEnvironment(proc, nil, nil, dotEnvVars)
 
 	for _, e := range env {
 		if strings.HasPrefix(e, "DOTENV_VAR=") {
@@ -71,3 +81,69 @@ func TestBuildProcessEnvironment_DisableDotEnv(t *testing.T) {
 		}
 	}
 }
+
+func TestProjectRunner_SecretRedaction(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("s3cr3t-token"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := secrets.Resolve(context.Background(), types.Secrets{
+		"API_TOKEN": {Provider: types.SecretProviderFile, Path: tokenFile},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	proc := types.ProcessConfig{Name: "web", ReplicaName: "web"}
+	runner := &ProjectRunner{
+		project: &types.Project{Processes: types.Processes{"web": proc}},
+	}
+	runner.secrets.Store(resolved)
+
+	info, err := runner.GetProcessInfo("web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(info.SecretEnvironment, []string{"API_TOKEN=" + types.RedactedValue}) {
+		t.Errorf("SecretEnvironment = %q", info.SecretEnvironment)
+	}
+	if info.OriginalConfig != "" || slices.ContainsFunc(info.Environment, func(e string) bool { return strings.Contains(e, "s3cr3t") }) {
+		t.Errorf("process info leaks the secret: %+v", info)
+	}
+	if !slices.Contains(runner.GetFullProcessEnvironment(&proc), "API_TOKEN="+types.RedactedValue) {
+		t.Error("GetFullProcessEnvironment() should redact the secret")
+	}
+	if !slices.Contains(runner.GetProcessLaunchEnvironment(&proc), "API_TOKEN=s3cr3t-token") {
+		t.Error("GetProcessLaunchEnvironment() should include the secret")
+	}
+
+	logs := pclog.NewLogBuffer(10)
+	process := NewProcess(
+		withProcConf(&proc),
+		withProcLog(logs),
+		withLogger(pclog.NewNilLogger()),
+		withSecrets(resolved),
+	)
+	process.handleInfo("token is s3cr3t-token")
+	process.handleError("auth failed for s3cr3t-token")
+	want := []string{"token is " + types.RedactedValue, "auth failed for " + types.RedactedValue}
+	if got := logs.GetLogRange(0, 10); !slices.Equal(got, want) {
+		t.Errorf("logs = %q, want %q", got, want)
+	}
+}
+
+func TestProjectRunner_PrepareSecretsStrict(t *testing.T) {
+	missing := types.Secrets{
+		"API_TOKEN": {Provider: types.SecretProviderFile, Path: filepath.Join(t.TempDir(), "missing")},
+	}
+	runner := &ProjectRunner{
+		ctxApp:  context.Background(),
+		project: &types.Project{Secrets: missing},
+	}
+	if err := runner.prepareSecrets(); err != nil {
+		t.Errorf("prepareSecrets() error = %v, want the secret left unset", err)
+	}
+	runner.project.IsStrict = true
+	if err := runner.prepareSecrets(); err == nil {
+		t.Error("prepareSecrets() should fail the start of a strict project")
+	}
+}
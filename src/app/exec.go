@@ -0,0 +1,163 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/creack/pty"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+)
+
+// execWaitDelay bounds the wait for the output of a command that exited, when
+// its children keep the output open.
+const execWaitDelay = time.Second
+
+// ExecProcess runs a command with the environment and working directory of a
+// process, whether it is running or not. It returns the exit code of the
+// command, and an error if the command could not be run. Cancelling ctx kills
+// the command.
+func (p *ProjectRunner) ExecProcess(ctx context.Context, name string, req *types.ExecRequest, streams *types.ExecStreams) (int, error) {
+	if err := req.Validate(); err != nil {
+		return -1, err
+	}
+	p.runProcMutex.Lock()
+	proc, ok := p.project.Processes[name]
+	p.runProcMutex.Unlock()
+	if !ok {
+		return -1, fmt.Errorf("process %s does not exist", name)
+	}
+	if proc.Container != nil {
+		// The command would run on the host, without the filesystem and the
+		// tools of the container.
+		return -1, fmt.Errorf("process %s runs in a container, exec is not supported: use the exec command of the container engine", name)
+	}
+	env := p.GetProcessLaunchEnvironment(&proc)
+	path, err := lookPathInEnv(req.Command[0], env)
+	if err != nil {
+		return -1, err
+	}
+	cmd := exec.CommandContext(ctx, path, req.Command[1:]...)
+	cmd.Env = env
+	cmd.Dir = proc.WorkingDir
+	cmd.WaitDelay = execWaitDelay
+
+	log.Info().Msgf("Running %q in the environment of %s", req.Command, name)
+	if req.Tty {
+		err = runExecPty(cmd, req.Size, streams)
+	} else {
+		err = runExecPipes(cmd, streams)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return -1, err
+	}
+	return 0, nil
+}
+
+func runExecPipes(cmd *exec.Cmd, streams *types.ExecStreams) error {
+	cmd.Stdout, cmd.Stderr = streams.Stdout, streams.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+	// The copy isn't waited for: the command may exit before its stdin ends.
+	go copyAndClose(stdin, streams.Stdin)
+	return cmd.Wait()
+}
+
+func runExecPty(cmd *exec.Cmd, size types.TerminalSize, streams *types.ExecStreams) error {
+	ptmx, err := pty.StartWithSize(cmd, &pty.Winsize{Rows: size.Rows, Cols: size.Cols})
+	if err != nil {
+		return fmt.Errorf("error starting PTY command: %w", err)
+	}
+	defer ptmx.Close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case size, ok := <-streams.Resize:
+				if !ok {
+					return
+				}
+				_ = pty.Setsize(ptmx, &pty.Winsize{Rows: size.Rows, Cols: size.Cols})
+			}
+		}
+	}()
+	go func() {
+		if streams.Stdin != nil {
+			_, _ = io.Copy(ptmx, streams.Stdin)
+		}
+	}()
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		// Reading the PTY fails with EIO once the command exited and its
+		// output was read.
+		_, _ = io.Copy(streams.Stdout, ptmx)
+	})
+	err = cmd.Wait()
+	waitTimeout(&wg, execWaitDelay)
+	return err
+}
+
+func copyAndClose(dst io.WriteCloser, src io.Reader) {
+	if src != nil {
+		_, _ = io.Copy(dst, src)
+	}
+	_ = dst.Close()
+}
+
+func waitTimeout(wg *sync.WaitGroup, timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+}
+
+// lookPathInEnv looks the executable up in the PATH of env, rather than in the
+// PATH of process-compose.
+func lookPathInEnv(file string, env []string) (string, error) {
+	if strings.ContainsAny(file, `/\`) {
+		return file, nil
+	}
+	var path string
+	for _, kv := range env {
+		if key, value, ok := strings.Cut(kv, "="); ok && strings.EqualFold(key, "PATH") {
+			path = value
+		}
+	}
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+		if found, err := exec.LookPath(filepath.Join(dir, file)); err == nil {
+			return found, nil
+		}
+	}
+	found, err := exec.LookPath(file)
+	if err != nil {
+		return "", fmt.Errorf("executable %s not found in the process PATH: %w", file, err)
+	}
+	return found, nil
+}
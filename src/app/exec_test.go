@@ -0,0 +1,122 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func newExecRunner(t *testing.T, proc types.ProcessConfig) *ProjectRunner {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX commands")
+	}
+	proc.Name = "proc"
+	proc.ReplicaName = "proc"
+	runner, err := NewProjectRunner(&ProjectOpts{
+		project: &types.Project{
+			Processes:   map[string]types.ProcessConfig{"proc": proc},
+			ShellConfig: command.DefaultShellConfig(),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return runner
+}
+
+func TestExecProcess(t *testing.T) {
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "bin")
+	if err := os.Mkdir(bin, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\necho \"$GREETING from $(pwd)\"\ncat\necho oops >&2\nexit 4\n"
+	if err := os.WriteFile(filepath.Join(bin, "greet"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env.proc"), []byte("GREETING=hello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runner := newExecRunner(t, types.ProcessConfig{
+		WorkingDir:  dir,
+		EnvFile:     ".env.proc",
+		Environment: []string{"PATH=" + bin + string(os.PathListSeparator) + os.Getenv("PATH")},
+	})
+
+	var stdout, stderr bytes.Buffer
+	code, err := runner.ExecProcess(context.Background(), "proc", &types.ExecRequest{Command: []string{"greet"}}, &types.ExecStreams{
+		Stdin:  strings.NewReader("input\n"),
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != 4 {
+		t.Errorf("exit code = %d, want 4", code)
+	}
+	realDir, _ := filepath.EvalSymlinks(dir)
+	if got, want := stdout.String(), "hello from "+realDir+"\ninput\n"; got != want && got != "hello from "+dir+"\ninput\n" {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+	if stderr.String() != "oops\n" {
+		t.Errorf("stderr = %q, want %q", stderr.String(), "oops\n")
+	}
+}
+
+func TestExecProcess_Tty(t *testing.T) {
+	runner := newExecRunner(t, types.ProcessConfig{})
+	var stdout bytes.Buffer
+	code, err := runner.ExecProcess(context.Background(), "proc", &types.ExecRequest{
+		Command: []string{"sh", "-c", "test -t 0 && stty size"},
+		Tty:     true,
+		Size:    types.TerminalSize{Rows: 42, Cols: 100},
+	}, &types.ExecStreams{Stdout: &stdout})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != 0 {
+		t.Errorf("exit code = %d, want 0: %q", code, stdout.String())
+	}
+	if got := strings.TrimSpace(stdout.String()); got != "42 100" {
+		t.Errorf("stty size = %q, want %q", got, "42 100")
+	}
+}
+
+func TestExecProcess_Errors(t *testing.T) {
+	runner := newExecRunner(t, types.ProcessConfig{})
+	tests := []struct {
+		name    string
+		proc    string
+		command []string
+		want    string
+	}{
+		{"unknown process", "nope", []string{"true"}, "does not exist"},
+		{"no command", "proc", nil, "command is required"},
+		{"unknown executable", "proc", []string{"no-such-executable-pc"}, "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runner.ExecProcess(context.Background(), tt.proc, &types.ExecRequest{Command: tt.command}, &types.ExecStreams{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecProcess_Container(t *testing.T) {
+	runner := newExecRunner(t, types.ProcessConfig{Container: &types.ContainerConfig{Image: "postgres"}})
+	_, err := runner.ExecProcess(context.Background(), "proc", &types.ExecRequest{Command: []string{"true"}}, &types.ExecStreams{})
+	if err == nil || !strings.Contains(err.Error(), "container") {
+		t.Errorf("err = %v, want exec to be rejected for a container", err)
+	}
+}
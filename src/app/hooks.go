@@ -0,0 +1,126 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+)
+
+// runHook runs a lifecycle hook with the environment and working directory of
+// the process. The output of the hook goes to the process log, and its outcome
+// to the process state.
+func (p *Process) runHook(ctx context.Context, name string, hook *types.HookConfig, extraEnv ...string) error {
+	if hook == nil {
+		return nil
+	}
+	timeout := time.Duration(hook.Timeout()) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	log.Debug().Str("process", p.getName()).Msgf("Running %s hook", name)
+	cmd := command.BuildCommandShellArgContext(ctx, p.shellConfig, hook.Command)
+	cmd.SetEnv(append(p.getProcessEnvironment(), extraEnv...))
+	cmd.SetDir(p.procConf.WorkingDir)
+	err := p.runHookCommand(cmd, name)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %v", timeout)
+		}
+		err = fmt.Errorf("%s hook failed: %w", name, err)
+		log.Error().Err(err).Str("process", p.getName()).Send()
+		p.handleError(err.Error())
+	}
+	p.setHookResult(name, err)
+	return err
+}
+
+// runHookCommand runs the hook command, and writes its output to the process
+// log, each line prefixed with the hook name.
+func (p *Process) runHookCommand(cmd command.Commander, name string) error {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+	prefix := "[" + name + "] "
+	var wg sync.WaitGroup
+	wg.Go(func() { p.handleHookOutput(stdout, prefix, p.handleInfo) })
+	wg.Go(func() { p.handleHookOutput(stderr, prefix, p.handleError) })
+	wg.Wait()
+	return cmd.Wait()
+}
+
+func (p *Process) handleHookOutput(pipe io.Reader, prefix string, handler func(message string)) {
+	scanner := bufio.NewScanner(pipe)
+	for scanner.Scan() {
+		handler(prefix + scanner.Text())
+	}
+}
+
+// setHookResult reports a failed hook in the process state and in the journal.
+// A hook that succeeds clears its own earlier failure.
+func (p *Process) setHookResult(name string, err error) {
+	p.stateMtx.Lock()
+	switch {
+	case err != nil:
+		p.procState.FailedHook = name
+		p.procState.HookError = err.Error()
+	case p.procState.FailedHook == name:
+		p.procState.FailedHook = ""
+		p.procState.HookError = ""
+	default:
+		p.stateMtx.Unlock()
+		return
+	}
+	ev := p.snapshotEventLocked()
+	p.stateMtx.Unlock()
+	p.publishLocked(ev)
+	if err != nil {
+		p.record(types.JournalEvent{
+			Type:    types.JournalEventHookFailure,
+			Reason:  err.Error(),
+			Details: map[string]string{"hook": name},
+		})
+	}
+}
+
+// runPreStartHook reports whether the process may launch. A stop during the
+// hook cancels it.
+func (p *Process) runPreStartHook() bool {
+	return p.runHook(p.procRunCtx, types.HookPreStart, p.procConf.Hooks.PreStart) == nil
+}
+
+// runPostStartHook runs the post_start hook once per launch, when the process
+// becomes ready.
+func (p *Process) runPostStartHook() {
+	if p.procConf.Hooks.PostStart == nil || p.postStartDone.Swap(true) {
+		return
+	}
+	go func() {
+		_ = p.runHook(p.procRunCtx, types.HookPostStart, p.procConf.Hooks.PostStart)
+	}()
+}
+
+func (p *Process) runPreStopHook() {
+	_ = p.runHook(context.Background(), types.HookPreStop, p.procConf.Hooks.PreStop)
+}
+
+func (p *Process) runPostStopHook() {
+	exitCode := types.EnvVarExitCode + "=" + strconv.Itoa(p.getExitCode())
+	_ = p.runHook(context.Background(), types.HookPostStop, p.procConf.Hooks.PostStop, exitCode)
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func runHooksProject(t *testing.T, proc types.ProcessConfig) *ProjectRunner {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	shell := command.DefaultShellConfig()
+	proc.Name = "proc"
+	proc.ReplicaName = "proc"
+	proc.Executable = shell.ShellCommand
+	proc.Args = []string{shell.ShellArgument, proc.Command}
+	runner, err := NewProjectRunner(&ProjectOpts{
+		project: &types.Project{
+			Processes:   map[string]types.ProcessConfig{"proc": proc},
+			ShellConfig: shell,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = runner.Run()
+	}()
+	t.Cleanup(func() { _ = runner.ShutDownProject() })
+	return runner
+}
+
+func waitForLogLine(t *testing.T, runner *ProjectRunner, line string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		logs, _ := runner.GetProcessLog("proc", 0, 0)
+		if slices.Contains(logs, line) {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	logs, _ := runner.GetProcessLog("proc", 0, 0)
+	t.Errorf("log line %q not found in %q", line, logs)
+}
+
+func TestHooks_PreStartFailure(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "started")
+	runner := runHooksProject(t, types.ProcessConfig{
+		Command: "touch " + marker,
+		Hooks: types.ProcessHooks{
+			PreStart: &types.HookConfig{Command: "echo migration failed >&2; exit 3"},
+		},
+	})
+	waitForProcessState(t, runner, "proc", types.ProcessStateError, 5*time.Second)
+	waitForLogLine(t, runner, "[pre_start] migration failed")
+
+	state, err := runner.GetProcessState("proc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.FailedHook != types.HookPreStart || state.HookError == "" {
+		t.Errorf("FailedHook = %q, HookError = %q, want the pre_start failure", state.FailedHook, state.HookError)
+	}
+	if state.ExitCode != 1 {
+		t.Errorf("ExitCode = %d, want 1", state.ExitCode)
+	}
+	if _, err = os.Stat(marker); err == nil {
+		t.Error("the process started although pre_start failed")
+	}
+}
+
+func TestHooks_PostStopExitCode(t *testing.T) {
+	runner := runHooksProject(t, types.ProcessConfig{
+		Command: "exit 2",
+		Hooks: types.ProcessHooks{
+			PreStart: &types.HookConfig{Command: "echo migrating"},
+			PostStop: &types.HookConfig{Command: `echo "exited with $PC_EXIT_CODE"`},
+		},
+	})
+	waitForProcessState(t, runner, "proc", types.ProcessStateCompleted, 5*time.Second)
+	waitForLogLine(t, runner, "[pre_start] migrating")
+	waitForLogLine(t, runner, "[post_stop] exited with 2")
+
+	state, err := runner.GetProcessState("proc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.FailedHook != "" {
+		t.Errorf("FailedHook = %q, want none", state.FailedHook)
+	}
+}
+
+func TestHooks_PostStartAndPreStop(t *testing.T) {
+	runner := runHooksProject(t, types.ProcessConfig{
+		Command:      "echo ready && sleep 10",
+		ReadyLogLine: "ready",
+		Hooks: types.ProcessHooks{
+			PostStart: &types.HookConfig{Command: "echo started"},
+			PreStop:   &types.HookConfig{Command: "echo stopping; exit 1"},
+		},
+	})
+	waitForLogLine(t, runner, "[post_start] started")
+
+	if err := runner.StopProcess("proc"); err != nil {
+		t.Fatal(err)
+	}
+	waitForProcessState(t, runner, "proc", types.ProcessStateCompleted, 5*time.Second)
+	waitForLogLine(t, runner, "[pre_stop] stopping")
+
+	state, err := runner.GetProcessState("proc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.FailedHook != types.HookPreStop {
+		t.Errorf("FailedHook = %q, want %s", state.FailedHook, types.HookPreStop)
+	}
+}
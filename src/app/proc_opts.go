@@ -4,7 +4,9 @@ import (
 	"time"
 
 	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/limits"
 	"github.com/f1bonacc1/process-compose/src/pclog"
+	"github.com/f1bonacc1/process-compose/src/secrets"
 	"github.com/f1bonacc1/process-compose/src/types"
 )
 
@@ -28,6 +30,12 @@ func withDotEnv(env map[string]string) ProcOpts {
 	}
 }
 
+func withSecrets(resolved *secrets.Resolved) ProcOpts {
+	return func(p *Process) {
+		p.secrets = resolved
+	}
+}
+
 func withLogger(logger pclog.PcLogger) ProcOpts {
 	return func(p *Process) {
 		p.logger = logger
@@ -105,3 +113,27 @@ func withStatePublisher(publish StatePublisher) ProcOpts {
 		p.publishState = publish
 	}
 }
+
+func withEventRecorder(record EventRecorder) ProcOpts {
+	return func(p *Process) {
+		p.recordEvent = record
+	}
+}
+
+func withResourceRecorder(record ResourceRecorder) ProcOpts {
+	return func(p *Process) {
+		p.recordResources = record
+	}
+}
+
+func withLimiter(limiter *limits.Limiter) ProcOpts {
+	return func(p *Process) {
+		p.limiter = limiter
+	}
+}
+
+func withProjectName(name string) ProcOpts {
+	return func(p *Process) {
+		p.projectName = name
+	}
+}
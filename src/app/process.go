@@ -6,7 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
+	"net"
 	"os"
+	"os/exec"
 	"runtime"
 	"slices"
 	"strconv"
@@ -21,7 +24,9 @@ import (
 
 	"github.com/f1bonacc1/process-compose/src/command"
 	"github.com/f1bonacc1/process-compose/src/health"
+	"github.com/f1bonacc1/process-compose/src/limits"
 	"github.com/f1bonacc1/process-compose/src/pclog"
+	"github.com/f1bonacc1/process-compose/src/secrets"
 
 	"github.com/fatih/color"
 	"github.com/rs/zerolog/log"
@@ -76,12 +81,14 @@ type Process struct {
 	extraArgs            []string
 	isStopped            atomic.Bool
 	isRestarting         atomic.Bool
+	postStartDone        atomic.Bool
 	stdin                io.WriteCloser
 	passProvided         bool
 	isTuiEnabled         bool
 	stdOutDone           chan struct{}
 	stdErrDone           chan struct{}
 	dotEnvVars           map[string]string
+	secrets              *secrets.Resolved
 	truncateLogs         bool
 	metricsProc          *puproc.Process
 	lastStatusPoll       time.Time
@@ -89,6 +96,14 @@ type Process struct {
 	withRecursiveMetrics bool
 	processTree          *ProcessTree
 	publishState         StatePublisher
+	recordEvent          EventRecorder
+	recordResources      ResourceRecorder
+	projectName          string
+	limiter              *limits.Limiter
+	effectiveLimits      *types.EffectiveLimits
+	oomKillsAtStart      int
+	restartAttempt       int
+	crashLoop            crashLoopDetector
 }
 
 // StatePublisher is invoked from Process whenever the observable state of
@@ -96,6 +111,18 @@ type Process struct {
 // and may be nil — Process treats that as a no-op.
 type StatePublisher func(ev types.ProcessStateEvent)
 
+// EventRecorder appends an event to the project's event journal. Like
+// StatePublisher it may be nil.
+type EventRecorder func(ev types.JournalEvent)
+
+// ResourceRecorder appends a resource usage sample of a process to the
+// project's resource history. Like StatePublisher it may be nil.
+type ResourceRecorder func(name string, sample types.ResourceSample)
+
+// dependencyPollInterval is how often the process_port_open and file_exists
+// dependency conditions are checked.
+const dependencyPollInterval = 250 * time.Millisecond
+
 // waitResult reports how a wait on another process ended.
 type waitResult int
 
@@ -159,6 +186,15 @@ func (p *Process) run() int {
 	p.onProcessStart()
 loop:
 	for {
+		if !p.runPreStartHook() {
+			if p.procRunCtx.Err() != nil {
+				break
+			}
+			p.setExitCode(1)
+			p.onProcessEnd(types.ProcessStateError)
+			return 1
+		}
+		p.postStartDone.Store(false)
 		err := p.setStateAndRun(p.getStartingStateName(), p.getProcessStarter())
 		if err != nil {
 			log.Error().Err(err).Msgf(`Failed to run command ["%v"] for process %s`, strings.Join(p.getCommand(), `" "`), p.getName())
@@ -186,43 +222,59 @@ loop:
 			log.Err(err).Msgf("Could not find pid %d with name %s", p.procState.Pid, p.getName())
 		}
 		p.stateMtx.Unlock()
+		p.recordResourceLimits()
 		log.Info().
 			Str("process", p.getName()).
 			Strs("command", p.getCommand()).
 			Msg("Started")
 
 		p.startProbes()
+		if p.readyProber == nil && p.procConf.ReadyLogLine == "" {
+			p.runPostStartHook()
+		}
 
 		p.waitForStdOutErr()
 		_ = p.command.Wait()
 		p.Lock()
 		p.setExitCode(p.command.ExitCode())
 		p.Unlock()
+		p.checkOOMKill()
 		log.Info().
 			Str("process", p.getName()).
 			Int("exit_code", p.getExitCode()).
 			Msg("Exited")
+		p.recordExit()
 
 		if p.isDaemonLaunched() {
 			p.setState(types.ProcessStateLaunched)
 			p.waitForDaemonCompletion()
 		}
+		p.runPostStopHook()
 
 		if !p.isRestartable() {
 			break
 		}
-		p.setState(types.ProcessStateRestarting)
+		backoff, crashLooping := p.getRestartBackoff(time.Since(now))
+		if crashLooping {
+			p.setState(types.ProcessStateCrashLoopBackOff)
+		} else {
+			p.setState(types.ProcessStateRestarting)
+		}
 		p.stateMtx.Lock()
 		p.procState.Restarts += 1
 		p.stateMtx.Unlock()
+		p.record(types.JournalEvent{
+			Type:   types.JournalEventRestart,
+			Reason: fmt.Sprintf("exited with code %d", p.getExitCode()),
+		})
 		log.Info().Msgf("Restarting %s in %v second(s)... Restarts: %d",
-			p.getName(), p.getBackoff().Seconds(), p.procState.Restarts)
+			p.getName(), backoff.Seconds(), p.procState.Restarts)
 
 		select {
 		case <-p.procRunCtx.Done():
 			log.Debug().Str("process", p.getName()).Msg("process stopped while waiting to restart")
 			break loop
-		case <-time.After(p.getBackoff()):
+		case <-time.After(backoff):
 			p.handleInfo("\n")
 			continue
 		}
@@ -278,6 +330,8 @@ func (p *Process) getProcessStarter() func() error {
 		p.command = p.getCommander()
 		p.command.SetEnv(p.getProcessEnvironment())
 		p.command.SetDir(p.procConf.WorkingDir)
+		p.prepareResourceLimits()
+		defer p.limiter.Started()
 
 		if p.isMain || (p.procConf.IsElevated && !p.isTuiEnabled) {
 			p.command.AttachIo()
@@ -311,6 +365,9 @@ func (p *Process) getProcessStarter() func() error {
 }
 
 func (p *Process) getCommander() command.Commander {
+	if p.procConf.Container != nil {
+		return p.getContainerCommander()
+	}
 	if (p.procConf.IsTty || p.procConf.IsInteractive) && !p.isMain {
 		return command.BuildPtyCommand(
 			p.procConf.Executable,
@@ -346,8 +403,27 @@ func (p *Process) getBackoff() time.Duration {
 	return time.Duration(backoff) * time.Second
 }
 
+// getRestartBackoff returns the delay before restarting a process that exited
+// after running for ranFor, and whether the process is crash looping. Unlike
+// getBackoff, the delay grows with every consecutive restart, until the
+// process runs long enough for backoff_reset_seconds.
+func (p *Process) getRestartBackoff(ranFor time.Duration) (time.Duration, bool) {
+	policy := &p.procConf.RestartPolicy
+	if policy.BackoffResetSeconds > 0 && ranFor >= time.Duration(policy.BackoffResetSeconds)*time.Second {
+		p.restartAttempt = 0
+		p.crashLoop.reset()
+	}
+	crashLooping := false
+	if !p.procConf.IsExitCodeSuccess(p.getExitCode()) {
+		crashLooping = p.crashLoop.recordFailure(policy.CrashLoop, time.Now())
+	}
+	backoff := policy.Backoff(p.restartAttempt, rand.Float64())
+	p.restartAttempt++
+	return backoff, crashLooping
+}
+
 func (p *Process) getProcessEnvironment() []string {
-	return buildProcessEnvironment(p.procConf, p.globalEnv, p.dotEnvVars)
+	return buildProcessEnvironment(p.procConf, p.globalEnv, p.secrets.Env(), p.dotEnvVars)
 }
 
 func (p *Process) isRestartable() bool {
@@ -388,12 +464,14 @@ func (p *Process) isRestartable() bool {
 // the *waiting* process (its procRunCtx.Done()), so that stopping a process
 // that is still waiting on its dependencies releases it immediately instead of
 // leaving its goroutine parked until the dependency happens to resolve.
-func (p *Process) waitForStarted(abort <-chan struct{}) {
+func (p *Process) waitForStarted(abort <-chan struct{}) waitResult {
 	select {
 	case <-p.procStartedChan:
 	case <-p.procRunCtx.Done():
 	case <-abort:
+		return waitAborted
 	}
+	return waitOk
 }
 
 func (p *Process) waitForCompletion() int {
@@ -444,6 +522,47 @@ func (p *Process) waitUntilLogReady(abort <-chan struct{}) waitResult {
 	return waitFailed
 }
 
+// waitUntilPortOpen waits until address accepts TCP connections.
+func (p *Process) waitUntilPortOpen(abort <-chan struct{}, address string) waitResult {
+	return p.pollUntil(abort, func() bool {
+		conn, err := net.DialTimeout("tcp", address, dependencyPollInterval)
+		if err != nil {
+			return false
+		}
+		_ = conn.Close()
+		return true
+	})
+}
+
+// waitUntilFileExists waits until a file exists at path.
+func (p *Process) waitUntilFileExists(abort <-chan struct{}, path string) waitResult {
+	return p.pollUntil(abort, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	})
+}
+
+// pollUntil checks the condition every dependencyPollInterval. Once the process
+// is done, the condition is checked one last time: a process may well create
+// the awaited file on its way out.
+func (p *Process) pollUntil(abort <-chan struct{}, condition func() bool) waitResult {
+	ticker := time.NewTicker(dependencyPollInterval)
+	defer ticker.Stop()
+	for !condition() {
+		select {
+		case <-ticker.C:
+		case <-p.procDoneChan:
+			if condition() {
+				return waitOk
+			}
+			return waitFailed
+		case <-abort:
+			return waitAborted
+		}
+	}
+	return waitOk
+}
+
 // waitUntilTerminated blocks until the goroutine that owns this process (the
 // one started by ProjectRunner.runProcess) has exited, or until the timeout
 // elapses. It reports whether the goroutine is gone.
@@ -484,6 +603,15 @@ func (p *Process) wontRun() {
 	p.onProcessEnd(types.ProcessStateSkipped)
 }
 
+// dependencyFailed ends a process that won't run because one of its
+// dependencies timed out with on_timeout: fail. Unlike wontRun, the process
+// ends in error, the same way as a process that failed to launch.
+func (p *Process) dependencyFailed(err error) {
+	p.logBuffer.Write(err.Error())
+	p.setExitCode(1)
+	p.onProcessEnd(types.ProcessStateError)
+}
+
 // perform graceful process shutdown if defined in configuration
 func (p *Process) shutDownNoRestart() error {
 	p.prepareForShutDown()
@@ -557,6 +685,7 @@ func (p *Process) stopProcess(withNoRestart bool) error {
 		}
 		p.cancelReadyLogFunc(fmt.Errorf("process %s was shut down", p.getName()))
 	}
+	p.runPreStopHook()
 	if isStringDefined(p.procConf.ShutDownParams.ShutDownCommand) {
 		return p.doConfiguredStop(p.procConf.ShutDownParams)
 	}
@@ -726,6 +855,7 @@ func (p *Process) onProcessEnd(state string) {
 	}
 	ev := p.snapshotEventLocked()
 	p.stateMtx.Unlock()
+	ev.Stopped = p.procRunCtx.Err() != nil
 	// Single publish carrying the terminal Status, final IsRunning=false
 	// (set by updateProcState), final ExitCode, and ProcessEndTime.
 	p.publishLocked(ev)
@@ -773,6 +903,9 @@ func (p *Process) getNameWithSmartReplica() string {
 }
 
 func (p *Process) getCommand() []string {
+	if p.procConf.Container != nil {
+		return append([]string{p.procConf.Container.Image}, p.procConf.Container.Command...)
+	}
 	return append(
 		[]string{(*p.procConf).Executable},
 		p.mergeExtraArgs()...,
@@ -790,7 +923,9 @@ func (p *Process) updateProcState() {
 		p.procState.Name = p.getName()
 		if time.Since(p.lastStatusPoll) > p.refRate {
 			p.procState.Mem, p.procState.CPU = p.getResourceUsage()
+			p.procState.OOMKills = p.limiter.OOMKills()
 			p.lastStatusPoll = time.Now()
+			p.recordResourceSample()
 		}
 	}
 	p.procState.IsRunning = isRunning
@@ -885,6 +1020,62 @@ func (p *Process) getProcResources(proc *puproc.Process) (int64, float64) {
 	return int64(memoryInfo.RSS), cpuPercent
 }
 
+// prepareResourceLimits sets the command of the incarnation about to start up
+// to run under the process's `resources` limits. A limit that cannot be
+// enforced is reported in the process log rather than failing the start. It is
+// called with stateMtx held.
+func (p *Process) prepareResourceLimits() {
+	if p.limiter == nil {
+		return
+	}
+	var err error
+	if local, ok := p.command.(interface{ ExecCmd() *exec.Cmd }); ok {
+		p.effectiveLimits, err = p.limiter.Prepare(local.ExecCmd())
+	} else {
+		p.effectiveLimits = &types.EffectiveLimits{Enforcer: types.LimitsEnforcerNone}
+		err = errors.New("resource limits are not supported for containers")
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("process", p.getName()).Msg("Failed to apply resource limits")
+		p.logBuffer.Write("Warning: failed to apply resource limits - " + err.Error())
+	}
+	p.oomKillsAtStart = p.limiter.OOMKills()
+}
+
+// recordResourceLimits publishes the limits the started incarnation runs
+// under.
+func (p *Process) recordResourceLimits() {
+	if p.limiter == nil {
+		return
+	}
+	p.stateMtx.Lock()
+	p.procState.Limits = p.effectiveLimits
+	p.procState.OOMKills = p.oomKillsAtStart
+	p.procState.OOMKilled = false
+	p.stateMtx.Unlock()
+}
+
+// checkOOMKill records whether the incarnation that just exited was killed by
+// the cgroup OOM killer.
+func (p *Process) checkOOMKill() {
+	if p.limiter == nil {
+		return
+	}
+	oomKills := p.limiter.OOMKills()
+	p.stateMtx.Lock()
+	p.procState.OOMKills = oomKills
+	p.procState.OOMKilled = oomKills > p.oomKillsAtStart
+	oomKilled := p.procState.OOMKilled
+	p.stateMtx.Unlock()
+	if oomKilled {
+		log.Warn().
+			Str("process", p.getName()).
+			Str("memory_max", p.procConf.Resources.MemoryMax).
+			Msg("Killed by the OOM killer")
+		p.logBuffer.Write("Error: process was killed by the OOM killer (memory_max " + p.procConf.Resources.MemoryMax + ")")
+	}
+}
+
 func (p *Process) handleInput(pipe io.WriteCloser) {
 	reader := bufio.NewReader(os.Stdin)
 	for {
@@ -952,6 +1143,7 @@ func (p *Process) checkElevatedProcOutput(line string) {
 }
 
 func (p *Process) handleInfo(message string) {
+	message = p.secrets.Redact(message)
 	p.logger.Info(message, p.getName(), p.procConf.ReplicaNum)
 	if p.printLogs {
 		fmt.Printf("[%s\t] %s\n", p.procColor(p.getName()), message)
@@ -960,6 +1152,7 @@ func (p *Process) handleInfo(message string) {
 }
 
 func (p *Process) handleError(message string) {
+	message = p.secrets.Redact(message)
 	p.logger.Error(message, p.getName(), p.procConf.ReplicaNum)
 	if p.printLogs {
 		fmt.Printf("[%s\t] %s\n", p.procColor(p.getName()), p.redColor(message))
@@ -1015,6 +1208,66 @@ func (p *Process) publishLocked(ev types.ProcessStateEvent) {
 	}
 }
 
+// recordResourceSample hands the resource usage of the process to the
+// resource recorder, if any. Daemons and processes whose usage could not be
+// read have no sample. Called with stateMtx held.
+func (p *Process) recordResourceSample() {
+	if p.recordResources == nil || p.procState.Mem < 0 {
+		return
+	}
+	p.recordResources(p.getName(), types.ResourceSample{
+		Time:     p.lastStatusPoll,
+		Mem:      p.procState.Mem,
+		CPU:      max(p.procState.CPU, 0),
+		Restarts: p.procState.Restarts,
+	})
+}
+
+// record stamps ev with the process name and hands it to the event recorder,
+// if any.
+func (p *Process) record(ev types.JournalEvent) {
+	if p.recordEvent == nil {
+		return
+	}
+	ev.Process = p.getName()
+	p.recordEvent(ev)
+}
+
+func (p *Process) recordExit() {
+	exitCode := p.getExitCode()
+	ev := types.JournalEvent{
+		Type:     types.JournalEventExit,
+		ExitCode: &exitCode,
+	}
+	p.stateMtx.Lock()
+	if p.procState.OOMKilled {
+		ev.Reason = "killed by the OOM killer"
+	}
+	p.stateMtx.Unlock()
+	p.record(ev)
+}
+
+// recordProbeFailure journals a failed check, together with what the checker
+// reported about it.
+func (p *Process) recordProbeFailure(probe string, isFatal bool, err string, details any) {
+	ev := types.JournalEvent{
+		Type:    types.JournalEventProbeFailure,
+		Reason:  err,
+		Details: map[string]string{"probe": probe},
+	}
+	if rcMap, ok := details.(map[string]string); ok {
+		for key, value := range rcMap {
+			if value != "" {
+				ev.Details[key] = value
+			}
+		}
+	}
+	if isFatal {
+		ev.Details["fatal"] = "true"
+	}
+	p.record(ev)
+}
+
 func (p *Process) getState() *types.ProcessState {
 	p.updateProcState()
 	p.stateMtx.Lock()
@@ -1064,7 +1317,7 @@ func (p *Process) onStateChange(state string) {
 	switch state {
 	case types.ProcessStateSkipped:
 		p.setExitCodeLocked(1)
-	case types.ProcessStateRestarting:
+	case types.ProcessStateRestarting, types.ProcessStateCrashLoopBackOff:
 		fallthrough
 	case types.ProcessStateLaunching:
 		fallthrough
@@ -1088,6 +1341,7 @@ func (p *Process) setUpProbes() {
 			*p.procConf.LivenessProbe,
 			p.getProcessEnvironment(),
 			p.shellConfig,
+			p.logBuffer,
 			p.onLivenessCheckEnd,
 		)
 		if err != nil {
@@ -1102,6 +1356,7 @@ func (p *Process) setUpProbes() {
 			*p.procConf.ReadinessProbe,
 			p.getProcessEnvironment(),
 			p.shellConfig,
+			p.logBuffer,
 			p.onReadinessCheckEnd,
 		)
 		if err != nil {
@@ -1131,7 +1386,11 @@ func (p *Process) stopProbes() {
 	}
 }
 
-func (p *Process) onLivenessCheckEnd(_, isFatal bool, err string, details any) {
+func (p *Process) onLivenessCheckEnd(isOk, isFatal bool, err string, details any) {
+	p.countProbeResult(false, isOk)
+	if !isOk {
+		p.recordProbeFailure("liveness", isFatal, err, details)
+	}
 	if isFatal {
 		p.logBuffer.Write("Error: liveness check fail - " + err)
 		p.notifyDaemonStopped()
@@ -1142,13 +1401,35 @@ func (p *Process) onLivenessCheckEnd(_, isFatal bool, err string, details any) {
 	}
 }
 
+// countProbeResult increments one of a probe's counters. They are exported as
+// metrics only, so no state event is published for them.
+func (p *Process) countProbeResult(isReadiness, isOk bool) {
+	p.stateMtx.Lock()
+	defer p.stateMtx.Unlock()
+	stats := &p.procState.ProbeStats
+	switch {
+	case isReadiness && isOk:
+		stats.ReadinessSuccesses++
+	case isReadiness:
+		stats.ReadinessFailures++
+	case isOk:
+		stats.LivenessSuccesses++
+	default:
+		stats.LivenessFailures++
+	}
+}
+
 func (p *Process) printDetails(details map[string]string, err, source string) {
-	exitCode, _ := strconv.Atoi(details["exit_code"])
 	output := details["output"]
-	log.Warn().
-		Str("error", err).
-		Int("exit_code", exitCode).
-		Msgf("%s %s probe failed", p.getName(), source)
+	event := log.Warn().Str("error", err)
+	if code, ok := details["exit_code"]; ok {
+		exitCode, _ := strconv.Atoi(code)
+		event = event.Int("exit_code", exitCode)
+	}
+	if target := details["target"]; target != "" {
+		event = event.Str("target", target)
+	}
+	event.Msgf("%s %s probe failed", p.getName(), source)
 	if cmd := details["command"]; cmd != "" {
 		log.Debug().Msgf("%s %s probe command: %s", p.getName(), source, cmd)
 	}
@@ -1158,6 +1439,10 @@ func (p *Process) printDetails(details map[string]string, err, source string) {
 }
 
 func (p *Process) onReadinessCheckEnd(isOk, isFatal bool, err string, details any) {
+	p.countProbeResult(true, isOk)
+	if !isOk {
+		p.recordProbeFailure("readiness", isFatal, err, details)
+	}
 	if isFatal {
 		p.setProcHealth(types.ProcessHealthNotReady)
 		p.logBuffer.Write("Error: readiness check fail - " + err)
@@ -1218,16 +1503,24 @@ func (p *Process) collectPortPids() map[int]struct{} {
 }
 
 func (p *Process) getOpenPorts(ports *types.ProcessPorts) error {
+	if p.procConf.Container != nil {
+		return p.getContainerPorts(ports)
+	}
 	pids := p.collectPortPids()
-	if err := p.collectSockets("TCP", netstat.TCPSocks, netstat.TCP6Socks, func(s *netstat.SockTabEntry) bool {
-		return s.State == netstat.Listen
-	}, pids, &ports.TcpPorts); err != nil {
+	if err := p.collectSockets("TCP", netstat.TCPSocks, netstat.TCP6Socks, isListeningTCP, pids, &ports.TcpPorts); err != nil {
 		return err
 	}
 
-	return p.collectSockets("UDP", netstat.UDPSocks, netstat.UDP6Socks, func(s *netstat.SockTabEntry) bool {
-		return s.RemoteAddr != nil && s.RemoteAddr.Port == 0
-	}, pids, &ports.UdpPorts)
+	return p.collectSockets("UDP", netstat.UDPSocks, netstat.UDP6Socks, isListeningUDP, pids, &ports.UdpPorts)
+}
+
+func isListeningTCP(s *netstat.SockTabEntry) bool {
+	return s.State == netstat.Listen
+}
+
+// isListeningUDP accepts the UDP sockets that are not connected to a peer.
+func isListeningUDP(s *netstat.SockTabEntry) bool {
+	return s.RemoteAddr != nil && s.RemoteAddr.Port == 0
 }
 
 func (p *Process) collectSockets(label string, v4, v6 func(netstat.AcceptFn) ([]netstat.SockTabEntry, error), filter netstat.AcceptFn, pids map[int]struct{}, target *[]uint16) error {
@@ -1298,6 +1591,9 @@ func (p *Process) setProcHealth(health string) {
 	if changed {
 		p.publishLocked(ev)
 	}
+	if changed && health == types.ProcessHealthReady {
+		p.runPostStartHook()
+	}
 }
 
 // set elevated process password
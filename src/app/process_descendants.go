@@ -0,0 +1,171 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"syscall"
+
+	"github.com/f1bonacc1/netstat/netstat"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+	puproc "github.com/shirou/gopsutil/v4/process"
+)
+
+var errNoProcessTree = errors.New("the process tree is not available")
+
+// errContainerProcessTree rejects the process tree of a container: the PID
+// reported by the engine is not a host PID when the engine runs in a VM or in
+// a user namespace, so it can't be looked up or signaled.
+var errContainerProcessTree = errors.New("the process tree is not available for container processes")
+
+func (p *Process) getPid() int {
+	p.stateMtx.Lock()
+	defer p.stateMtx.Unlock()
+	return p.procState.Pid
+}
+
+// getProcessTree returns the tree of the OS processes spawned by the process,
+// with the resource usage and the listening ports of each of them.
+func (p *Process) getProcessTree() (*types.ProcessTree, error) {
+	if p.procConf.Container != nil {
+		return nil, errContainerProcessTree
+	}
+	pid := int32(p.getPid())
+	if !p.isSignalable() || pid == 0 {
+		return nil, fmt.Errorf("process %s is not running", p.getName())
+	}
+	if p.processTree == nil {
+		return nil, errNoProcessTree
+	}
+	if err := p.processTree.Update(); err != nil {
+		return nil, fmt.Errorf("failed to read the process tree of %s: %w", p.getName(), err)
+	}
+	describe := func(pid int32) types.ProcessTreeNode {
+		proc := p.processTree.GetProcess(pid)
+		if proc == nil {
+			// started after the tree was cached
+			var err error
+			if proc, err = puproc.NewProcess(pid); err != nil {
+				return types.ProcessTreeNode{Pid: int(pid), Mem: -1, CPU: -1}
+			}
+		}
+		return p.describeOSProcess(proc)
+	}
+	root := buildProcessTreeNode(pid, p.processTree.GetChildren, describe, map[int32]bool{})
+	p.setTreePorts(&root)
+	return &types.ProcessTree{
+		Name: p.getName(),
+		Root: root,
+	}, nil
+}
+
+func (p *Process) describeOSProcess(proc *puproc.Process) types.ProcessTreeNode {
+	node := types.ProcessTreeNode{Pid: int(proc.Pid)}
+	node.Mem, node.CPU = p.getProcResources(proc)
+	if cmdline, err := proc.Cmdline(); err == nil && cmdline != "" {
+		node.Command = cmdline
+	} else if name, err := proc.Name(); err == nil {
+		node.Command = name
+	}
+	return node
+}
+
+// buildProcessTreeNode builds the node of the PID and the nodes of its
+// descendants, ordered by PID. A PID met twice, reused while the tree was
+// read, is only built once.
+func buildProcessTreeNode(pid int32, children func(pid int32) []int32, describe func(pid int32) types.ProcessTreeNode, seen map[int32]bool) types.ProcessTreeNode {
+	seen[pid] = true
+	node := describe(pid)
+	kids := children(pid)
+	slices.Sort(kids)
+	for _, child := range kids {
+		if seen[child] {
+			continue
+		}
+		node.Children = append(node.Children, buildProcessTreeNode(child, children, describe, seen))
+	}
+	return node
+}
+
+// setTreePorts sets the ports each process of the tree listens on. A failure
+// to read the sockets leaves the ports empty rather than failing the tree.
+func (p *Process) setTreePorts(root *types.ProcessTreeNode) {
+	pids := map[int]struct{}{}
+	root.Walk(func(node *types.ProcessTreeNode, _ int) {
+		pids[node.Pid] = struct{}{}
+	})
+	tcp, err := listeningPortsByPid(netstat.TCPSocks, netstat.TCP6Socks, isListeningTCP, pids)
+	if err != nil {
+		log.Err(err).Msgf("failed to get open TCP ports for %s", p.getName())
+	}
+	udp, err := listeningPortsByPid(netstat.UDPSocks, netstat.UDP6Socks, isListeningUDP, pids)
+	if err != nil {
+		log.Err(err).Msgf("failed to get open UDP ports for %s", p.getName())
+	}
+	root.Walk(func(node *types.ProcessTreeNode, _ int) {
+		node.TcpPorts = tcp[node.Pid]
+		node.UdpPorts = udp[node.Pid]
+	})
+}
+
+// listeningPortsByPid returns the sorted ports of the accepted sockets of
+// each PID. A port listened on both IPv4 and IPv6 is returned once.
+func listeningPortsByPid(v4, v6 func(netstat.AcceptFn) ([]netstat.SockTabEntry, error), filter netstat.AcceptFn, pids map[int]struct{}) (map[int][]uint16, error) {
+	socks, err := v4(filter)
+	if err != nil {
+		return nil, err
+	}
+	socks6, err := v6(filter)
+	if err != nil {
+		return nil, err
+	}
+	ports := map[int][]uint16{}
+	for _, e := range append(socks, socks6...) {
+		if e.Process == nil {
+			continue
+		}
+		if _, ok := pids[e.Process.Pid]; !ok {
+			continue
+		}
+		ports[e.Process.Pid] = append(ports[e.Process.Pid], e.LocalAddr.Port)
+	}
+	for pid := range ports {
+		slices.Sort(ports[pid])
+		ports[pid] = slices.Compact(ports[pid])
+	}
+	return ports, nil
+}
+
+// signalDescendant sends the signal to a single OS process of the process
+// tree, unlike sendSignal which signals the process group.
+func (p *Process) signalDescendant(pid, sig int) error {
+	if p.procConf.Container != nil {
+		return errContainerProcessTree
+	}
+	root := p.getPid()
+	if !p.isSignalable() || root == 0 {
+		return fmt.Errorf("process %s is not running", p.getName())
+	}
+	if pid != root {
+		if p.processTree == nil {
+			return errNoProcessTree
+		}
+		if err := p.processTree.Update(); err != nil {
+			return fmt.Errorf("failed to read the process tree of %s: %w", p.getName(), err)
+		}
+		isDescendant := slices.ContainsFunc(p.processTree.GetDescendants(int32(root)), func(proc *puproc.Process) bool {
+			return int(proc.Pid) == pid
+		})
+		if !isDescendant {
+			return fmt.Errorf("pid %d is not a descendant of process %s", pid, p.getName())
+		}
+	}
+	osProc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	log.Info().Msgf("Sending signal %d to pid %d of %s", sig, pid, p.getName())
+	return osProc.Signal(syscall.Signal(sig))
+}
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestBuildProcessTreeNode(t *testing.T) {
+	tests := []struct {
+		name     string
+		tree     map[int32][]int32
+		wantPids []int
+		wantDeep []int
+	}{
+		{
+			name:     "no children",
+			tree:     map[int32][]int32{},
+			wantPids: []int{1},
+			wantDeep: []int{0},
+		},
+		{
+			name: "children ordered by pid",
+			tree: map[int32][]int32{
+				1:  {30, 20},
+				20: {21},
+			},
+			wantPids: []int{1, 20, 21, 30},
+			wantDeep: []int{0, 1, 2, 1},
+		},
+		{
+			name: "reused pid is built once",
+			tree: map[int32][]int32{
+				1: {2},
+				2: {1},
+			},
+			wantPids: []int{1, 2},
+			wantDeep: []int{0, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			children := func(pid int32) []int32 {
+				return slices.Clone(tt.tree[pid])
+			}
+			describe := func(pid int32) types.ProcessTreeNode {
+				return types.ProcessTreeNode{Pid: int(pid)}
+			}
+			root := buildProcessTreeNode(1, children, describe, map[int32]bool{})
+			var pids, depths []int
+			root.Walk(func(node *types.ProcessTreeNode, depth int) {
+				pids = append(pids, node.Pid)
+				depths = append(depths, depth)
+			})
+			if !slices.Equal(pids, tt.wantPids) || !slices.Equal(depths, tt.wantDeep) {
+				t.Errorf("tree = %v at depths %v, want %v at depths %v", pids, depths, tt.wantPids, tt.wantDeep)
+			}
+		})
+	}
+}
+
+func TestProjectRunner_ProcessTree(t *testing.T) {
+	runner := runHooksProject(t, types.ProcessConfig{Command: "sleep 30 & sleep 31 & wait"})
+	waitForProcessState(t, runner, "proc", types.ProcessStateRunning, 5*time.Second)
+
+	children := func() []types.ProcessTreeNode {
+		t.Helper()
+		tree, err := runner.GetProcessTree("proc")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tree.Root.Children
+	}
+	waitForChildren := func(n int) []types.ProcessTreeNode {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if kids := children(); len(kids) == n {
+				return kids
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		t.Fatalf("process tree children = %v, want %d", children(), n)
+		return nil
+	}
+
+	kids := waitForChildren(2)
+	if !strings.HasPrefix(kids[0].Command, "sleep") {
+		t.Errorf("child command = %q, want sleep", kids[0].Command)
+	}
+	if err := runner.SignalProcessTree("proc", 1, 15); err == nil {
+		t.Error("signaled a pid outside of the process tree")
+	}
+	if err := runner.SignalProcessTree("proc", kids[0].Pid, 15); err != nil {
+		t.Fatal(err)
+	}
+	if kids = waitForChildren(1); kids[0].Command != "sleep 31" {
+		t.Errorf("remaining child = %q, want sleep 31", kids[0].Command)
+	}
+	if _, err := runner.GetProcessTree("nope"); err == nil {
+		t.Error("got the tree of an unknown process")
+	}
+}
+
+func TestProcess_ContainerProcessTree(t *testing.T) {
+	proc := &Process{
+		procConf:  &types.ProcessConfig{Name: "db", ReplicaName: "db", Container: &types.ContainerConfig{Image: "postgres"}},
+		procState: &types.ProcessState{Status: types.ProcessStateRunning, IsRunning: true, Pid: 4321},
+	}
+	if _, err := proc.getProcessTree(); !errors.Is(err, errContainerProcessTree) {
+		t.Errorf("getProcessTree() error = %v, want %v", err, errContainerProcessTree)
+	}
+	if err := proc.signalDescendant(4321, 15); !errors.Is(err, errContainerProcessTree) {
+		t.Errorf("signalDescendant() error = %v, want %v", err, errContainerProcessTree)
+	}
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"sync"
+
+	"github.com/f1bonacc1/process-compose/src/limits"
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+// processLimiters keeps the resource Limiter of every limited process. A
+// Limiter outlives the restarts, scaling and updates of its process, so that
+// its cgroup, and the OOM kills counted in it, carry over from one incarnation
+// to the next, until the process is removed from the project or the project
+// shuts down.
+//
+// All methods are safe to call on a nil *processLimiters.
+type processLimiters struct {
+	mtx      sync.Mutex
+	limiters map[string]*limits.Limiter
+}
+
+func newProcessLimiters() *processLimiters {
+	return &processLimiters{
+		limiters: map[string]*limits.Limiter{},
+	}
+}
+
+// get returns the Limiter for the next incarnation of the process, or nil when
+// it has no limits.
+func (l *processLimiters) get(config *types.ProcessConfig) *limits.Limiter {
+	if l == nil {
+		return nil
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	limiter, ok := l.limiters[config.ReplicaName]
+	if !config.Resources.IsEnabled() {
+		if ok {
+			limiter.Release()
+			delete(l.limiters, config.ReplicaName)
+		}
+		return nil
+	}
+	if ok {
+		limiter.Update(config.Resources)
+		return limiter
+	}
+	limiter = limits.New(config.ReplicaName, config.Resources)
+	l.limiters[config.ReplicaName] = limiter
+	return limiter
+}
+
+func (l *processLimiters) rename(name, newName string) {
+	if l == nil {
+		return
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if limiter, ok := l.limiters[name]; ok {
+		delete(l.limiters, name)
+		l.limiters[newName] = limiter
+	}
+}
+
+// remove releases the Limiter of a process that has exited for good.
+func (l *processLimiters) remove(name string) {
+	if l == nil {
+		return
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if limiter, ok := l.limiters[name]; ok {
+		limiter.Release()
+		delete(l.limiters, name)
+	}
+}
+
+// releaseAll releases the Limiters of all the processes, once they exited.
+func (l *processLimiters) releaseAll() {
+	if l == nil {
+		return
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	for name, limiter := range l.limiters {
+		limiter.Release()
+		delete(l.limiters, name)
+	}
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestProcessLimiters(t *testing.T) {
+	l := newProcessLimiters()
+	limited := &types.ProcessConfig{ReplicaName: "api", Resources: &types.ResourceLimits{NoFile: 64}}
+
+	first := l.get(limited)
+	if first == nil {
+		t.Fatal("get() = nil for a limited process")
+	}
+	if again := l.get(limited); again != first {
+		t.Error("get() returned a new Limiter for the next incarnation")
+	}
+	if got := l.get(&types.ProcessConfig{ReplicaName: "web"}); got != nil {
+		t.Errorf("get() = %v for a process without limits", got)
+	}
+
+	l.rename("api", "api-0")
+	if got := l.get(&types.ProcessConfig{ReplicaName: "api-0", Resources: limited.Resources}); got != first {
+		t.Error("get() returned a new Limiter for a renamed process")
+	}
+
+	l.remove("api-0")
+	if got := l.get(&types.ProcessConfig{ReplicaName: "api-0", Resources: limited.Resources}); got == first {
+		t.Error("get() reused the Limiter of a removed process")
+	}
+
+	// Dropping the limits from the configuration releases the Limiter
+	l.get(&types.ProcessConfig{ReplicaName: "api-0"})
+	if len(l.limiters) != 0 {
+		t.Errorf("limiters = %v, want none", l.limiters)
+	}
+
+	var nilLimiters *processLimiters
+	if got := nilLimiters.get(limited); got != nil {
+		t.Errorf("nil get() = %v, want nil", got)
+	}
+	nilLimiters.releaseAll()
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"slices"
 	"sync"
 	"time"
 
@@ -90,3 +91,17 @@ func (pt *ProcessTree) GetDescendants(pid int32) []*puproc.Process {
 
 	return descendants
 }
+
+// GetChildren returns the PIDs of the direct children of the process.
+func (pt *ProcessTree) GetChildren(pid int32) []int32 {
+	pt.RLock()
+	defer pt.RUnlock()
+	return slices.Clone(pt.tree[pid])
+}
+
+// GetProcess returns the process of the PID, nil when it isn't in the tree.
+func (pt *ProcessTree) GetProcess(pid int32) *puproc.Process {
+	pt.RLock()
+	defer pt.RUnlock()
+	return pt.procs[pid]
+}
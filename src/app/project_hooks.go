@@ -0,0 +1,233 @@
+package app
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+)
+
+// projectHooksQueueSize bounds the project hook calls waiting to run. Calls
+// beyond it are dropped: the state broadcaster must never block on them.
+const projectHooksQueueSize = 64
+
+type projectHookCall struct {
+	hook  string
+	event *types.ProcessStateEvent
+}
+
+// projectHooks runs the project hooks. It is a types.StateObserver: the
+// process state events it is notified of are turned into hook calls, which
+// run one at a time, in order, away from the broadcaster's lock.
+//
+// shutdown and close are safe to call on a nil *projectHooks, the runner's
+// when the project has no hooks.
+type projectHooks struct {
+	runner *ProjectRunner
+	hooks  types.ProjectHooks
+	queue  chan projectHookCall
+	done   chan struct{}
+	// states holds the last state of every process, from the initial
+	// snapshot on, and ready whether they were all ready.
+	mtx          sync.Mutex
+	states       map[string]types.ProcessState
+	ready        bool
+	shuttingDown atomic.Bool
+	shutdownOnce sync.Once
+	closeOnce    sync.Once
+}
+
+func newProjectHooks(runner *ProjectRunner) *projectHooks {
+	h := &projectHooks{
+		runner: runner,
+		hooks:  runner.project.Hooks,
+		queue:  make(chan projectHookCall, projectHooksQueueSize),
+		done:   make(chan struct{}),
+		states: map[string]types.ProcessState{},
+	}
+	go h.run()
+	return h
+}
+
+// Notify implements types.StateObserver. A failure is reported once, on the
+// transition of the process to its failed status, and on_ready once the last
+// process that wasn't ready becomes ready.
+func (h *projectHooks) Notify(ev types.ProcessStateEvent) {
+	h.mtx.Lock()
+	prev := h.states[ev.State.Name]
+	h.states[ev.State.Name] = ev.State
+	wasReady := h.ready
+	h.ready = h.isReadyLocked()
+	becameReady := h.ready && !wasReady
+	h.mtx.Unlock()
+	if ev.Snapshot || h.shuttingDown.Load() {
+		return
+	}
+	if prev.Status != ev.State.Status && isPermanentFailure(&ev) && len(h.hooks.OnFailure) > 0 {
+		h.enqueue(types.ProjectHookOnFailure, &ev)
+	}
+	if becameReady && len(h.hooks.OnReady) > 0 {
+		h.enqueue(types.ProjectHookOnReady, &ev)
+	}
+}
+
+func (h *projectHooks) isReadyLocked() bool {
+	for _, state := range h.states {
+		if !state.IsReady() {
+			return false
+		}
+	}
+	return len(h.states) > 0
+}
+
+// UniqueID implements types.StateObserver.
+func (h *projectHooks) UniqueID() string {
+	return "project-hooks"
+}
+
+// isPermanentFailure reports whether the event ends a process that failed on
+// its own. Processes that end after a stop are not failures, whatever their
+// exit code.
+func isPermanentFailure(ev *types.ProcessStateEvent) bool {
+	if ev.Stopped {
+		return false
+	}
+	switch ev.State.Status {
+	case types.ProcessStateError:
+		return true
+	case types.ProcessStateCompleted:
+		return !ev.State.IsExitCodeSuccess()
+	}
+	return false
+}
+
+func (h *projectHooks) enqueue(hook string, ev *types.ProcessStateEvent) {
+	select {
+	case h.queue <- projectHookCall{hook: hook, event: ev}:
+	default:
+		log.Warn().Msgf("Too many pending project hooks, dropping %s for %s", hook, ev.State.Name)
+	}
+}
+
+// shutdown runs the on_shutdown hook, once. Process events that follow are
+// ignored: processes that are being shut down neither fail nor become ready.
+func (h *projectHooks) shutdown() {
+	if h == nil {
+		return
+	}
+	h.shutdownOnce.Do(func() {
+		h.shuttingDown.Store(true)
+		if len(h.hooks.OnShutdown) > 0 {
+			h.queue <- projectHookCall{hook: types.ProjectHookOnShutdown}
+		}
+	})
+}
+
+// close runs the on_shutdown hook if it didn't run yet, and waits for the
+// pending hooks to complete. The hooks must no longer be notified.
+func (h *projectHooks) close() {
+	if h == nil {
+		return
+	}
+	h.shutdown()
+	h.closeOnce.Do(func() {
+		close(h.queue)
+	})
+	<-h.done
+}
+
+func (h *projectHooks) run() {
+	defer close(h.done)
+	for call := range h.queue {
+		h.fire(call)
+	}
+}
+
+func (h *projectHooks) fire(call projectHookCall) {
+	payload, err := json.Marshal(types.ProjectHookEvent{
+		Hook:    call.hook,
+		Project: h.runner.project.Name,
+		Time:    time.Now(),
+		Event:   call.event,
+	})
+	if err != nil {
+		log.Err(err).Msgf("Failed to encode the %s project hook event", call.hook)
+		return
+	}
+	log.Info().Msgf("Running %s project hooks", call.hook)
+	for i, action := range h.hooks.Get(call.hook) {
+		if err = h.runAction(call.hook, &action, payload); err != nil {
+			log.Err(err).Msgf("Project hook %s[%d] failed", call.hook, i)
+		}
+	}
+}
+
+func (h *projectHooks) runAction(hook string, action *types.ProjectHook, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(action.Timeout())*time.Second)
+	defer cancel()
+	if action.Webhook != nil {
+		return callWebhook(ctx, action.Webhook, hook, payload)
+	}
+	return h.runCommand(ctx, action.Command, hook, payload)
+}
+
+// runCommand runs the command of a project hook with the project environment,
+// the event on its stdin.
+func (h *projectHooks) runCommand(ctx context.Context, cmdLine, hook string, payload []byte) error {
+	cmd := command.BuildCommandShellArgContext(ctx, *h.runner.project.ShellConfig, cmdLine)
+	env := append(os.Environ(), h.runner.project.Environment...)
+	env = append(env, h.runner.secrets.Load().Env()...)
+	cmd.SetEnv(append(env, types.EnvVarHook+"="+hook))
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	go func() {
+		_, _ = stdin.Write(payload)
+		_ = stdin.Close()
+	}()
+	out, err := cmd.CombinedOutput()
+	output := h.runner.secrets.Load().Redact(strings.TrimSpace(string(out)))
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, output)
+	}
+	if output != "" {
+		log.Debug().Msgf("Project hook %s output: %s", hook, output)
+	}
+	return nil
+}
+
+func callWebhook(ctx context.Context, webhook *types.WebhookConfig, hook string, payload []byte) error {
+	method := cmp.Or(webhook.Method, http.MethodPost)
+	req, err := http.NewRequestWithContext(ctx, method, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Process-Compose-Hook", hook)
+	for key, value := range webhook.Headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook %s returned %s", webhook.URL, resp.Status)
+	}
+	return nil
+}
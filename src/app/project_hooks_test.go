@@ -0,0 +1,160 @@
+package app
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestIsPermanentFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		ev   types.ProcessStateEvent
+		want bool
+	}{
+		{"error", types.ProcessStateEvent{State: types.ProcessState{Status: types.ProcessStateError}}, true},
+		{"failed", types.ProcessStateEvent{State: types.ProcessState{Status: types.ProcessStateCompleted, ExitCode: 3}}, true},
+		{"succeeded", types.ProcessStateEvent{State: types.ProcessState{Status: types.ProcessStateCompleted}}, false},
+		{"success exit code", types.ProcessStateEvent{State: types.ProcessState{Status: types.ProcessStateCompleted, ExitCode: 130, SuccessExitCodes: []int{130}}}, false},
+		{"stopped", types.ProcessStateEvent{Stopped: true, State: types.ProcessState{Status: types.ProcessStateCompleted, ExitCode: 143}}, false},
+		{"restarting", types.ProcessStateEvent{State: types.ProcessState{Status: types.ProcessStateRestarting, ExitCode: 1}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPermanentFailure(&tt.ev); got != tt.want {
+				t.Errorf("isPermanentFailure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func newHooksRunner(t *testing.T, hooks types.ProjectHooks, commands map[string]string) *ProjectRunner {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	shell := command.DefaultShellConfig()
+	processes := types.Processes{}
+	for name, cmd := range commands {
+		processes[name] = types.ProcessConfig{
+			Name:        name,
+			ReplicaName: name,
+			Executable:  shell.ShellCommand,
+			Args:        []string{shell.ShellArgument, cmd},
+		}
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{
+		project: &types.Project{
+			Name:        "hooked",
+			Processes:   processes,
+			ShellConfig: shell,
+			Hooks:       hooks,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return runner
+}
+
+func TestProjectHooks_ReadyAndShutdownWebhooks(t *testing.T) {
+	var mtx sync.Mutex
+	var received []types.ProjectHookEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev types.ProjectHookEvent
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &ev); err != nil {
+			t.Errorf("invalid payload %q: %v", body, err)
+		}
+		if hook := r.Header.Get("X-Process-Compose-Hook"); hook != ev.Hook {
+			t.Errorf("hook header = %q, want %q", hook, ev.Hook)
+		}
+		mtx.Lock()
+		received = append(received, ev)
+		mtx.Unlock()
+	}))
+	defer server.Close()
+
+	webhook := []types.ProjectHook{{Webhook: &types.WebhookConfig{URL: server.URL}}}
+	runner := newHooksRunner(t, types.ProjectHooks{OnReady: webhook, OnShutdown: webhook}, map[string]string{
+		"web": "sleep 10",
+		"job": "exit 0",
+	})
+	done := make(chan struct{})
+	go func() {
+		_ = runner.Run()
+		close(done)
+	}()
+	waitForProcessState(t, runner, "job", types.ProcessStateCompleted, 5*time.Second)
+	waitForProcessState(t, runner, "web", types.ProcessStateRunning, 5*time.Second)
+	if err := runner.ShutDownProject(); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	if len(received) != 2 {
+		t.Fatalf("received %d webhooks, want on_ready and on_shutdown: %+v", len(received), received)
+	}
+	if received[0].Hook != types.ProjectHookOnReady || received[0].Project != "hooked" || received[0].Event == nil {
+		t.Errorf("first webhook = %+v, want on_ready with its event", received[0])
+	}
+	if received[1].Hook != types.ProjectHookOnShutdown || received[1].Event != nil {
+		t.Errorf("second webhook = %+v, want on_shutdown without event", received[1])
+	}
+}
+
+func TestProjectHooks_OnFailureCommand(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "failures")
+	runner := newHooksRunner(t, types.ProjectHooks{
+		OnFailure: []types.ProjectHook{{Command: `echo "$PC_HOOK $(cat)" >> ` + out}},
+	}, map[string]string{
+		"broken":  "exit 3",
+		"stopped": "sleep 10",
+	})
+	done := make(chan struct{})
+	go func() {
+		_ = runner.Run()
+		close(done)
+	}()
+	waitForProcessState(t, runner, "broken", types.ProcessStateCompleted, 5*time.Second)
+	waitForProcessState(t, runner, "stopped", types.ProcessStateRunning, 5*time.Second)
+	if err := runner.StopProcess("stopped"); err != nil {
+		t.Fatal(err)
+	}
+	waitForProcessState(t, runner, "stopped", types.ProcessStateCompleted, 5*time.Second)
+	_ = runner.ShutDownProject()
+	<-done
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("on_failure ran %d times, want once: %q", len(lines), lines)
+	}
+	hook, payload, _ := strings.Cut(lines[0], " ")
+	if hook != types.ProjectHookOnFailure {
+		t.Errorf("PC_HOOK = %q, want %s", hook, types.ProjectHookOnFailure)
+	}
+	var ev types.ProjectHookEvent
+	if err = json.Unmarshal([]byte(payload), &ev); err != nil {
+		t.Fatalf("invalid payload %q: %v", payload, err)
+	}
+	if ev.Event == nil || ev.Event.State.Name != "broken" || ev.Event.State.ExitCode != 3 {
+		t.Errorf("payload = %+v, want the failure of broken", ev)
+	}
+}
@@ -1,7 +1,9 @@
 package app
 
 import (
+	"context"
 	"os"
+	"time"
 
 	"github.com/f1bonacc1/process-compose/src/pclog"
 	"github.com/f1bonacc1/process-compose/src/types"
@@ -35,6 +37,8 @@ type IProject interface {
 	RestartProcess(name string) error
 	ScaleProcess(name string, scale int) error
 	GetProcessPorts(name string) (*types.ProcessPorts, error)
+	GetProcessTree(name string) (*types.ProcessTree, error)
+	SignalProcessTree(name string, pid int, sig int) error
 	SetProcessPassword(name string, password string) error
 	UpdateProject(project *types.Project) (map[string]string, error)
 	UpdateProcess(updated *types.ProcessConfig) error
@@ -43,7 +47,12 @@ type IProject interface {
 	GetProcessPty(name string) *os.File
 	SendProcessKeys(name string, keys string) error
 	GetFullProcessEnvironment(proc *types.ProcessConfig) []string
+	GetProcessLaunchEnvironment(proc *types.ProcessConfig) []string
+	ExecProcess(ctx context.Context, name string, req *types.ExecRequest, streams *types.ExecStreams) (int, error)
 	GetDependencyGraph() (*types.DependencyGraph, error)
+	GetEvents(query types.JournalQuery) ([]types.JournalEvent, error)
+	GetResourceHistory(name string, since time.Time) ([]types.ResourceHistory, error)
+	SaveProjectState() (string, error)
 
 	RegisterStateObserver(observer types.StateObserver)
 	UnregisterStateObserver(observer types.StateObserver)
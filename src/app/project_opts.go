@@ -2,6 +2,7 @@ package app
 
 import (
 	"github.com/f1bonacc1/process-compose/src/admitter"
+	"github.com/f1bonacc1/process-compose/src/journal"
 	"github.com/f1bonacc1/process-compose/src/types"
 	"time"
 )
@@ -20,6 +21,9 @@ type ProjectOpts struct {
 	withRecursiveMetrics bool
 	noWatch              bool
 	admitters            []admitter.Admitter
+	journal              *journal.Journal
+	stateFile            string
+	restoreState         bool
 }
 
 func (p *ProjectOpts) WithProject(project *types.Project) *ProjectOpts {
@@ -90,3 +94,23 @@ func (p *ProjectOpts) WithAdmitters(admitters ...admitter.Admitter) *ProjectOpts
 	p.admitters = admitters
 	return p
 }
+
+// WithJournal records process lifecycle events to j. A nil journal disables
+// recording.
+func (p *ProjectOpts) WithJournal(j *journal.Journal) *ProjectOpts {
+	p.journal = j
+	return p
+}
+
+// WithStateFile saves the runtime state of the project to path when it ends.
+// An empty path disables the state file.
+func (p *ProjectOpts) WithStateFile(path string) *ProjectOpts {
+	p.stateFile = path
+	return p
+}
+
+// WithRestoreState replays the state saved in the state file on start.
+func (p *ProjectOpts) WithRestoreState(restore bool) *ProjectOpts {
+	p.restoreState = restore
+	return p
+}
@@ -5,11 +5,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"net"
 	"os"
 	"os/user"
 	"path"
+	"path/filepath"
 	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -19,9 +23,11 @@ import (
 	"github.com/f1bonacc1/process-compose/src/command"
 	"github.com/f1bonacc1/process-compose/src/config"
 	"github.com/f1bonacc1/process-compose/src/health"
+	"github.com/f1bonacc1/process-compose/src/journal"
 	"github.com/f1bonacc1/process-compose/src/loader"
 	"github.com/f1bonacc1/process-compose/src/pclog"
 	"github.com/f1bonacc1/process-compose/src/scheduler"
+	"github.com/f1bonacc1/process-compose/src/secrets"
 	"github.com/f1bonacc1/process-compose/src/templater"
 	"github.com/f1bonacc1/process-compose/src/types"
 	"github.com/f1bonacc1/process-compose/src/watcher"
@@ -52,12 +58,15 @@ type ProjectRunner struct {
 	// pendingRestartReset marks processes whose next incarnation should start
 	// with a zeroed restart counter. Guarded by statesMutex.
 	pendingRestartReset map[string]bool
-	runProcMutex        sync.Mutex
-	runningProcesses    map[string]*Process
-	doneProcMutex       sync.Mutex
-	doneProcesses       map[string]*Process
-	restartMutex        sync.Mutex
-	restartCalls        map[string]*RestartCall
+	// stoppedProcesses marks the processes stopped by the user, which the
+	// saved project state restores stopped. Guarded by statesMutex.
+	stoppedProcesses map[string]bool
+	runProcMutex     sync.Mutex
+	runningProcesses map[string]*Process
+	doneProcMutex    sync.Mutex
+	doneProcesses    map[string]*Process
+	restartMutex     sync.Mutex
+	restartCalls     map[string]*RestartCall
 	// stopEpochs counts the stop requests made for each process. A restart
 	// samples it before tearing the running incarnation down and re-reads it
 	// before launching the replacement: a stop that lands inside that window
@@ -91,6 +100,15 @@ type ProjectRunner struct {
 	processWatcher       atomic.Pointer[watcher.Watcher]
 	stateBroadcaster     *ProcessStateBroadcaster
 	admitters            []admitter.Admitter
+	journal              *journal.Journal
+	resources            *resourceHistory
+	limiters             *processLimiters
+	projectHooks         *projectHooks
+	stateFile            string
+	restoreState         bool
+	// secrets are resolved once, when the project starts, and are never
+	// stored in the project or process configuration.
+	secrets atomic.Pointer[secrets.Resolved]
 }
 
 // RestartCall represents an in-flight restart operation
@@ -108,7 +126,26 @@ func (p *ProjectRunner) init() {
 	p.initProcessLogs()
 	p.initRestartCoalescing()
 	p.processTree = NewProcessTree(p.refRate)
+	p.resources = newResourceHistory(p.refRate)
+	p.limiters = newProcessLimiters()
 	p.stateBroadcaster = NewProcessStateBroadcaster(p.snapshotProcessStates)
+	if p.journal != nil {
+		p.stateBroadcaster.Subscribe(p.journal)
+	}
+	if !p.project.Hooks.IsEmpty() {
+		p.projectHooks = newProjectHooks(p)
+		p.stateBroadcaster.SubscribeWithSnapshot(p.projectHooks)
+	}
+}
+
+// closeProjectHooks stops notifying the project hooks, and waits for the
+// pending ones, on_shutdown included, to complete.
+func (p *ProjectRunner) closeProjectHooks() {
+	if p.projectHooks == nil {
+		return
+	}
+	p.stateBroadcaster.Unsubscribe(p.projectHooks)
+	p.projectHooks.close()
 }
 
 // snapshotProcessStates returns the current state of every process. Used by
@@ -151,7 +188,73 @@ func (p *ProjectRunner) publishProcessState(ev types.ProcessStateEvent) {
 	p.stateBroadcaster.Publish(ev)
 }
 
+// recordEvent is the event recorder injected into each Process. The journal
+// is nil when disabled, which makes this a no-op.
+func (p *ProjectRunner) recordEvent(ev types.JournalEvent) {
+	p.journal.Record(ev)
+}
+
+// sampleResources polls the resource usage of the running processes, so that
+// their history is recorded even when no client polls their state.
+func (p *ProjectRunner) sampleResources(ctx context.Context) {
+	ticker := time.NewTicker(max(p.refRate, config.DefaultRefreshRate))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if p.withRecursiveMetrics {
+				_ = p.processTree.Update()
+			}
+			p.runProcMutex.Lock()
+			procs := slices.Collect(maps.Values(p.runningProcesses))
+			p.runProcMutex.Unlock()
+			for _, proc := range procs {
+				proc.updateProcState()
+			}
+		}
+	}
+}
+
+// GetResourceHistory returns the resource usage samples of the process taken
+// since the given time, or of all the processes if name is empty.
+func (p *ProjectRunner) GetResourceHistory(name string, since time.Time) ([]types.ResourceHistory, error) {
+	names := []string{name}
+	if name == "" {
+		p.procConfMutex.Lock()
+		names, _ = p.project.GetLexicographicProcessNames()
+		p.procConfMutex.Unlock()
+	} else if _, err := p.GetProcessInfo(name); err != nil {
+		return nil, err
+	}
+	histories := make([]types.ResourceHistory, 0, len(names))
+	for _, n := range names {
+		samples := p.resources.get(n, since)
+		if samples == nil {
+			samples = []types.ResourceSample{}
+		}
+		histories = append(histories, types.ResourceHistory{Name: n, Samples: samples})
+	}
+	return histories, nil
+}
+
+// GetEvents queries the event journal.
+func (p *ProjectRunner) GetEvents(query types.JournalQuery) ([]types.JournalEvent, error) {
+	return p.journal.Query(query)
+}
+
+// CloseJournal closes the event journal once the project is done. Events
+// recorded afterward are dropped.
+func (p *ProjectRunner) CloseJournal() {
+	if err := p.journal.Close(); err != nil {
+		log.Err(err).Msgf("Failed to close event journal %s", p.journal.Path())
+	}
+}
+
 func (p *ProjectRunner) Run() error {
+	defer p.saveStateOnExit()
+	defer p.closeProjectHooks()
 	p.runProcMutex.Lock()
 	p.runningProcesses = make(map[string]*Process)
 	p.runProcMutex.Unlock()
@@ -160,7 +263,7 @@ func (p *ProjectRunner) Run() error {
 	p.doneProcMutex.Unlock()
 	runOrder := []types.ProcessConfig{}
 	err := p.project.WithProcesses([]string{}, func(process types.ProcessConfig) error {
-		if process.IsDeferred() {
+		if process.IsDeferred() || p.isStopped(process.ReplicaName) {
 			return nil
 		}
 		runOrder = append(runOrder, process)
@@ -180,6 +283,9 @@ func (p *ProjectRunner) Run() error {
 		defer p.logger.Close()
 	}
 	p.prepareEnvCmds()
+	if err = p.prepareSecrets(); err != nil {
+		return err
+	}
 	//zerolog.SetGlobalLevel(zerolog.PanicLevel)
 	log.Debug().Msgf("Spinning up %d processes. Order: %q", len(runOrder), nameOrder)
 
@@ -193,7 +299,7 @@ func (p *ProjectRunner) Run() error {
 			if proc.Schedule != nil && proc.Schedule.IsScheduled() {
 				if err := sched.AddProcess(name, proc.Schedule); err != nil {
 					log.Error().Err(err).Msgf("Failed to schedule process %s", name)
-				} else if proc.Disabled {
+				} else if proc.Disabled || p.isStopped(name) {
 					if err := sched.PauseProcess(name); err != nil {
 						log.Error().Err(err).Msgf("Failed to pause schedule for disabled process %s", name)
 					}
@@ -221,6 +327,7 @@ func (p *ProjectRunner) Run() error {
 	// still in flight, leaving two incarnations.
 	p.startWatcher()
 	defer p.stopWatcher()
+	go p.sampleResources(p.ctxApp)
 
 	for {
 		select {
@@ -288,6 +395,7 @@ func (p *ProjectRunner) runProcess(config *types.ProcessConfig) {
 		procLog = pclog.NewLogBuffer(0)
 	}
 	procState := p.newIncarnationState(config)
+	p.setStopped(config.ReplicaName, false)
 	isMain := config.Name == p.mainProcess
 	hasMain := p.mainProcess != ""
 	printLogs := !hasMain && !p.isTuiOn && !p.project.MCPServer.IsStdio()
@@ -300,6 +408,7 @@ func (p *ProjectRunner) runProcess(config *types.ProcessConfig) {
 		withTuiOn(p.isTuiOn),
 		withGlobalEnv(p.project.Environment),
 		withDotEnv(p.project.DotEnvVars),
+		withSecrets(p.secrets.Load()),
 		withLogger(procLogger),
 		withProcConf(config),
 		withProcState(procState),
@@ -313,6 +422,10 @@ func (p *ProjectRunner) runProcess(config *types.ProcessConfig) {
 		withRecursiveMetrics(p.withRecursiveMetrics),
 		withProcessTree(p.processTree),
 		withStatePublisher(p.publishProcessState),
+		withEventRecorder(p.recordEvent),
+		withResourceRecorder(p.resources.record),
+		withLimiter(p.limiters.get(config)),
+		withProjectName(p.project.Name),
 	)
 	p.addRunningProcess(process)
 	go func(proc *Process) {
@@ -325,6 +438,11 @@ func (p *ProjectRunner) runProcess(config *types.ProcessConfig) {
 				if !proc.isDone() {
 					proc.onProcessEnd(types.ProcessStateTerminating)
 				}
+			} else if errors.Is(waitErr, errDependencyTimedOut) {
+				log.Error().Msgf("Error: %s", waitErr.Error())
+				proc.dependencyFailed(waitErr)
+				p.addDoneProcess(proc)
+				p.onProcessEnd(1, proc.procConf)
 			} else {
 				log.Error().Msgf("Error: %s", waitErr.Error())
 				log.Error().Msgf("Error: process %s won't run", proc.getName())
@@ -359,51 +477,21 @@ func (p *ProjectRunner) runProcess(config *types.ProcessConfig) {
 // skipped process.
 var errWaitAborted = errors.New("process was stopped while waiting for its dependencies")
 
+// errDependencyTimedOut is returned by waitIfNeeded when a dependency with
+// on_timeout: fail did not meet its condition in time. Unlike the other unmet
+// dependencies, which skip the process, it fails the process.
+var errDependencyTimedOut = errors.New("dependency timed out")
+
 func (p *ProjectRunner) waitIfNeeded(waiter *Process) error {
 	process := waiter.procConf
 	// Cancelled when the waiting process is shut down, which releases every
 	// wait below instead of leaving this goroutine parked on a dependency that
 	// may only resolve minutes later (or never).
 	abort := waiter.procRunCtx.Done()
-	for k := range process.DependsOn {
+	for k, dep := range process.DependsOn {
 		if proc := p.getDoneOrRunningProcess(k); proc != nil {
-			switch process.DependsOn[k].Condition {
-			case types.ProcessConditionCompleted:
-				if _, res := proc.waitForCompletionOrAbort(abort); res == waitAborted {
-					return errWaitAborted
-				}
-			case types.ProcessConditionCompletedSuccessfully:
-				log.Info().Msgf("%s is waiting for %s to complete successfully", process.ReplicaName, k)
-				exitCode, res := proc.waitForCompletionOrAbort(abort)
-				if res == waitAborted {
-					return errWaitAborted
-				}
-				if !proc.procConf.IsExitCodeSuccess(exitCode) {
-					return fmt.Errorf("process %s depended on %s to complete successfully, but it exited with status %d",
-						process.ReplicaName, k, exitCode)
-				}
-			case types.ProcessConditionHealthy:
-				if proc.procConf.ReadinessProbe == nil && proc.procConf.LivenessProbe == nil {
-					return fmt.Errorf("health dependency defined in '%s' but no health check exists in '%s'", process.ReplicaName, k)
-				}
-				log.Info().Msgf("%s is waiting for %s to be healthy", process.ReplicaName, k)
-				switch proc.waitUntilReady(abort) {
-				case waitAborted:
-					return errWaitAborted
-				case waitFailed:
-					return fmt.Errorf("process %s depended on %s to become ready, but it was terminated", process.ReplicaName, k)
-				}
-			case types.ProcessConditionLogReady:
-				log.Info().Msgf("%s is waiting for %s log line %s", process.ReplicaName, k, proc.procConf.ReadyLogLine)
-				switch proc.waitUntilLogReady(abort) {
-				case waitAborted:
-					return errWaitAborted
-				case waitFailed:
-					return fmt.Errorf("process %s depended on %s to become ready, but it was terminated", process.ReplicaName, k)
-				}
-			case types.ProcessConditionStarted:
-				log.Info().Msgf("%s is waiting for %s to start", process.ReplicaName, k)
-				proc.waitForStarted(abort)
+			if err := p.waitForDependency(waiter, k, dep, proc); err != nil {
+				return err
 			}
 		} else {
 			log.Error().Msgf("Error: process %s depends on %s, but it isn't running or completed", process.ReplicaName, k)
@@ -420,6 +508,102 @@ func (p *ProjectRunner) waitIfNeeded(waiter *Process) error {
 	return nil
 }
 
+// waitForDependency waits for proc to meet the condition of dep, for no longer
+// than its timeout. What happens then is decided by on_timeout.
+func (p *ProjectRunner) waitForDependency(waiter *Process, name string, dep types.ProcessDependency, proc *Process) error {
+	ctx, cancel := waiter.procRunCtx, context.CancelFunc(func() {})
+	timeout := dep.GetTimeout()
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(waiter.procRunCtx, timeout)
+	}
+	defer cancel()
+	err := waitForCondition(ctx.Done(), waiter.procConf, name, dep, proc)
+	if !errors.Is(err, errWaitAborted) || waiter.procRunCtx.Err() != nil {
+		return err
+	}
+	msg := fmt.Sprintf("process %s gave up waiting for %s after %s", waiter.procConf.ReplicaName, name, timeout)
+	switch dep.OnTimeout {
+	case types.DependencyTimeoutContinue:
+		log.Warn().Msgf("%s, starting it anyway", msg)
+		return nil
+	case types.DependencyTimeoutSkip:
+		return errors.New(msg)
+	default:
+		return fmt.Errorf("%w: %s", errDependencyTimedOut, msg)
+	}
+}
+
+// waitForCondition blocks until proc meets the condition of dep. It returns
+// errWaitAborted once abort is closed.
+func waitForCondition(abort <-chan struct{}, process *types.ProcessConfig, name string, dep types.ProcessDependency, proc *Process) error {
+	switch dep.Condition {
+	case types.ProcessConditionCompleted:
+		if _, res := proc.waitForCompletionOrAbort(abort); res == waitAborted {
+			return errWaitAborted
+		}
+	case types.ProcessConditionCompletedSuccessfully:
+		log.Info().Msgf("%s is waiting for %s to complete successfully", process.ReplicaName, name)
+		exitCode, res := proc.waitForCompletionOrAbort(abort)
+		if res == waitAborted {
+			return errWaitAborted
+		}
+		if !proc.procConf.IsExitCodeSuccess(exitCode) {
+			return fmt.Errorf("process %s depended on %s to complete successfully, but it exited with status %d",
+				process.ReplicaName, name, exitCode)
+		}
+	case types.ProcessConditionHealthy:
+		if proc.procConf.ReadinessProbe == nil && proc.procConf.LivenessProbe == nil {
+			return fmt.Errorf("health dependency defined in '%s' but no health check exists in '%s'", process.ReplicaName, name)
+		}
+		log.Info().Msgf("%s is waiting for %s to be healthy", process.ReplicaName, name)
+		switch proc.waitUntilReady(abort) {
+		case waitAborted:
+			return errWaitAborted
+		case waitFailed:
+			return fmt.Errorf("process %s depended on %s to become ready, but it was terminated", process.ReplicaName, name)
+		}
+	case types.ProcessConditionLogReady:
+		log.Info().Msgf("%s is waiting for %s log line %s", process.ReplicaName, name, proc.procConf.ReadyLogLine)
+		switch proc.waitUntilLogReady(abort) {
+		case waitAborted:
+			return errWaitAborted
+		case waitFailed:
+			return fmt.Errorf("process %s depended on %s to become ready, but it was terminated", process.ReplicaName, name)
+		}
+	case types.ProcessConditionPortOpen:
+		host := dep.Host
+		if host == "" {
+			host = "localhost"
+		}
+		address := net.JoinHostPort(host, strconv.Itoa(dep.Port))
+		log.Info().Msgf("%s is waiting for %s to open %s", process.ReplicaName, name, address)
+		switch proc.waitUntilPortOpen(abort, address) {
+		case waitAborted:
+			return errWaitAborted
+		case waitFailed:
+			return fmt.Errorf("process %s depended on %s to open %s, but it was terminated", process.ReplicaName, name, address)
+		}
+	case types.ProcessConditionFileExists:
+		file := dep.Path
+		if !filepath.IsAbs(file) && process.WorkingDir != "" {
+			file = filepath.Join(process.WorkingDir, file)
+		}
+		log.Info().Msgf("%s is waiting for %s to create %s", process.ReplicaName, name, file)
+		switch proc.waitUntilFileExists(abort, file) {
+		case waitAborted:
+			return errWaitAborted
+		case waitFailed:
+			return fmt.Errorf("process %s depended on %s to create %s, but it was terminated", process.ReplicaName, name, file)
+		}
+	case types.ProcessConditionStarted:
+		log.Info().Msgf("%s is waiting for %s to start", process.ReplicaName, name)
+		if proc.waitForStarted(abort) == waitAborted {
+			return errWaitAborted
+		}
+	}
+	return nil
+}
+
 func (p *ProjectRunner) onProcessEnd(exitCode int, procConf *types.ProcessConfig) {
 	success := procConf.IsExitCodeSuccess(exitCode)
 	if (!success && procConf.RestartPolicy.Restart == types.RestartPolicyExitOnFailure) ||
@@ -451,15 +635,17 @@ func (p *ProjectRunner) onProcessSkipped(procConf *types.ProcessConfig) {
 // newIncarnationState builds the state object for a fresh incarnation of a
 // process. Previously the state was cloned from the outgoing instance, which
 // made a new instance inherit a terminal status ("Terminating") and a stale
-// process_end_time. Only the cumulative restart counter is carried over. The
-// state is also registered as the canonical one for that name, so lookups
-// performed while no instance is running observe the latest incarnation.
+// process_end_time. Only the cumulative restart and probe counters are carried
+// over. The state is also registered as the canonical one for that name, so
+// lookups performed while no instance is running observe the latest
+// incarnation.
 func (p *ProjectRunner) newIncarnationState(config *types.ProcessConfig) *types.ProcessState {
 	state := types.NewProcessState(config)
 	p.statesMutex.Lock()
 	defer p.statesMutex.Unlock()
 	if prev, ok := p.processStates[config.ReplicaName]; ok && prev != nil {
 		state.Restarts = prev.Restarts
+		state.ProbeStats = prev.ProbeStats
 	}
 	if p.pendingRestartReset[config.ReplicaName] {
 		state.Restarts = 0
@@ -565,6 +751,7 @@ func (p *ProjectRunner) GetProcessState(name string) (*types.ProcessState, error
 	if s := p.processScheduler.Load(); s != nil {
 		nextRun := s.GetNextRunTime(name)
 		state.NextRunTime = nextRun
+		state.ScheduledRuns, state.ScheduledRunsSkipped = s.GetRunCounts(name)
 		if nextRun != nil {
 			if !state.IsRunning {
 				state.Status = types.ProcessStateScheduled
@@ -773,6 +960,7 @@ func (p *ProjectRunner) StopProcess(name string) error {
 
 	// A deliberately stopped process must not be brought back by a file change.
 	p.watchPause(name)
+	p.setStopped(name, true)
 
 	// Pause schedule if it was running or scheduled
 	if sched := p.processScheduler.Load(); sched != nil && sched.IsScheduled(name) {
@@ -1015,10 +1203,12 @@ type restartOpts struct {
 	// crash-loop budget still spent, so the user's fix-and-save would start it
 	// once and then refuse to restart it again.
 	resetRestarts bool
+	// reason is recorded in the event journal.
+	reason string
 }
 
 func (p *ProjectRunner) RestartProcess(name string) error {
-	return p.restartProcessWithOpts(name, restartOpts{})
+	return p.restartProcessWithOpts(name, restartOpts{reason: "restart requested"})
 }
 
 func (p *ProjectRunner) restartProcessWithOpts(name string, opts restartOpts) error {
@@ -1039,6 +1229,7 @@ func (p *ProjectRunner) restartProcessWithOpts(name string, opts restartOpts) er
 	p.restartMutex.Unlock()
 
 	// Perform the restart
+	p.recordEvent(types.JournalEvent{Process: name, Type: types.JournalEventRestart, Reason: opts.reason})
 	err := p.doRestart(name, opts)
 
 	// Complete the operation and notify waiters
@@ -1122,6 +1313,7 @@ func (p *ProjectRunner) GetProcessInfo(name string) (*types.ProcessConfig, error
 	p.runProcMutex.Lock()
 	defer p.runProcMutex.Unlock()
 	if processConfig, ok := p.project.Processes[name]; ok {
+		processConfig.SecretEnvironment = p.secrets.Load().RedactedEnv()
 		return &processConfig, nil
 	} else {
 		return nil, fmt.Errorf("no such process: %s", name)
@@ -1140,16 +1332,22 @@ func (p *ProjectRunner) SetProcessInfo(config *types.ProcessConfig) error {
 }
 
 func (p *ProjectRunner) GetProcessPorts(name string) (*types.ProcessPorts, error) {
-	proc := p.getRunningProcess(name)
-	if proc == nil {
-		return nil, fmt.Errorf("can't get ports: process %s is not running", name)
-	}
-
 	ports := &types.ProcessPorts{
 		Name:     name,
 		TcpPorts: make([]uint16, 0),
 		UdpPorts: make([]uint16, 0),
 	}
+	if config, err := p.GetProcessInfo(name); err == nil {
+		ports.Assigned = config.AssignedPorts
+	}
+	proc := p.getRunningProcess(name)
+	if proc == nil {
+		if len(ports.Assigned) > 0 {
+			return ports, nil
+		}
+		return nil, fmt.Errorf("can't get ports: process %s is not running", name)
+	}
+
 	err := proc.getOpenPorts(ports)
 	if err != nil {
 		return nil, err
@@ -1157,6 +1355,32 @@ func (p *ProjectRunner) GetProcessPorts(name string) (*types.ProcessPorts, error
 	return ports, nil
 }
 
+// GetProcessTree returns the OS processes spawned by the process, directly or
+// not, rooted at its PID.
+func (p *ProjectRunner) GetProcessTree(name string) (*types.ProcessTree, error) {
+	proc := p.getRunningProcess(name)
+	if proc == nil {
+		if _, err := p.GetProcessInfo(name); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("can't get process tree: process %s is not running", name)
+	}
+	return proc.getProcessTree()
+}
+
+// SignalProcessTree sends the signal to a single OS process of the process
+// tree, which is either the process itself or one of its descendants.
+func (p *ProjectRunner) SignalProcessTree(name string, pid int, sig int) error {
+	proc := p.getRunningProcess(name)
+	if proc == nil {
+		if _, err := p.GetProcessInfo(name); err != nil {
+			return err
+		}
+		return fmt.Errorf("process %s is not running", name)
+	}
+	return proc.signalDescendant(pid, sig)
+}
+
 func (p *ProjectRunner) SetProcessPassword(name, pass string) error {
 	p.runProcMutex.Lock()
 	var elevatedProcs []*Process
@@ -1266,6 +1490,7 @@ func (p *ProjectRunner) ShutDownProject() error {
 	// shutdown.command must not trigger a restart of a process that is already
 	// on its way out.
 	p.stopWatcher()
+	p.projectHooks.shutdown()
 
 	p.runProcMutex.Lock()
 	shutdownOrder := []*Process{}
@@ -1297,6 +1522,7 @@ func (p *ProjectRunner) ShutDownProject() error {
 	}
 
 	p.shutDownAndWait(shutdownOrder)
+	p.limiters.releaseAll()
 	p.cancelAppFn()
 	return nil
 }
@@ -1427,22 +1653,36 @@ func (p *ProjectRunner) getCurrentReplicaCount(name string) int {
 
 func (p *ProjectRunner) scaleUpProcess(proc types.ProcessConfig, toAdd, scale, origScale int) {
 	for i := range toAdd {
-		var procFromConf types.ProcessConfig
-		err := json.Unmarshal([]byte(proc.OriginalConfig), &procFromConf)
+		procFromConf, err := p.newReplica(proc, origScale+i, scale, nil)
 		if err != nil {
-			log.Err(err).Msgf("failed to unmarshal config for %s", proc.Name)
+			log.Err(err).Msgf("failed to scale up %s", proc.Name)
 			return
 		}
-		procFromConf.ReplicaNum = origScale + i
-		procFromConf.Replicas = scale
-		procFromConf.ReplicaName = procFromConf.CalculateReplicaName()
-		tpl := templater.New(p.project.Vars)
-		tpl.RenderProcess(&procFromConf)
-		procFromConf.AssignProcessExecutableAndArgs(p.project.ShellConfig, p.project.GetElevatedShellArg())
 		p.addProcessAndRun(procFromConf)
 	}
 }
 
+// newReplica builds a replica of proc from its original configuration.
+// pending are the replicas that are not in the project yet, so that their
+// ports are part of process_ports as well.
+func (p *ProjectRunner) newReplica(proc types.ProcessConfig, replicaNum, scale int, pending []types.ProcessConfig) (types.ProcessConfig, error) {
+	var procFromConf types.ProcessConfig
+	err := json.Unmarshal([]byte(proc.OriginalConfig), &procFromConf)
+	if err != nil {
+		return procFromConf, fmt.Errorf("failed to unmarshal config for %s: %w", proc.Name, err)
+	}
+	procFromConf.ReplicaNum = replicaNum
+	procFromConf.Replicas = scale
+	procFromConf.ReplicaName = procFromConf.CalculateReplicaName()
+	if err = loader.AssignProcessPorts(&procFromConf, nil); err != nil {
+		return procFromConf, err
+	}
+	tpl := templater.New(p.project.TemplateVars(append(slices.Clone(pending), procFromConf)...))
+	tpl.RenderProcess(&procFromConf)
+	procFromConf.AssignProcessExecutableAndArgs(p.project.ShellConfig, p.project.GetElevatedShellArg())
+	return procFromConf, nil
+}
+
 func (p *ProjectRunner) scaleDownProcess(name string, scale int) {
 	toRemove := []string{}
 	p.procConfMutex.Lock()
@@ -1508,6 +1748,8 @@ func (p *ProjectRunner) renameProcess(name string, newName string) {
 		procConf.ReplicaName = newName
 		p.project.Processes[newName] = procConf
 	}
+	p.resources.rename(name, newName)
+	p.limiters.rename(name, newName)
 	// The watcher is keyed by replica name, and scaling down to 1 renames
 	// e.g. api-0 to api. Re-key it here, alongside the logs and state above,
 	// or the watch would be stranded under a name nothing looks up.
@@ -1530,6 +1772,7 @@ func (p *ProjectRunner) removeProcessLogs(name string) *pclog.ProcessLogBuffer {
 func (p *ProjectRunner) removeProcess(name string) error {
 	p.watchRemove(name)
 	p.removeProcessLogs(name)
+	p.resources.remove(name)
 	p.procConfMutex.Lock()
 	delete(p.project.Processes, name)
 	p.procConfMutex.Unlock()
@@ -1543,6 +1786,7 @@ func (p *ProjectRunner) removeProcess(name string) error {
 			running.waitForCompletion()
 		}
 	}
+	p.limiters.remove(name)
 	return nil
 }
 
@@ -1719,6 +1963,9 @@ func NewProjectRunner(opts *ProjectOpts) (*ProjectRunner, error) {
 		refRate:              opts.refRate,
 		withRecursiveMetrics: opts.withRecursiveMetrics,
 		noWatch:              opts.noWatch,
+		journal:              opts.journal,
+		stateFile:            opts.stateFile,
+		restoreState:         opts.restoreState,
 		projectState: &types.ProjectState{
 			FileNames: opts.project.FileNames,
 			StartTime: time.Now(),
@@ -1735,11 +1982,23 @@ func NewProjectRunner(opts *ProjectOpts) (*ProjectRunner, error) {
 		runner.projectState.ProjectName = name
 	}
 
+	// The replicas are restored before the selection, so that it covers the
+	// restored ones as well.
+	snap := runner.loadSnapshot()
+	if snap != nil {
+		runner.restoreScale(snap)
+	}
 	if err = runner.applySelection(runner.project); err != nil {
 		return nil, err
 	}
+	if snap != nil {
+		runner.restoreStopped(snap)
+	}
 	runner.projectState.ProcessNum = len(runner.project.Processes)
 	runner.init()
+	if snap != nil {
+		runner.restoreStates(snap)
+	}
 	runner.ctxApp, runner.cancelAppFn = context.WithCancel(context.Background())
 	return runner, nil
 }
@@ -1846,10 +2105,13 @@ func (p *ProjectRunner) ReloadProject() (map[string]string, error) {
 	opts := &loader.LoaderOptions{
 		FileNames:        p.project.FileNames,
 		EnvFileNames:     p.project.EnvFileNames,
+		Profiles:         p.project.ActiveProfiles,
 		IsInternalLoader: true,
 	}
 	opts.WithTuiDisabled(p.disableDotenv)
 	opts.WithTuiDisabled(p.isTuiOn)
+	opts.WithAssignedPorts(p.assignedPorts())
+	opts.WithRequestedProcesses(p.processesToRun)
 	project, err := loader.Load(opts)
 	if err != nil {
 		log.Err(err).Msg("Failed to load project")
@@ -1862,6 +2124,20 @@ func (p *ProjectRunner) ReloadProject() (map[string]string, error) {
 	}
 	return status, nil
 }
+
+// assignedPorts returns the ports assigned to the processes, by replica name.
+func (p *ProjectRunner) assignedPorts() map[string]map[string]int {
+	p.runProcMutex.Lock()
+	defer p.runProcMutex.Unlock()
+	ports := map[string]map[string]int{}
+	for name, proc := range p.project.Processes {
+		if len(proc.AssignedPorts) > 0 {
+			ports[name] = proc.AssignedPorts
+		}
+	}
+	return ports
+}
+
 func (p *ProjectRunner) UpdateProcess(updated *types.ProcessConfig) error {
 	defer p.beginUpdate()()
 	isScaleChanged := false
@@ -1869,6 +2145,13 @@ func (p *ProjectRunner) UpdateProcess(updated *types.ProcessConfig) error {
 	validateProbes(updated.ReadinessProbe)
 	updated.AssignProcessExecutableAndArgs(p.project.ShellConfig, p.project.ShellConfig.ElevatedShellArg)
 	if currentProc, ok := p.project.Processes[updated.ReplicaName]; ok {
+		if updated.AssignedPorts == nil {
+			// An edited process comes without its assigned ports. Keep them.
+			if err := loader.AssignProcessPorts(updated, currentProc.AssignedPorts); err != nil {
+				log.Err(err).Msgf("Failed to update process %s", updated.ReplicaName)
+				return err
+			}
+		}
 		equal := currentProc.Compare(updated)
 		if equal {
 			log.Debug().Msgf("Process %s is up to date", updated.Name)
@@ -1889,6 +2172,7 @@ func (p *ProjectRunner) UpdateProcess(updated *types.ProcessConfig) error {
 		log.Err(err).Msgf("Failed to remove process %s", updated.ReplicaName)
 		return err
 	}
+	p.recordEvent(types.JournalEvent{Process: updated.ReplicaName, Type: types.JournalEventRestart, Reason: "configuration changed"})
 	p.addProcessAndRun(*updated)
 
 	if isScaleChanged {
@@ -1916,6 +2200,21 @@ func (p *ProjectRunner) prepareEnvCmds() {
 	}
 }
 
+// prepareSecrets resolves the secrets of the project. A secret that can't be
+// resolved fails the start of a strict project, and is left unset otherwise.
+func (p *ProjectRunner) prepareSecrets() error {
+	resolved, err := secrets.Resolve(p.ctxApp, p.project.Secrets)
+	if err != nil {
+		if p.project.IsStrict {
+			return fmt.Errorf("failed to resolve secrets: %w", err)
+		}
+		log.Err(err).Msg("Failed to resolve secrets")
+	}
+	p.secrets.Store(resolved)
+	log.Debug().Msgf("Resolved secrets %q", resolved.Names())
+	return nil
+}
+
 func runCmd(envCmd string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
@@ -1954,12 +2253,21 @@ func (p *ProjectRunner) SendProcessKeys(name string, keys string) error {
 	return proc.sendKeys(keys)
 }
 
+// GetFullProcessEnvironment returns the environment of a process, with the
+// secret values redacted.
 func (p *ProjectRunner) GetFullProcessEnvironment(proc *types.ProcessConfig) []string {
+	return p.secrets.Load().RedactEnv(p.GetProcessLaunchEnvironment(proc))
+}
+
+// GetProcessLaunchEnvironment returns the environment a process is started
+// with, including the secret values. Use GetFullProcessEnvironment for
+// anything displayed.
+func (p *ProjectRunner) GetProcessLaunchEnvironment(proc *types.ProcessConfig) []string {
 	var dotEnvVars map[string]string
 	if !p.disableDotenv {
 		dotEnvVars = p.project.DotEnvVars
 	}
-	return buildProcessEnvironment(proc, p.project.Environment, dotEnvVars)
+	return buildProcessEnvironment(proc, p.project.Environment, p.secrets.Load().Env(), dotEnvVars)
 }
 
 // GetDependencyGraph builds and returns the process dependency graph with current status
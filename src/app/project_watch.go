@@ -173,6 +173,16 @@ func (p *ProjectRunner) lastWatchTrigger(name string) (string, *time.Time) {
 	return info.Path, &at
 }
 
+// recordWatchTrigger journals the change that is about to restart name.
+func (p *ProjectRunner) recordWatchTrigger(name string) {
+	ev := types.JournalEvent{Process: name, Type: types.JournalEventWatchTrigger}
+	if path, at := p.lastWatchTrigger(name); at != nil {
+		ev.Time = *at
+		ev.Details = map[string]string{"path": path}
+	}
+	p.recordEvent(ev)
+}
+
 // hasBackgroundTriggers reports whether anything outside the running process
 // set can still start a process. While it is true, an empty running set must
 // not complete the project - otherwise a project of one-shot builders would
@@ -198,9 +208,10 @@ func (p *ProjectRunner) hasBackgroundTriggers() bool {
 // asked for by saving a file, not a crash loop being damped.
 func (p *ProjectRunner) RestartProcesses(names []string) error {
 	defer p.beginUpdate()()
-	opts := restartOpts{skipBackoff: true, resetRestarts: true}
+	opts := restartOpts{skipBackoff: true, resetRestarts: true, reason: "watched file changed"}
 	errs := make([]error, 0, len(names))
 	for _, name := range names {
+		p.recordWatchTrigger(name)
 		if err := p.restartProcessWithOpts(name, opts); err != nil {
 			errs = append(errs, err)
 		}
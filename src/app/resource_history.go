@@ -0,0 +1,102 @@
+package app
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+// resourceHistoryDuration is how far back the resource usage of the processes
+// is kept.
+const resourceHistoryDuration = 15 * time.Minute
+
+// resourceHistory keeps the last resource usage samples of every process, in
+// a ring buffer per process. The history of a process outlives its restarts,
+// scaling and updates, until the process is removed from the project.
+//
+// All methods are safe to call on a nil *resourceHistory, which records
+// nothing.
+type resourceHistory struct {
+	mtx     sync.Mutex
+	size    int
+	samples map[string]*sampleRing
+}
+
+type sampleRing struct {
+	samples []types.ResourceSample
+	next    int
+}
+
+// newResourceHistory creates a history keeping resourceHistoryDuration of
+// samples taken every interval.
+func newResourceHistory(interval time.Duration) *resourceHistory {
+	size := 1
+	if interval > 0 {
+		size = max(int(resourceHistoryDuration/interval), 1)
+	}
+	return &resourceHistory{
+		size:    size,
+		samples: map[string]*sampleRing{},
+	}
+}
+
+func (h *resourceHistory) record(name string, sample types.ResourceSample) {
+	if h == nil {
+		return
+	}
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	ring, ok := h.samples[name]
+	if !ok {
+		ring = &sampleRing{}
+		h.samples[name] = ring
+	}
+	if len(ring.samples) < h.size {
+		ring.samples = append(ring.samples, sample)
+		return
+	}
+	ring.samples[ring.next] = sample
+	ring.next = (ring.next + 1) % h.size
+}
+
+// get returns the samples of the process taken since the given time, oldest
+// first. A zero since returns all of them.
+func (h *resourceHistory) get(name string, since time.Time) []types.ResourceSample {
+	if h == nil {
+		return nil
+	}
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	ring, ok := h.samples[name]
+	if !ok {
+		return nil
+	}
+	samples := slices.Concat(ring.samples[ring.next:], ring.samples[:ring.next])
+	first, _ := slices.BinarySearchFunc(samples, since, func(s types.ResourceSample, t time.Time) int {
+		return s.Time.Compare(t)
+	})
+	return samples[first:]
+}
+
+func (h *resourceHistory) rename(name, newName string) {
+	if h == nil {
+		return
+	}
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if ring, ok := h.samples[name]; ok {
+		delete(h.samples, name)
+		h.samples[newName] = ring
+	}
+}
+
+func (h *resourceHistory) remove(name string) {
+	if h == nil {
+		return
+	}
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	delete(h.samples, name)
+}
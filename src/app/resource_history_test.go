@@ -0,0 +1,68 @@
+package app
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestResourceHistory(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	sampleAt := func(sec int) types.ResourceSample {
+		return types.ResourceSample{Time: start.Add(time.Duration(sec) * time.Second), Mem: int64(sec)}
+	}
+	mems := func(samples []types.ResourceSample) []int64 {
+		var got []int64
+		for _, s := range samples {
+			got = append(got, s.Mem)
+		}
+		return got
+	}
+
+	h := newResourceHistory(resourceHistoryDuration / 3)
+	if h.size != 3 {
+		t.Fatalf("size = %d, want 3", h.size)
+	}
+	for sec := range 5 {
+		h.record("web", sampleAt(sec))
+	}
+	h.record("db", sampleAt(0))
+
+	tests := []struct {
+		name  string
+		proc  string
+		since time.Time
+		want  []int64
+	}{
+		{name: "oldest samples dropped", proc: "web", want: []int64{2, 3, 4}},
+		{name: "since", proc: "web", since: start.Add(3 * time.Second), want: []int64{3, 4}},
+		{name: "since between samples", proc: "web", since: start.Add(2500 * time.Millisecond), want: []int64{3, 4}},
+		{name: "since after the last sample", proc: "web", since: start.Add(time.Minute), want: nil},
+		{name: "other process", proc: "db", want: []int64{0}},
+		{name: "unknown process", proc: "api", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mems(h.get(tt.proc, tt.since)); !slices.Equal(got, tt.want) {
+				t.Errorf("get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	h.rename("web", "web-0")
+	if got := mems(h.get("web-0", time.Time{})); len(got) != 3 {
+		t.Errorf("renamed history = %v, want 3 samples", got)
+	}
+	h.remove("web-0")
+	if got := h.get("web-0", time.Time{}); got != nil {
+		t.Errorf("removed history = %v, want none", got)
+	}
+
+	var nilHistory *resourceHistory
+	nilHistory.record("web", sampleAt(0))
+	if got := nilHistory.get("web", time.Time{}); got != nil {
+		t.Errorf("nil history = %v, want none", got)
+	}
+}
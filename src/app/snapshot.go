@@ -0,0 +1,226 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+)
+
+// SaveProjectState writes the runtime state of the project to the state file,
+// and returns its path.
+func (p *ProjectRunner) SaveProjectState() (string, error) {
+	if p.stateFile == "" {
+		return "", errors.New("the state file is disabled")
+	}
+	if err := writeSnapshot(p.stateFile, p.snapshot()); err != nil {
+		return "", fmt.Errorf("failed to save the project state: %w", err)
+	}
+	return p.stateFile, nil
+}
+
+// saveStateOnExit saves the project state when the project ends.
+func (p *ProjectRunner) saveStateOnExit() {
+	if p.stateFile == "" {
+		return
+	}
+	if _, err := p.SaveProjectState(); err != nil {
+		log.Err(err).Send()
+		return
+	}
+	log.Info().Msgf("Project state saved to %s", p.stateFile)
+}
+
+func (p *ProjectRunner) snapshot() *types.ProjectSnapshot {
+	snap := &types.ProjectSnapshot{
+		SavedAt:   time.Now(),
+		Replicas:  map[string]int{},
+		Processes: map[string]types.ProcessSnapshot{},
+	}
+	p.procConfMutex.Lock()
+	for name, proc := range p.project.Processes {
+		snap.Replicas[proc.Name] = proc.Replicas
+		snap.Processes[name] = types.ProcessSnapshot{}
+	}
+	p.procConfMutex.Unlock()
+
+	p.statesMutex.Lock()
+	defer p.statesMutex.Unlock()
+	for name := range snap.Processes {
+		var procSnap types.ProcessSnapshot
+		if state, ok := p.processStates[name]; ok {
+			procSnap.Restarts = state.Restarts
+		}
+		procSnap.Stopped = p.stoppedProcesses[name]
+		if procSnap == (types.ProcessSnapshot{}) {
+			delete(snap.Processes, name)
+			continue
+		}
+		snap.Processes[name] = procSnap
+	}
+	return snap
+}
+
+// setStopped records whether a process was stopped by the user.
+func (p *ProjectRunner) setStopped(name string, stopped bool) {
+	p.statesMutex.Lock()
+	defer p.statesMutex.Unlock()
+	if !stopped {
+		delete(p.stoppedProcesses, name)
+		return
+	}
+	if p.stoppedProcesses == nil {
+		p.stoppedProcesses = make(map[string]bool)
+	}
+	p.stoppedProcesses[name] = true
+}
+
+// loadSnapshot reads the state file to restore, if asked to. A missing or
+// unreadable state file is reported, and the project starts from its
+// configuration.
+func (p *ProjectRunner) loadSnapshot() *types.ProjectSnapshot {
+	if !p.restoreState || p.stateFile == "" {
+		return nil
+	}
+	snap, err := readSnapshot(p.stateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Info().Msgf("No project state to restore in %s", p.stateFile)
+		return nil
+	}
+	if err != nil {
+		log.Err(err).Msgf("Failed to restore the project state from %s", p.stateFile)
+		return nil
+	}
+	log.Info().Msgf("Restoring the project state saved at %s", snap.SavedAt.Local().Format(time.DateTime))
+	return snap
+}
+
+// restoreScale replays the replica counts of a snapshot on the project,
+// before anything runs.
+func (p *ProjectRunner) restoreScale(snap *types.ProjectSnapshot) {
+	for name, scale := range snap.Replicas {
+		if err := p.restoreReplicas(name, scale); err != nil {
+			log.Err(err).Msgf("Failed to restore the replicas of %s", name)
+		}
+	}
+}
+
+// restoreStopped marks the stopped processes of a snapshot as stopped by the
+// user, so that they are not started with the project and their schedules
+// are paused, but can still be started by hand. The processes selected with
+// `up <process>...` are started anyway.
+func (p *ProjectRunner) restoreStopped(snap *types.ProjectSnapshot) {
+	for name, procSnap := range snap.Processes {
+		proc, ok := p.project.Processes[name]
+		if !ok || !procSnap.Stopped {
+			continue
+		}
+		if len(p.processesToRun) > 0 && !proc.Disabled {
+			continue
+		}
+		p.setStopped(name, true)
+	}
+}
+
+// isStopped reports whether a process was stopped by the user.
+func (p *ProjectRunner) isStopped(name string) bool {
+	p.statesMutex.Lock()
+	defer p.statesMutex.Unlock()
+	return p.stoppedProcesses[name]
+}
+
+// restoreStates carries the restart counts of a snapshot over to the process
+// states, and shows the stopped processes as disabled until they are started.
+func (p *ProjectRunner) restoreStates(snap *types.ProjectSnapshot) {
+	p.statesMutex.Lock()
+	defer p.statesMutex.Unlock()
+	for name, procSnap := range snap.Processes {
+		if state, ok := p.processStates[name]; ok {
+			state.Restarts = procSnap.Restarts
+		}
+	}
+	for name := range p.stoppedProcesses {
+		if state, ok := p.processStates[name]; ok {
+			state.Status = types.ProcessStateDisabled
+		}
+	}
+}
+
+// restoreReplicas is the ScaleProcess of a project that is not running yet:
+// it adds or removes the replicas of a process in the configuration, and
+// renames the remaining ones.
+func (p *ProjectRunner) restoreReplicas(name string, scale int) error {
+	var replicas []types.ProcessConfig
+	for _, proc := range p.project.Processes {
+		if proc.Name == name {
+			replicas = append(replicas, proc)
+		}
+	}
+	if len(replicas) == 0 || scale < 1 || len(replicas) == scale {
+		return nil
+	}
+	slices.SortFunc(replicas, func(a, b types.ProcessConfig) int { return a.ReplicaNum - b.ReplicaNum })
+	for _, proc := range replicas {
+		delete(p.project.Processes, proc.ReplicaName)
+	}
+	replicas = replicas[:min(scale, len(replicas))]
+	for i := range replicas {
+		replicas[i].Replicas = scale
+		replicas[i].ReplicaName = replicas[i].CalculateReplicaName()
+	}
+	for i := len(replicas); i < scale; i++ {
+		replica, err := p.newReplica(replicas[0], i, scale, replicas)
+		if err != nil {
+			return err
+		}
+		replicas = append(replicas, replica)
+	}
+	for _, proc := range replicas {
+		p.project.Processes[proc.ReplicaName] = proc
+	}
+	log.Info().Msgf("Restored %d replicas of %s", scale, name)
+	return nil
+}
+
+func readSnapshot(path string) (*types.ProjectSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	snap := &types.ProjectSnapshot{}
+	if err = json.Unmarshal(data, snap); err != nil {
+		return nil, fmt.Errorf("invalid state file: %w", err)
+	}
+	return snap, nil
+}
+
+// writeSnapshot replaces the state file atomically, so that a crash while
+// saving leaves the previous state intact.
+func writeSnapshot(path string, snap *types.ProjectSnapshot) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
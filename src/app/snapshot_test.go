@@ -0,0 +1,207 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/loader"
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+const snapshotProject = `
+processes:
+  web:
+    command: "sleep 60"
+  api:
+    command: "sleep 60"
+  worker:
+    command: "echo worker {{ .PC_REPLICA_NUM }}"
+    replicas: 2
+`
+
+func newSnapshotRunner(t *testing.T, stateFile string, restore bool) *ProjectRunner {
+	t.Helper()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "process-compose.yaml")
+	if err := os.WriteFile(file, []byte(snapshotProject), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	project, err := loader.Load(&loader.LoaderOptions{FileNames: []string{file}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{
+		project:      project,
+		stateFile:    stateFile,
+		restoreState: restore,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return runner
+}
+
+func TestProjectRunner_SaveProjectState(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state", "project.json")
+	runner := newSnapshotRunner(t, stateFile, false)
+	runner.setStopped("api", true)
+	runner.processStates["web"].Restarts = 3
+
+	path, err := runner.SaveProjectState()
+	if err != nil {
+		t.Fatalf("SaveProjectState() error = %v", err)
+	}
+	if path != stateFile {
+		t.Errorf("SaveProjectState() = %s, want %s", path, stateFile)
+	}
+	snap, err := readSnapshot(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantReplicas := map[string]int{"web": 1, "api": 1, "worker": 2}
+	if !reflect.DeepEqual(snap.Replicas, wantReplicas) {
+		t.Errorf("Replicas = %v, want %v", snap.Replicas, wantReplicas)
+	}
+	wantProcesses := map[string]types.ProcessSnapshot{
+		"web": {Restarts: 3},
+		"api": {Stopped: true},
+	}
+	if !reflect.DeepEqual(snap.Processes, wantProcesses) {
+		t.Errorf("Processes = %v, want %v", snap.Processes, wantProcesses)
+	}
+
+	runner.stateFile = ""
+	if _, err = runner.SaveProjectState(); err == nil {
+		t.Error("SaveProjectState() should fail without a state file")
+	}
+}
+
+func TestProjectRunner_RestoreState(t *testing.T) {
+	tests := []struct {
+		name         string
+		snap         *types.ProjectSnapshot
+		wantWorkers  []string
+		wantStopped  []string
+		wantRestarts map[string]int
+	}{
+		{
+			name: "scale up",
+			snap: &types.ProjectSnapshot{
+				Replicas: map[string]int{"worker": 3},
+				Processes: map[string]types.ProcessSnapshot{
+					"api":      {Stopped: true},
+					"worker-2": {Restarts: 2},
+				},
+			},
+			wantWorkers:  []string{"worker-0", "worker-1", "worker-2"},
+			wantStopped:  []string{"api"},
+			wantRestarts: map[string]int{"worker-2": 2, "web": 0},
+		},
+		{
+			name: "scale down",
+			snap: &types.ProjectSnapshot{
+				Replicas:  map[string]int{"worker": 1},
+				Processes: map[string]types.ProcessSnapshot{"web": {Restarts: 4, Stopped: true}},
+			},
+			wantWorkers:  []string{"worker"},
+			wantStopped:  []string{"web"},
+			wantRestarts: map[string]int{"web": 4},
+		},
+		{
+			name:        "unknown processes",
+			snap:        &types.ProjectSnapshot{Replicas: map[string]int{"gone": 2}, Processes: map[string]types.ProcessSnapshot{"gone": {Stopped: true}}},
+			wantWorkers: []string{"worker-0", "worker-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stateFile := filepath.Join(t.TempDir(), "project.json")
+			if err := writeSnapshot(stateFile, tt.snap); err != nil {
+				t.Fatal(err)
+			}
+			runner := newSnapshotRunner(t, stateFile, true)
+
+			var workers, stopped []string
+			for name, proc := range runner.project.Processes {
+				if proc.Name == "worker" {
+					workers = append(workers, name)
+					if proc.Replicas != len(tt.wantWorkers) {
+						t.Errorf("%s has %d replicas, want %d", name, proc.Replicas, len(tt.wantWorkers))
+					}
+				}
+				if proc.Disabled {
+					t.Errorf("%s should not be disabled", name)
+				}
+				if runner.isStopped(name) {
+					stopped = append(stopped, name)
+					if status := runner.processStates[name].Status; status != types.ProcessStateDisabled {
+						t.Errorf("%s status = %s, want %s", name, status, types.ProcessStateDisabled)
+					}
+				}
+			}
+			slices.Sort(workers)
+			if !slices.Equal(workers, tt.wantWorkers) {
+				t.Errorf("workers = %v, want %v", workers, tt.wantWorkers)
+			}
+			if !slices.Equal(stopped, tt.wantStopped) {
+				t.Errorf("stopped = %v, want %v", stopped, tt.wantStopped)
+			}
+			for name, want := range tt.wantRestarts {
+				if got := runner.processStates[name].Restarts; got != want {
+					t.Errorf("%s restarts = %d, want %d", name, got, want)
+				}
+			}
+			// The restored state is saved again as it was.
+			snap := runner.snapshot()
+			for _, name := range tt.wantStopped {
+				if !snap.Processes[name].Stopped {
+					t.Errorf("%s should still be saved as stopped", name)
+				}
+			}
+		})
+	}
+}
+
+func TestProjectRunner_RestoreState_Missing(t *testing.T) {
+	runner := newSnapshotRunner(t, filepath.Join(t.TempDir(), "nope.json"), true)
+	if len(runner.project.Processes) != 4 {
+		t.Errorf("project has %d processes, want the 4 of the configuration", len(runner.project.Processes))
+	}
+}
+
+func TestProjectRunner_RestoreState_Selection(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "project.json")
+	snap := &types.ProjectSnapshot{Processes: map[string]types.ProcessSnapshot{
+		"api": {Stopped: true},
+		"web": {Stopped: true},
+	}}
+	if err := writeSnapshot(stateFile, snap); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "process-compose.yaml")
+	if err := os.WriteFile(file, []byte(snapshotProject), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	project, err := loader.Load(&loader.LoaderOptions{FileNames: []string{file}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{
+		project:        project,
+		stateFile:      stateFile,
+		restoreState:   true,
+		processesToRun: []string{"api"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runner.isStopped("api") {
+		t.Error("the selected api should start even though it was stopped")
+	}
+	if !runner.isStopped("web") {
+		t.Error("web should stay stopped")
+	}
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/f1bonacc1/process-compose/src/command"
 	"github.com/f1bonacc1/process-compose/src/health"
+	"github.com/f1bonacc1/process-compose/src/journal"
 	"github.com/f1bonacc1/process-compose/src/loader"
 	"github.com/f1bonacc1/process-compose/src/pclog"
 	"github.com/f1bonacc1/process-compose/src/types"
@@ -2022,3 +2024,214 @@ func TestSystem_TestEnvFile(t *testing.T) {
 		}
 	})
 }
+
+func TestSystem_EventJournal(t *testing.T) {
+	shell := command.DefaultShellConfig()
+	j, err := journal.Open(filepath.Join(t.TempDir(), "events.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	const proc = "flaky"
+	runner, err := NewProjectRunner(&ProjectOpts{
+		project: &types.Project{
+			ShellConfig: shell,
+			Processes: map[string]types.ProcessConfig{
+				proc: {
+					Name:        proc,
+					ReplicaName: proc,
+					Executable:  shell.ShellCommand,
+					Args:        []string{shell.ShellArgument, "exit 3"},
+					RestartPolicy: types.RestartPolicyConfig{
+						Restart:     types.RestartPolicyOnFailure,
+						MaxRestarts: 1,
+					},
+				},
+			},
+		},
+		journal: j,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = runner.Run()
+
+	events, err := runner.GetEvents(types.JournalQuery{Process: proc})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exits, restarts int
+	for _, ev := range events {
+		switch ev.Type {
+		case types.JournalEventExit:
+			exits++
+			if ev.ExitCode == nil || *ev.ExitCode != 3 {
+				t.Errorf("exit event without exit code 3: %+v", ev)
+			}
+		case types.JournalEventRestart:
+			restarts++
+			if ev.Reason != "exited with code 3" {
+				t.Errorf("restart reason = %q, want %q", ev.Reason, "exited with code 3")
+			}
+		}
+	}
+	if exits != 2 || restarts != 1 {
+		t.Errorf("journal recorded %d exits and %d restarts, want 2 and 1: %+v", exits, restarts, events)
+	}
+	if last := events[len(events)-1]; last.Type != types.JournalEventStatus || last.Status != types.ProcessStateCompleted {
+		t.Errorf("last event = %+v, want the Completed status", last)
+	}
+}
+
+func TestSystem_DependencyTimeout(t *testing.T) {
+	shell := command.DefaultShellConfig()
+	tests := []struct {
+		name       string
+		onTimeout  types.DependencyTimeoutAction
+		wantStatus string
+		wantExit   int
+	}{
+		{"fail", types.DependencyTimeoutFail, types.ProcessStateError, 1},
+		{"skip", types.DependencyTimeoutSkip, types.ProcessStateSkipped, 1},
+		{"continue", types.DependencyTimeoutContinue, types.ProcessStateCompleted, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, err := NewProjectRunner(&ProjectOpts{
+				project: &types.Project{
+					ShellConfig: shell,
+					Processes: map[string]types.ProcessConfig{
+						"builder": {
+							Name:        "builder",
+							ReplicaName: "builder",
+							Executable:  shell.ShellCommand,
+							Args:        []string{shell.ShellArgument, "sleep 1"},
+						},
+						"app": {
+							Name:        "app",
+							ReplicaName: "app",
+							Executable:  shell.ShellCommand,
+							Args:        []string{shell.ShellArgument, "exit 0"},
+							DependsOn: types.DependsOnConfig{
+								"builder": {
+									Condition: types.ProcessConditionFileExists,
+									Path:      filepath.Join(t.TempDir(), "never"),
+									Timeout:   "100ms",
+									OnTimeout: tt.onTimeout,
+								},
+							},
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			start := time.Now()
+			go func() { _ = runner.Run() }()
+			waitForProcessState(t, runner, "app", tt.wantStatus, 800*time.Millisecond)
+			if elapsed := time.Since(start); elapsed > 800*time.Millisecond {
+				t.Errorf("app waited %s for its dependency, want about the timeout", elapsed)
+			}
+			state, _ := runner.GetProcessState("app")
+			if state.ExitCode != tt.wantExit {
+				t.Errorf("app exit code = %d, want %d", state.ExitCode, tt.wantExit)
+			}
+			_ = runner.ShutDownProject()
+		})
+	}
+}
+
+func TestSystem_DependencyPortAndFile(t *testing.T) {
+	shell := command.DefaultShellConfig()
+	artifact := filepath.Join(t.TempDir(), "artifact")
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := lis.Addr().(*net.TCPAddr).Port
+	_ = lis.Close()
+
+	runner, err := NewProjectRunner(&ProjectOpts{
+		project: &types.Project{
+			ShellConfig: shell,
+			Processes: map[string]types.ProcessConfig{
+				"builder": {
+					Name:        "builder",
+					ReplicaName: "builder",
+					Executable:  shell.ShellCommand,
+					Args:        []string{shell.ShellArgument, "sleep 0.3 && touch " + artifact},
+				},
+				"server": {
+					Name:        "server",
+					ReplicaName: "server",
+					Executable:  shell.ShellCommand,
+					Args:        []string{shell.ShellArgument, "sleep 1"},
+				},
+				"app": {
+					Name:        "app",
+					ReplicaName: "app",
+					Executable:  shell.ShellCommand,
+					Args:        []string{shell.ShellArgument, "exit 0"},
+					DependsOn: types.DependsOnConfig{
+						"builder": {Condition: types.ProcessConditionFileExists, Path: artifact},
+						"server": {
+							Condition: types.ProcessConditionPortOpen,
+							Host:      "127.0.0.1",
+							Port:      port,
+							Timeout:   "5s",
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = runner.Run() }()
+	defer func() { _ = runner.ShutDownProject() }()
+
+	time.Sleep(500 * time.Millisecond)
+	state, _ := runner.GetProcessState("app")
+	if state.Status != types.ProcessStatePending {
+		t.Fatalf("app status = %s before the port opened, want %s", state.Status, types.ProcessStatePending)
+	}
+	lis, err = net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	waitForProcessState(t, runner, "app", types.ProcessStateCompleted, 2*time.Second)
+}
+
+func TestSystem_CrashLoopBackOff(t *testing.T) {
+	shell := command.DefaultShellConfig()
+	const proc = "crasher"
+	runner, err := NewProjectRunner(&ProjectOpts{
+		project: &types.Project{
+			ShellConfig: shell,
+			Processes: map[string]types.ProcessConfig{
+				proc: {
+					Name:        proc,
+					ReplicaName: proc,
+					Executable:  shell.ShellCommand,
+					Args:        []string{shell.ShellArgument, "exit 1"},
+					RestartPolicy: types.RestartPolicyConfig{
+						Restart:        types.RestartPolicyOnFailure,
+						BackoffSeconds: 1,
+						CrashLoop:      &types.CrashLoopConfig{MaxFailures: 2, WindowSeconds: 60},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = runner.Run() }()
+	defer func() { _ = runner.ShutDownProject() }()
+
+	waitForProcessState(t, runner, proc, types.ProcessStateRestarting, time.Second)
+	waitForProcessState(t, runner, proc, types.ProcessStateCrashLoopBackOff, 3*time.Second)
+}
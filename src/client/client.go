@@ -45,6 +45,10 @@ type PcClient struct {
 	firstError time.Time
 	isErrored  bool
 	client     *http.Client
+	// observers maps the registered state observers to the cancellation of
+	// their state stream.
+	observersMtx sync.Mutex
+	observers    map[string]context.CancelFunc
 }
 
 func NewUdsClient(sockPath string, logLength int) *PcClient {
@@ -85,6 +89,7 @@ func newClient(address string, client *http.Client, logLength int) *PcClient {
 		firstError: zeroTime,
 		isErrored:  false,
 		client:     client,
+		observers:  map[string]context.CancelFunc{},
 	}
 }
 
@@ -134,6 +139,14 @@ func (p *PcClient) GetProcessPorts(name string) (*types.ProcessPorts, error) {
 	return p.getProcessPorts(name)
 }
 
+func (p *PcClient) GetProcessTree(name string) (*types.ProcessTree, error) {
+	return p.getProcessTree(name)
+}
+
+func (p *PcClient) SignalProcessTree(name string, pid int, sig int) error {
+	return p.signalProcessTree(name, pid, sig)
+}
+
 func (p *PcClient) GetProcessState(name string) (*types.ProcessState, error) {
 	state, err := p.getProcessState(name)
 	return state, err
@@ -242,17 +255,32 @@ func (p *PcClient) GetFullProcessEnvironment(proc *types.ProcessConfig) []string
 	return append(os.Environ(), proc.Environment...)
 }
 
+// GetProcessLaunchEnvironment is the same as GetFullProcessEnvironment: the
+// secrets are resolved by the server and never sent to its clients.
+func (p *PcClient) GetProcessLaunchEnvironment(proc *types.ProcessConfig) []string {
+	return p.GetFullProcessEnvironment(proc)
+}
+
+func (p *PcClient) ExecProcess(ctx context.Context, name string, req *types.ExecRequest, streams *types.ExecStreams) (int, error) {
+	return p.execProcess(ctx, name, req, streams)
+}
+
 func (p *PcClient) GetDependencyGraph() (*types.DependencyGraph, error) {
 	return p.getDependencyGraph()
 }
 
+func (p *PcClient) GetEvents(query types.JournalQuery) ([]types.JournalEvent, error) {
+	return p.getEvents(query)
+}
+
+func (p *PcClient) GetResourceHistory(name string, since time.Time) ([]types.ResourceHistory, error) {
+	return p.getResourceHistory(name, since)
+}
+
+func (p *PcClient) SaveProjectState() (string, error) {
+	return p.saveProjectState()
+}
+
 func (p *PcClient) GetNamespaces() ([]string, error) {
 	return p.getNamespaces()
 }
-
-// RegisterStateObserver is a no-op for the remote client. The state-stream
-// WebSocket endpoint can be consumed via the dedicated SubscribeProcessStates
-// helper (see state_stream.go); the IProject hooks are reserved for
-// in-process use.
-func (p *PcClient) RegisterStateObserver(_ types.StateObserver)   {}
-func (p *PcClient) UnregisterStateObserver(_ types.StateObserver) {}
@@ -1,6 +1,9 @@
 package client
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
@@ -8,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/f1bonacc1/process-compose/src/api"
 	"github.com/f1bonacc1/process-compose/src/app"
@@ -127,3 +131,93 @@ func TestParseErrorResponse(t *testing.T) {
 		}
 	}
 }
+
+func (f *fakeProject) GetProcessInfo(name string) (*types.ProcessConfig, error) {
+	return &types.ProcessConfig{Name: name}, nil
+}
+
+func (f *fakeProject) ExecProcess(_ context.Context, name string, req *types.ExecRequest, streams *types.ExecStreams) (int, error) {
+	if req.Command[0] == "missing" {
+		return -1, errors.New("executable missing not found in the process PATH")
+	}
+	input, err := io.ReadAll(streams.Stdin)
+	if err != nil {
+		return -1, err
+	}
+	_, _ = fmt.Fprintf(streams.Stdout, "%s %v %s", name, req.Command, input)
+	_, _ = fmt.Fprint(streams.Stderr, "warning")
+	return 3, nil
+}
+
+func TestExecProcess(t *testing.T) {
+	c := newTestClient(t, &fakeProject{})
+	var stdout, stderr strings.Builder
+	code, err := c.ExecProcess(context.Background(), "db", &types.ExecRequest{Command: []string{"psql", "-c", "select 1"}}, &types.ExecStreams{
+		Stdin:  strings.NewReader("input"),
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		t.Fatalf("failed to exec: %v", err)
+	}
+	if code != 3 {
+		t.Errorf("expected exit code 3, got %d", code)
+	}
+	if got, want := stdout.String(), "db [psql -c select 1] input"; got != want {
+		t.Errorf("expected stdout %q, got %q", want, got)
+	}
+	if stderr.String() != "warning" {
+		t.Errorf("expected stderr %q, got %q", "warning", stderr.String())
+	}
+
+	_, err = c.ExecProcess(context.Background(), "db", &types.ExecRequest{Command: []string{"missing"}}, &types.ExecStreams{Stdin: strings.NewReader("")})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected the exec error, got %v", err)
+	}
+	_, err = c.ExecProcess(context.Background(), "db", &types.ExecRequest{}, &types.ExecStreams{})
+	if err == nil || !strings.Contains(err.Error(), "command is required") {
+		t.Errorf("expected a bad request error, got %v", err)
+	}
+}
+
+// stateProject replays a single snapshot event to each state observer.
+type stateProject struct {
+	app.IProject
+	unregistered chan struct{}
+}
+
+func (s *stateProject) RegisterStateObserver(o types.StateObserver) {
+	o.Notify(types.ProcessStateEvent{Snapshot: true, State: types.ProcessState{Name: "web", Status: types.ProcessStateRunning}})
+}
+
+func (s *stateProject) UnregisterStateObserver(_ types.StateObserver) {
+	close(s.unregistered)
+}
+
+type chanObserver chan types.ProcessStateEvent
+
+func (c chanObserver) Notify(ev types.ProcessStateEvent) { c <- ev }
+func (c chanObserver) UniqueID() string                  { return "test" }
+
+func TestRegisterStateObserver(t *testing.T) {
+	project := &stateProject{unregistered: make(chan struct{})}
+	c := newTestClient(t, project)
+	observer := make(chanObserver, 1)
+
+	c.RegisterStateObserver(observer)
+	select {
+	case ev := <-observer:
+		if !ev.Snapshot || ev.State.Name != "web" || ev.State.Status != types.ProcessStateRunning {
+			t.Errorf("unexpected event %+v", ev)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the snapshot event")
+	}
+
+	c.UnregisterStateObserver(observer)
+	select {
+	case <-project.unregistered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the server to drop the state stream")
+	}
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func (p *PcClient) getEvents(query types.JournalQuery) ([]types.JournalEvent, error) {
+	q := url.Values{}
+	if query.Process != "" {
+		q.Set("process", query.Process)
+	}
+	if !query.Since.IsZero() {
+		q.Set("since", query.Since.Format(time.RFC3339Nano))
+	}
+	if query.AfterSeq > 0 {
+		q.Set("after", strconv.FormatUint(query.AfterSeq, 10))
+	}
+	if query.Limit > 0 {
+		q.Set("limit", strconv.Itoa(query.Limit))
+	}
+	url := fmt.Sprintf("http://%s/events?%s", p.address, q.Encode())
+	resp, err := p.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseErrorResponse(resp, "get events")
+	}
+	var events types.JournalEvents
+	if err = json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return nil, err
+	}
+	return events.Events, nil
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/f1bonacc1/process-compose/src/api"
+	"github.com/f1bonacc1/process-compose/src/config"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+// execBufferSize is the size of the stdin chunks sent to the server.
+const execBufferSize = 32 * 1024
+
+func (p *PcClient) execProcess(ctx context.Context, name string, req *types.ExecRequest, streams *types.ExecStreams) (int, error) {
+	q := url.Values{}
+	q.Set("name", name)
+	q["command"] = req.Command
+	if req.Tty {
+		q.Set("tty", "true")
+		q.Set("rows", strconv.Itoa(int(req.Size.Rows)))
+		q.Set("cols", strconv.Itoa(int(req.Size.Cols)))
+	}
+	wsURL := fmt.Sprintf("ws://%s/process/exec/ws?%s", p.address, q.Encode())
+
+	dialer := *websocket.DefaultDialer
+	if p.address == "unix" {
+		sockPath := p.logger.socketPath
+		dialer.NetDialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", sockPath)
+		}
+	}
+
+	var header http.Header
+	if token := config.GetApiToken(); token != "" {
+		header = make(http.Header)
+		header.Set(config.TokenHeader, token)
+	}
+
+	ws, resp, err := dialer.DialContext(ctx, wsURL, header)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusUnauthorized {
+			log.Fatal().Msgf("authentication failed: invalid or missing %s", config.EnvVarApiToken)
+		}
+		if resp != nil && resp.StatusCode == http.StatusBadRequest {
+			return -1, parseErrorResponse(resp, "exec in process "+name)
+		}
+		return -1, fmt.Errorf("failed to connect to %s: %w", p.address, err)
+	}
+	defer ws.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = ws.Close()
+		case <-done:
+		}
+	}()
+
+	var mtx sync.Mutex
+	send := func(msg api.ExecMessage) error {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return ws.WriteJSON(msg)
+	}
+	if streams.Stdin != nil {
+		go sendExecStdin(streams.Stdin, send)
+	}
+	if streams.Resize != nil {
+		go func() {
+			for {
+				select {
+				case <-done:
+					return
+				case size := <-streams.Resize:
+					if send(api.ExecMessage{Resize: &size}) != nil {
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	for {
+		var msg api.ExecMessage
+		if err = ws.ReadJSON(&msg); err != nil {
+			if ctx.Err() != nil {
+				return -1, ctx.Err()
+			}
+			return -1, fmt.Errorf("exec in process %s interrupted: %w", name, err)
+		}
+		switch {
+		case msg.Error != "":
+			return -1, errors.New(msg.Error)
+		case msg.ExitCode != nil:
+			return *msg.ExitCode, nil
+		case msg.Stream == api.ExecStreamStderr && streams.Stderr != nil:
+			_, _ = streams.Stderr.Write(msg.Data)
+		case streams.Stdout != nil:
+			_, _ = streams.Stdout.Write(msg.Data)
+		}
+	}
+}
+
+func sendExecStdin(stdin io.Reader, send func(api.ExecMessage) error) {
+	buf := make([]byte, execBufferSize)
+	for {
+		n, err := stdin.Read(buf)
+		if n > 0 {
+			if send(api.ExecMessage{Data: buf[:n]}) != nil {
+				return
+			}
+		}
+		if err != nil {
+			_ = send(api.ExecMessage{EOF: true})
+			return
+		}
+	}
+}
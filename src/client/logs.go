@@ -23,6 +23,8 @@ type LogClient struct {
 	socketPath       string
 	address          string
 	PrintProcessName bool
+	// Filter selects the log lines by level and fields, see pclog.ParseLogFilter.
+	Filter string
 }
 
 func NewLogClient(address, socketPath string) *LogClient {
@@ -40,6 +42,9 @@ func (l *LogClient) ReadProcessLogs(name string, offset int, follow bool, fn fun
 	q.Set("name", name)
 	q.Set("offset", strconv.Itoa(offset))
 	q.Set("follow", strconv.FormatBool(follow))
+	if l.Filter != "" {
+		q.Set("filter", l.Filter)
+	}
 	url := fmt.Sprintf("ws://%s/process/logs/ws?%s", l.address, q.Encode())
 	log.Info().Msgf("Connecting to %s", url)
 
@@ -64,6 +69,9 @@ func (l *LogClient) ReadProcessLogs(name string, offset int, follow bool, fn fun
 		if resp != nil && resp.StatusCode == http.StatusUnauthorized {
 			log.Fatal().Msgf("authentication failed: invalid or missing %s", config.EnvVarApiToken)
 		}
+		if resp != nil && resp.StatusCode == http.StatusBadRequest {
+			return done, parseErrorResponse(resp, "read process logs")
+		}
 		log.Error().Msgf("failed to dial to %s error: %v", url, err)
 		return done, fmt.Errorf("failed to connect to %s: %w", l.address, err)
 	}
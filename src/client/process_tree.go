@@ -0,0 +1,31 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func (p *PcClient) getProcessTree(name string) (*types.ProcessTree, error) {
+	url := fmt.Sprintf("http://%s/process/tree/%s", p.address, escapePathSegment(name))
+	resp, err := p.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseErrorResponse(resp, fmt.Sprintf("get process %s tree", name))
+	}
+	var tree types.ProcessTree
+	if err = json.NewDecoder(resp.Body).Decode(&tree); err != nil {
+		return nil, err
+	}
+	return &tree, nil
+}
+
+func (p *PcClient) signalProcessTree(name string, pid int, sig int) error {
+	url := fmt.Sprintf("http://%s/process/tree/signal/%s/%d/%d", p.address, escapePathSegment(name), pid, sig)
+	return p.doAction(http.MethodPatch, url, fmt.Sprintf("send signal to pid %d of %s", pid, name))
+}
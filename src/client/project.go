@@ -74,6 +74,23 @@ func (p *PcClient) updateProject(project *types.Project) (map[string]string, err
 	return nil, parseErrorResponse(resp, "update project")
 }
 
+func (p *PcClient) saveProjectState() (string, error) {
+	url := fmt.Sprintf("http://%s/project/state/save", p.address)
+	resp, err := p.client.Post(url, "application/json", nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", parseErrorResponse(resp, "save project state")
+	}
+	saved := map[string]string{}
+	if err = json.NewDecoder(resp.Body).Decode(&saved); err != nil {
+		return "", err
+	}
+	return saved["path"], nil
+}
+
 func (p *PcClient) reloadProject() (map[string]string, error) {
 	url := fmt.Sprintf("http://%s/project/configuration", p.address)
 	resp, err := p.client.Post(url, "application/json", nil)
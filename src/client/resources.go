@@ -0,0 +1,35 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func (p *PcClient) getResourceHistory(name string, since time.Time) ([]types.ResourceHistory, error) {
+	q := url.Values{}
+	if name != "" {
+		q.Set("process", name)
+	}
+	if !since.IsZero() {
+		q.Set("since", since.Format(time.RFC3339Nano))
+	}
+	url := fmt.Sprintf("http://%s/resources?%s", p.address, q.Encode())
+	resp, err := p.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseErrorResponse(resp, "get resource history")
+	}
+	var histories types.ResourceHistories
+	if err = json.NewDecoder(resp.Body).Decode(&histories); err != nil {
+		return nil, err
+	}
+	return histories.Histories, nil
+}
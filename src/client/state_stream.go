@@ -91,3 +91,37 @@ func (p *PcClient) SubscribeProcessStates(ctx context.Context, names ...string)
 
 	return out, nil
 }
+
+// RegisterStateObserver forwards the state stream of the server to the
+// observer, starting with a snapshot of every process, until it is
+// unregistered. Registering an observer again replaces its stream.
+func (p *PcClient) RegisterStateObserver(o types.StateObserver) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.observersMtx.Lock()
+	if stop, ok := p.observers[o.UniqueID()]; ok {
+		stop()
+	}
+	p.observers[o.UniqueID()] = cancel
+	p.observersMtx.Unlock()
+
+	events, err := p.SubscribeProcessStates(ctx)
+	if err != nil {
+		log.Err(err).Msgf("failed to subscribe %s to process states", o.UniqueID())
+		return
+	}
+	go func() {
+		for ev := range events {
+			o.Notify(ev)
+		}
+	}()
+}
+
+// UnregisterStateObserver stops the state stream of the observer.
+func (p *PcClient) UnregisterStateObserver(o types.StateObserver) {
+	p.observersMtx.Lock()
+	defer p.observersMtx.Unlock()
+	if stop, ok := p.observers[o.UniqueID()]; ok {
+		stop()
+		delete(p.observers, o.UniqueID())
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/f1bonacc1/process-compose/src/loader"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var convertOutPath string
+
+// convertCmd represents the convert command
+var convertCmd = &cobra.Command{
+	Use:   "convert [FILE]",
+	Short: "Convert a docker-compose file or a Procfile into a process-compose file",
+	Long: `Convert a docker-compose file or a Procfile into a process-compose file.
+The settings without process-compose equivalent are reported and left out.
+
+The docker-compose services with a build section run locally from their build
+context, the other services run as containers of their image.
+
+A project can be started from a docker-compose file or a Procfile without
+converting it, e.g. process-compose -f Procfile up.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		project, warnings, err := loader.ImportFile(args[0])
+		if err != nil {
+			log.Fatal().Err(err).Msgf("Failed to convert %s", args[0])
+		}
+		for _, warning := range warnings {
+			_, _ = fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		}
+		var buf bytes.Buffer
+		_, _ = fmt.Fprintf(&buf, "# Converted from %s\n", filepath.Base(args[0]))
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err = encoder.Encode(project); err != nil {
+			log.Fatal().Err(err).Msg("Failed to marshal the project")
+		}
+		data := buf.Bytes()
+		if convertOutPath == "" {
+			_, _ = os.Stdout.Write(data)
+			return
+		}
+		if err = os.WriteFile(convertOutPath, data, 0644); err != nil {
+			log.Fatal().Err(err).Msgf("Failed to write %s", convertOutPath)
+		}
+		fmt.Printf("Converted %s to %s\n", args[0], convertOutPath)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(convertCmd)
+	convertCmd.Flags().StringVarP(&convertOutPath, "output", "o", "", "Output file (default stdout)")
+}
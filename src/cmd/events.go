@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/config"
+	"github.com/f1bonacc1/process-compose/src/journal"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const eventsPollInterval = time.Second
+
+var (
+	eventsProcess     string
+	eventsSince       string
+	eventsLimit       int
+	eventsFollow      bool
+	eventsProjectName string
+)
+
+// eventsCmd represents the events command
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Show the process lifecycle history recorded in the event journal",
+	Long: `Show the process lifecycle history recorded in the event journal.
+
+The events are queried from the running process-compose. When none is
+reachable, they are read from the journal file left behind by the last run:
+the one given with --journal-file, or else the default journal of the project
+named with --project-name, or of the project in the current directory.`,
+	Annotations: map[string]string{clientModeAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		since, err := types.ParseJournalSince(eventsSince, time.Now())
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid --since")
+		}
+		getEvents := getClient().GetEvents
+		query := types.JournalQuery{
+			Process: eventsProcess,
+			Since:   since,
+			Limit:   eventsLimit,
+		}
+		for {
+			events, err := getEvents(query)
+			var opErr *net.OpError
+			if errors.As(err, &opErr) {
+				getEvents = journalReader()
+				events, err = getEvents(query)
+			}
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to get events")
+			}
+			for i := range events {
+				printEvent(&events[i])
+			}
+			if !eventsFollow {
+				return
+			}
+			if len(events) > 0 {
+				query.AfterSeq = events[len(events)-1].Seq
+			}
+			// Only the initial batch is bounded by --since and --limit.
+			query.Since = time.Time{}
+			query.Limit = 0
+			time.Sleep(eventsPollInterval)
+		}
+	},
+}
+
+// journalReader reads the events straight from the journal file, for when no
+// server is reachable.
+func journalReader() func(types.JournalQuery) ([]types.JournalEvent, error) {
+	path := *pcFlags.JournalFile
+	if path == "" {
+		var err error
+		if path, err = config.GetJournalPath(eventsProjectName); err != nil {
+			log.Fatal().Err(err).Msg("failed to locate the event journal")
+		}
+	}
+	log.Info().Msgf("No server is reachable, reading the event journal %s", path)
+	return func(query types.JournalQuery) ([]types.JournalEvent, error) {
+		return journal.ReadFile(path, query)
+	}
+}
+
+func printEvent(ev *types.JournalEvent) {
+	if *pcFlags.OutputFormat == "json" {
+		b, err := json.Marshal(ev)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to marshal event")
+		}
+		fmt.Fprintln(os.Stdout, string(b))
+		return
+	}
+	fmt.Printf("%s  %-20s %-14s %s\n", ev.Time.Local().Format(time.DateTime), ev.Process, ev.Type, describeEvent(ev))
+}
+
+func describeEvent(ev *types.JournalEvent) string {
+	var parts []string
+	switch ev.Type {
+	case types.JournalEventStatus:
+		parts = append(parts, ev.Status)
+	case types.JournalEventHealth:
+		parts = append(parts, ev.Health)
+	case types.JournalEventExit:
+		if ev.ExitCode != nil {
+			parts = append(parts, fmt.Sprintf("exit code %d", *ev.ExitCode))
+		}
+	case types.JournalEventProbeFailure:
+		parts = append(parts, ev.Details["probe"]+" probe")
+		if ev.Details["fatal"] == "true" {
+			parts = append(parts, "(fatal)")
+		}
+	case types.JournalEventWatchTrigger:
+		parts = append(parts, ev.Details["path"])
+	case types.JournalEventHookFailure:
+		parts = append(parts, ev.Details["hook"]+" hook")
+	}
+	if ev.Reason != "" {
+		parts = append(parts, ev.Reason)
+	}
+	return strings.Join(parts, " ")
+}
+
+func init() {
+	rootCmd.AddCommand(eventsCmd)
+
+	eventsCmd.Flags().StringVar(&eventsProcess, "process", "", "Show only the events of this process")
+	eventsCmd.Flags().StringVar(&eventsSince, "since", "", "Show events newer than a duration (e.g. 1h) or an RFC 3339 time")
+	eventsCmd.Flags().IntVarP(&eventsLimit, "limit", "n", 0, "Show only the most recent events (0 for all)")
+	eventsCmd.Flags().BoolVarP(&eventsFollow, "follow", "f", false, "Keep printing new events as they are recorded")
+	eventsCmd.Flags().StringVar(pcFlags.JournalFile, "journal-file", *pcFlags.JournalFile, "Journal file to read when no server is reachable (env: "+config.EnvVarJournalFile+")")
+	eventsCmd.Flags().StringVar(&eventsProjectName, "project-name", "", "Project whose default journal is read when no server is reachable (default: the project in the current directory)")
+	eventsCmd.Flags().StringVarP(pcFlags.OutputFormat, "output", "o", *pcFlags.OutputFormat, "Output format. One of: (json)")
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var execTty bool
+
+// execCmd represents the exec command
+var execCmd = &cobra.Command{
+	Use:   "exec PROCESS [flags] -- COMMAND [args]",
+	Short: "Run a command in the environment of a process",
+	Long: `Run a one-off command with the environment, env_file and working directory of PROCESS,
+as it is configured in the running Process Compose server, with std(in|out|err) attached.
+The command runs on the server, whether PROCESS is running or not, and its exit code is the exit code of exec.`,
+	Example: `  process-compose exec db -- psql
+  process-compose exec api -- ./manage.py migrate`,
+	Args:        cobra.MinimumNArgs(2),
+	Annotations: map[string]string{clientModeAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		if cmd.ArgsLenAtDash() != 1 {
+			fmt.Fprintln(os.Stderr, "Separate the command from PROCESS and the process-compose arguments with: --")
+			os.Exit(1)
+		}
+		os.Exit(runExec(cmd, args[0], args[1:]))
+	},
+}
+
+// runExec runs the command in the process environment and returns its exit
+// code, once the terminal is restored.
+func runExec(cmd *cobra.Command, name string, command []string) int {
+	stdinFd, stdoutFd := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !cmd.Flags().Changed("tty") {
+		execTty = term.IsTerminal(stdinFd) && term.IsTerminal(stdoutFd)
+	}
+	req := &types.ExecRequest{Command: command, Tty: execTty}
+	streams := &types.ExecStreams{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+	if execTty && term.IsTerminal(stdoutFd) {
+		req.Size = terminalSize(stdoutFd)
+		resize, stop := notifyResize(stdoutFd)
+		defer stop()
+		streams.Resize = resize
+	}
+	if execTty && term.IsTerminal(stdinFd) {
+		state, err := term.MakeRaw(stdinFd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to set the terminal to raw mode: %v\n", err)
+			return 1
+		}
+		defer func() { _ = term.Restore(stdinFd, state) }()
+	}
+	code, err := getClient().ExecProcess(context.Background(), name, req, streams)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "exec in %s failed: %v\r\n", name, err)
+		return 1
+	}
+	return code
+}
+
+func terminalSize(fd int) types.TerminalSize {
+	cols, rows, err := term.GetSize(fd)
+	if err != nil {
+		return types.TerminalSize{Rows: 24, Cols: 80}
+	}
+	return types.TerminalSize{Rows: uint16(rows), Cols: uint16(cols)}
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+	execCmd.Flags().BoolVarP(&execTty, "tty", "t", false, "run the command in a pseudo-terminal (default: when stdin and stdout are terminals)")
+	execCmd.Flags().StringVarP(pcFlags.Address, "address", "a", *pcFlags.Address, "address of the target process compose server")
+}
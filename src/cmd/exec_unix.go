@@ -0,0 +1,38 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+// notifyResize sends the size of the terminal on every SIGWINCH, until stop is
+// called.
+func notifyResize(fd int) (<-chan types.TerminalSize, func()) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGWINCH)
+	sizes := make(chan types.TerminalSize, 1)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-sigs:
+				select {
+				case sizes <- terminalSize(fd):
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+	return sizes, func() {
+		signal.Stop(sigs)
+		close(done)
+	}
+}
@@ -0,0 +1,8 @@
+package cmd
+
+import "github.com/f1bonacc1/process-compose/src/types"
+
+// notifyResize doesn't track the terminal size on Windows.
+func notifyResize(_ int) (<-chan types.TerminalSize, func()) {
+	return nil, func() {}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/config"
+	"github.com/f1bonacc1/process-compose/src/export"
+	"github.com/f1bonacc1/process-compose/src/loader"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat  = export.FormatSystemd
+	exportOutDir  string
+	exportOptions export.Options
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the project as systemd units, a supervisord configuration or launchd property lists",
+	Long: `Export the project as systemd units, a supervisord configuration or launchd property lists.
+The processes are exported in dependency order, with their command, working
+directory, environment, restart policy, shutdown signal and timeout, and
+success exit codes. The settings without equivalent are reported and left out.
+
+systemd: a service per process, and a target starting them all. The
+dependencies become Requires= and After=.
+
+supervisord: a program per process, in a group named after the project. The
+priorities follow the dependency order.
+
+launchd-plist: a property list per process.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts.WithTuiDisabled(true)
+		project, err := loader.Load(opts)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load project")
+		}
+		files, warnings, err := export.Export(project, exportFormat, exportOptions)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to export project")
+		}
+		for _, warning := range warnings {
+			_, _ = fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		}
+		if exportOutDir == "" {
+			for i, file := range files {
+				if len(files) > 1 {
+					if i > 0 {
+						fmt.Println()
+					}
+					fmt.Printf("==> %s <==\n", file.Name)
+				}
+				_, _ = os.Stdout.Write(file.Content)
+			}
+			return
+		}
+		if err = os.MkdirAll(exportOutDir, 0755); err != nil {
+			log.Fatal().Err(err).Msgf("Failed to create %s", exportOutDir)
+		}
+		for _, file := range files {
+			path := filepath.Join(exportOutDir, file.Name)
+			if err = os.WriteFile(path, file.Content, 0644); err != nil {
+				log.Fatal().Err(err).Msgf("Failed to write %s", path)
+			}
+			fmt.Printf("Exported %s\n", path)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVar(&exportFormat, "format", exportFormat, "export format: "+strings.Join(export.Formats, ", "))
+	exportCmd.Flags().StringVarP(&exportOutDir, "output", "o", "", "output directory (default stdout)")
+	exportCmd.Flags().StringVar(&exportOptions.Name, "name", "", "prefix of the service names (default the project name)")
+	exportCmd.Flags().StringVar(&exportOptions.User, "user", "", "user to run the services as")
+	exportCmd.Flags().StringArrayVarP(&opts.FileNames, "config", "f", config.GetConfigDefault(), "path to config files to load (env: "+config.EnvVarNameConfig+")")
+	exportCmd.Flags().StringArrayVarP(&opts.EnvFileNames, "env", "e", []string{".env"}, "path to env files to load")
+	exportCmd.Flags().StringSliceVar(&opts.Profiles, "profile", config.GetProfilesDefault(), "activate the profiles, comma separated (env: "+config.EnvVarNameProfiles+")")
+}
@@ -15,7 +15,11 @@ import (
 var graphCmd = &cobra.Command{
 	Use:   "graph",
 	Short: "Display process dependency graph",
-	Long:  `Output the process dependency graph in various formats (mermaid, json, yaml, ascii)`,
+	Long: `Output the process dependency graph in various formats (ascii, mermaid, dot, svg, json, yaml).
+The ascii, dot and svg formats include the current status of each process and the condition of each dependency.`,
+	Example: `  process-compose graph
+  process-compose graph --format dot | dot -Tpng -o graph.png
+  process-compose graph --format svg > graph.svg`,
 	Run: func(cmd *cobra.Command, args []string) {
 		graph, err := getClient().GetDependencyGraph()
 		if err != nil {
@@ -29,6 +33,10 @@ func printGraph(graph *types.DependencyGraph) {
 	switch *pcFlags.OutputFormat {
 	case "mermaid":
 		fmt.Println(graph.ToMermaid())
+	case "dot":
+		fmt.Print(graph.ToDOT())
+	case "svg":
+		fmt.Print(graph.ToSVG())
 	case "json":
 		b, err := json.MarshalIndent(graph, "", "  ")
 		if err != nil {
@@ -101,5 +109,5 @@ func printNode(graph *types.DependencyGraph, nodeName string, prefix string, con
 func init() {
 	rootCmd.AddCommand(graphCmd)
 	graphCmd.Flags().StringVarP(pcFlags.OutputFormat, "format", "f", "",
-		"Output format: mermaid, json, yaml, or ascii (default)")
+		"Output format: mermaid, dot, svg, json, yaml, or ascii (default)")
 }
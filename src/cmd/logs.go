@@ -15,16 +15,20 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var logsAll bool
+
 // logsCmd represents the logs command
 var logsCmd = &cobra.Command{
 	Use:   "logs [PROCESS]",
 	Short: "Fetch the logs of a process(es). For multiple processes, separate them with a comma (proc1,proc2)",
+	Long: `Fetch the logs of a process(es). For multiple processes, separate them with a comma (proc1,proc2).
+The logs of multiple processes are interleaved in the order they were written, and prefixed with the process name.`,
 	Args: func(cmd *cobra.Command, args []string) error {
-		if *pcFlags.Namespace != "" {
+		if *pcFlags.Namespace != "" || logsAll {
 			return nil
 		}
 		if err := cobra.MinimumNArgs(1)(cmd, args); err != nil {
-			return errors.New("requires at least one process name argument when --namespace is not set")
+			return errors.New("requires at least one process name argument when --namespace or --all are not set")
 		}
 		return nil
 	},
@@ -33,7 +37,16 @@ var logsCmd = &cobra.Command{
 		if len(args) > 0 {
 			name = args[0]
 		}
-		if *pcFlags.Namespace != "" {
+		if logsAll {
+			names, err := getClient().GetProcessesName()
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to list processes")
+			}
+			if len(names) == 0 {
+				log.Fatal().Msg("No processes in the project")
+			}
+			name = strings.Join(names, ",")
+		} else if *pcFlags.Namespace != "" {
 			processes := []string{}
 			if name != "" {
 				processes = strings.Split(name, ",")
@@ -58,16 +71,25 @@ var logsCmd = &cobra.Command{
 			}
 		}
 
+		if _, err := pclog.ParseLogFilter(*pcFlags.LogFilter); err != nil {
+			log.Fatal().Err(err).Msg("invalid --filter")
+		}
+
 		notifyInteractiveProcesses(name)
 
 		printProcessName := !*pcFlags.IsRawLogOutput && len(strings.Split(name, ",")) > 1
 		ct := pclog.NewColorTracker()
 		logger := getLogClient()
+		logger.Filter = *pcFlags.LogFilter
 		fn := func(message api.LogMessage) {
+			line := message.Message
+			if message.Record != nil && !*pcFlags.IsRawLogOutput {
+				line = pclog.ColorizeLevel(message.Record.Level, line)
+			}
 			if printProcessName {
-				fmt.Printf("[%s\t] %s\n", ct.GetColor(message.ProcessName)(message.ProcessName), message.Message)
+				fmt.Printf("[%s\t] %s\n", ct.GetColor(message.ProcessName)(message.ProcessName), line)
 			} else {
-				fmt.Printf("%s\n", message.Message)
+				fmt.Printf("%s\n", line)
 			}
 		}
 		done, err := logger.ReadProcessLogs(name, *pcFlags.LogTailLength, *pcFlags.LogFollow, fn)
@@ -94,6 +116,8 @@ func init() {
 	logsCmd.Flags().BoolVarP(pcFlags.LogFollow, "follow", "f", *pcFlags.LogFollow, "Follow log output")
 	logsCmd.Flags().BoolVar(pcFlags.IsRawLogOutput, "raw-log", *pcFlags.IsRawLogOutput, "If set, don't format the multi process log output to include the process name")
 	logsCmd.Flags().IntVarP(pcFlags.LogTailLength, "tail", "n", *pcFlags.LogTailLength, "Number of lines to show from the end of the logs")
+	logsCmd.Flags().StringVar(pcFlags.LogFilter, "filter", *pcFlags.LogFilter, "Show only the lines of structured logs that match all the conditions (e.g. 'level>=warn request_id=abc')")
+	logsCmd.Flags().BoolVar(&logsAll, "all", false, "Logs all the processes in the project")
 	logsCmd.Flags().StringVarP(pcFlags.Namespace, "namespace", "N", *pcFlags.Namespace, "Logs all the processes in the given namespace")
 }
 
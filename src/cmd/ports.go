@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+
 	"github.com/rs/zerolog/log"
 
 	"github.com/spf13/cobra"
@@ -21,6 +22,9 @@ var portsCmd = &cobra.Command{
 		}
 		log.Info().Msgf("Process %s TCP ports: %v", name, ports.TcpPorts)
 		fmt.Printf("Process %s TCP ports: %v\n", name, ports.TcpPorts)
+		if len(ports.Assigned) > 0 {
+			fmt.Printf("Process %s assigned ports: %s\n", name, ports.FormatAssigned())
+		}
 	},
 }
 
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	treeSignalPid int
+	treeSignal    int
+)
+
+// processTreeCmd represents the process tree command
+var processTreeCmd = &cobra.Command{
+	Use:   "tree [PROCESS]",
+	Short: "Show the OS processes spawned by a process, or by all the running processes",
+	Long: `Show the OS processes spawned by a process, directly or not, with their CPU and memory usage and the ports they listen on.
+Without a process name, the trees of all the running processes are shown.
+
+Use --pid to send a signal to a single OS process of the tree, rather than to the whole process group.`,
+	Example: `  process-compose process tree web
+  process-compose process tree web --pid 4250 --signal 15`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pcClient := getClient()
+		if treeSignalPid != 0 {
+			if len(args) == 0 {
+				log.Fatal().Msg("the process of the pid to signal is required")
+			}
+			if err := pcClient.SignalProcessTree(args[0], treeSignalPid, treeSignal); err != nil {
+				log.Fatal().Err(err).Msgf("failed to signal pid %d of %s", treeSignalPid, args[0])
+			}
+			fmt.Printf("Sent signal %d to pid %d of %s\n", treeSignal, treeSignalPid, args[0])
+			return
+		}
+
+		names := args
+		all := len(names) == 0
+		if all {
+			states, err := pcClient.GetRemoteProcessesState()
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to list processes")
+			}
+			for _, state := range states.States {
+				if state.IsRunning {
+					names = append(names, state.Name)
+				}
+			}
+			slices.Sort(names)
+		}
+		trees := make([]types.ProcessTree, 0, len(names))
+		for _, name := range names {
+			tree, err := pcClient.GetProcessTree(name)
+			if err != nil && all {
+				// container processes and processes that exited meanwhile
+				// have no tree, which shouldn't hide the trees of the others
+				log.Warn().Err(err).Msgf("skipping process %s tree", name)
+				continue
+			}
+			if err != nil {
+				log.Fatal().Err(err).Msgf("failed to get process %s tree", name)
+			}
+			trees = append(trees, *tree)
+		}
+		printProcessTrees(os.Stdout, trees)
+	},
+}
+
+func printProcessTrees(out io.Writer, trees []types.ProcessTree) {
+	if *pcFlags.OutputFormat == "json" {
+		b, err := json.MarshalIndent(trees, "", "\t")
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to marshal process trees")
+		}
+		fmt.Fprintln(out, string(b))
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROCESS\tPID\tCPU\tMEM\tPORTS\tCOMMAND")
+	for _, tree := range trees {
+		printProcessTreeNode(w, tree.Name, &tree.Root, "", "")
+	}
+	w.Flush()
+}
+
+// printProcessTreeNode prints the node and its descendants, prefixing the
+// command of each with the branches leading to it.
+func printProcessTreeNode(w io.Writer, name string, node *types.ProcessTreeNode, branch, indent string) {
+	fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s%s\n", name, node.Pid, formatTreeCPU(node.CPU), formatTreeMem(node.Mem), formatTreePorts(node), branch, node.Command)
+	for i := range node.Children {
+		if i == len(node.Children)-1 {
+			printProcessTreeNode(w, "", &node.Children[i], indent+"└─ ", indent+"   ")
+		} else {
+			printProcessTreeNode(w, "", &node.Children[i], indent+"├─ ", indent+"│  ")
+		}
+	}
+}
+
+func formatTreeCPU(cpu float64) string {
+	if cpu < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", cpu)
+}
+
+func formatTreeMem(mem int64) string {
+	if mem < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f MiB", float64(mem)/(1024*1024))
+}
+
+func formatTreePorts(node *types.ProcessTreeNode) string {
+	var ports []string
+	for _, port := range node.TcpPorts {
+		ports = append(ports, fmt.Sprint(port))
+	}
+	for _, port := range node.UdpPorts {
+		ports = append(ports, fmt.Sprintf("%d/udp", port))
+	}
+	if len(ports) == 0 {
+		return "-"
+	}
+	return strings.Join(ports, ",")
+}
+
+func init() {
+	processCmd.AddCommand(processTreeCmd)
+
+	processTreeCmd.Flags().IntVar(&treeSignalPid, "pid", 0, "Send a signal to this OS process of the tree instead of showing the tree")
+	processTreeCmd.Flags().IntVar(&treeSignal, "signal", 15, "Signal number to send with --pid")
+	processTreeCmd.Flags().StringVarP(pcFlags.OutputFormat, "output", "o", *pcFlags.OutputFormat, "Output format. One of: (json)")
+}
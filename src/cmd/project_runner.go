@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 
 	"github.com/f1bonacc1/process-compose/src/app"
 	"github.com/f1bonacc1/process-compose/src/config"
+	"github.com/f1bonacc1/process-compose/src/journal"
 	"github.com/f1bonacc1/process-compose/src/loader"
+	"github.com/f1bonacc1/process-compose/src/notify"
 	"github.com/f1bonacc1/process-compose/src/tui"
 	"github.com/f1bonacc1/process-compose/src/types"
 	"github.com/f1bonacc1/process-compose/src/util"
@@ -19,6 +22,7 @@ func getProjectRunner(process []string, noDeps bool, mainProcess string, mainPro
 	opts.DisableDotenv(*pcFlags.DisableDotEnv)
 	opts.WithTuiDisabled(!*pcFlags.IsTuiEnabled)
 	opts.WithOrderedShutdown(*pcFlags.IsOrderedShutdown)
+	opts.WithRequestedProcesses(process)
 
 	project, err := loader.Load(opts)
 	if err != nil {
@@ -42,7 +46,10 @@ func getProjectRunner(process []string, noDeps bool, mainProcess string, mainPro
 			WithSlowRefRate(*pcFlags.SlowRefreshRate).
 			WithRecursiveMetrics(*pcFlags.WithRecursiveMetrics).
 			WithNoWatch(*pcFlags.NoWatch).
-			WithAdmitters(opts.GetAdmitters()...),
+			WithAdmitters(opts.GetAdmitters()...).
+			WithJournal(openJournal(project)).
+			WithStateFile(getStateFile(project)).
+			WithRestoreState(*pcFlags.RestoreState),
 	)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to initialize the project")
@@ -50,6 +57,51 @@ func getProjectRunner(process []string, noDeps bool, mainProcess string, mainPro
 	return runner, project
 }
 
+// openJournal opens the event journal, unless disabled. A journal that
+// cannot be opened is reported and the project runs without one.
+func openJournal(project *types.Project) *journal.Journal {
+	if *pcFlags.NoJournal || opts.DryRun {
+		return nil
+	}
+	path := *pcFlags.JournalFile
+	if path == "" {
+		var err error
+		if path, err = config.GetJournalPath(project.Name); err != nil {
+			log.Warn().Err(err).Msg("Event journal disabled")
+			return nil
+		}
+	}
+	j, err := journal.Open(path)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Event journal disabled: failed to open %s", path)
+		return nil
+	}
+	log.Info().Msgf("Recording events to %s", path)
+	return j
+}
+
+// getStateFile returns the path of the saved project state. It is empty, which
+// disables saving, when the default path can't be resolved or on a dry run.
+func getStateFile(project *types.Project) string {
+	if opts.DryRun {
+		return ""
+	}
+	if *pcFlags.StateFile != "" {
+		path, err := filepath.Abs(*pcFlags.StateFile)
+		if err != nil {
+			log.Warn().Err(err).Msg("Project state will not be saved")
+			return ""
+		}
+		return path
+	}
+	path, err := config.GetStatePath(project.Name)
+	if err != nil {
+		log.Warn().Err(err).Msg("Project state will not be saved")
+		return ""
+	}
+	return path
+}
+
 func runProject(runner *app.ProjectRunner) error {
 	var err error
 	if *pcFlags.IsTuiEnabled {
@@ -60,6 +112,7 @@ func runProject(runner *app.ProjectRunner) error {
 	if *pcFlags.KeepProjectOn {
 		runner.WaitForProjectShutdown()
 	}
+	runner.CloseJournal()
 	os.Remove(*pcFlags.UnixSocketPath)
 	log.Info().Msg("Thank you for using process-compose")
 	return err
@@ -133,7 +186,14 @@ func startTui(runner app.IProject, isAsync bool) {
 		tui.WithDisabledExitConfirm(settings.DisableExitConfirmation),
 		tui.WithDetachOnSuccess(*pcFlags.DetachOnSuccess),
 		tui.LoadExtraShortCutsPaths(*pcFlags.ShortcutPaths),
+		tui.WithLogPanes(settings.LogPanes.Processes, settings.LogPanes.IsTiled),
+	}
+
+	notifyMethod, err := notify.ParseMethod(settings.Notifications.Method)
+	if err != nil {
+		log.Warn().Err(err).Msg("Desktop notifications are disabled")
 	}
+	tuiOptions = append(tuiOptions, tui.WithNotifications(notifyMethod, settings.Notifications.MinInterval))
 
 	tuiOptions = append(tuiOptions,
 		ternary(pcFlags.PcThemeChanged, tui.WithTheme(*pcFlags.PcTheme), tui.WithTheme(settings.Theme)))
@@ -27,7 +27,11 @@ import (
 )
 
 var (
-	opts      *loader.LoaderOptions
+	// opts is created before the init functions, which register the loader
+	// flags of the subcommands.
+	opts = &loader.LoaderOptions{
+		FileNames: []string{},
+	}
 	logFile   *os.File
 	updateMsg chan string // receives update notification from background check
 
@@ -123,10 +127,6 @@ func Execute() {
 }
 
 func init() {
-	opts = &loader.LoaderOptions{
-		FileNames: []string{},
-	}
-
 	nsAdmitter := &admitter.NamespaceAdmitter{}
 	opts.AddAdmitter(nsAdmitter)
 
@@ -144,6 +144,7 @@ func init() {
 	rootCmd.Flags().StringArrayVarP(&opts.FileNames, "config", "f", config.GetConfigDefault(), "path to config files to load (env: "+config.EnvVarNameConfig+")")
 	rootCmd.Flags().StringArrayVarP(&opts.EnvFileNames, "env", "e", []string{".env"}, "path to env files to load")
 	rootCmd.Flags().StringArrayVarP(&nsAdmitter.EnabledNamespaces, "namespace", "n", config.GetNamespaceDefault(), "run only specified namespaces (default all, env: "+config.EnvVarNameNamespace+")")
+	rootCmd.Flags().StringSliceVar(&opts.Profiles, "profile", config.GetProfilesDefault(), "activate the profiles, comma separated (env: "+config.EnvVarNameProfiles+")")
 	rootCmd.PersistentFlags().StringVarP(pcFlags.LogFile, flagLogFile, "L", *pcFlags.LogFile, "Specify the log file path (env: "+config.LogPathEnvVarName+")")
 	rootCmd.PersistentFlags().BoolVar(pcFlags.IsReadOnlyMode, "read-only", *pcFlags.IsReadOnlyMode, "enable read-only mode (env: "+config.EnvVarReadOnlyMode+")")
 	rootCmd.Flags().BoolVar(pcFlags.DisableDotEnv, "disable-dotenv", *pcFlags.DisableDotEnv, "disable .env file loading (env: "+config.EnvVarDisableDotEnv+"=1)")
@@ -151,6 +152,10 @@ func init() {
 	rootCmd.Flags().BoolVar(pcFlags.LogsTruncate, "logs-truncate", *pcFlags.LogsTruncate, "truncate process logs buffer on startup")
 	rootCmd.Flags().BoolVar(pcFlags.WithRecursiveMetrics, "recursive-metrics", *pcFlags.WithRecursiveMetrics, "collect metrics recursively (env: "+config.EnvVarWithRecursiveMetrics+")")
 	rootCmd.Flags().BoolVar(pcFlags.NoWatch, "no-watch", *pcFlags.NoWatch, "disable file watching, ignoring all 'watch' configuration (env: "+config.EnvVarNoWatch+")")
+	rootCmd.Flags().StringVar(pcFlags.JournalFile, "journal-file", *pcFlags.JournalFile, "path of the event journal (default under the XDG state directory, env: "+config.EnvVarJournalFile+")")
+	rootCmd.Flags().BoolVar(pcFlags.NoJournal, "no-journal", *pcFlags.NoJournal, "disable the event journal (env: "+config.EnvVarNoJournal+")")
+	rootCmd.Flags().StringVar(pcFlags.StateFile, "state-file", *pcFlags.StateFile, "path of the saved project state (default under the XDG state directory, env: "+config.EnvVarStateFile+")")
+	rootCmd.Flags().BoolVar(pcFlags.RestoreState, "restore-state", *pcFlags.RestoreState, "restore the replicas, restart counts and stopped processes saved on the last exit (env: "+config.EnvVarRestoreState+")")
 	rootCmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "validate the config and exit")
 	rootCmd.PersistentFlags().StringVar(pcFlags.ApiTokenPath, "token-file", *pcFlags.ApiTokenPath, "path to a file containing the API token (env: "+config.EnvVarApiTokenPath+")")
 	rootCmd.PersistentFlags().BoolVar(pcFlags.LogNoColor, "log-no-color", *pcFlags.LogNoColor, "disable color output in the log file (env: "+config.EnvVarLogNoColor+")")
@@ -49,6 +49,7 @@ func init() {
 
 	runCmd.Flags().BoolVarP(pcFlags.NoDependencies, "no-deps", "", *pcFlags.NoDependencies, "don't start dependent processes")
 	runCmd.Flags().AddFlag(rootCmd.Flags().Lookup("config"))
+	runCmd.Flags().AddFlag(rootCmd.Flags().Lookup("profile"))
 	runCmd.Flags().AddFlag(rootCmd.Flags().Lookup("disable-dotenv"))
 
 }
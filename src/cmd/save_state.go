@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// saveStateCmd represents the save-state command
+var saveStateCmd = &cobra.Command{
+	Use:   "save-state",
+	Short: "Save the runtime state of the project, to be restored with up --restore-state",
+	Long: `Save the runtime state of the running project to its state file: the replica
+counts, the restart counts and the processes stopped by the user. The state is
+also saved when Process Compose exits.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := getClient().SaveProjectState()
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to save project state")
+		}
+		fmt.Printf("Project state saved to %s\n", path)
+	},
+}
+
+func init() {
+	projectCmd.AddCommand(saveStateCmd)
+}
@@ -27,6 +27,7 @@ func init() {
 
 	upCmd.Flags().BoolVarP(pcFlags.NoDependencies, "no-deps", "", *pcFlags.NoDependencies, "don't start dependent processes")
 	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("namespace"))
+	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("profile"))
 	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("config"))
 	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("env"))
 	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("ref-rate"))
@@ -41,6 +42,10 @@ func init() {
 	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("dry-run"))
 	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("recursive-metrics"))
 	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("no-watch"))
+	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("journal-file"))
+	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("no-journal"))
+	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("state-file"))
+	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("restore-state"))
 	upCmd.Flags().AddFlag(commonFlags.Lookup(flagReverse))
 	upCmd.Flags().AddFlag(commonFlags.Lookup(flagSort))
 	upCmd.Flags().AddFlag(commonFlags.Lookup(flagTheme))
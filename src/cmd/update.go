@@ -87,6 +87,7 @@ func init() {
 	updateCmd.Flags().StringArrayVarP(&opts.FileNames, "config", "f", config.GetConfigDefault(), "path to config files to load (env: "+config.EnvVarNameConfig+")")
 	updateCmd.Flags().BoolVarP(&updateVerboseOutput, "verbose", "v", updateVerboseOutput, "verbose output")
 	updateCmd.Flags().AddFlag(rootCmd.Flags().Lookup("namespace"))
+	updateCmd.Flags().AddFlag(rootCmd.Flags().Lookup("profile"))
 	if os.Getenv(config.EnvVarNameConfig) == "" {
 		_ = updateCmd.MarkFlagRequired("config")
 	}
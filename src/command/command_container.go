@@ -0,0 +1,330 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/container"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// containerCallTimeout bounds the engine calls, except the image pull, the
+	// wait and the logs.
+	containerCallTimeout = 30 * time.Second
+	// containerLogsTimeout bounds the wait for the end of the logs of a stopped
+	// container.
+	containerLogsTimeout = 5 * time.Second
+)
+
+// ContainerCmd runs a process as a container. The container is created when
+// the command starts, and removed once it stopped, so that every start begins
+// with a fresh container.
+type ContainerCmd struct {
+	client *container.Client
+	name   string
+	spec   container.CreateRequest
+	pull   string
+
+	stdout, stderr io.Writer
+	// closers are the pipes returned by StdoutPipe and StderrPipe, closed at
+	// the end of the logs.
+	closers  []io.Closer
+	logsDone chan struct{}
+	cancel   context.CancelFunc
+
+	mtx      sync.Mutex
+	id       string
+	pid      int
+	stopped  bool
+	exitCode int
+}
+
+// BuildContainerCommand returns the command of the container named name, to
+// create with spec. pull is the image pull policy.
+func BuildContainerCommand(client *container.Client, name string, spec container.CreateRequest, pull string) *ContainerCmd {
+	return &ContainerCmd{
+		client:   client,
+		name:     name,
+		spec:     spec,
+		pull:     pull,
+		stdout:   io.Discard,
+		stderr:   io.Discard,
+		exitCode: -1,
+	}
+}
+
+func (c *ContainerCmd) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	if err := c.start(ctx); err != nil {
+		cancel()
+		c.closeOutput()
+		return err
+	}
+	logs, err := c.client.Logs(ctx, c.id)
+	if err != nil {
+		log.Err(err).Msgf("Failed to follow the logs of container %s", c.name)
+		c.closeOutput()
+		return nil
+	}
+	c.logsDone = make(chan struct{})
+	go func() {
+		defer close(c.logsDone)
+		defer c.closeOutput()
+		defer logs.Close()
+		if err := container.Demux(logs, c.stdout, c.stderr); err != nil && ctx.Err() == nil {
+			log.Err(err).Msgf("Failed to read the logs of container %s", c.name)
+		}
+	}()
+	return nil
+}
+
+func (c *ContainerCmd) start(ctx context.Context) error {
+	if err := c.ensureImage(ctx); err != nil {
+		return err
+	}
+	callCtx, cancel := context.WithTimeout(ctx, containerCallTimeout)
+	defer cancel()
+	if err := c.removeStale(callCtx); err != nil {
+		return err
+	}
+	id, err := c.client.Create(callCtx, c.name, &c.spec)
+	if err != nil {
+		return fmt.Errorf("failed to create container %s: %w", c.name, err)
+	}
+	c.mtx.Lock()
+	c.id = id
+	stopped := c.stopped
+	c.mtx.Unlock()
+	if stopped {
+		_ = c.client.Remove(callCtx, id)
+		return fmt.Errorf("container %s stopped before it started", c.name)
+	}
+	if err = c.client.Start(callCtx, id); err != nil {
+		_ = c.client.Remove(callCtx, id)
+		return fmt.Errorf("failed to start container %s: %w", c.name, err)
+	}
+	if info, err := c.client.Inspect(callCtx, id); err == nil {
+		c.mtx.Lock()
+		c.pid = info.State.Pid
+		c.mtx.Unlock()
+	}
+	return nil
+}
+
+func (c *ContainerCmd) ensureImage(ctx context.Context) error {
+	switch c.pull {
+	case container.PullNever:
+		return nil
+	case container.PullAlways:
+	default:
+		callCtx, cancel := context.WithTimeout(ctx, containerCallTimeout)
+		defer cancel()
+		exists, err := c.client.ImageExists(callCtx, c.spec.Image)
+		if err != nil {
+			return fmt.Errorf("failed to look up image %s: %w", c.spec.Image, err)
+		}
+		if exists {
+			return nil
+		}
+	}
+	err := c.client.PullImage(ctx, c.spec.Image, func(status string) {
+		_, _ = fmt.Fprintln(c.stdout, status)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to pull image %s: %w", c.spec.Image, err)
+	}
+	return nil
+}
+
+// removeStale removes the container left with the same name by a previous run,
+// which stopped without cleaning up. Containers not created by process-compose
+// are left alone.
+func (c *ContainerCmd) removeStale(ctx context.Context) error {
+	info, err := c.client.Inspect(ctx, c.name)
+	if errors.Is(err, container.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to inspect container %s: %w", c.name, err)
+	}
+	if info.Config.Labels[container.LabelManaged] != "true" {
+		return fmt.Errorf("container name %s is already used by a container not managed by process-compose", c.name)
+	}
+	log.Info().Msgf("Removing stale container %s", c.name)
+	return c.client.Remove(ctx, info.ID)
+}
+
+func (c *ContainerCmd) closeOutput() {
+	for _, closer := range c.closers {
+		_ = closer.Close()
+	}
+	c.closers = nil
+}
+
+// Wait waits for the container to stop, and removes it.
+func (c *ContainerCmd) Wait() error {
+	c.mtx.Lock()
+	id := c.id
+	c.mtx.Unlock()
+	if id == "" {
+		return errors.New("container not started")
+	}
+	code, err := c.client.Wait(context.Background(), id)
+	if c.logsDone != nil {
+		select {
+		case <-c.logsDone:
+		case <-time.After(containerLogsTimeout):
+		}
+	}
+	c.cancel()
+	c.mtx.Lock()
+	c.exitCode = code
+	c.mtx.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), containerCallTimeout)
+	defer cancel()
+	if rmErr := c.client.Remove(ctx, id); rmErr != nil {
+		log.Err(rmErr).Msgf("Failed to remove container %s", c.name)
+	}
+	c.client.Close()
+	return err
+}
+
+func (c *ContainerCmd) Run() error {
+	if err := c.Start(); err != nil {
+		return err
+	}
+	return c.Wait()
+}
+
+// Stop sends the signal to the main process of the container. A container that
+// isn't created yet won't start.
+func (c *ContainerCmd) Stop(sig int, _ bool) error {
+	c.mtx.Lock()
+	id := c.id
+	c.stopped = true
+	c.mtx.Unlock()
+	if id == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), containerCallTimeout)
+	defer cancel()
+	err := c.client.Kill(ctx, id, strconv.Itoa(sig))
+	// The container already stopped.
+	var engineErr *container.EngineError
+	if errors.As(err, &engineErr) && engineErr.StatusCode == http.StatusConflict {
+		return nil
+	}
+	if errors.Is(err, container.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (c *ContainerCmd) ExitCode() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.exitCode
+}
+
+// Pid returns the host PID of the main process of the container, or 0 when the
+// engine doesn't report it.
+func (c *ContainerCmd) Pid() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.pid
+}
+
+// PublishedPorts returns the host ports the container is published on.
+func (c *ContainerCmd) PublishedPorts() (tcp, udp []uint16, err error) {
+	c.mtx.Lock()
+	id := c.id
+	c.mtx.Unlock()
+	if id == "" {
+		return nil, nil, fmt.Errorf("container %s is not running", c.name)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), containerCallTimeout)
+	defer cancel()
+	info, err := c.client.Inspect(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	tcp, udp = info.PublishedPorts()
+	return tcp, udp, nil
+}
+
+func (c *ContainerCmd) StdoutPipe() (io.ReadCloser, error) {
+	r, w := io.Pipe()
+	c.stdout = w
+	c.closers = append(c.closers, w)
+	return r, nil
+}
+
+func (c *ContainerCmd) StderrPipe() (io.ReadCloser, error) {
+	r, w := io.Pipe()
+	c.stderr = w
+	c.closers = append(c.closers, w)
+	return r, nil
+}
+
+func (c *ContainerCmd) StdinPipe() (io.WriteCloser, error) {
+	return nil, errors.New("stdin is not supported for containers")
+}
+
+func (c *ContainerCmd) AttachIo() {
+	c.stdout = os.Stdout
+	c.stderr = os.Stderr
+}
+
+// SetCmdArgs is a no-op: the container is not a child of process-compose.
+func (c *ContainerCmd) SetCmdArgs() {}
+
+func (c *ContainerCmd) SetEnv(env []string) {
+	c.spec.Env = env
+}
+
+// SetDir resolves the relative sources of the bind mounts against dir. The
+// sources that aren't paths are named volumes.
+func (c *ContainerCmd) SetDir(dir string) {
+	for i, bind := range c.spec.HostConfig.Binds {
+		source, rest, _ := strings.Cut(bind, ":")
+		if !strings.HasPrefix(source, ".") {
+			continue
+		}
+		abs, err := filepath.Abs(filepath.Join(dir, source))
+		if err != nil {
+			log.Err(err).Msgf("Failed to resolve the volume %s of container %s", bind, c.name)
+			continue
+		}
+		c.spec.HostConfig.Binds[i] = abs + ":" + rest
+	}
+}
+
+func (c *ContainerCmd) Output() ([]byte, error) {
+	var stdout bytes.Buffer
+	c.stdout = &stdout
+	err := c.Run()
+	return stdout.Bytes(), err
+}
+
+func (c *ContainerCmd) CombinedOutput() ([]byte, error) {
+	var output bytes.Buffer
+	c.stdout, c.stderr = &output, &output
+	err := c.Run()
+	return output.Bytes(), err
+}
+
+func (c *ContainerCmd) GetPty() *os.File {
+	return nil
+}
@@ -14,6 +14,11 @@ func (c *CmdWrapper) GetPty() *os.File {
 	return nil
 }
 
+// ExecCmd returns the OS command, to be set up further before it is started.
+func (c *CmdWrapper) ExecCmd() *exec.Cmd {
+	return c.cmd
+}
+
 func (c *CmdWrapper) Start() error {
 	return c.cmd.Start()
 }
@@ -40,5 +40,8 @@ func (c *CmdWrapper) Stop(sig int, parentOnly bool) error {
 }
 
 func (c *CmdWrapper) SetCmdArgs() {
-	c.cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if c.cmd.SysProcAttr == nil {
+		c.cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	c.cmd.SysProcAttr.Setpgid = true
 }
@@ -36,6 +36,7 @@ const (
 	EnvVarNameTui              = "PC_DISABLE_TUI"
 	EnvVarNameConfig           = "PC_CONFIG_FILES"
 	EnvVarNameNamespace        = "PC_NAMESPACES"
+	EnvVarNameProfiles         = "PC_PROFILES"
 	EnvVarNameShortcuts        = "PC_SHORTCUTS_FILES"
 	EnvVarNameRecipes          = "PC_RECIPE_FILES"
 	EnvVarNameNoServer         = "PC_NO_SERVER"
@@ -50,6 +51,10 @@ const (
 	EnvVarNameAddress          = "PC_ADDRESS"
 	EnvVarLogNoColor           = "PC_LOG_NO_COLOR"
 	EnvVarNoWatch              = "PC_NO_WATCH"
+	EnvVarJournalFile          = "PC_JOURNAL_FILE"
+	EnvVarNoJournal            = "PC_NO_JOURNAL"
+	EnvVarStateFile            = "PC_STATE_FILE"
+	EnvVarRestoreState         = "PC_RESTORE_STATE"
 )
 
 // Flags represents PC configuration flags.
@@ -63,6 +68,7 @@ type Flags struct {
 	LogLength            *int
 	LogFollow            *bool
 	LogTailLength        *int
+	LogFilter            *string
 	IsRawLogOutput       *bool
 	IsTuiEnabled         *bool
 	Command              *string
@@ -96,6 +102,10 @@ type Flags struct {
 	ApiTokenPath         *string
 	LogNoColor           *bool
 	NoWatch              *bool
+	JournalFile          *string
+	NoJournal            *bool
+	StateFile            *string
+	RestoreState         *bool
 }
 
 // NewFlags returns new configuration flags.
@@ -111,6 +121,7 @@ func NewFlags() *Flags {
 		LogFile:              new(GetLogFilePath()),
 		LogFollow:            new(false),
 		LogTailLength:        new(math.MaxInt),
+		LogFilter:            new(""),
 		NoDependencies:       new(false),
 		HideDisabled:         new(getHideDisabledDefault()),
 		SortColumn:           new(DefaultSortColumn),
@@ -139,5 +150,9 @@ func NewFlags() *Flags {
 		ApiTokenPath:         new(getApiTokenPathDefault()),
 		LogNoColor:           new(getLogNoColorDefault()),
 		NoWatch:              new(getNoWatchEnvDefault()),
+		JournalFile:          new(getJournalFileDefault()),
+		NoJournal:            new(getNoJournalEnvDefault()),
+		StateFile:            new(getStateFileDefault()),
+		RestoreState:         new(getRestoreStateEnvDefault()),
 	}
 }
@@ -68,6 +68,13 @@ const (
 	EnvVarApiToken     = "PC_API_TOKEN"
 	EnvVarApiTokenPath = "PC_API_TOKEN_PATH"
 	TokenHeader        = "X-PC-Token-Key"
+	// TokenCookie carries the token of the browsers logged in to the web UI,
+	// which can't set TokenHeader.
+	TokenCookie = "pc_token"
+	// UIRequestHeader marks the API requests of the web UI. The browsers don't
+	// send it across origins without asking the server, so the requests
+	// authenticated by TokenCookie must carry it, or come from the same origin.
+	UIRequestHeader    = "X-PC-UI-Request"
 	pcConfigEnv        = "PROC_COMP_CONFIG"
 	LogPathEnvVarName  = "PC_LOG_FILE"
 	LogLevelEnvVarName = "PC_LOG_LEVEL"
@@ -78,6 +85,8 @@ const (
 	settingsFileName   = "settings.yaml"
 	configHome         = "process-compose"
 	recipesPath        = "recipes"
+	journalPath        = "journal"
+	statePath          = "state"
 	DonateURL          = "https://github.com/sponsors/f1bonacc1"
 	DiscussionsURL     = "https://github.com/f1bonacc1/process-compose/discussions"
 )
@@ -211,6 +220,14 @@ func GetNamespaceDefault() []string {
 	return []string{}
 }
 
+func GetProfilesDefault() []string {
+	val, found := os.LookupEnv(EnvVarNameProfiles)
+	if found {
+		return strings.Split(val, ",")
+	}
+	return []string{}
+}
+
 func CreateProcCompHome() string {
 	if env := os.Getenv(pcConfigEnv); env != "" {
 		return env
@@ -302,6 +319,45 @@ func GetRecipesDir() string {
 	return recipesDir
 }
 
+// GetJournalPath returns the default event journal location under the XDG
+// state directory. Projects are told apart by name or, when unnamed, by their
+// working directory.
+func GetJournalPath(projectName string) (string, error) {
+	key, err := projectStateKey(projectName)
+	if err != nil {
+		return "", err
+	}
+	return xdg.StateFile(filepath.Join(configHome, journalPath, key+".jsonl"))
+}
+
+// GetStatePath returns the default location of the saved project state,
+// next to the event journal.
+func GetStatePath(projectName string) (string, error) {
+	key, err := projectStateKey(projectName)
+	if err != nil {
+		return "", err
+	}
+	return xdg.StateFile(filepath.Join(configHome, statePath, key+".json"))
+}
+
+func projectStateKey(projectName string) (string, error) {
+	key := projectName
+	if key == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		key = wd
+	}
+	return strings.Trim(strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		}
+		return '_'
+	}, key), "_"), nil
+}
+
 func getUser() string {
 	usr, err := user.Current()
 	if err != nil {
@@ -362,3 +418,21 @@ func getNoWatchEnvDefault() bool {
 	_, found := os.LookupEnv(EnvVarNoWatch)
 	return found
 }
+
+func getJournalFileDefault() string {
+	return os.Getenv(EnvVarJournalFile)
+}
+
+func getNoJournalEnvDefault() bool {
+	_, found := os.LookupEnv(EnvVarNoJournal)
+	return found
+}
+
+func getStateFileDefault() string {
+	return os.Getenv(EnvVarStateFile)
+}
+
+func getRestoreStateEnvDefault() bool {
+	_, found := os.LookupEnv(EnvVarRestoreState)
+	return found
+}
@@ -1,9 +1,11 @@
 package config
 
 import (
+	"github.com/f1bonacc1/process-compose/src/notify"
 	"github.com/rs/zerolog/log"
 	"gopkg.in/yaml.v3"
 	"os"
+	"time"
 )
 
 type (
@@ -11,10 +13,20 @@ type (
 		By         string `yaml:"by"`
 		IsReversed bool   `yaml:"isReversed"`
 	}
+	Notifications struct {
+		Method      string        `yaml:"method"`
+		MinInterval time.Duration `yaml:"min_interval"`
+	}
+	LogPanes struct {
+		Processes []string `yaml:"processes"`
+		IsTiled   bool     `yaml:"isTiled"`
+	}
 	Settings struct {
-		Theme                   string `yaml:"theme"`
-		Sort                    Sort   `yaml:"sort"`
-		DisableExitConfirmation bool   `yaml:"disable_exit_confirmation"`
+		Theme                   string        `yaml:"theme"`
+		Sort                    Sort          `yaml:"sort"`
+		DisableExitConfirmation bool          `yaml:"disable_exit_confirmation"`
+		Notifications           Notifications `yaml:"notifications"`
+		LogPanes                LogPanes      `yaml:"log_panes"`
 	}
 )
 
@@ -25,6 +37,10 @@ func NewSettings() *Settings {
 			By:         DefaultSortColumn,
 			IsReversed: false,
 		},
+		Notifications: Notifications{
+			Method:      string(notify.MethodAuto),
+			MinInterval: notify.DefaultMinInterval,
+		},
 	}
 }
 
@@ -0,0 +1,293 @@
+// Package container is a minimal client of the Docker Engine API, which Podman
+// serves as well. It covers what process-compose needs to run a process as a
+// container: images, the container lifecycle and its logs.
+package container
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// LabelManaged marks the containers created by process-compose. Only those
+	// are replaced when their name is taken.
+	LabelManaged = "dev.process-compose.managed"
+	// LabelProcess holds the name of the process of the container.
+	LabelProcess = "dev.process-compose.process"
+
+	defaultDockerSocket = "/var/run/docker.sock"
+)
+
+// The image pull policies.
+const (
+	// PullMissing pulls the image when it is not present locally.
+	PullMissing = "missing"
+	// PullAlways pulls the image every time the container starts.
+	PullAlways = "always"
+	// PullNever never pulls the image.
+	PullNever = "never"
+)
+
+// ErrNotFound is returned for the containers and images that don't exist.
+var ErrNotFound = errors.New("not found")
+
+// Client calls the engine API over a unix socket or TCP.
+type Client struct {
+	http *http.Client
+	// base is the scheme and host of the API URLs.
+	base string
+}
+
+// DefaultHost returns DOCKER_HOST, or the Docker socket, or the rootless Podman
+// socket when only that one exists.
+func DefaultHost() string {
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		return host
+	}
+	if _, err := os.Stat(defaultDockerSocket); err == nil {
+		return "unix://" + defaultDockerSocket
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		podman := filepath.Join(dir, "podman", "podman.sock")
+		if _, err := os.Stat(podman); err == nil {
+			return "unix://" + podman
+		}
+	}
+	return "unix://" + defaultDockerSocket
+}
+
+// NewClient returns a client of the engine at host: unix:///path/to/socket,
+// tcp://host:port or http://host:port. An empty host is DefaultHost.
+func NewClient(host string) (*Client, error) {
+	if host == "" {
+		host = DefaultHost()
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid container engine host '%s': %w", host, err)
+	}
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+			},
+		}
+		return &Client{http: &http.Client{Transport: transport}, base: "http://engine"}, nil
+	case "tcp", "http":
+		return &Client{http: &http.Client{}, base: "http://" + u.Host}, nil
+	}
+	return nil, fmt.Errorf("unsupported container engine host '%s': expected unix://, tcp:// or http://", host)
+}
+
+// Close closes the idle connections to the engine.
+func (c *Client) Close() {
+	c.http.CloseIdleConnections()
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	u := c.base + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("container engine unreachable: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+	return resp, nil
+}
+
+// call sends a request and decodes its JSON response into out, when not nil.
+func (c *Client) call(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	resp, err := c.do(ctx, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func responseError(resp *http.Response) error {
+	var msg struct {
+		Message string `json:"message"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	text := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &msg) == nil && msg.Message != "" {
+		text = msg.Message
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrNotFound, text)
+	}
+	return &EngineError{StatusCode: resp.StatusCode, Message: text}
+}
+
+// EngineError is an error response of the engine.
+type EngineError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *EngineError) Error() string {
+	return fmt.Sprintf("container engine error %d: %s", e.StatusCode, e.Message)
+}
+
+// ImageExists reports whether the image is present locally.
+func (c *Client) ImageExists(ctx context.Context, image string) (bool, error) {
+	err := c.call(ctx, http.MethodGet, "/images/"+image+"/json", nil, nil, nil)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// PullImage pulls the image, and reports the progress messages to progress.
+func (c *Client) PullImage(ctx context.Context, image string, progress func(string)) error {
+	name, tag := splitImageTag(image)
+	query := url.Values{"fromImage": {name}, "tag": {tag}}
+	resp, err := c.do(ctx, http.MethodPost, "/images/create", query, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// The pull errors come within the progress stream, with a 200 status.
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var msg struct {
+			Status string `json:"status"`
+			ID     string `json:"id"`
+			Error  string `json:"error"`
+		}
+		if err = decoder.Decode(&msg); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if msg.Error != "" {
+			return fmt.Errorf("failed to pull %s: %s", image, msg.Error)
+		}
+		if progress != nil && msg.Status != "" && msg.ID == "" {
+			progress(msg.Status)
+		}
+	}
+}
+
+// splitImageTag splits the tag out of an image reference, ignoring the port of
+// the registry: localhost:5000/app:1.0 is localhost:5000/app and 1.0. Digests
+// are kept in the name.
+func splitImageTag(image string) (string, string) {
+	if strings.Contains(image, "@") {
+		return image, ""
+	}
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return image, "latest"
+	}
+	return image[:i], image[i+1:]
+}
+
+// Create creates a container and returns its ID.
+func (c *Client) Create(ctx context.Context, name string, spec *CreateRequest) (string, error) {
+	var created struct {
+		ID       string   `json:"Id"`
+		Warnings []string `json:"Warnings"`
+	}
+	query := url.Values{}
+	if name != "" {
+		query.Set("name", name)
+	}
+	if err := c.call(ctx, http.MethodPost, "/containers/create", query, spec, &created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+func (c *Client) Start(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodPost, "/containers/"+id+"/start", nil, nil, nil)
+}
+
+// Wait blocks until the container stops, and returns its exit code.
+func (c *Client) Wait(ctx context.Context, id string) (int, error) {
+	var result struct {
+		StatusCode int `json:"StatusCode"`
+		Error      *struct {
+			Message string `json:"Message"`
+		} `json:"Error"`
+	}
+	if err := c.call(ctx, http.MethodPost, "/containers/"+id+"/wait", nil, nil, &result); err != nil {
+		return -1, err
+	}
+	if result.Error != nil && result.Error.Message != "" {
+		return result.StatusCode, errors.New(result.Error.Message)
+	}
+	return result.StatusCode, nil
+}
+
+// Kill sends a signal, by number or name, to the main process of the container.
+func (c *Client) Kill(ctx context.Context, id, signal string) error {
+	return c.call(ctx, http.MethodPost, "/containers/"+id+"/kill", url.Values{"signal": {signal}}, nil, nil)
+}
+
+// Remove removes the container, even if it is running. Removing a container
+// that doesn't exist isn't an error.
+func (c *Client) Remove(ctx context.Context, id string) error {
+	err := c.call(ctx, http.MethodDelete, "/containers/"+id, url.Values{"force": {"true"}}, nil, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// Inspect returns the state of a container, by ID or name.
+func (c *Client) Inspect(ctx context.Context, id string) (*ContainerInfo, error) {
+	var info ContainerInfo
+	if err := c.call(ctx, http.MethodGet, "/containers/"+id+"/json", nil, nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Logs follows the stdout and stderr of the container, from its start. The
+// stream is multiplexed, see Demux.
+func (c *Client) Logs(ctx context.Context, id string) (io.ReadCloser, error) {
+	query := url.Values{"follow": {"true"}, "stdout": {"true"}, "stderr": {"true"}}
+	resp, err := c.do(ctx, http.MethodGet, "/containers/"+id+"/logs", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
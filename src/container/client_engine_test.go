@@ -0,0 +1,93 @@
+package container_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/container"
+	"github.com/f1bonacc1/process-compose/src/container/containertest"
+)
+
+func TestClient_Lifecycle(t *testing.T) {
+	engine, err := containertest.NewEngine(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Close()
+	client, err := container.NewClient(engine.Host())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	if exists, err := client.ImageExists(ctx, "alpine:3"); err != nil || exists {
+		t.Fatalf("ImageExists() = %v, %v, want false", exists, err)
+	}
+	var progress []string
+	if err = client.PullImage(ctx, "alpine:3", func(status string) { progress = append(progress, status) }); err != nil {
+		t.Fatal(err)
+	}
+	if len(progress) != 2 {
+		t.Errorf("pull progress = %q, want the messages without layer ID", progress)
+	}
+	if exists, err := client.ImageExists(ctx, "alpine:3"); err != nil || !exists {
+		t.Fatalf("ImageExists() after pull = %v, %v, want true", exists, err)
+	}
+
+	id, err := client.Create(ctx, "web", &container.CreateRequest{Image: "alpine:3", Cmd: []string{"fail", "boom", "3"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.Create(ctx, "web", &container.CreateRequest{Image: "alpine:3"}); err == nil {
+		t.Error("Create() reused a taken name")
+	}
+	if err = client.Start(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	logs, err := client.Logs(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	if err = container.Demux(logs, &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	_ = logs.Close()
+	if stderr.String() != "boom\n" || stdout.Len() != 0 {
+		t.Errorf("logs = %q, %q, want boom on stderr", stdout.String(), stderr.String())
+	}
+	if code, err := client.Wait(ctx, id); err != nil || code != 3 {
+		t.Errorf("Wait() = %d, %v, want 3", code, err)
+	}
+
+	var engineErr *container.EngineError
+	if err = client.Kill(ctx, id, "15"); !errors.As(err, &engineErr) || engineErr.StatusCode != 409 {
+		t.Errorf("Kill() of a stopped container = %v, want a conflict", err)
+	}
+	if err = client.Remove(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if err = client.Remove(ctx, id); err != nil {
+		t.Errorf("Remove() of a removed container = %v, want nil", err)
+	}
+	if _, err = client.Inspect(ctx, id); !errors.Is(err, container.ErrNotFound) {
+		t.Errorf("Inspect() of a removed container = %v, want ErrNotFound", err)
+	}
+	if _, err = client.Logs(ctx, id); !errors.Is(err, container.ErrNotFound) {
+		t.Errorf("Logs() of a removed container = %v, want ErrNotFound", err)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	for _, host := range []string{"unix:///var/run/docker.sock", "tcp://127.0.0.1:2375", "http://localhost:2375"} {
+		if _, err := container.NewClient(host); err != nil {
+			t.Errorf("NewClient(%q) = %v", host, err)
+		}
+	}
+	if _, err := container.NewClient("ssh://host"); err == nil {
+		t.Error("NewClient() accepted an ssh host")
+	}
+}
@@ -0,0 +1,70 @@
+package container
+
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+	"testing"
+)
+
+func TestSplitImageTag(t *testing.T) {
+	tests := []struct {
+		image, name, tag string
+	}{
+		{"alpine", "alpine", "latest"},
+		{"alpine:3.20", "alpine", "3.20"},
+		{"ghcr.io/org/app:v1", "ghcr.io/org/app", "v1"},
+		{"localhost:5000/app", "localhost:5000/app", "latest"},
+		{"localhost:5000/app:1.0", "localhost:5000/app", "1.0"},
+		{"alpine@sha256:abc", "alpine@sha256:abc", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			name, tag := splitImageTag(tt.image)
+			if name != tt.name || tag != tt.tag {
+				t.Errorf("splitImageTag(%q) = %q, %q, want %q, %q", tt.image, name, tag, tt.name, tt.tag)
+			}
+		})
+	}
+}
+
+func frame(stream byte, data string) []byte {
+	header := make([]byte, 8)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(data)))
+	return append(header, data...)
+}
+
+func TestDemux(t *testing.T) {
+	var stream []byte
+	stream = append(stream, frame(streamStdout, "hello\n")...)
+	stream = append(stream, frame(streamStderr, "oops\n")...)
+	stream = append(stream, frame(streamStdout, "world\n")...)
+	var stdout, stderr bytes.Buffer
+	if err := Demux(bytes.NewReader(stream), &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.String() != "hello\nworld\n" || stderr.String() != "oops\n" {
+		t.Errorf("stdout = %q, stderr = %q", stdout.String(), stderr.String())
+	}
+
+	if err := Demux(bytes.NewReader(frame(3, "x")), &stdout, &stderr); err == nil {
+		t.Error("Demux() accepted an unknown stream")
+	}
+	if err := Demux(bytes.NewReader(frame(streamStdout, "truncated")[:10]), &stdout, &stderr); err == nil {
+		t.Error("Demux() accepted a truncated frame")
+	}
+}
+
+func TestContainerInfo_PublishedPorts(t *testing.T) {
+	var info ContainerInfo
+	info.NetworkSettings.Ports = map[string][]PortBinding{
+		"80/tcp":   {{HostIP: "0.0.0.0", HostPort: "8080"}, {HostIP: "::", HostPort: "8080"}},
+		"53/udp":   {{HostIP: "0.0.0.0", HostPort: "5353"}},
+		"9000/tcp": nil,
+	}
+	tcp, udp := info.PublishedPorts()
+	if !slices.Equal(tcp, []uint16{8080}) || !slices.Equal(udp, []uint16{5353}) {
+		t.Errorf("PublishedPorts() = %v, %v, want [8080], [5353]", tcp, udp)
+	}
+}
@@ -0,0 +1,390 @@
+// Package containertest provides a fake container engine, serving the part of
+// the Docker Engine API the container client uses, for tests.
+//
+// The containers don't run anything: their command is a script of the fake
+// engine.
+//
+//	echo ARGS...      writes ARGS to stdout and exits with 0
+//	env               writes the environment to stdout and exits with 0
+//	fail MSG CODE     writes MSG to stderr and exits with CODE
+//	sleep             runs until it is killed, and exits with 128 + signal
+package containertest
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/f1bonacc1/process-compose/src/container"
+)
+
+// Engine is a fake container engine listening on a unix socket.
+type Engine struct {
+	server *httptest.Server
+	socket string
+
+	mtx        sync.Mutex
+	images     map[string]bool
+	containers map[string]*Container
+	nextID     int
+	nextPort   int
+	pulls      int
+}
+
+// Container is a container of the fake engine.
+type Container struct {
+	ID       string
+	Name     string
+	Spec     container.CreateRequest
+	Running  bool
+	ExitCode int
+	Signals  []string
+	// Removed reports whether the container was removed.
+	Removed bool
+
+	ports  map[string][]container.PortBinding
+	output []frame
+	done   chan struct{}
+}
+
+type frame struct {
+	stream byte
+	data   string
+}
+
+// NewEngine starts a fake engine with the images, in a socket of dir.
+func NewEngine(dir string, images ...string) (*Engine, error) {
+	e := &Engine{
+		images:     map[string]bool{},
+		containers: map[string]*Container{},
+		nextPort:   32768,
+	}
+	for _, image := range images {
+		e.images[image] = true
+	}
+	e.socket = filepath.Join(dir, "engine.sock")
+	listener, err := net.Listen("unix", e.socket)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /images/{name...}", e.inspectImage)
+	mux.HandleFunc("POST /images/create", e.pullImage)
+	mux.HandleFunc("POST /containers/create", e.create)
+	mux.HandleFunc("POST /containers/{id}/start", e.start)
+	mux.HandleFunc("POST /containers/{id}/wait", e.wait)
+	mux.HandleFunc("POST /containers/{id}/kill", e.kill)
+	mux.HandleFunc("DELETE /containers/{id}", e.remove)
+	mux.HandleFunc("GET /containers/{id}/json", e.inspect)
+	mux.HandleFunc("GET /containers/{id}/logs", e.logs)
+	e.server = httptest.NewUnstartedServer(mux)
+	e.server.Listener = listener
+	e.server.Start()
+	return e, nil
+}
+
+// Host returns the address of the engine, for container.NewClient.
+func (e *Engine) Host() string {
+	return "unix://" + e.socket
+}
+
+func (e *Engine) Close() {
+	e.server.CloseClientConnections()
+	e.server.Close()
+	_ = os.Remove(e.socket)
+}
+
+// Container returns a copy of the container, by ID or name. The removed
+// containers are found as well, after the others.
+func (e *Engine) Container(idOrName string) (Container, bool) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	if c := e.lookup(idOrName); c != nil {
+		return *c, true
+	}
+	for _, c := range e.containers {
+		if c.ID == idOrName || c.Name == idOrName {
+			return *c, true
+		}
+	}
+	return Container{}, false
+}
+
+// Pulls returns the number of image pulls.
+func (e *Engine) Pulls() int {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	return e.pulls
+}
+
+// AddContainer adds a stopped container, as left by another run or tool.
+func (e *Engine) AddContainer(name string, labels map[string]string) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.nextID++
+	c := &Container{
+		ID:   fmt.Sprintf("c%d", e.nextID),
+		Name: name,
+		done: make(chan struct{}),
+	}
+	c.Spec.Labels = labels
+	close(c.done)
+	e.containers[c.ID] = c
+}
+
+// lookup returns the container by ID or name. The engine lock must be held.
+func (e *Engine) lookup(idOrName string) *Container {
+	if c, ok := e.containers[idOrName]; ok && !c.Removed {
+		return c
+	}
+	for _, c := range e.containers {
+		if c.Name == idOrName && !c.Removed {
+			return c
+		}
+	}
+	return nil
+}
+
+func (e *Engine) container(w http.ResponseWriter, r *http.Request) *Container {
+	c := e.lookup(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: "+r.PathValue("id"))
+	}
+	return c
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": msg})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (e *Engine) inspectImage(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSuffix(r.PathValue("name"), "/json")
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	if !e.images[name] {
+		writeError(w, http.StatusNotFound, "No such image: "+name)
+		return
+	}
+	writeJSON(w, map[string]string{"Id": name})
+}
+
+func (e *Engine) pullImage(w http.ResponseWriter, r *http.Request) {
+	image := r.URL.Query().Get("fromImage") + ":" + r.URL.Query().Get("tag")
+	e.mtx.Lock()
+	e.images[image] = true
+	e.pulls++
+	e.mtx.Unlock()
+	writeJSON(w, map[string]string{"status": "Pulling from " + image})
+	writeJSON(w, map[string]string{"status": "Pulling fs layer", "id": "layer"})
+	writeJSON(w, map[string]string{"status": "Status: Downloaded newer image for " + image})
+}
+
+func (e *Engine) create(w http.ResponseWriter, r *http.Request) {
+	var spec container.CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	name := r.URL.Query().Get("name")
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	if !e.images[spec.Image] {
+		writeError(w, http.StatusNotFound, "No such image: "+spec.Image)
+		return
+	}
+	if name != "" && e.lookup(name) != nil {
+		writeError(w, http.StatusConflict, "The container name "+name+" is already in use")
+		return
+	}
+	e.nextID++
+	c := &Container{
+		ID:    fmt.Sprintf("c%d", e.nextID),
+		Name:  name,
+		Spec:  spec,
+		ports: map[string][]container.PortBinding{},
+		done:  make(chan struct{}),
+	}
+	for key, bindings := range spec.HostConfig.PortBindings {
+		for _, binding := range bindings {
+			if binding.HostPort == "" {
+				binding.HostPort = strconv.Itoa(e.nextPort)
+				e.nextPort++
+			}
+			if binding.HostIP == "" {
+				binding.HostIP = "0.0.0.0"
+			}
+			c.ports[key] = append(c.ports[key], binding)
+		}
+	}
+	e.containers[c.ID] = c
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, map[string]any{"Id": c.ID, "Warnings": []string{}})
+}
+
+func (e *Engine) start(w http.ResponseWriter, r *http.Request) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	c := e.container(w, r)
+	if c == nil {
+		return
+	}
+	c.Running = true
+	c.run()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// run plays the script of the container. The engine lock must be held.
+func (c *Container) run() {
+	args := append(append([]string{}, c.Spec.Entrypoint...), c.Spec.Cmd...)
+	if len(args) == 0 {
+		args = []string{"sleep"}
+	}
+	switch args[0] {
+	case "echo":
+		c.output = append(c.output, frame{1, strings.Join(args[1:], " ") + "\n"})
+		c.exit(0)
+	case "env":
+		for _, env := range c.Spec.Env {
+			c.output = append(c.output, frame{1, env + "\n"})
+		}
+		c.exit(0)
+	case "fail":
+		code := 1
+		if len(args) > 2 {
+			code, _ = strconv.Atoi(args[2])
+		}
+		if len(args) > 1 {
+			c.output = append(c.output, frame{2, args[1] + "\n"})
+		}
+		c.exit(code)
+	}
+}
+
+// exit stops the container. The engine lock must be held.
+func (c *Container) exit(code int) {
+	if !c.Running {
+		return
+	}
+	c.Running = false
+	c.ExitCode = code
+	close(c.done)
+}
+
+func (e *Engine) wait(w http.ResponseWriter, r *http.Request) {
+	e.mtx.Lock()
+	c := e.container(w, r)
+	e.mtx.Unlock()
+	if c == nil {
+		return
+	}
+	select {
+	case <-c.done:
+	case <-r.Context().Done():
+		return
+	}
+	e.mtx.Lock()
+	code := c.ExitCode
+	e.mtx.Unlock()
+	writeJSON(w, map[string]any{"StatusCode": code})
+}
+
+func (e *Engine) kill(w http.ResponseWriter, r *http.Request) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	c := e.container(w, r)
+	if c == nil {
+		return
+	}
+	if !c.Running {
+		writeError(w, http.StatusConflict, "Container "+c.ID+" is not running")
+		return
+	}
+	signal := r.URL.Query().Get("signal")
+	c.Signals = append(c.Signals, signal)
+	sig, _ := strconv.Atoi(signal)
+	c.exit(128 + sig)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (e *Engine) remove(w http.ResponseWriter, r *http.Request) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	c := e.container(w, r)
+	if c == nil {
+		return
+	}
+	if c.Running && r.URL.Query().Get("force") != "true" {
+		writeError(w, http.StatusConflict, "Container "+c.ID+" is running")
+		return
+	}
+	c.exit(137)
+	c.Removed = true
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (e *Engine) inspect(w http.ResponseWriter, r *http.Request) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	c := e.container(w, r)
+	if c == nil {
+		return
+	}
+	var info container.ContainerInfo
+	info.ID = c.ID
+	info.Name = "/" + c.Name
+	info.Config.Labels = c.Spec.Labels
+	info.State.Running = c.Running
+	info.State.ExitCode = c.ExitCode
+	info.State.Status = "exited"
+	if c.Running {
+		info.State.Status = "running"
+	}
+	info.NetworkSettings.Ports = c.ports
+	writeJSON(w, info)
+}
+
+func (e *Engine) logs(w http.ResponseWriter, r *http.Request) {
+	e.mtx.Lock()
+	c := e.container(w, r)
+	e.mtx.Unlock()
+	if c == nil {
+		return
+	}
+	w.Header().Set("Content-Type", "application/vnd.docker.multiplexed-stream")
+	w.WriteHeader(http.StatusOK)
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	if r.URL.Query().Get("follow") == "true" {
+		select {
+		case <-c.done:
+		case <-r.Context().Done():
+			return
+		}
+	}
+	e.mtx.Lock()
+	output := append([]frame(nil), c.output...)
+	e.mtx.Unlock()
+	for _, f := range output {
+		var header [8]byte
+		header[0] = f.stream
+		binary.BigEndian.PutUint32(header[4:], uint32(len(f.data)))
+		_, _ = w.Write(header[:])
+		_, _ = w.Write([]byte(f.data))
+	}
+}
@@ -0,0 +1,106 @@
+package container
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CreateRequest is the body of the container create call.
+type CreateRequest struct {
+	Image        string              `json:"Image"`
+	Cmd          []string            `json:"Cmd,omitempty"`
+	Entrypoint   []string            `json:"Entrypoint,omitempty"`
+	Env          []string            `json:"Env,omitempty"`
+	User         string              `json:"User,omitempty"`
+	Labels       map[string]string   `json:"Labels,omitempty"`
+	ExposedPorts map[string]struct{} `json:"ExposedPorts,omitempty"`
+	HostConfig   HostConfig          `json:"HostConfig"`
+}
+
+type HostConfig struct {
+	Binds        []string                 `json:"Binds,omitempty"`
+	PortBindings map[string][]PortBinding `json:"PortBindings,omitempty"`
+	NetworkMode  string                   `json:"NetworkMode,omitempty"`
+}
+
+type PortBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string `json:"HostPort"`
+}
+
+// ContainerInfo is the part of the container inspection process-compose uses.
+type ContainerInfo struct {
+	ID     string `json:"Id"`
+	Name   string `json:"Name"`
+	Config struct {
+		Labels map[string]string `json:"Labels"`
+	} `json:"Config"`
+	State struct {
+		Status   string `json:"Status"`
+		Running  bool   `json:"Running"`
+		Pid      int    `json:"Pid"`
+		ExitCode int    `json:"ExitCode"`
+	} `json:"State"`
+	NetworkSettings struct {
+		Ports map[string][]PortBinding `json:"Ports"`
+	} `json:"NetworkSettings"`
+}
+
+// PublishedPorts returns the host ports of the container, by protocol.
+func (info *ContainerInfo) PublishedPorts() (tcp, udp []uint16) {
+	seen := map[string]bool{}
+	for key, bindings := range info.NetworkSettings.Ports {
+		_, proto, _ := strings.Cut(key, "/")
+		for _, binding := range bindings {
+			port, err := strconv.ParseUint(binding.HostPort, 10, 16)
+			if err != nil || seen[proto+binding.HostPort] {
+				continue
+			}
+			// The engine binds the IPv4 and IPv6 addresses separately.
+			seen[proto+binding.HostPort] = true
+			switch proto {
+			case "tcp", "":
+				tcp = append(tcp, uint16(port))
+			case "udp":
+				udp = append(udp, uint16(port))
+			}
+		}
+	}
+	return tcp, udp
+}
+
+const (
+	streamStdout = 1
+	streamStderr = 2
+)
+
+// Demux splits the multiplexed stream of the logs of a container without TTY:
+// frames of an 8 bytes header, with the stream and the size of the payload,
+// followed by the payload.
+func Demux(r io.Reader, stdout, stderr io.Writer) error {
+	var header [8]byte
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		size := int64(binary.BigEndian.Uint32(header[4:]))
+		var dst io.Writer
+		switch header[0] {
+		case streamStdout:
+			dst = stdout
+		case streamStderr:
+			dst = stderr
+		default:
+			return fmt.Errorf("invalid log stream %d", header[0])
+		}
+		if _, err := io.CopyN(dst, r, size); err != nil {
+			return err
+		}
+	}
+}
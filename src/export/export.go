@@ -0,0 +1,262 @@
+// Package export converts a project into the service definitions of a process
+// supervisor: systemd units, a supervisord configuration or launchd property
+// lists.
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/joho/godotenv"
+)
+
+// The formats a project can be exported to.
+const (
+	FormatSystemd     = "systemd"
+	FormatSupervisord = "supervisord"
+	FormatLaunchd     = "launchd-plist"
+)
+
+// Formats lists the supported formats.
+var Formats = []string{FormatSystemd, FormatSupervisord, FormatLaunchd}
+
+// Options customizes the exported services.
+type Options struct {
+	// Name prefixes the service names. Defaults to the project name, or to the
+	// name of the current directory.
+	Name string
+	// User runs the services, when set.
+	User string
+}
+
+// File is an exported file, named relative to the output directory.
+type File struct {
+	Name    string
+	Content []byte
+}
+
+type exporter func(project *types.Project, procs []types.ProcessConfig, opts Options) ([]File, []string)
+
+var exporters = map[string]exporter{
+	FormatSystemd:     exportSystemd,
+	FormatSupervisord: exportSupervisord,
+	FormatLaunchd:     exportLaunchd,
+}
+
+// Export converts the processes of the project into the format, in dependency
+// order. The settings that have no equivalent in the format are returned as
+// warnings. Disabled processes, container processes and scheduled processes
+// are left out.
+func Export(project *types.Project, format string, opts Options) ([]File, []string, error) {
+	export, ok := exporters[format]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown format %s, expected one of %s", format, strings.Join(Formats, ", "))
+	}
+	if opts.Name == "" {
+		opts.Name = defaultName(project)
+	}
+	opts.Name = sanitizeName(opts.Name)
+	procs, warnings, err := exportedProcesses(project)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(procs) == 0 {
+		return nil, nil, fmt.Errorf("no processes to export")
+	}
+	if len(project.Secrets) > 0 {
+		warnings = append(warnings, "secrets are not exported")
+	}
+	files, formatWarnings := export(project, procs, opts)
+	return files, append(warnings, formatWarnings...), nil
+}
+
+// exportedProcesses returns the processes to export, dependencies first. The
+// processes without dependencies keep their lexicographic order.
+func exportedProcesses(project *types.Project) ([]types.ProcessConfig, []string, error) {
+	names, err := project.GetLexicographicProcessNames()
+	if err != nil {
+		return nil, nil, err
+	}
+	var procs []types.ProcessConfig
+	var warnings []string
+	err = project.WithProcesses(names, func(proc types.ProcessConfig) error {
+		switch {
+		case proc.Disabled:
+			warnings = append(warnings, fmt.Sprintf("%s: disabled, skipped", proc.ReplicaName))
+		case proc.Container != nil:
+			warnings = append(warnings, fmt.Sprintf("%s: container processes are not supported, skipped", proc.ReplicaName))
+		case proc.Schedule != nil:
+			warnings = append(warnings, fmt.Sprintf("%s: scheduled processes are not supported, skipped", proc.ReplicaName))
+		default:
+			procs = append(procs, proc)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return procs, warnings, nil
+}
+
+func defaultName(project *types.Project) string {
+	if project.Name != "" {
+		return project.Name
+	}
+	if wd, err := os.Getwd(); err == nil {
+		return filepath.Base(wd)
+	}
+	return "process-compose"
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// sanitizeName keeps the characters allowed in the names of all the formats.
+func sanitizeName(name string) string {
+	name = strings.Trim(unsafeNameChars.ReplaceAllString(name, "-"), "-")
+	if name == "" {
+		return "process-compose"
+	}
+	return name
+}
+
+// environment returns the variables of the process, as process-compose sets
+// them, except for the system environment, the secrets and the env_file,
+// which is read only when inlineEnvFile is set. Later variables override
+// earlier ones.
+func environment(project *types.Project, proc *types.ProcessConfig, inlineEnvFile bool) ([]string, error) {
+	env := []string{
+		"PC_PROC_NAME=" + proc.Name,
+		"PC_REPLICA_NUM=" + strconv.Itoa(proc.ReplicaNum),
+	}
+	for _, name := range sortedKeys(proc.AssignedPorts) {
+		env = append(env, types.PortEnvName(name)+"="+strconv.Itoa(proc.AssignedPorts[name]))
+	}
+	if !proc.DisableDotEnv {
+		for _, name := range sortedKeys(project.DotEnvVars) {
+			env = append(env, name+"="+project.DotEnvVars[name])
+		}
+	}
+	env = append(env, project.Environment...)
+	if inlineEnvFile && proc.EnvFile != "" {
+		vars, err := godotenv.Read(envFilePath(proc))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read env_file of %s: %w", proc.ReplicaName, err)
+		}
+		for _, name := range sortedKeys(vars) {
+			env = append(env, name+"="+vars[name])
+		}
+	}
+	env = append(env, proc.Environment...)
+	return dedupEnv(env), nil
+}
+
+// dedupEnv keeps the last value of every variable, at its first position.
+func dedupEnv(env []string) []string {
+	index := map[string]int{}
+	var result []string
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		if i, ok := index[name]; ok {
+			result[i] = kv
+			continue
+		}
+		index[name] = len(result)
+		result = append(result, kv)
+	}
+	return result
+}
+
+func envFilePath(proc *types.ProcessConfig) string {
+	if filepath.IsAbs(proc.EnvFile) || proc.WorkingDir == "" {
+		return proc.EnvFile
+	}
+	return filepath.Join(proc.WorkingDir, proc.EnvFile)
+}
+
+// absPath resolves a path against the current directory: the services don't
+// run from the directory of the project.
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// commandLine returns the executable and the arguments of the process.
+func commandLine(proc *types.ProcessConfig) []string {
+	return append([]string{proc.Executable}, proc.Args...)
+}
+
+// stopSignal returns the shutdown signal of the process, SIGTERM by default.
+func stopSignal(proc *types.ProcessConfig) int {
+	if proc.ShutDownParams.Signal == 0 {
+		return 15
+	}
+	return proc.ShutDownParams.Signal
+}
+
+var signalNames = map[int]string{
+	1:  "HUP",
+	2:  "INT",
+	3:  "QUIT",
+	6:  "ABRT",
+	9:  "KILL",
+	10: "USR1",
+	12: "USR2",
+	14: "ALRM",
+	15: "TERM",
+}
+
+// signalName returns the name of the signal without the SIG prefix, or its
+// number when the signal has no portable name.
+func signalName(sig int) string {
+	if name, ok := signalNames[sig]; ok {
+		return name
+	}
+	return strconv.Itoa(sig)
+}
+
+// successExitCodes returns the exit codes other than 0 the process succeeds
+// with.
+func successExitCodes(proc *types.ProcessConfig) []string {
+	var codes []string
+	for _, code := range proc.SuccessExitCodes {
+		if code != 0 {
+			codes = append(codes, strconv.Itoa(code))
+		}
+	}
+	return codes
+}
+
+// commonWarnings reports the settings that none of the formats supports.
+func commonWarnings(proc *types.ProcessConfig) []string {
+	var warnings []string
+	warn := func(msg string) {
+		warnings = append(warnings, proc.ReplicaName+": "+msg)
+	}
+	if proc.ReadinessProbe != nil || proc.LivenessProbe != nil {
+		warn("health probes are not exported")
+	}
+	if proc.ShutDownParams.ShutDownCommand != "" {
+		warn("the shutdown command is not exported")
+	}
+	if proc.RestartPolicy.Restart == types.RestartPolicyExitOnFailure {
+		warn("exit_on_failure is exported as no restart")
+	}
+	return warnings
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,256 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func testProject(t *testing.T) *types.Project {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "api.env"), []byte("TOKEN=secret\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return &types.Project{
+		Name:        "shop",
+		Environment: types.Environment{"GLOBAL=1", "LEVEL=info"},
+		Processes: types.Processes{
+			"migrate": {
+				Name:        "migrate",
+				ReplicaName: "migrate",
+				Executable:  "bash",
+				Args:        []string{"-c", "echo migrating $DB"},
+				Environment: types.Environment{"DB=pg"},
+			},
+			"api": {
+				Name:        "api",
+				ReplicaName: "api",
+				Executable:  "bash",
+				Args:        []string{"-c", "echo \"100% ready\"\nsleep 10"},
+				Environment: types.Environment{"LEVEL=debug"},
+				EnvFile:     "api.env",
+				WorkingDir:  dir,
+				RestartPolicy: types.RestartPolicyConfig{
+					Restart:        types.RestartPolicyAlways,
+					BackoffSeconds: 3,
+					MaxRestarts:    5,
+				},
+				DependsOn: types.DependsOnConfig{
+					"migrate": {Condition: types.ProcessConditionCompletedSuccessfully},
+				},
+				ShutDownParams:   types.ShutDownParams{Signal: 2, ShutDownTimeout: 20},
+				SuccessExitCodes: []int{0, 3},
+			},
+			"worker": {
+				Name:        "worker",
+				ReplicaName: "worker",
+				Executable:  "sleep",
+				Args:        []string{"100"},
+				RestartPolicy: types.RestartPolicyConfig{
+					Restart: types.RestartPolicyOnFailure,
+				},
+				DependsOn: types.DependsOnConfig{
+					"api": {Condition: types.ProcessConditionHealthy},
+				},
+			},
+			"web": {
+				Name:        "web",
+				ReplicaName: "web",
+				Container:   &types.ContainerConfig{Image: "nginx"},
+			},
+		},
+	}
+}
+
+func fileNames(files []File) []string {
+	names := make([]string, len(files))
+	for i, file := range files {
+		names[i] = file.Name
+	}
+	return names
+}
+
+func TestExportFiles(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{FormatSystemd, []string{"shop-migrate.service", "shop-api.service", "shop-worker.service", "shop.target"}},
+		{FormatSupervisord, []string{"shop.conf"}},
+		{FormatLaunchd, []string{"shop.migrate.plist", "shop.api.plist", "shop.worker.plist"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			files, warnings, err := Export(testProject(t), tt.format, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fileNames(files); !slices.Equal(got, tt.want) {
+				t.Errorf("files = %q, want %q", got, tt.want)
+			}
+			if !slices.Contains(warnings, "web: container processes are not supported, skipped") {
+				t.Errorf("warnings = %q, want the container process skipped", warnings)
+			}
+		})
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	if _, _, err := Export(testProject(t), "upstart", Options{}); err == nil {
+		t.Error("Export() succeeded with an unknown format")
+	}
+}
+
+func exportFile(t *testing.T, format, name string, opts Options) (string, []string) {
+	t.Helper()
+	files, warnings, err := Export(testProject(t), format, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if file.Name == name {
+			return string(file.Content), warnings
+		}
+	}
+	t.Fatalf("%s not exported, got %q", name, fileNames(files))
+	return "", nil
+}
+
+func assertLines(t *testing.T, content string, want []string) {
+	t.Helper()
+	lines := strings.Split(content, "\n")
+	for _, line := range want {
+		if !slices.Contains(lines, line) {
+			t.Errorf("missing line %q in:\n%s", line, content)
+		}
+	}
+}
+
+func TestExportSystemd(t *testing.T) {
+	api, _ := exportFile(t, FormatSystemd, "shop-api.service", Options{User: "deploy"})
+	assertLines(t, api, []string{
+		"PartOf=shop.target",
+		"Requires=shop-migrate.service",
+		"After=shop-migrate.service",
+		"StartLimitIntervalSec=infinity",
+		"StartLimitBurst=6",
+		"Type=simple",
+		"User=deploy",
+		`Environment="GLOBAL=1"`,
+		`Environment="LEVEL=debug"`,
+		`ExecStart=bash -c "echo \"100%% ready\"\nsleep 10"`,
+		"Restart=always",
+		"RestartSec=3",
+		"KillSignal=SIGINT",
+		"TimeoutStopSec=20",
+		"SuccessExitStatus=3",
+		"WantedBy=shop.target",
+	})
+	if strings.Contains(api, "LEVEL=info") {
+		t.Errorf("the process environment doesn't override the global one:\n%s", api)
+	}
+	if !strings.Contains(api, "\nEnvironmentFile=/") || !strings.Contains(api, "/api.env\n") {
+		t.Errorf("missing the absolute EnvironmentFile in:\n%s", api)
+	}
+
+	migrate, _ := exportFile(t, FormatSystemd, "shop-migrate.service", Options{})
+	assertLines(t, migrate, []string{
+		"Type=oneshot",
+		"RemainAfterExit=yes",
+		`ExecStart=bash -c "echo migrating $$DB"`,
+		"Restart=no",
+		"KillSignal=SIGTERM",
+	})
+
+	worker, warnings := exportFile(t, FormatSystemd, "shop-worker.service", Options{})
+	assertLines(t, worker, []string{
+		"Requires=shop-api.service",
+		"After=shop-api.service",
+		"StartLimitIntervalSec=0",
+		"Restart=on-failure",
+	})
+	if !slices.Contains(warnings, "worker: waits for api to start, not for process_healthy") {
+		t.Errorf("warnings = %q, want the healthy condition reported", warnings)
+	}
+
+	target, _ := exportFile(t, FormatSystemd, "shop.target", Options{})
+	assertLines(t, target, []string{
+		"Wants=shop-migrate.service shop-api.service shop-worker.service",
+		"WantedBy=multi-user.target",
+	})
+}
+
+func TestExportSupervisord(t *testing.T) {
+	conf, warnings := exportFile(t, FormatSupervisord, "shop.conf", Options{})
+	assertLines(t, conf, []string{
+		"[program:migrate]",
+		"command=bash -c 'echo migrating $DB'",
+		"priority=100",
+		"[program:api]",
+		`command=bash -c 'echo "100%% ready"`,
+		"  sleep 10'",
+		`environment=PC_PROC_NAME="api",PC_REPLICA_NUM="0",GLOBAL="1",LEVEL="debug",TOKEN="secret"`,
+		"priority=110",
+		"autorestart=true",
+		"startretries=5",
+		"exitcodes=0,3",
+		"stopsignal=INT",
+		"stopwaitsecs=20",
+		"autorestart=unexpected",
+		"[group:shop]",
+		"programs=migrate,api,worker",
+	})
+	if !slices.Contains(warnings, "api: starts after its dependencies, without waiting for them") {
+		t.Errorf("warnings = %q, want the dependencies reported", warnings)
+	}
+}
+
+func TestExportLaunchd(t *testing.T) {
+	plist, warnings := exportFile(t, FormatLaunchd, "shop.api.plist", Options{})
+	assertLines(t, plist, []string{
+		"\t<string>shop.api</string>",
+		"\t\t<string>echo &#34;100% ready&#34;&#xA;sleep 10</string>",
+		"\t\t<key>TOKEN</key>",
+		"\t<key>ThrottleInterval</key>",
+		"\t<integer>3</integer>",
+		"\t<key>ExitTimeOut</key>",
+		"\t<integer>20</integer>",
+	})
+	for _, want := range []string{
+		"api: launchd stops the jobs with SIGTERM, not SIGINT",
+		"api: launchd only succeeds with exit code 0",
+	} {
+		if !slices.Contains(warnings, want) {
+			t.Errorf("warnings = %q, want %q", warnings, want)
+		}
+	}
+
+	worker, _ := exportFile(t, FormatLaunchd, "shop.worker.plist", Options{})
+	if !strings.Contains(worker, "<key>KeepAlive</key>\n\t<dict>\n\t\t<key>SuccessfulExit</key>\n\t\t<false/>") {
+		t.Errorf("on_failure is not exported as KeepAlive SuccessfulExit:\n%s", worker)
+	}
+}
+
+func TestSystemdCommand(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"sleep", "10"}, "sleep 10"},
+		{[]string{"sh", "-c", "echo $HOME"}, `sh -c "echo $$HOME"`},
+		{[]string{"echo", ""}, `echo ""`},
+		{[]string{"echo", `a\b`}, `echo "a\\b"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := systemdCommand(tt.args); got != tt.want {
+				t.Errorf("systemdCommand() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
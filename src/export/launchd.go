@@ -0,0 +1,132 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+const plistHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+`
+
+// exportLaunchd writes a launchd property list per process. launchd starts all
+// the jobs at once: the dependencies are not exported.
+func exportLaunchd(project *types.Project, procs []types.ProcessConfig, opts Options) ([]File, []string) {
+	var files []File
+	var warnings []string
+	for i := range procs {
+		proc := &procs[i]
+		label := opts.Name + "." + sanitizeName(proc.ReplicaName)
+		plist, procWarnings := launchdPlist(project, proc, opts, label)
+		warnings = append(warnings, procWarnings...)
+		if plist != nil {
+			files = append(files, File{Name: label + ".plist", Content: plist})
+		}
+	}
+	return files, warnings
+}
+
+func launchdPlist(project *types.Project, proc *types.ProcessConfig, opts Options, label string) ([]byte, []string) {
+	warnings := commonWarnings(proc)
+	warn := func(format string, args ...any) {
+		warnings = append(warnings, proc.ReplicaName+": "+fmt.Sprintf(format, args...))
+	}
+	env, err := environment(project, proc, true)
+	if err != nil {
+		warn("%v, skipped", err)
+		return nil, warnings
+	}
+	if len(proc.DependsOn) > 0 {
+		warn("launchd doesn't order the jobs, the dependencies are not exported")
+	}
+	if proc.IsDaemon {
+		warn("launchd can't run daemons, the process must stay in the foreground")
+	}
+	if sig := stopSignal(proc); sig != 15 {
+		warn("launchd stops the jobs with SIGTERM, not SIG%s", signalName(sig))
+	}
+	if len(successExitCodes(proc)) > 0 {
+		warn("launchd only succeeds with exit code 0")
+	}
+	if proc.RestartPolicy.MaxRestarts > 0 {
+		warn("launchd doesn't limit the restarts")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(plistHeader)
+	buf.WriteString("<dict>\n")
+	key := func(indent, name string) {
+		fmt.Fprintf(&buf, "%s<key>%s</key>\n", indent, plistEscape(name))
+	}
+	str := func(indent, value string) {
+		fmt.Fprintf(&buf, "%s<string>%s</string>\n", indent, plistEscape(value))
+	}
+	key("\t", "Label")
+	str("\t", label)
+	key("\t", "ProgramArguments")
+	buf.WriteString("\t<array>\n")
+	for _, arg := range commandLine(proc) {
+		str("\t\t", arg)
+	}
+	buf.WriteString("\t</array>\n")
+	if proc.WorkingDir != "" {
+		key("\t", "WorkingDirectory")
+		str("\t", absPath(proc.WorkingDir))
+	}
+	if len(env) > 0 {
+		key("\t", "EnvironmentVariables")
+		buf.WriteString("\t<dict>\n")
+		for _, kv := range env {
+			name, value, _ := strings.Cut(kv, "=")
+			key("\t\t", name)
+			str("\t\t", value)
+		}
+		buf.WriteString("\t</dict>\n")
+	}
+	if opts.User != "" {
+		key("\t", "UserName")
+		str("\t", opts.User)
+	}
+	key("\t", "RunAtLoad")
+	buf.WriteString("\t<true/>\n")
+	key("\t", "KeepAlive")
+	switch proc.RestartPolicy.Restart {
+	case types.RestartPolicyAlways:
+		buf.WriteString("\t<true/>\n")
+	case types.RestartPolicyOnFailure:
+		buf.WriteString("\t<dict>\n")
+		key("\t\t", "SuccessfulExit")
+		buf.WriteString("\t\t<false/>\n")
+		buf.WriteString("\t</dict>\n")
+	default:
+		buf.WriteString("\t<false/>\n")
+	}
+	if proc.RestartPolicy.Restart != types.RestartPolicyNo && proc.RestartPolicy.BackoffSeconds > 0 {
+		key("\t", "ThrottleInterval")
+		fmt.Fprintf(&buf, "\t<integer>%s</integer>\n", strconv.Itoa(proc.RestartPolicy.BackoffSeconds))
+	}
+	if proc.ShutDownParams.ShutDownTimeout > 0 {
+		key("\t", "ExitTimeOut")
+		fmt.Fprintf(&buf, "\t<integer>%s</integer>\n", strconv.Itoa(proc.ShutDownParams.ShutDownTimeout))
+	}
+	if proc.LogLocation != "" {
+		key("\t", "StandardOutPath")
+		str("\t", absPath(proc.LogLocation))
+		key("\t", "StandardErrorPath")
+		str("\t", absPath(proc.LogLocation))
+	}
+	buf.WriteString("</dict>\n</plist>\n")
+	return buf.Bytes(), warnings
+}
+
+func plistEscape(value string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(value))
+	return buf.String()
+}
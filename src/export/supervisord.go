@@ -0,0 +1,139 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+// supervisordPriorityBase and supervisordPriorityStep order the programs:
+// supervisord starts the programs with the lower priority first.
+const (
+	supervisordPriorityBase = 100
+	supervisordPriorityStep = 10
+)
+
+// exportSupervisord writes a configuration with a program per process, in a
+// group named after the project. supervisord starts the programs in dependency
+// order, but doesn't wait for the dependencies.
+func exportSupervisord(project *types.Project, procs []types.ProcessConfig, opts Options) ([]File, []string) {
+	var warnings []string
+	var programs []string
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "; Generated by process-compose export\n")
+	for i := range procs {
+		proc := &procs[i]
+		program, procWarnings := supervisordProgram(project, proc, opts, supervisordPriorityBase+i*supervisordPriorityStep)
+		warnings = append(warnings, procWarnings...)
+		if program == nil {
+			continue
+		}
+		programs = append(programs, sanitizeName(proc.ReplicaName))
+		buf.WriteString("\n")
+		buf.Write(program)
+	}
+	fmt.Fprintf(&buf, "\n[group:%s]\n", opts.Name)
+	fmt.Fprintf(&buf, "programs=%s\n", strings.Join(programs, ","))
+	return []File{{Name: opts.Name + ".conf", Content: buf.Bytes()}}, warnings
+}
+
+func supervisordProgram(project *types.Project, proc *types.ProcessConfig, opts Options, priority int) ([]byte, []string) {
+	warnings := commonWarnings(proc)
+	warn := func(format string, args ...any) {
+		warnings = append(warnings, proc.ReplicaName+": "+fmt.Sprintf(format, args...))
+	}
+	env, err := environment(project, proc, true)
+	if err != nil {
+		warn("%v, skipped", err)
+		return nil, warnings
+	}
+	if len(proc.DependsOn) > 0 {
+		warn("starts after its dependencies, without waiting for them")
+	}
+	if proc.IsDaemon {
+		warn("supervisord can't run daemons, the process must stay in the foreground")
+	}
+
+	var buf bytes.Buffer
+	line := func(key, value string) {
+		// The indented lines continue the value.
+		fmt.Fprintf(&buf, "%s=%s\n", key, strings.ReplaceAll(value, "\n", "\n  "))
+	}
+	fmt.Fprintf(&buf, "[program:%s]\n", sanitizeName(proc.ReplicaName))
+	line("command", supervisordCommand(commandLine(proc)))
+	if proc.WorkingDir != "" {
+		line("directory", supervisordEscape(absPath(proc.WorkingDir)))
+	}
+	if len(env) > 0 {
+		vars := make([]string, len(env))
+		for i, kv := range env {
+			name, value, _ := strings.Cut(kv, "=")
+			vars[i] = name + "=" + supervisordQuote(value)
+		}
+		line("environment", strings.Join(vars, ","))
+	}
+	if opts.User != "" {
+		line("user", opts.User)
+	}
+	line("priority", strconv.Itoa(priority))
+	line("autostart", "true")
+	line("autorestart", supervisordRestart(proc.RestartPolicy.Restart))
+	if proc.RestartPolicy.MaxRestarts > 0 {
+		line("startretries", strconv.Itoa(proc.RestartPolicy.MaxRestarts))
+	}
+	line("exitcodes", strings.Join(append([]string{"0"}, successExitCodes(proc)...), ","))
+	line("stopsignal", signalName(stopSignal(proc)))
+	if proc.ShutDownParams.ShutDownTimeout > 0 {
+		line("stopwaitsecs", strconv.Itoa(proc.ShutDownParams.ShutDownTimeout))
+	}
+	line("stopasgroup", strconv.FormatBool(!proc.ShutDownParams.ParentOnly))
+	line("killasgroup", strconv.FormatBool(!proc.ShutDownParams.ParentOnly))
+	if proc.LogLocation != "" {
+		line("stdout_logfile", supervisordEscape(absPath(proc.LogLocation)))
+		line("redirect_stderr", "true")
+	}
+	return buf.Bytes(), warnings
+}
+
+func supervisordRestart(policy types.RestartPolicy) string {
+	switch policy {
+	case types.RestartPolicyAlways:
+		return "true"
+	case types.RestartPolicyOnFailure:
+		return "unexpected"
+	}
+	return "false"
+}
+
+// supervisordEscape escapes the interpolations of a configuration value.
+func supervisordEscape(value string) string {
+	return strings.ReplaceAll(value, "%", "%%")
+}
+
+var supervisordQuoter = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"%", "%%",
+)
+
+// supervisordQuote double-quotes a word, as split by supervisord.
+func supervisordQuote(word string) string {
+	return `"` + supervisordQuoter.Replace(word) + `"`
+}
+
+// supervisordCommand returns the command line of a program. supervisord splits
+// it as a POSIX shell would, without running a shell.
+func supervisordCommand(args []string) string {
+	words := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\n\"'\\$`;&|<>()*?#~%") {
+			words[i] = arg
+			continue
+		}
+		words[i] = "'" + supervisordEscape(strings.ReplaceAll(arg, "'", `'"'"'`)) + "'"
+	}
+	return strings.Join(words, " ")
+}
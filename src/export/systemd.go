@@ -0,0 +1,253 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+// exportSystemd writes a service unit per process, and a target that starts
+// them all. The processes other processes wait for to complete are oneshot
+// services, so that the units ordered after them start once they exited.
+func exportSystemd(project *types.Project, procs []types.ProcessConfig, opts Options) ([]File, []string) {
+	units := serviceNames(procs, func(proc *types.ProcessConfig) string {
+		return opts.Name + "-" + sanitizeName(proc.ReplicaName) + ".service"
+	})
+	oneshot := map[string]bool{}
+	for _, proc := range procs {
+		for name, dep := range proc.DependsOn {
+			if dep.Condition == types.ProcessConditionCompleted || dep.Condition == types.ProcessConditionCompletedSuccessfully {
+				oneshot[name] = true
+			}
+		}
+	}
+	target := opts.Name + ".target"
+	var files []File
+	var warnings []string
+	var all []string
+	for i := range procs {
+		proc := &procs[i]
+		unit, unitWarnings := systemdUnit(project, proc, opts, target, units, oneshot[proc.Name] || oneshot[proc.ReplicaName])
+		warnings = append(warnings, unitWarnings...)
+		if unit == nil {
+			continue
+		}
+		name := units.byReplica[proc.ReplicaName]
+		files = append(files, File{Name: name, Content: unit})
+		all = append(all, name)
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Generated by process-compose export\n")
+	fmt.Fprintf(&buf, "[Unit]\n")
+	fmt.Fprintf(&buf, "Description=%s\n", systemdEscape("process-compose project "+opts.Name))
+	fmt.Fprintf(&buf, "Wants=%s\n", strings.Join(all, " "))
+	fmt.Fprintf(&buf, "\n[Install]\n")
+	fmt.Fprintf(&buf, "WantedBy=multi-user.target\n")
+	files = append(files, File{Name: target, Content: buf.Bytes()})
+	return files, warnings
+}
+
+// services names the service of every exported replica, and lists the
+// services of every process, for the dependencies.
+type services struct {
+	byReplica map[string]string
+	byProcess map[string][]string
+}
+
+// dependency returns the services of a dependency, a process or one of its
+// replicas.
+func (s services) dependency(name string) []string {
+	if names, ok := s.byProcess[name]; ok {
+		return names
+	}
+	if name, ok := s.byReplica[name]; ok {
+		return []string{name}
+	}
+	return nil
+}
+
+func serviceNames(procs []types.ProcessConfig, name func(proc *types.ProcessConfig) string) services {
+	s := services{byReplica: map[string]string{}, byProcess: map[string][]string{}}
+	for i := range procs {
+		proc := &procs[i]
+		s.byReplica[proc.ReplicaName] = name(proc)
+		s.byProcess[proc.Name] = append(s.byProcess[proc.Name], name(proc))
+	}
+	return s
+}
+
+// conditionName returns the name of the condition in the configuration.
+func conditionName(condition types.ProcessCondition) string {
+	name, err := condition.MarshalYAML()
+	if err != nil {
+		return condition.String()
+	}
+	return fmt.Sprint(name)
+}
+
+func systemdUnit(
+	project *types.Project,
+	proc *types.ProcessConfig,
+	opts Options,
+	target string,
+	units services,
+	oneshot bool,
+) ([]byte, []string) {
+	warnings := commonWarnings(proc)
+	warn := func(format string, args ...any) {
+		warnings = append(warnings, proc.ReplicaName+": "+fmt.Sprintf(format, args...))
+	}
+	env, err := environment(project, proc, false)
+	if err != nil {
+		warn("%v, skipped", err)
+		return nil, warnings
+	}
+
+	var requires, wants, after []string
+	for _, name := range sortedKeys(proc.DependsOn) {
+		dep := proc.DependsOn[name]
+		names := units.dependency(name)
+		if len(names) == 0 {
+			warn("dependency %s is not exported", name)
+			continue
+		}
+		after = append(after, names...)
+		switch dep.Condition {
+		case types.ProcessConditionCompleted:
+			wants = append(wants, names...)
+		case types.ProcessConditionCompletedSuccessfully, types.ProcessConditionStarted:
+			requires = append(requires, names...)
+		default:
+			warn("waits for %s to start, not for %s", name, conditionName(dep.Condition))
+			requires = append(requires, names...)
+		}
+	}
+
+	serviceType := "simple"
+	switch {
+	case oneshot:
+		serviceType = "oneshot"
+	case proc.IsDaemon:
+		serviceType = "forking"
+	}
+	restart := systemdRestart(proc.RestartPolicy.Restart)
+	if oneshot && restart == "always" {
+		warn("processes waited for to complete can't restart always, exported as on-failure")
+		restart = "on-failure"
+	}
+
+	var buf bytes.Buffer
+	line := func(key, value string) {
+		fmt.Fprintf(&buf, "%s=%s\n", key, value)
+	}
+	fmt.Fprintf(&buf, "# Generated by process-compose export\n")
+	fmt.Fprintf(&buf, "[Unit]\n")
+	description := proc.Description
+	if description == "" {
+		description = opts.Name + " " + proc.ReplicaName
+	}
+	line("Description", systemdEscape(description))
+	line("PartOf", target)
+	if len(requires) > 0 {
+		line("Requires", strings.Join(requires, " "))
+	}
+	if len(wants) > 0 {
+		line("Wants", strings.Join(wants, " "))
+	}
+	if len(after) > 0 {
+		line("After", strings.Join(after, " "))
+	}
+	if restart != "no" {
+		if proc.RestartPolicy.MaxRestarts > 0 {
+			line("StartLimitIntervalSec", "infinity")
+			line("StartLimitBurst", strconv.Itoa(proc.RestartPolicy.MaxRestarts+1))
+		} else {
+			line("StartLimitIntervalSec", "0")
+		}
+	}
+
+	fmt.Fprintf(&buf, "\n[Service]\n")
+	line("Type", serviceType)
+	if oneshot {
+		line("RemainAfterExit", "yes")
+		line("TimeoutStartSec", "infinity")
+	}
+	if opts.User != "" {
+		line("User", opts.User)
+	}
+	if proc.WorkingDir != "" {
+		line("WorkingDirectory", systemdEscape(absPath(proc.WorkingDir)))
+	}
+	for _, kv := range env {
+		line("Environment", systemdQuote(kv))
+	}
+	if proc.EnvFile != "" {
+		line("EnvironmentFile", systemdEscape(absPath(envFilePath(proc))))
+	}
+	line("ExecStart", systemdCommand(commandLine(proc)))
+	line("Restart", restart)
+	if restart != "no" && proc.RestartPolicy.BackoffSeconds > 0 {
+		line("RestartSec", strconv.Itoa(proc.RestartPolicy.BackoffSeconds))
+	}
+	line("KillSignal", "SIG"+signalName(stopSignal(proc)))
+	if proc.ShutDownParams.ShutDownTimeout > 0 {
+		line("TimeoutStopSec", strconv.Itoa(proc.ShutDownParams.ShutDownTimeout))
+	}
+	if codes := successExitCodes(proc); len(codes) > 0 {
+		line("SuccessExitStatus", strings.Join(codes, " "))
+	}
+	if proc.LogLocation != "" {
+		line("StandardOutput", "append:"+systemdEscape(absPath(proc.LogLocation)))
+		line("StandardError", "append:"+systemdEscape(absPath(proc.LogLocation)))
+	}
+
+	fmt.Fprintf(&buf, "\n[Install]\n")
+	line("WantedBy", target)
+	return buf.Bytes(), warnings
+}
+
+func systemdRestart(policy types.RestartPolicy) string {
+	switch policy {
+	case types.RestartPolicyAlways:
+		return "always"
+	case types.RestartPolicyOnFailure:
+		return "on-failure"
+	}
+	return "no"
+}
+
+// systemdEscape escapes the specifiers of a unit setting.
+func systemdEscape(value string) string {
+	return strings.ReplaceAll(value, "%", "%%")
+}
+
+var systemdQuoter = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\t", `\t`,
+	"%", "%%",
+)
+
+// systemdQuote double-quotes a word of a unit setting.
+func systemdQuote(word string) string {
+	return `"` + systemdQuoter.Replace(word) + `"`
+}
+
+// systemdCommand returns a command line of ExecStart. The words are quoted, and
+// the dollar signs escaped, so that systemd passes them unchanged.
+func systemdCommand(args []string) string {
+	words := make([]string, len(args))
+	for i, arg := range args {
+		word := strings.ReplaceAll(arg, "$", "$$")
+		if word != "" && !strings.ContainsAny(word, " \t\n\"'\\;%") {
+			words[i] = word
+			continue
+		}
+		words[i] = systemdQuote(word)
+	}
+	return strings.Join(words, " ")
+}
@@ -25,9 +25,11 @@ type Prober struct {
 	stopped        atomic.Bool
 	env            []string
 	shellConfig    command.ShellConfig
+	logs           LogFollower
+	logChecker     *logChecker
 }
 
-func New(name string, probe Probe, env []string, shellConfig command.ShellConfig, onCheckEnd func(bool, bool, string, any)) (*Prober, error) {
+func New(name string, probe Probe, env []string, shellConfig command.ShellConfig, logs LogFollower, onCheckEnd func(bool, bool, string, any)) (*Prober, error) {
 	probe.ValidateAndSetDefaults()
 	p := &Prober{
 		probe:          probe,
@@ -36,26 +38,43 @@ func New(name string, probe Probe, env []string, shellConfig command.ShellConfig
 		hc:             health.New(),
 		env:            env,
 		shellConfig:    shellConfig,
+		logs:           logs,
 	}
 	p.hc.DisableLogging()
-	if probe.Exec != nil {
-		err := p.addProber(p.getExecChecker)
-		if err != nil {
-			return nil, err
-		}
-		return p, err
+	factory := p.getCheckerFactory()
+	if factory == nil {
+		return nil, fmt.Errorf("no probes [http_get, exec, tcp_socket, grpc, log_pattern] configured for %s", name)
 	}
-	if probe.HttpGet != nil {
-		err := p.addProber(p.getHttpChecker)
-		if err != nil {
-			return nil, err
-		}
-		return p, err
+	err := p.addProber(factory)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Prober) getCheckerFactory() func() (health.ICheckable, error) {
+	switch {
+	case p.probe.Exec != nil:
+		return p.getExecChecker
+	case p.probe.HttpGet != nil:
+		return p.getHttpChecker
+	case p.probe.TcpSocket != nil:
+		return p.getTcpChecker
+	case p.probe.Grpc != nil:
+		return p.getGrpcChecker
+	case p.probe.LogPattern != nil:
+		return p.getLogChecker
+	default:
+		return nil
 	}
-	return nil, fmt.Errorf("no probes [http_get, exec] configured for %s", name)
 }
 
 func (p *Prober) Start() {
+	// Follow the log right away: lines printed during the initial delay
+	// count towards the first check.
+	if p.logChecker != nil {
+		p.logChecker.start()
+	}
 	go func() {
 		p.stopped.Store(false)
 		time.Sleep(time.Duration(p.probe.InitialDelay) * time.Second)
@@ -72,6 +91,9 @@ func (p *Prober) Start() {
 }
 
 func (p *Prober) Stop() {
+	if p.logChecker != nil {
+		p.logChecker.stop()
+	}
 	if p.hc != nil {
 		_ = p.hc.Stop()
 		p.stopped.Store(true)
@@ -144,3 +166,33 @@ func (p *Prober) getExecChecker() (health.ICheckable, error) {
 		shellConfig: p.shellConfig,
 	}, nil
 }
+
+func (p *Prober) getTcpChecker() (health.ICheckable, error) {
+	if p.probe.TcpSocket.NumPort == 0 {
+		return nil, fmt.Errorf("tcp_socket probe requires a valid port")
+	}
+	return &tcpChecker{
+		address: joinHostPort(p.probe.TcpSocket.Host, p.probe.TcpSocket.NumPort),
+		timeout: time.Duration(p.probe.TimeoutSeconds) * time.Second,
+	}, nil
+}
+
+func (p *Prober) getGrpcChecker() (health.ICheckable, error) {
+	if p.probe.Grpc.NumPort == 0 {
+		return nil, fmt.Errorf("grpc probe requires a valid port")
+	}
+	return newGrpcChecker(
+		joinHostPort(p.probe.Grpc.Host, p.probe.Grpc.NumPort),
+		p.probe.Grpc.Service,
+		time.Duration(p.probe.TimeoutSeconds)*time.Second,
+	), nil
+}
+
+func (p *Prober) getLogChecker() (health.ICheckable, error) {
+	checker, err := newLogChecker(p.probe.LogPattern, p.logs)
+	if err != nil {
+		return nil, err
+	}
+	p.logChecker = checker
+	return checker, nil
+}
@@ -105,7 +105,7 @@ func TestProber_getHttpChecker(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p, err := New(tt.fields.Name, tt.fields.Probe, tt.fields.Env, *command.DefaultShellConfig(), tt.fields.OnCheckEnd)
+			p, err := New(tt.fields.Name, tt.fields.Probe, tt.fields.Env, *command.DefaultShellConfig(), nil, tt.fields.OnCheckEnd)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("health.New error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,107 @@
+package health
+
+import (
+	"errors"
+	"regexp"
+	"sync"
+)
+
+// LogFollower is the part of a process log the log_pattern probe needs. It is
+// satisfied by *pclog.ProcessLogBuffer.
+type LogFollower interface {
+	// Follow calls onLine for every line written from now on, until the
+	// returned function is called.
+	Follow(onLine func(line string)) (unfollow func())
+}
+
+// logChecker follows a process log and reports the health implied by the most
+// recent line that matched one of its patterns.
+type logChecker struct {
+	healthy   *regexp.Regexp
+	unhealthy *regexp.Regexp
+	logs      LogFollower
+	unfollow  func()
+	mtx       sync.Mutex
+	matched   bool
+	isHealthy bool
+	lastMatch string
+}
+
+func newLogChecker(probe *LogProbe, logs LogFollower) (*logChecker, error) {
+	if logs == nil {
+		return nil, errors.New("log_pattern probe requires the process log")
+	}
+	if probe.Healthy == "" && probe.Unhealthy == "" {
+		return nil, errors.New("log_pattern probe requires a healthy or unhealthy pattern")
+	}
+	c := &logChecker{
+		logs: logs,
+	}
+	var err error
+	if probe.Healthy != "" {
+		if c.healthy, err = regexp.Compile(probe.Healthy); err != nil {
+			return nil, err
+		}
+	}
+	if probe.Unhealthy != "" {
+		if c.unhealthy, err = regexp.Compile(probe.Unhealthy); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// start forgets what the previous incarnation printed and follows new lines.
+func (c *logChecker) start() {
+	c.mtx.Lock()
+	c.matched = false
+	c.isHealthy = false
+	c.lastMatch = ""
+	if c.unfollow == nil {
+		c.unfollow = c.logs.Follow(c.onLine)
+	}
+	c.mtx.Unlock()
+}
+
+func (c *logChecker) stop() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.unfollow != nil {
+		c.unfollow()
+		c.unfollow = nil
+	}
+}
+
+func (c *logChecker) Status() (any, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	rcMap := map[string]string{"output": c.lastMatch}
+	switch {
+	case !c.matched && c.healthy == nil:
+		// Without a healthy pattern, the process is healthy until it logs
+		// an unhealthy line.
+		return rcMap, nil
+	case !c.matched:
+		return rcMap, errors.New("no matching log line yet")
+	case !c.isHealthy:
+		return rcMap, errors.New("unhealthy log line matched")
+	}
+	return rcMap, nil
+}
+
+func (c *logChecker) onLine(line string) {
+	// An unhealthy match takes precedence when a line matches both.
+	isHealthy := false
+	switch {
+	case c.unhealthy != nil && c.unhealthy.MatchString(line):
+	case c.healthy != nil && c.healthy.MatchString(line):
+		isHealthy = true
+	default:
+		return
+	}
+	c.mtx.Lock()
+	c.matched = true
+	c.isHealthy = isHealthy
+	c.lastMatch = line
+	c.mtx.Unlock()
+}
@@ -0,0 +1,93 @@
+package health
+
+import (
+	"testing"
+)
+
+type fakeLogs struct {
+	onLine func(line string)
+}
+
+func (f *fakeLogs) Follow(onLine func(line string)) func() {
+	f.onLine = onLine
+	return func() {
+		f.onLine = nil
+	}
+}
+
+func (f *fakeLogs) write(line string) {
+	if f.onLine != nil {
+		f.onLine(line)
+	}
+}
+
+func TestLogChecker(t *testing.T) {
+	logs := &fakeLogs{}
+	checker, err := newLogChecker(&LogProbe{Healthy: `listening on :\d+`, Unhealthy: `(?i)fatal`}, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker.start()
+
+	if _, err := checker.Status(); err == nil {
+		t.Errorf("Status() = nil error before any line matched")
+	}
+	logs.write("booting")
+	if _, err := checker.Status(); err == nil {
+		t.Errorf("Status() = nil error after a non-matching line")
+	}
+	logs.write("listening on :8080")
+	if _, err := checker.Status(); err != nil {
+		t.Errorf("Status() error = %v after a healthy line", err)
+	}
+	logs.write("FATAL: lost connection")
+	if _, err := checker.Status(); err == nil {
+		t.Errorf("Status() = nil error after an unhealthy line")
+	}
+
+	// A restart forgets the previous incarnation.
+	logs.write("listening on :8080")
+	checker.stop()
+	checker.start()
+	if _, err := checker.Status(); err == nil {
+		t.Errorf("Status() = nil error after restart")
+	}
+	checker.stop()
+	if logs.onLine != nil {
+		t.Errorf("stop() did not unfollow the log")
+	}
+}
+
+func TestLogChecker_UnhealthyOnly(t *testing.T) {
+	logs := &fakeLogs{}
+	checker, err := newLogChecker(&LogProbe{Unhealthy: `(?i)fatal`}, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker.start()
+	defer checker.stop()
+
+	if _, err := checker.Status(); err != nil {
+		t.Errorf("Status() error = %v before any line matched", err)
+	}
+	logs.write("booting")
+	if _, err := checker.Status(); err != nil {
+		t.Errorf("Status() error = %v after a non-matching line", err)
+	}
+	logs.write("FATAL: lost connection")
+	if _, err := checker.Status(); err == nil {
+		t.Errorf("Status() = nil error after an unhealthy line")
+	}
+}
+
+func TestNewLogChecker_Errors(t *testing.T) {
+	if _, err := newLogChecker(&LogProbe{Healthy: "ok"}, nil); err == nil {
+		t.Errorf("newLogChecker() = nil error without a log")
+	}
+	if _, err := newLogChecker(&LogProbe{}, &fakeLogs{}); err == nil {
+		t.Errorf("newLogChecker() = nil error without patterns")
+	}
+	if _, err := newLogChecker(&LogProbe{Healthy: "("}, &fakeLogs{}); err == nil {
+		t.Errorf("newLogChecker() = nil error for an invalid pattern")
+	}
+}
@@ -9,13 +9,16 @@ import (
 )
 
 type Probe struct {
-	Exec             *ExecProbe `yaml:"exec,omitempty" json:"exec,omitempty"`
-	HttpGet          *HttpProbe `yaml:"http_get,omitempty" json:"httpGet,omitempty"`
-	InitialDelay     int        `yaml:"initial_delay_seconds,omitempty" json:"initialDelay,omitempty"`
-	PeriodSeconds    int        `yaml:"period_seconds,omitempty" json:"periodSeconds,omitempty"`
-	TimeoutSeconds   int        `yaml:"timeout_seconds,omitempty" json:"timeoutSeconds,omitempty"`
-	SuccessThreshold int        `yaml:"success_threshold,omitempty" json:"successThreshold,omitempty"`
-	FailureThreshold int        `yaml:"failure_threshold,omitempty" json:"failureThreshold,omitempty"`
+	Exec             *ExecProbe      `yaml:"exec,omitempty" json:"exec,omitempty"`
+	HttpGet          *HttpProbe      `yaml:"http_get,omitempty" json:"httpGet,omitempty"`
+	TcpSocket        *TcpSocketProbe `yaml:"tcp_socket,omitempty" json:"tcpSocket,omitempty"`
+	Grpc             *GrpcProbe      `yaml:"grpc,omitempty" json:"grpc,omitempty"`
+	LogPattern       *LogProbe       `yaml:"log_pattern,omitempty" json:"logPattern,omitempty"`
+	InitialDelay     int             `yaml:"initial_delay_seconds,omitempty" json:"initialDelay,omitempty"`
+	PeriodSeconds    int             `yaml:"period_seconds,omitempty" json:"periodSeconds,omitempty"`
+	TimeoutSeconds   int             `yaml:"timeout_seconds,omitempty" json:"timeoutSeconds,omitempty"`
+	SuccessThreshold int             `yaml:"success_threshold,omitempty" json:"successThreshold,omitempty"`
+	FailureThreshold int             `yaml:"failure_threshold,omitempty" json:"failureThreshold,omitempty"`
 }
 
 type ExecProbe struct {
@@ -33,6 +36,33 @@ type HttpProbe struct {
 	StatusCode int               `yaml:"status_code,omitempty" json:"statusCode,omitempty"`
 }
 
+// TcpSocketProbe succeeds when a TCP connection to Host:Port can be opened.
+type TcpSocketProbe struct {
+	Host    string `yaml:"host,omitempty" json:"host,omitempty"`
+	Port    string `yaml:"port,omitempty" json:"port,omitempty"`
+	NumPort int    `yaml:"num_port,omitempty" json:"numPort,omitempty"`
+}
+
+// GrpcProbe calls the standard grpc.health.v1.Health/Check method over
+// plaintext HTTP/2 and succeeds when the service reports SERVING. An empty
+// Service asks for the overall health of the server.
+type GrpcProbe struct {
+	Host    string `yaml:"host,omitempty" json:"host,omitempty"`
+	Port    string `yaml:"port,omitempty" json:"port,omitempty"`
+	NumPort int    `yaml:"num_port,omitempty" json:"numPort,omitempty"`
+	Service string `yaml:"service,omitempty" json:"service,omitempty"`
+}
+
+// LogProbe derives the health of a process from its own output. The process
+// becomes healthy once a line matches Healthy and unhealthy once a line
+// matches Unhealthy; the most recent match wins. Until either matches, the
+// process is not healthy when Healthy is set, and healthy when only Unhealthy
+// is set.
+type LogProbe struct {
+	Healthy   string `yaml:"healthy,omitempty" json:"healthy,omitempty"`
+	Unhealthy string `yaml:"unhealthy,omitempty" json:"unhealthy,omitempty"`
+}
+
 func (h *HttpProbe) getUrl() (*url.URL, error) {
 	urlStr := ""
 	if h.NumPort != 0 {
@@ -64,6 +94,25 @@ func (p *Probe) ValidateAndSetDefaults() {
 	if p.HttpGet != nil {
 		p.HttpGet.validateAndSetHttpDefaults()
 	}
+	if p.TcpSocket != nil {
+		p.TcpSocket.Host, p.TcpSocket.NumPort = hostPortDefaults(p.TcpSocket.Host, p.TcpSocket.Port)
+	}
+	if p.Grpc != nil {
+		p.Grpc.Host, p.Grpc.NumPort = hostPortDefaults(p.Grpc.Host, p.Grpc.Port)
+	}
+}
+
+// hostPortDefaults applies the defaults shared by the socket based probes.
+func hostPortDefaults(host, port string) (string, int) {
+	if len(strings.TrimSpace(host)) == 0 {
+		host = "127.0.0.1"
+	}
+	numPort, _ := strconv.Atoi(port)
+	if numPort < 1 || numPort > 65535 {
+		// if undefined or wrong value - will be treated as undefined
+		numPort = 0
+	}
+	return host, numPort
 }
 
 func (p *HttpProbe) validateAndSetHttpDefaults() {
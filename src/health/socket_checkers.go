@@ -0,0 +1,181 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	grpcHealthCheckPath = "/grpc.health.v1.Health/Check"
+	// grpcServing is HealthCheckResponse.ServingStatus SERVING.
+	grpcServing = 1
+)
+
+// grpcServingStatus names the grpc.health.v1 HealthCheckResponse statuses.
+var grpcServingStatus = map[uint64]string{
+	0: "UNKNOWN",
+	1: "SERVING",
+	2: "NOT_SERVING",
+	3: "SERVICE_UNKNOWN",
+}
+
+type tcpChecker struct {
+	address string
+	timeout time.Duration
+}
+
+func (c *tcpChecker) Status() (any, error) {
+	rcMap := map[string]string{"target": c.address}
+	conn, err := net.DialTimeout("tcp", c.address, c.timeout)
+	if err != nil {
+		rcMap["error"] = err.Error()
+		return rcMap, err
+	}
+	_ = conn.Close()
+	return rcMap, nil
+}
+
+type grpcChecker struct {
+	address string
+	service string
+	timeout time.Duration
+	client  *http.Client
+}
+
+func newGrpcChecker(address, service string, timeout time.Duration) *grpcChecker {
+	var protocols http.Protocols
+	protocols.SetUnencryptedHTTP2(true)
+	return &grpcChecker{
+		address: address,
+		service: service,
+		timeout: timeout,
+		client: &http.Client{
+			Transport: &http.Transport{Protocols: &protocols},
+		},
+	}
+}
+
+func (c *grpcChecker) Status() (any, error) {
+	rcMap := map[string]string{"target": c.address + grpcHealthCheckPath}
+	if c.service != "" {
+		rcMap["service"] = c.service
+	}
+	status, err := c.check()
+	if err != nil {
+		rcMap["error"] = err.Error()
+		return rcMap, err
+	}
+	rcMap["output"] = grpcServingStatus[status]
+	if status != grpcServing {
+		err = fmt.Errorf("grpc health status is %s", rcMap["output"])
+		rcMap["error"] = err.Error()
+		return rcMap, err
+	}
+	return rcMap, nil
+}
+
+// check performs a unary grpc.health.v1.Health/Check call and returns the
+// reported serving status. The message is small and fixed, so it is framed
+// and encoded here rather than pulling in a full gRPC stack.
+func (c *grpcChecker) check() (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	var request []byte
+	if c.service != "" {
+		request = protowire.AppendTag(request, 1, protowire.BytesType)
+		request = protowire.AppendString(request, c.service)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://"+c.address+grpcHealthCheckPath, bytes.NewReader(grpcFrame(request)))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected HTTP status %s", resp.Status)
+	}
+	// Trailers-only responses carry grpc-status in the headers.
+	grpcStatus := resp.Trailer.Get("grpc-status")
+	if grpcStatus == "" {
+		grpcStatus = resp.Header.Get("grpc-status")
+	}
+	if grpcStatus != "0" {
+		message := resp.Trailer.Get("grpc-message")
+		if message == "" {
+			message = resp.Header.Get("grpc-message")
+		}
+		return 0, fmt.Errorf("grpc call failed with status %s %s", grpcStatus, message)
+	}
+	return parseServingStatus(body)
+}
+
+// grpcFrame prefixes message with the gRPC length-prefixed message header:
+// a zero compression flag and the big-endian message length.
+func grpcFrame(message []byte) []byte {
+	frame := make([]byte, 5, 5+len(message))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+	return append(frame, message...)
+}
+
+// parseServingStatus extracts HealthCheckResponse.status (field 1) from a
+// single framed response message. A missing field is proto3's zero value,
+// UNKNOWN.
+func parseServingStatus(body []byte) (uint64, error) {
+	if len(body) < 5 {
+		return 0, errors.New("grpc response is too short")
+	}
+	size := binary.BigEndian.Uint32(body[1:5])
+	message := body[5:]
+	if uint32(len(message)) < size {
+		return 0, errors.New("grpc response is truncated")
+	}
+	message = message[:size]
+	var status uint64
+	for len(message) > 0 {
+		num, typ, n := protowire.ConsumeTag(message)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		message = message[n:]
+		if num == 1 && typ == protowire.VarintType {
+			value, m := protowire.ConsumeVarint(message)
+			if m < 0 {
+				return 0, protowire.ParseError(m)
+			}
+			status = value
+			message = message[m:]
+			continue
+		}
+		m := protowire.ConsumeFieldValue(num, typ, message)
+		if m < 0 {
+			return 0, protowire.ParseError(m)
+		}
+		message = message[m:]
+	}
+	return status, nil
+}
+
+func joinHostPort(host string, port int) string {
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
@@ -0,0 +1,100 @@
+package health
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestTcpChecker(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+
+	checker := &tcpChecker{address: address, timeout: time.Second}
+	if _, err := checker.Status(); err != nil {
+		t.Errorf("Status() error = %v for an open port", err)
+	}
+	_ = listener.Close()
+	if _, err := checker.Status(); err == nil {
+		t.Errorf("Status() = nil error for a closed port")
+	}
+}
+
+// grpcHealthServer serves grpc.health.v1.Health/Check over plaintext HTTP/2,
+// answering with status for every service.
+func grpcHealthServer(t *testing.T, status uint64) string {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != grpcHealthCheckPath || r.ProtoMajor != 2 {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		var message []byte
+		message = protowire.AppendTag(message, 1, protowire.VarintType)
+		message = protowire.AppendVarint(message, status)
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Trailer", "grpc-status")
+		_, _ = w.Write(grpcFrame(message))
+		w.Header().Set("grpc-status", "0")
+	}))
+	var protocols http.Protocols
+	protocols.SetUnencryptedHTTP2(true)
+	server.Config.Protocols = &protocols
+	server.Start()
+	t.Cleanup(server.Close)
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+func TestGrpcChecker(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  uint64
+		wantErr bool
+	}{
+		{name: "serving", status: grpcServing},
+		{name: "not serving", status: 2, wantErr: true},
+		{name: "unknown", status: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := newGrpcChecker(grpcHealthServer(t, tt.status), "", time.Second)
+			details, err := checker.Status()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Status() error = %v, wantErr %v (details %v)", err, tt.wantErr, details)
+			}
+		})
+	}
+}
+
+func TestGrpcChecker_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+	if _, err := newGrpcChecker(address, "", time.Second).Status(); err == nil {
+		t.Errorf("Status() = nil error for a closed port")
+	}
+}
+
+func TestParseServingStatus(t *testing.T) {
+	if _, err := parseServingStatus([]byte{0, 0}); err == nil {
+		t.Errorf("parseServingStatus() = nil error for a short response")
+	}
+	if _, err := parseServingStatus(grpcFrame([]byte{0x08})[:5]); err == nil {
+		t.Errorf("parseServingStatus() = nil error for a truncated response")
+	}
+	status, err := parseServingStatus(grpcFrame(nil))
+	if err != nil || status != 0 {
+		t.Errorf("parseServingStatus() = %d, %v for an empty message, want 0, nil", status, err)
+	}
+}
@@ -0,0 +1,330 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// maxLineSize bounds a single journal line, well above any event written
+	// by process-compose itself.
+	maxLineSize = 1024 * 1024
+	// defaultMaxSize is the size past which the journal file is rotated. The
+	// previous file is kept, so a journal takes up to twice as much.
+	defaultMaxSize = 10 * 1024 * 1024
+	// rotatedSuffix is appended to the journal path to name the previous file.
+	rotatedSuffix = ".1"
+)
+
+// Journal is an append-only JSON Lines record of process lifecycle events. It
+// outlives process restarts, and process-compose runs, so the history of why
+// a process restarted is still there after the fact.
+//
+// A Journal is also a types.StateObserver: subscribed to the state
+// broadcaster, it records the status and health transitions. The events that
+// the state does not carry - exit codes, probe failures, restart reasons - are
+// recorded explicitly.
+//
+// All methods are safe to call on a nil *Journal, which records nothing.
+type Journal struct {
+	mtx     sync.Mutex
+	path    string
+	maxSize int64
+	file    *os.File
+	size    int64
+	seq     uint64
+	// last holds the status and health last recorded for every process, so
+	// that state events that change neither are not recorded.
+	last map[string]types.JournalEvent
+}
+
+// Open opens the journal at path, creating it if needed. Sequence numbers
+// continue from the last event already in the file.
+func Open(path string) (*Journal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	j := &Journal{
+		path:    path,
+		maxSize: defaultMaxSize,
+		last:    map[string]types.JournalEvent{},
+	}
+	snap, err := openSnapshot(path)
+	if err == nil {
+		err = snap.scan(func(ev *types.JournalEvent) {
+			j.seq = ev.Seq
+		})
+		snap.close()
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err = j.openFile(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// ReadFile returns the events selected by q from the journal at path, without
+// opening it for recording. It reads the journal of a process-compose that is
+// no longer running.
+func ReadFile(path string, q types.JournalQuery) ([]types.JournalEvent, error) {
+	snap, err := openSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+	defer snap.close()
+	return snap.query(q)
+}
+
+func (j *Journal) openFile() error {
+	file, err := os.OpenFile(j.path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0o600)
+	if err != nil {
+		return err
+	}
+	if err = terminateLastLine(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	j.file = file
+	j.size = info.Size()
+	return nil
+}
+
+// rotateLocked moves the journal file aside, replacing the previous rotated
+// one, and starts a new file. On failure, recording goes on in the current
+// file.
+func (j *Journal) rotateLocked() {
+	if err := j.file.Close(); err != nil {
+		log.Err(err).Msgf("Failed to close event journal %s", j.path)
+	}
+	j.file = nil
+	if err := os.Rename(j.path, j.path+rotatedSuffix); err != nil {
+		log.Err(err).Msgf("Failed to rotate event journal %s", j.path)
+	}
+	if err := j.openFile(); err != nil {
+		log.Err(err).Msgf("Failed to reopen event journal %s", j.path)
+	}
+}
+
+// terminateLastLine appends a newline if the file does not end with one, so
+// that a line cut short by a crash does not swallow the next event.
+func terminateLastLine(f *os.File) error {
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+	last := make([]byte, 1)
+	if _, err = f.ReadAt(last, info.Size()-1); err != nil {
+		return err
+	}
+	if last[0] != '\n' {
+		_, err = f.Write([]byte{'\n'})
+	}
+	return err
+}
+
+// Path returns the location of the journal file.
+func (j *Journal) Path() string {
+	if j == nil {
+		return ""
+	}
+	return j.path
+}
+
+// Record appends ev to the journal, stamping its sequence number and, unless
+// already set, its time. Write failures are logged rather than returned: a
+// full disk must not get in the way of supervising processes.
+func (j *Journal) Record(ev types.JournalEvent) {
+	if j == nil {
+		return
+	}
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	j.recordLocked(ev)
+}
+
+func (j *Journal) recordLocked(ev types.JournalEvent) {
+	if j.file == nil {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	j.seq++
+	ev.Seq = j.seq
+	data, err := json.Marshal(ev)
+	if err != nil {
+		log.Err(err).Msgf("Failed to encode journal event for %s", ev.Process)
+		return
+	}
+	data = append(data, '\n')
+	if j.size > 0 && j.size+int64(len(data)) > j.maxSize {
+		j.rotateLocked()
+		if j.file == nil {
+			return
+		}
+	}
+	n, err := j.file.Write(data)
+	j.size += int64(n)
+	if err != nil {
+		log.Err(err).Msgf("Failed to write to event journal %s", j.path)
+	}
+}
+
+// Notify implements types.StateObserver. Appending a line to a local file is
+// quick enough to be done while the broadcaster holds its lock.
+func (j *Journal) Notify(ev types.ProcessStateEvent) {
+	if j == nil || ev.Snapshot {
+		return
+	}
+	state := &ev.State
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	last, seen := j.last[state.Name]
+	if !seen || last.Status != state.Status {
+		j.recordLocked(types.JournalEvent{
+			Process: state.Name,
+			Type:    types.JournalEventStatus,
+			Status:  state.Status,
+		})
+	}
+	if state.HasHealthProbe && seen && last.Health != state.Health {
+		j.recordLocked(types.JournalEvent{
+			Process: state.Name,
+			Type:    types.JournalEventHealth,
+			Health:  state.Health,
+		})
+	}
+	j.last[state.Name] = types.JournalEvent{Status: state.Status, Health: state.Health}
+}
+
+// UniqueID implements types.StateObserver.
+func (j *Journal) UniqueID() string {
+	return "journal"
+}
+
+// Query returns the events selected by q, oldest first. The journal files are
+// read without holding the journal lock, so that a long query does not hold
+// up recording, and the state broadcasts that wait on it.
+func (j *Journal) Query(q types.JournalQuery) ([]types.JournalEvent, error) {
+	if j == nil {
+		return nil, errors.New("the event journal is disabled")
+	}
+	j.mtx.Lock()
+	snap, err := openSnapshot(j.path)
+	j.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	defer snap.close()
+	return snap.query(q)
+}
+
+// Close closes the journal file. Events recorded afterward are dropped.
+func (j *Journal) Close() error {
+	if j == nil {
+		return nil
+	}
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// snapshot is the content of the journal files, the rotated one first, as of
+// when they were opened: events recorded, and rotations made, while a snapshot
+// is read are not part of it.
+type snapshot struct {
+	files []*os.File
+	sizes []int64
+}
+
+func openSnapshot(path string) (*snapshot, error) {
+	snap := &snapshot{}
+	var missing error
+	for _, name := range []string{path + rotatedSuffix, path} {
+		f, err := os.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			missing = err
+			continue
+		}
+		var info os.FileInfo
+		if err == nil {
+			if info, err = f.Stat(); err != nil {
+				_ = f.Close()
+			}
+		}
+		if err != nil {
+			snap.close()
+			return nil, err
+		}
+		snap.files = append(snap.files, f)
+		snap.sizes = append(snap.sizes, info.Size())
+	}
+	if len(snap.files) == 0 {
+		return nil, missing
+	}
+	return snap, nil
+}
+
+func (s *snapshot) close() {
+	for _, f := range s.files {
+		_ = f.Close()
+	}
+}
+
+func (s *snapshot) query(q types.JournalQuery) ([]types.JournalEvent, error) {
+	events := []types.JournalEvent{}
+	err := s.scan(func(ev *types.JournalEvent) {
+		if q.Matches(ev) {
+			events = append(events, *ev)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if q.Limit > 0 && len(events) > q.Limit {
+		events = events[len(events)-q.Limit:]
+	}
+	return events, nil
+}
+
+// scan calls fn for every event in the snapshot. Lines that do not parse,
+// such as one cut short by a crash, are skipped.
+func (s *snapshot) scan(fn func(ev *types.JournalEvent)) error {
+	for i, f := range s.files {
+		scanner := bufio.NewScanner(io.NewSectionReader(f, 0, s.sizes[i]))
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		for scanner.Scan() {
+			var ev types.JournalEvent
+			if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+				continue
+			}
+			fn(&ev)
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func openTestJournal(t *testing.T, path string) *Journal {
+	t.Helper()
+	j, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = j.Close() })
+	return j
+}
+
+func TestJournal_RecordAndQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "events.jsonl")
+	j := openTestJournal(t, path)
+
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	exitCode := 2
+	j.Record(types.JournalEvent{Time: base, Process: "web", Type: types.JournalEventStatus, Status: types.ProcessStateRunning})
+	j.Record(types.JournalEvent{Time: base.Add(time.Minute), Process: "db", Type: types.JournalEventStatus, Status: types.ProcessStateRunning})
+	j.Record(types.JournalEvent{Time: base.Add(2 * time.Minute), Process: "web", Type: types.JournalEventExit, ExitCode: &exitCode})
+	j.Record(types.JournalEvent{Time: base.Add(3 * time.Minute), Process: "web", Type: types.JournalEventRestart, Reason: "exited with code 2"})
+
+	tests := []struct {
+		name    string
+		query   types.JournalQuery
+		wantSeq []uint64
+	}{
+		{"all", types.JournalQuery{}, []uint64{1, 2, 3, 4}},
+		{"process", types.JournalQuery{Process: "web"}, []uint64{1, 3, 4}},
+		{"since", types.JournalQuery{Since: base.Add(90 * time.Second)}, []uint64{3, 4}},
+		{"after seq", types.JournalQuery{AfterSeq: 2}, []uint64{3, 4}},
+		{"limit keeps the latest", types.JournalQuery{Process: "web", Limit: 2}, []uint64{3, 4}},
+		{"no match", types.JournalQuery{Process: "api"}, []uint64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := j.Query(tt.query)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if len(events) != len(tt.wantSeq) {
+				t.Fatalf("Query() returned %d events, want %d", len(events), len(tt.wantSeq))
+			}
+			for i, ev := range events {
+				if ev.Seq != tt.wantSeq[i] {
+					t.Errorf("event %d seq = %d, want %d", i, ev.Seq, tt.wantSeq[i])
+				}
+			}
+		})
+	}
+
+	events, _ := j.Query(types.JournalQuery{Process: "web", Limit: 2})
+	if events[0].ExitCode == nil || *events[0].ExitCode != 2 {
+		t.Errorf("exit event lost its exit code: %+v", events[0])
+	}
+}
+
+func TestJournal_ReopenContinuesSequence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	j := openTestJournal(t, path)
+	j.Record(types.JournalEvent{Process: "web", Type: types.JournalEventStatus})
+	j.Record(types.JournalEvent{Process: "web", Type: types.JournalEventStatus})
+	_ = j.Close()
+
+	// Simulate a crash in the middle of writing an event.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"seq":3,"process":"we`)
+	_ = f.Close()
+
+	j = openTestJournal(t, path)
+	j.Record(types.JournalEvent{Process: "web", Type: types.JournalEventRestart})
+	events, err := j.Query(types.JournalQuery{})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Query() returned %d events, want 3", len(events))
+	}
+	if last := events[2]; last.Seq != 3 || last.Type != types.JournalEventRestart {
+		t.Errorf("last event = %+v, want seq 3 restart", last)
+	}
+}
+
+func TestJournal_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	j := openTestJournal(t, path)
+	// Room for two events per file
+	line, _ := json.Marshal(types.JournalEvent{Seq: 1, Time: time.Now(), Process: "web", Type: types.JournalEventStatus, Status: types.ProcessStateRunning})
+	j.maxSize = int64(2*len(line) + 10)
+	for range 5 {
+		j.Record(types.JournalEvent{Process: "web", Type: types.JournalEventStatus, Status: types.ProcessStateRunning})
+	}
+
+	seqs := func(events []types.JournalEvent) []uint64 {
+		got := []uint64{}
+		for _, ev := range events {
+			got = append(got, ev.Seq)
+		}
+		return got
+	}
+	events, err := j.Query(types.JournalQuery{})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if got := seqs(events); !slices.Equal(got, []uint64{3, 4, 5}) {
+		t.Errorf("Query() seqs = %v, want [3 4 5]", got)
+	}
+	for _, name := range []string{path, path + rotatedSuffix} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > j.maxSize {
+			t.Errorf("%s is %d bytes, over the %d bytes limit", name, info.Size(), j.maxSize)
+		}
+	}
+	_ = j.Close()
+
+	// The sequence continues from the rotated file when the current one is
+	// empty, as left by a crash right after a rotation
+	if err = os.Rename(path, path+rotatedSuffix); err != nil {
+		t.Fatal(err)
+	}
+	j = openTestJournal(t, path)
+	j.Record(types.JournalEvent{Process: "web", Type: types.JournalEventRestart})
+	events, err = ReadFile(path, types.JournalQuery{})
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if got := seqs(events); !slices.Equal(got, []uint64{5, 6}) {
+		t.Errorf("ReadFile() seqs = %v, want [5 6]", got)
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	if _, err := ReadFile(path, types.JournalQuery{}); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ReadFile() error = %v for a missing journal, want ErrNotExist", err)
+	}
+	j := openTestJournal(t, path)
+	j.Record(types.JournalEvent{Process: "web", Type: types.JournalEventStatus})
+	j.Record(types.JournalEvent{Process: "db", Type: types.JournalEventStatus})
+	_ = j.Close()
+
+	events, err := ReadFile(path, types.JournalQuery{Process: "db"})
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if len(events) != 1 || events[0].Seq != 2 {
+		t.Errorf("ReadFile() = %+v, want the db event", events)
+	}
+}
+
+func TestJournal_Notify(t *testing.T) {
+	j := openTestJournal(t, filepath.Join(t.TempDir(), "events.jsonl"))
+	notify := func(status, health string, snapshot bool) {
+		j.Notify(types.ProcessStateEvent{
+			Snapshot: snapshot,
+			State:    types.ProcessState{Name: "web", Status: status, Health: health, HasHealthProbe: true},
+		})
+	}
+	notify(types.ProcessStateRunning, types.ProcessHealthUnknown, true)
+	notify(types.ProcessStateRunning, types.ProcessHealthUnknown, false)
+	notify(types.ProcessStateRunning, types.ProcessHealthUnknown, false)
+	notify(types.ProcessStateRunning, types.ProcessHealthReady, false)
+	notify(types.ProcessStateCompleted, types.ProcessHealthReady, false)
+
+	events, err := j.Query(types.JournalQuery{})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	want := []types.JournalEvent{
+		{Type: types.JournalEventStatus, Status: types.ProcessStateRunning},
+		{Type: types.JournalEventHealth, Health: types.ProcessHealthReady},
+		{Type: types.JournalEventStatus, Status: types.ProcessStateCompleted},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("recorded %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, ev := range events {
+		if ev.Type != want[i].Type || ev.Status != want[i].Status || ev.Health != want[i].Health {
+			t.Errorf("event %d = %+v, want %+v", i, ev, want[i])
+		}
+	}
+}
+
+func TestJournal_Nil(t *testing.T) {
+	var j *Journal
+	j.Record(types.JournalEvent{Process: "web"})
+	j.Notify(types.ProcessStateEvent{})
+	if _, err := j.Query(types.JournalQuery{}); err == nil {
+		t.Error("Query() on a disabled journal should fail")
+	}
+	if err := j.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+// Package limits enforces per-process resource limits.
+//
+// On Linux every limited process is started in its own cgroup v2 leaf when the
+// cgroup process-compose runs in is delegated to it (e.g. a systemd user
+// service with Delegate=yes, or `systemd-run --user --scope -p Delegate=yes`).
+// Starting a process directly in a cgroup requires Linux 5.7 or later. When the
+// cgroup is not delegated, the limits that setrlimit can express are applied
+// instead.
+//
+// cgroup v2 only lets controllers be enabled for the children of a cgroup that
+// holds no processes itself, so the first time a leaf is needed
+// process-compose moves itself, and the processes it already started, into a
+// pc-supervisor child of its cgroup. It stays there until it exits.
+package limits
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+// Limiter applies one process's ResourceLimits to each of its incarnations.
+// A single cgroup leaf is reused across restarts so that its OOM counters
+// keep accumulating; it is only removed by Release.
+type Limiter struct {
+	mtx  sync.Mutex
+	name string
+	conf *types.ResourceLimits
+	// leaf is the cgroup directory of the process, empty when the limits are
+	// not enforced by cgroups.
+	leaf string
+	// cgroupDir is the leaf opened for the incarnation being started, until
+	// Started is called.
+	cgroupDir *os.File
+}
+
+// New creates a Limiter for the process name. It returns nil when conf does
+// not limit anything; all Limiter methods are safe to call on nil.
+func New(name string, conf *types.ResourceLimits) *Limiter {
+	if !conf.IsEnabled() {
+		return nil
+	}
+	return &Limiter{
+		name: name,
+		conf: conf,
+	}
+}
+
+// Update replaces the limits applied to the next incarnations of the process.
+func (l *Limiter) Update(conf *types.ResourceLimits) {
+	if l == nil {
+		return
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.conf = conf
+}
+
+// Prepare sets cmd up, before it is started, so that the process runs under
+// the configured limits from its very first instruction: it is started inside
+// its cgroup leaf, and its rlimits are lowered before the command is executed.
+// Started must be called once cmd has been started, or failed to.
+//
+// It reports which limits are in effect. A non-nil error describes the limits
+// that could not be applied; the returned EffectiveLimits is valid either way.
+func (l *Limiter) Prepare(cmd *exec.Cmd) (*types.EffectiveLimits, error) {
+	if l == nil {
+		return nil, nil
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.prepare(cmd)
+}
+
+// Started releases what Prepare held on to for starting the process.
+func (l *Limiter) Started() {
+	if l == nil {
+		return
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.cgroupDir != nil {
+		_ = l.cgroupDir.Close()
+		l.cgroupDir = nil
+	}
+}
+
+// OOMKills returns how many times the OOM killer fired inside the process's
+// cgroup. It is always 0 when the limits are not enforced by cgroups.
+func (l *Limiter) OOMKills() int {
+	if l == nil {
+		return 0
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.leaf == "" {
+		return 0
+	}
+	return l.oomKills()
+}
+
+// Release removes the process's cgroup leaf. It must only be called once the
+// process is removed from the project, or the project shuts down, and every
+// descendant it left behind has exited.
+func (l *Limiter) Release() {
+	if l == nil {
+		return
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.leaf == "" {
+		return
+	}
+	l.release()
+}
+
+// leafName maps a process name to a cgroup directory name. Process names may
+// contain path separators, which are not valid in a single path element.
+func leafName(name string) string {
+	return "pc-" + strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+}
@@ -0,0 +1,347 @@
+package limits
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	cgroupRoot = "/sys/fs/cgroup"
+	// supervisorLeaf holds process-compose itself once it has moved out of
+	// the cgroup it was started in.
+	supervisorLeaf = "pc-supervisor"
+	// shellPath runs the ulimit builtin for the rlimit fallback.
+	shellPath = "/bin/sh"
+	// cpuPeriod is the cpu.max bandwidth period, in microseconds.
+	cpuPeriod = 100000
+	// minCPUQuota is the smallest cpu.max quota the kernel accepts.
+	minCPUQuota = 1000
+)
+
+var (
+	controllers = []string{"cpu", "memory", "pids"}
+
+	delegateOnce sync.Once
+	delegatedDir string
+	delegateErr  error
+)
+
+func (l *Limiter) prepare(cmd *exec.Cmd) (*types.EffectiveLimits, error) {
+	// Both values were validated when the project was loaded.
+	memoryMax, _ := l.conf.GetMemoryMaxBytes()
+	cpuQuota, _ := l.conf.GetCPUQuotaPercent()
+
+	effective := &types.EffectiveLimits{Enforcer: types.LimitsEnforcerCgroup}
+	var errs []error
+	var rlimits []rlimit
+	if err := l.prepareCgroup(cmd, memoryMax, cpuQuota, effective); err != nil {
+		log.Debug().Err(err).Str("process", l.name).Msg("cgroup limits unavailable, falling back to rlimit")
+		effective = &types.EffectiveLimits{Enforcer: types.LimitsEnforcerRlimit}
+		if memoryMax > 0 {
+			// RLIMIT_AS caps address space rather than resident memory, so
+			// runtimes that reserve large virtual regions up front may need a
+			// generous value.
+			rlimits = append(rlimits, rlimit{resource: unix.RLIMIT_AS, option: "-v", unit: 1024, value: uint64(memoryMax)})
+		}
+		if cpuQuota > 0 || l.conf.PidsMax > 0 {
+			errs = append(errs, fmt.Errorf("cpu_quota and pids_max require a delegated cgroup v2 hierarchy: %w", err))
+		}
+	}
+	if l.conf.NoFile > 0 {
+		rlimits = append(rlimits, rlimit{resource: unix.RLIMIT_NOFILE, option: "-n", unit: 1, value: l.conf.NoFile})
+	}
+	if len(rlimits) > 0 {
+		if err := withRlimits(cmd, rlimits); err != nil {
+			errs = append(errs, fmt.Errorf("rlimits: %w", err))
+			return effective, errors.Join(errs...)
+		}
+		for _, limit := range rlimits {
+			switch limit.resource {
+			case unix.RLIMIT_AS:
+				effective.MemoryMax = int64(limit.value)
+			case unix.RLIMIT_NOFILE:
+				effective.NoFile = limit.value
+			}
+		}
+	}
+	return effective, errors.Join(errs...)
+}
+
+func (l *Limiter) prepareCgroup(cmd *exec.Cmd, memoryMax int64, cpuQuota float64, effective *types.EffectiveLimits) error {
+	base, err := delegatedCgroup()
+	if err != nil {
+		return err
+	}
+	leaf := filepath.Join(base, leafName(l.name))
+	if err = os.Mkdir(leaf, 0o755); err != nil && !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+	var dir *os.File
+	err = l.writeLimits(leaf, memoryMax, cpuQuota, effective)
+	if err == nil {
+		dir, err = os.Open(leaf)
+	}
+	if err != nil {
+		_ = os.Remove(leaf)
+		l.leaf = ""
+		return err
+	}
+	l.leaf = leaf
+	l.cgroupDir = dir
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(dir.Fd())
+	return nil
+}
+
+// writeLimits sets the limits of the leaf. The limits that are not configured
+// are lifted, as the leaf may have been created for an earlier configuration.
+func (l *Limiter) writeLimits(leaf string, memoryMax int64, cpuQuota float64, effective *types.EffectiveLimits) error {
+	memory := ""
+	if memoryMax > 0 {
+		memory = strconv.FormatInt(memoryMax, 10)
+		effective.MemoryMax = memoryMax
+	}
+	if err := setCgroupLimit(leaf, "memory.max", memory); err != nil {
+		return err
+	}
+	cpu := ""
+	if cpuQuota > 0 {
+		quota := max(int64(cpuQuota/100*cpuPeriod), minCPUQuota)
+		cpu = fmt.Sprintf("%d %d", quota, cpuPeriod)
+		effective.CPUQuota = cpuQuota
+	}
+	if err := setCgroupLimit(leaf, "cpu.max", cpu); err != nil {
+		return err
+	}
+	pids := ""
+	if l.conf.PidsMax > 0 {
+		pids = strconv.Itoa(l.conf.PidsMax)
+		effective.PidsMax = l.conf.PidsMax
+	}
+	return setCgroupLimit(leaf, "pids.max", pids)
+}
+
+// setCgroupLimit writes a limit file of the leaf, or lifts the limit when value
+// is empty. Lifting a limit of a controller the leaf does not have is a no-op.
+func setCgroupLimit(leaf, name, value string) error {
+	if value != "" {
+		return writeCgroupFile(leaf, name, value)
+	}
+	if err := writeCgroupFile(leaf, name, "max"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l *Limiter) oomKills() int {
+	data, err := os.ReadFile(filepath.Join(l.leaf, "memory.events"))
+	if err != nil {
+		return 0
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), " ")
+		if key == "oom_kill" {
+			kills, _ := strconv.Atoi(value)
+			return kills
+		}
+	}
+	return 0
+}
+
+func (l *Limiter) release() {
+	if err := os.Remove(l.leaf); err != nil {
+		log.Debug().Err(err).Str("process", l.name).Msgf("failed to remove cgroup %s", l.leaf)
+	}
+	l.leaf = ""
+}
+
+// delegatedCgroup returns the cgroup under which process leaves are created,
+// preparing it on first use.
+func delegatedCgroup() (string, error) {
+	delegateOnce.Do(func() {
+		delegatedDir, delegateErr = setupDelegation()
+		if delegateErr == nil {
+			log.Info().Msgf("Enforcing resource limits with cgroups under %s", delegatedDir)
+		}
+	})
+	return delegatedDir, delegateErr
+}
+
+func setupDelegation() (string, error) {
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		return "", fmt.Errorf("cgroup v2 is not mounted at %s", cgroupRoot)
+	}
+	own, err := ownCgroup()
+	if err != nil {
+		return "", err
+	}
+	base := filepath.Join(cgroupRoot, own)
+	// The root cgroup is exempt from the "no internal processes" rule.
+	if own != "/" {
+		if err = evacuate(base); err != nil {
+			return "", err
+		}
+	}
+	available, err := os.ReadFile(filepath.Join(base, "cgroup.controllers"))
+	if err != nil {
+		return "", err
+	}
+	var enable []string
+	for _, controller := range strings.Fields(string(available)) {
+		if slices.Contains(controllers, controller) {
+			enable = append(enable, "+"+controller)
+		}
+	}
+	if len(enable) > 0 {
+		if err = writeCgroupFile(base, "cgroup.subtree_control", strings.Join(enable, " ")); err != nil {
+			return "", err
+		}
+	}
+	return base, nil
+}
+
+// evacuate moves process-compose, and any process it already started, from
+// base into a supervisor leaf. cgroup v2 only lets controllers be enabled for
+// the children of a cgroup that holds no processes itself. A cgroup shared
+// with processes process-compose does not own is left alone.
+func evacuate(base string) error {
+	pids, err := readPids(filepath.Join(base, "cgroup.procs"))
+	if err != nil {
+		return err
+	}
+	self := os.Getpid()
+	for _, pid := range pids {
+		if pid != self && !isDescendant(pid, self) {
+			return fmt.Errorf("cgroup %s is shared with process %d, which process-compose does not own", base, pid)
+		}
+	}
+	supervisor := filepath.Join(base, supervisorLeaf)
+	if err = os.Mkdir(supervisor, 0o755); err != nil && !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+	for _, pid := range pids {
+		if err = writeCgroupFile(supervisor, "cgroup.procs", strconv.Itoa(pid)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ownCgroup returns the unified hierarchy path of the current process.
+func ownCgroup() (string, error) {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", err
+	}
+	for line := range strings.SplitSeq(string(data), "\n") {
+		if path, ok := strings.CutPrefix(line, "0::"); ok {
+			return path, nil
+		}
+	}
+	return "", errors.New("process-compose is not in a cgroup v2 hierarchy")
+}
+
+func readPids(path string) ([]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var pids []int
+	for _, field := range strings.Fields(string(data)) {
+		pid, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+		pids = append(pids, pid)
+	}
+	return pids, nil
+}
+
+func isDescendant(pid, ancestor int) bool {
+	for pid > 1 {
+		ppid, err := parentPid(pid)
+		if err != nil {
+			return false
+		}
+		if ppid == ancestor {
+			return true
+		}
+		pid = ppid
+	}
+	return false
+}
+
+func parentPid(pid int) (int, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+	// The command name is parenthesized and may itself contain spaces and
+	// parentheses, so parse from the last ')': "<state> <ppid> ...".
+	fields := strings.Fields(string(data[bytes.LastIndexByte(data, ')')+1:]))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+	return strconv.Atoi(fields[1])
+}
+
+func writeCgroupFile(dir, name, value string) error {
+	return os.WriteFile(filepath.Join(dir, name), []byte(value), 0o644)
+}
+
+// rlimit is a resource limit set by the shell's ulimit builtin.
+type rlimit struct {
+	resource int
+	// option is the ulimit option of the resource.
+	option string
+	// unit is the size of the ulimit value of the resource, e.g. 1024 when
+	// ulimit counts KiB.
+	unit uint64
+	// value is the limit, capped by withRlimits to what can be set.
+	value uint64
+}
+
+// withRlimits makes cmd set both the soft and hard limits before it executes
+// the command, by running it through `sh -c 'ulimit ... && exec "$0" "$@"'`.
+// The process keeps its pid, and the limits are in place before the command
+// runs a single instruction, which prlimit on the started process can't
+// ensure. Each value is lowered to the hard limit process-compose runs with,
+// which a non-root process can't raise.
+func withRlimits(cmd *exec.Cmd, limits []rlimit) error {
+	if cmd.Err != nil {
+		return cmd.Err
+	}
+	var script strings.Builder
+	for i := range limits {
+		limit := &limits[i]
+		var current unix.Rlimit
+		if err := unix.Getrlimit(limit.resource, &current); err != nil {
+			return err
+		}
+		limit.value = min(limit.value, current.Max) / limit.unit * limit.unit
+		fmt.Fprintf(&script, "ulimit %s %d && ", limit.option, limit.value/limit.unit)
+	}
+	script.WriteString(`exec "$0" "$@"`)
+	cmd.Args = append([]string{shellPath, "-c", script.String(), cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = shellPath
+	return nil
+}
@@ -0,0 +1,118 @@
+package limits
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"golang.org/x/sys/unix"
+)
+
+func startSleeper(t *testing.T) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command("sleep", "10")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start sleeper: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	return cmd
+}
+
+func TestNew_NoLimits(t *testing.T) {
+	if l := New("proc", nil); l != nil {
+		t.Errorf("New() = %v for nil config, want nil", l)
+	}
+	if l := New("proc", &types.ResourceLimits{}); l != nil {
+		t.Errorf("New() = %v for empty config, want nil", l)
+	}
+	var l *Limiter
+	if eff, err := l.Prepare(exec.Command("true")); eff != nil || err != nil {
+		t.Errorf("nil Prepare() = %v, %v, want nil, nil", eff, err)
+	}
+	l.Started()
+	if kills := l.OOMKills(); kills != 0 {
+		t.Errorf("nil OOMKills() = %d, want 0", kills)
+	}
+	l.Release()
+}
+
+func TestLeafName(t *testing.T) {
+	if got := leafName("ns/api-1"); got != "pc-ns_api-1" {
+		t.Errorf("leafName() = %s, want pc-ns_api-1", got)
+	}
+}
+
+func TestIsDescendant(t *testing.T) {
+	cmd := startSleeper(t)
+	if !isDescendant(cmd.Process.Pid, os.Getpid()) {
+		t.Errorf("isDescendant() = false for own child")
+	}
+	if isDescendant(os.Getpid(), cmd.Process.Pid) {
+		t.Errorf("isDescendant() = true for own parent")
+	}
+}
+
+func TestWithRlimits(t *testing.T) {
+	var hard unix.Rlimit
+	if err := unix.Getrlimit(unix.RLIMIT_NOFILE, &hard); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		value uint64
+		want  uint64
+	}{
+		{name: "lowered", value: 64, want: 64},
+		{name: "capped by the hard limit", value: hard.Max + 1, want: hard.Max},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// hard.Max+1 wraps around when the hard limit is unlimited
+			if tt.value < tt.want {
+				t.Skip("no finite hard limit to cap by")
+			}
+			cmd := exec.Command("sh", "-c", `echo "$0 $1 $(ulimit -S -n) $(ulimit -H -n)"`, "first", "second")
+			limits := []rlimit{{resource: unix.RLIMIT_NOFILE, option: "-n", unit: 1, value: tt.value}}
+			if err := withRlimits(cmd, limits); err != nil {
+				t.Fatalf("withRlimits() error = %v", err)
+			}
+			if limits[0].value != tt.want {
+				t.Errorf("value = %d, want %d", limits[0].value, tt.want)
+			}
+			out, err := cmd.Output()
+			if err != nil {
+				t.Fatalf("command failed: %v", err)
+			}
+			want := fmt.Sprintf("first second %d %d\n", tt.want, tt.want)
+			if string(out) != want {
+				t.Errorf("output = %q, want %q", out, want)
+			}
+		})
+	}
+}
+
+func TestWithRlimits_CommandNotFound(t *testing.T) {
+	cmd := exec.Command("process-compose-no-such-command")
+	limits := []rlimit{{resource: unix.RLIMIT_NOFILE, option: "-n", unit: 1, value: 64}}
+	if err := withRlimits(cmd, limits); err == nil {
+		t.Errorf("withRlimits() error = nil for a missing command")
+	}
+}
+
+func TestOOMKills(t *testing.T) {
+	leaf := t.TempDir()
+	events := "low 0\nhigh 0\nmax 12\noom 3\noom_kill 2\noom_group_kill 0\n"
+	if err := os.WriteFile(filepath.Join(leaf, "memory.events"), []byte(events), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	l := &Limiter{name: "proc", leaf: leaf}
+	if kills := l.OOMKills(); kills != 2 {
+		t.Errorf("OOMKills() = %d, want 2", kills)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !linux
+
+package limits
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func (l *Limiter) prepare(_ *exec.Cmd) (*types.EffectiveLimits, error) {
+	return &types.EffectiveLimits{Enforcer: types.LimitsEnforcerNone},
+		fmt.Errorf("resource limits are not supported on %s", runtime.GOOS)
+}
+
+func (l *Limiter) oomKills() int {
+	return 0
+}
+
+func (l *Limiter) release() {}
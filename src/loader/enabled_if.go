@@ -0,0 +1,129 @@
+package loader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/expr-lang/expr"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/joho/godotenv"
+)
+
+// evaluateEnabledIf disables the processes whose enabled_if expression is
+// false. The expressions see the environment of the process, the vars, the
+// OS and architecture, and the active profiles.
+func evaluateEnabledIf(p *types.Project, profiles []string) error {
+	for name, proc := range p.Processes {
+		if proc.EnabledIf == "" {
+			continue
+		}
+		enabled, err := evalEnabledIf(p, &proc, profiles)
+		if err != nil {
+			return fmt.Errorf("invalid enabled_if of process %s: %w", name, err)
+		}
+		proc.Disabled = proc.Disabled || !enabled
+		p.Processes[name] = proc
+	}
+	return nil
+}
+
+func evalEnabledIf(p *types.Project, proc *types.ProcessConfig, profiles []string) (bool, error) {
+	env := enabledIfEnv(p, proc, profiles)
+	program, err := expr.Compile(proc.EnabledIf, expr.Env(env), expr.AsBool())
+	if err != nil {
+		return false, err
+	}
+	out, err := expr.Run(program, env)
+	if err != nil {
+		return false, err
+	}
+	return out.(bool), nil
+}
+
+// enabledIfEnv returns the variables and functions of the enabled_if
+// expressions:
+//
+//	env       the environment of the process, by name
+//	vars      the project vars, overridden by the process vars
+//	os, arch  the OS and architecture, as in GOOS and GOARCH
+//	profiles  the active profiles
+//	name      the name of the process
+//	replica   the replica number of the process
+//	profile(names...)  whether one of the profiles is active
+//	exists(path)       whether the path exists, relative to working_dir
+func enabledIfEnv(p *types.Project, proc *types.ProcessConfig, profiles []string) map[string]any {
+	vars := map[string]any{}
+	for k, v := range p.TemplateVars() {
+		vars[k] = v
+	}
+	for k, v := range proc.Vars {
+		vars[k] = v
+	}
+	if profiles == nil {
+		profiles = []string{}
+	}
+	return map[string]any{
+		"env":      enabledIfProcessEnv(p, proc),
+		"vars":     vars,
+		"os":       runtime.GOOS,
+		"arch":     runtime.GOARCH,
+		"profiles": profiles,
+		"name":     proc.Name,
+		"replica":  proc.ReplicaNum,
+		"profile": func(names ...string) bool {
+			return slices.ContainsFunc(names, func(name string) bool {
+				return slices.Contains(profiles, name)
+			})
+		},
+		"exists": func(path string) bool {
+			if !filepath.IsAbs(path) && proc.WorkingDir != "" {
+				path = filepath.Join(proc.WorkingDir, path)
+			}
+			_, err := os.Stat(path)
+			return err == nil
+		},
+	}
+}
+
+// enabledIfProcessEnv returns the environment of the process, with the
+// precedence of the running processes, except for the secrets.
+func enabledIfProcessEnv(p *types.Project, proc *types.ProcessConfig) map[string]string {
+	env := map[string]string{}
+	if !proc.DisableDotEnv {
+		for k, v := range p.DotEnvVars {
+			env[k] = v
+		}
+	}
+	setEnv := func(vars []string) {
+		for _, kv := range vars {
+			if k, v, ok := strings.Cut(kv, "="); ok {
+				env[k] = v
+			}
+		}
+	}
+	if proc.Container == nil {
+		setEnv(os.Environ())
+	}
+	setEnv(p.Environment)
+	if proc.EnvFile != "" {
+		envFile := proc.EnvFile
+		if !filepath.IsAbs(envFile) && proc.WorkingDir != "" {
+			envFile = filepath.Join(proc.WorkingDir, envFile)
+		}
+		// A missing env_file is reported by validateProcessEnvFileExists.
+		if vars, err := godotenv.Read(envFile); err == nil {
+			for k, v := range vars {
+				env[k] = v
+			}
+		}
+	}
+	setEnv(proc.Environment)
+	if proc.Container != nil {
+		setEnv(proc.Container.Environment)
+	}
+	return env
+}
@@ -0,0 +1,77 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func Test_evaluateEnabledIf(t *testing.T) {
+	t.Setenv("PC_TEST_GPU", "0")
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "gpu.conf"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name         string
+		enabledIf    string
+		disabled     bool
+		profiles     []string
+		wantDisabled bool
+		wantErr      bool
+	}{
+		{name: "no expression", enabledIf: "", wantDisabled: false},
+		{name: "no expression with disabled", enabledIf: "", disabled: true, wantDisabled: true},
+		{name: "system env set", enabledIf: `"PC_TEST_GPU" in env`, wantDisabled: false},
+		{name: "system env unset", enabledIf: `"PC_TEST_UNSET" in env`, wantDisabled: true},
+		{name: "process env overrides", enabledIf: `env.PC_TEST_GPU == "1"`, wantDisabled: false},
+		{name: "global env", enabledIf: `env.LEVEL == "debug"`, wantDisabled: false},
+		{name: "dotenv", enabledIf: `env.FROM_DOTENV == "yes"`, wantDisabled: false},
+		{name: "project var", enabledIf: `vars.REGION == "eu"`, wantDisabled: false},
+		{name: "process var overrides", enabledIf: `vars.MODE == "fast"`, wantDisabled: false},
+		{name: "os", enabledIf: `os == "` + runtime.GOOS + `" && arch == "` + runtime.GOARCH + `"`, wantDisabled: false},
+		{name: "file exists", enabledIf: `exists("gpu.conf")`, wantDisabled: false},
+		{name: "file missing", enabledIf: `exists("cpu.conf")`, wantDisabled: true},
+		{name: "profile active", enabledIf: `profile("offline")`, profiles: []string{"dev", "offline"}, wantDisabled: false},
+		{name: "profile inactive", enabledIf: `profile("offline", "test")`, profiles: []string{"dev"}, wantDisabled: true},
+		{name: "no profiles", enabledIf: `len(profiles) == 0`, wantDisabled: false},
+		{name: "name", enabledIf: `name == "worker" && replica == 0`, wantDisabled: false},
+		{name: "true with disabled", enabledIf: "true", disabled: true, wantDisabled: true},
+		{name: "not a bool", enabledIf: `env.PC_TEST_GPU`, wantErr: true},
+		{name: "syntax error", enabledIf: `env.PC_TEST_GPU ==`, wantErr: true},
+		{name: "unknown variable", enabledIf: `gpu == true`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &types.Project{
+				Environment: types.Environment{"LEVEL=debug"},
+				Vars:        types.Vars{"REGION": "eu", "MODE": "slow"},
+				DotEnvVars:  map[string]string{"FROM_DOTENV": "yes"},
+				Processes: types.Processes{
+					"worker": {
+						Name:        "worker",
+						ReplicaName: "worker",
+						EnabledIf:   tt.enabledIf,
+						Disabled:    tt.disabled,
+						WorkingDir:  dir,
+						Environment: types.Environment{"PC_TEST_GPU=1"},
+						Vars:        types.Vars{"MODE": "fast"},
+					},
+				},
+			}
+			err := evaluateEnabledIf(p, tt.profiles)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evaluateEnabledIf() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := p.Processes["worker"].Disabled; got != tt.wantDisabled {
+				t.Errorf("Disabled = %t, want %t", got, tt.wantDisabled)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package loader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"gopkg.in/yaml.v3"
+)
+
+// The formats of the files that can be imported into a project.
+const (
+	FormatProcessCompose = "process-compose"
+	FormatDockerCompose  = "docker-compose"
+	FormatProcfile       = "procfile"
+)
+
+// DefaultImportFileNames are suggested for import when there is no
+// process-compose file.
+var DefaultImportFileNames = []string{
+	"docker-compose.yml",
+	"docker-compose.yaml",
+	"Procfile",
+}
+
+// DetectFormat returns the format of the file: Procfile and Procfile.* are
+// Procfiles, and the YAML files with services but without processes are
+// docker-compose files, whatever their name.
+func DetectFormat(fileName string, data []byte) string {
+	base := filepath.Base(fileName)
+	if base == "Procfile" || strings.HasPrefix(base, "Procfile.") {
+		return FormatProcfile
+	}
+	var keys map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &keys); err != nil {
+		return FormatProcessCompose
+	}
+	_, hasServices := keys["services"]
+	_, hasProcesses := keys["processes"]
+	if hasServices && !hasProcesses {
+		return FormatDockerCompose
+	}
+	return FormatProcessCompose
+}
+
+// ImportFile converts a docker-compose file or a Procfile into a project. The
+// settings that have no process-compose equivalent are returned as warnings.
+func ImportFile(fileName string) (*types.Project, []string, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return importProject(fileName, data)
+}
+
+func importProject(fileName string, data []byte) (*types.Project, []string, error) {
+	switch DetectFormat(fileName, data) {
+	case FormatProcfile:
+		return importProcfile(data)
+	case FormatDockerCompose:
+		return importDockerCompose(data)
+	}
+	return nil, nil, fmt.Errorf("%s is neither a docker-compose file nor a Procfile", fileName)
+}
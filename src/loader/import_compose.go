@@ -0,0 +1,645 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/container"
+	"github.com/f1bonacc1/process-compose/src/health"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"gopkg.in/yaml.v3"
+)
+
+// composeSignals are the stop_signal names of docker-compose, with their Linux
+// numbers.
+var composeSignals = map[string]int{
+	"SIGHUP":  1,
+	"SIGINT":  2,
+	"SIGQUIT": 3,
+	"SIGKILL": 9,
+	"SIGUSR1": 10,
+	"SIGUSR2": 12,
+	"SIGTERM": 15,
+}
+
+// composeConditions maps the depends_on conditions of docker-compose.
+var composeConditions = map[string]types.ProcessCondition{
+	"service_started":                types.ProcessConditionStarted,
+	"service_healthy":                types.ProcessConditionHealthy,
+	"service_completed_successfully": types.ProcessConditionCompletedSuccessfully,
+}
+
+// composeConverter converts a docker-compose file. The services with a build
+// section run locally from their build context, the others run as containers
+// of their image.
+type composeConverter struct {
+	warnings []string
+}
+
+func (c *composeConverter) warn(path, format string, args ...any) {
+	c.warnings = append(c.warnings, path+": "+fmt.Sprintf(format, args...))
+}
+
+func importDockerCompose(data []byte) (*types.Project, []string, error) {
+	var file map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, nil, err
+	}
+	project := &types.Project{
+		Version:   "0.5",
+		Processes: types.Processes{},
+	}
+	c := &composeConverter{}
+	for _, key := range sortedKeys(file) {
+		node := file[key]
+		switch {
+		case key == "services":
+		case key == "name":
+			if err := node.Decode(&project.Name); err != nil {
+				return nil, nil, fmt.Errorf("name: %w", err)
+			}
+		case key == "version", strings.HasPrefix(key, "x-"):
+			// The version is obsolete, and the extensions are only anchors.
+		default:
+			c.warn(key, "not supported")
+		}
+	}
+	var services map[string]map[string]yaml.Node
+	servicesNode := file["services"]
+	if err := servicesNode.Decode(&services); err != nil {
+		return nil, nil, fmt.Errorf("services: %w", err)
+	}
+	for _, name := range sortedKeys(services) {
+		proc, err := c.convertService("services."+name, services[name])
+		if err != nil {
+			return nil, nil, err
+		}
+		project.Processes[name] = proc
+	}
+	c.relaxHealthyDependencies(project)
+	return project, c.warnings, nil
+}
+
+// relaxHealthyDependencies waits for the start of the dependencies that have
+// no health check left, such as the containers, instead of their health.
+func (c *composeConverter) relaxHealthyDependencies(project *types.Project) {
+	for _, name := range sortedKeys(project.Processes) {
+		proc := project.Processes[name]
+		for _, depName := range sortedKeys(proc.DependsOn) {
+			dep := proc.DependsOn[depName]
+			target, ok := project.Processes[depName]
+			if dep.Condition != types.ProcessConditionHealthy || !ok || target.ReadinessProbe != nil {
+				continue
+			}
+			c.warn("services."+name+".depends_on."+depName, "%s has no health check, waiting for service_started instead", depName)
+			dep.Condition = types.ProcessConditionStarted
+			proc.DependsOn[depName] = dep
+		}
+	}
+}
+
+func (c *composeConverter) convertService(path string, svc map[string]yaml.Node) (types.ProcessConfig, error) {
+	var proc types.ProcessConfig
+	_, hasBuild := svc["build"]
+	if _, hasImage := svc["image"]; !hasImage && !hasBuild {
+		return proc, fmt.Errorf("%s: image or build is required", path)
+	}
+	isContainer := !hasBuild
+	if isContainer {
+		proc.Container = &types.ContainerConfig{}
+	}
+	var command, entrypoint []string
+	var shellCommand string
+	_, hasRestart := svc["restart"]
+	for _, key := range sortedKeys(svc) {
+		node := svc[key]
+		keyPath := path + "." + key
+		var err error
+		switch key {
+		case "image":
+			if isContainer {
+				err = node.Decode(&proc.Container.Image)
+			}
+		case "build":
+			err = c.convertBuild(keyPath, &node, &proc)
+		case "command":
+			command, shellCommand, err = decodeCommand(&node)
+		case "entrypoint":
+			var shell string
+			entrypoint, shell, err = decodeCommand(&node)
+			if err == nil && shell != "" {
+				entrypoint, err = splitShellWords(shell)
+			}
+		case "environment":
+			var env []string
+			env, err = c.decodeEnvironment(keyPath, &node, isContainer)
+			if isContainer {
+				proc.Container.Environment = env
+			} else {
+				proc.Environment = env
+			}
+		case "env_file":
+			err = c.convertEnvFile(keyPath, &node, &proc)
+		case "depends_on":
+			proc.DependsOn, err = c.decodeDependsOn(keyPath, &node)
+		case "healthcheck":
+			proc.ReadinessProbe, err = c.decodeHealthcheck(keyPath, &node)
+			if err == nil && proc.ReadinessProbe != nil && isContainer {
+				// The exec probes run on the host, without the tools of the
+				// container.
+				c.warn(keyPath, "not supported for services that run as containers")
+				proc.ReadinessProbe = nil
+			}
+		case "restart":
+			err = c.convertRestart(keyPath, &node, &proc.RestartPolicy)
+		case "deploy":
+			err = c.convertDeploy(keyPath, &node, &proc, hasRestart)
+		case "scale":
+			err = node.Decode(&proc.Replicas)
+		case "stop_signal":
+			err = c.convertStopSignal(keyPath, &node, &proc.ShutDownParams)
+		case "stop_grace_period":
+			var d time.Duration
+			if d, err = decodeComposeDuration(&node); err == nil {
+				proc.ShutDownParams.ShutDownTimeout = durationSeconds(d)
+			}
+		case "ports", "volumes", "network_mode", "user", "container_name", "pull_policy":
+			if !isContainer {
+				c.warn(keyPath, "not supported for services that run locally")
+				continue
+			}
+			err = c.convertContainerKey(keyPath, key, &node, proc.Container)
+		default:
+			if !strings.HasPrefix(key, "x-") {
+				c.warn(keyPath, "not supported")
+			}
+		}
+		if err != nil {
+			return proc, fmt.Errorf("%s: %w", keyPath, err)
+		}
+	}
+	if proc.EnvFile != "" && proc.WorkingDir != "" && !filepath.IsAbs(proc.EnvFile) {
+		// The env files of docker-compose are relative to the project, those of
+		// process-compose to the working directory of the process.
+		if rel, err := filepath.Rel(proc.WorkingDir, proc.EnvFile); err == nil {
+			proc.EnvFile = rel
+		}
+	}
+	if isContainer {
+		if shellCommand != "" {
+			var err error
+			if command, err = splitShellWords(shellCommand); err != nil {
+				return proc, fmt.Errorf("%s.command: %w", path, err)
+			}
+		}
+		proc.Container.Command = command
+		proc.Container.Entrypoint = entrypoint
+	} else if len(entrypoint) == 0 && shellCommand != "" {
+		proc.Command = shellCommand
+	} else {
+		if shellCommand != "" {
+			var err error
+			if command, err = splitShellWords(shellCommand); err != nil {
+				return proc, fmt.Errorf("%s.command: %w", path, err)
+			}
+		}
+		proc.Entrypoint = append(entrypoint, command...)
+		if len(proc.Entrypoint) == 0 {
+			return proc, fmt.Errorf("%s: command or entrypoint is required for services that run locally", path)
+		}
+	}
+	return proc, nil
+}
+
+func (c *composeConverter) convertBuild(path string, node *yaml.Node, proc *types.ProcessConfig) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&proc.WorkingDir)
+	}
+	var build map[string]yaml.Node
+	if err := node.Decode(&build); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(build) {
+		if key == "context" {
+			value := build[key]
+			if err := value.Decode(&proc.WorkingDir); err != nil {
+				return err
+			}
+			continue
+		}
+		c.warn(path+"."+key, "not supported, the service runs locally from its build context")
+	}
+	return nil
+}
+
+func (c *composeConverter) convertEnvFile(path string, node *yaml.Node, proc *types.ProcessConfig) error {
+	var files []string
+	if node.Kind == yaml.ScalarNode {
+		files = []string{node.Value}
+	} else {
+		var entries []yaml.Node
+		if err := node.Decode(&entries); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.Kind == yaml.ScalarNode {
+				files = append(files, entry.Value)
+				continue
+			}
+			var long struct {
+				Path string `yaml:"path"`
+			}
+			if err := entry.Decode(&long); err != nil {
+				return err
+			}
+			files = append(files, long.Path)
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	proc.EnvFile = files[0]
+	if len(files) > 1 {
+		c.warn(path, "only the first env file is supported, ignored %s", strings.Join(files[1:], ", "))
+	}
+	return nil
+}
+
+// decodeEnvironment returns the environment as NAME=value. The variables
+// without value are passed from the host by docker-compose: local processes
+// inherit them anyway.
+func (c *composeConverter) decodeEnvironment(path string, node *yaml.Node, isContainer bool) ([]string, error) {
+	var env []string
+	if node.Kind == yaml.MappingNode {
+		var vars map[string]*string
+		if err := node.Decode(&vars); err != nil {
+			return nil, err
+		}
+		for _, name := range sortedKeys(vars) {
+			if vars[name] == nil {
+				if isContainer {
+					c.warn(path+"."+name, "passing a variable from the host is not supported")
+				}
+				continue
+			}
+			env = append(env, name+"="+*vars[name])
+		}
+		return env, nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return nil, err
+	}
+	for _, entry := range list {
+		if !strings.Contains(entry, "=") {
+			if isContainer {
+				c.warn(path+"."+entry, "passing a variable from the host is not supported")
+			}
+			continue
+		}
+		env = append(env, entry)
+	}
+	return env, nil
+}
+
+func (c *composeConverter) decodeDependsOn(path string, node *yaml.Node) (types.DependsOnConfig, error) {
+	deps := types.DependsOnConfig{}
+	if node.Kind == yaml.SequenceNode {
+		var names []string
+		if err := node.Decode(&names); err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			deps[name] = types.ProcessDependency{Condition: types.ProcessConditionStarted}
+		}
+		return deps, nil
+	}
+	var long map[string]map[string]yaml.Node
+	if err := node.Decode(&long); err != nil {
+		return nil, err
+	}
+	for _, name := range sortedKeys(long) {
+		dep := types.ProcessDependency{Condition: types.ProcessConditionStarted}
+		for _, key := range sortedKeys(long[name]) {
+			value := long[name][key]
+			if key != "condition" {
+				c.warn(path+"."+name+"."+key, "not supported")
+				continue
+			}
+			condition, ok := composeConditions[value.Value]
+			if !ok {
+				return nil, fmt.Errorf("unknown condition '%s' of %s", value.Value, name)
+			}
+			dep.Condition = condition
+		}
+		deps[name] = dep
+	}
+	return deps, nil
+}
+
+func (c *composeConverter) decodeHealthcheck(path string, node *yaml.Node) (*health.Probe, error) {
+	var check map[string]yaml.Node
+	if err := node.Decode(&check); err != nil {
+		return nil, err
+	}
+	probe := &health.Probe{}
+	for _, key := range sortedKeys(check) {
+		value := check[key]
+		var err error
+		switch key {
+		case "test":
+			probe.Exec, err = decodeHealthTest(&value)
+			if err == nil && probe.Exec == nil {
+				return nil, nil
+			}
+		case "disable":
+			var disabled bool
+			if err = value.Decode(&disabled); err == nil && disabled {
+				return nil, nil
+			}
+		case "interval":
+			probe.PeriodSeconds, err = decodeComposeSeconds(&value)
+		case "timeout":
+			probe.TimeoutSeconds, err = decodeComposeSeconds(&value)
+		case "start_period":
+			probe.InitialDelay, err = decodeComposeSeconds(&value)
+		case "retries":
+			err = value.Decode(&probe.FailureThreshold)
+		default:
+			c.warn(path+"."+key, "not supported")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	if probe.Exec == nil {
+		// A health check without test inherits the HEALTHCHECK of the image.
+		c.warn(path, "a health check without test is not supported")
+		return nil, nil
+	}
+	return probe, nil
+}
+
+// decodeHealthTest converts the CMD, CMD-SHELL and plain string tests. NONE
+// disables the health check.
+func decodeHealthTest(node *yaml.Node) (*health.ExecProbe, error) {
+	if node.Kind == yaml.ScalarNode {
+		return &health.ExecProbe{Command: node.Value}, nil
+	}
+	var test []string
+	if err := node.Decode(&test); err != nil {
+		return nil, err
+	}
+	if len(test) == 0 {
+		return nil, errors.New("empty test")
+	}
+	switch test[0] {
+	case "NONE":
+		return nil, nil
+	case "CMD":
+		return &health.ExecProbe{Command: shellJoin(test[1:])}, nil
+	case "CMD-SHELL":
+		return &health.ExecProbe{Command: strings.Join(test[1:], " ")}, nil
+	}
+	return nil, fmt.Errorf("unknown test type '%s'", test[0])
+}
+
+func (c *composeConverter) convertRestart(path string, node *yaml.Node, policy *types.RestartPolicyConfig) error {
+	restart, maxRetries, _ := strings.Cut(node.Value, ":")
+	switch restart {
+	case "no", "":
+		policy.Restart = types.RestartPolicyNo
+	case "always":
+		policy.Restart = types.RestartPolicyAlways
+	case "unless-stopped":
+		policy.Restart = types.RestartPolicyAlways
+	case "on-failure":
+		policy.Restart = types.RestartPolicyOnFailure
+		if maxRetries != "" {
+			retries, err := strconv.Atoi(maxRetries)
+			if err != nil {
+				return fmt.Errorf("invalid maximum retries '%s'", maxRetries)
+			}
+			policy.MaxRestarts = retries
+		}
+	default:
+		return fmt.Errorf("unknown restart policy '%s'", node.Value)
+	}
+	return nil
+}
+
+// convertDeploy converts the replicas, and the restart policy unless restart
+// is set: restart takes precedence, as with docker-compose.
+func (c *composeConverter) convertDeploy(path string, node *yaml.Node, proc *types.ProcessConfig, hasRestart bool) error {
+	var deploy map[string]yaml.Node
+	if err := node.Decode(&deploy); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(deploy) {
+		value := deploy[key]
+		switch key {
+		case "replicas":
+			if err := value.Decode(&proc.Replicas); err != nil {
+				return fmt.Errorf("replicas: %w", err)
+			}
+		case "restart_policy":
+			if hasRestart {
+				continue
+			}
+			var policy struct {
+				Condition   string `yaml:"condition"`
+				MaxAttempts int    `yaml:"max_attempts"`
+				Delay       string `yaml:"delay"`
+			}
+			if err := value.Decode(&policy); err != nil {
+				return fmt.Errorf("restart_policy: %w", err)
+			}
+			switch policy.Condition {
+			case "none":
+				proc.RestartPolicy.Restart = types.RestartPolicyNo
+			case "on-failure":
+				proc.RestartPolicy.Restart = types.RestartPolicyOnFailure
+			case "any", "":
+				proc.RestartPolicy.Restart = types.RestartPolicyAlways
+			default:
+				return fmt.Errorf("restart_policy: unknown condition '%s'", policy.Condition)
+			}
+			proc.RestartPolicy.MaxRestarts = policy.MaxAttempts
+			if policy.Delay != "" {
+				delay, err := time.ParseDuration(policy.Delay)
+				if err != nil {
+					return fmt.Errorf("restart_policy: invalid delay '%s'", policy.Delay)
+				}
+				proc.RestartPolicy.BackoffSeconds = durationSeconds(delay)
+			}
+		default:
+			c.warn(path+"."+key, "not supported")
+		}
+	}
+	return nil
+}
+
+func (c *composeConverter) convertStopSignal(path string, node *yaml.Node, params *types.ShutDownParams) error {
+	name := strings.ToUpper(node.Value)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	if sig, ok := composeSignals[name]; ok {
+		params.Signal = sig
+		return nil
+	}
+	if sig, err := strconv.Atoi(node.Value); err == nil {
+		params.Signal = sig
+		return nil
+	}
+	c.warn(path, "signal %s not supported", node.Value)
+	return nil
+}
+
+func (c *composeConverter) convertContainerKey(path, key string, node *yaml.Node, conf *types.ContainerConfig) error {
+	switch key {
+	case "ports":
+		var ports []yaml.Node
+		if err := node.Decode(&ports); err != nil {
+			return err
+		}
+		for _, port := range ports {
+			if port.Kind != yaml.ScalarNode {
+				c.warn(path, "the long syntax is not supported")
+				continue
+			}
+			conf.Ports = append(conf.Ports, port.Value)
+		}
+	case "volumes":
+		var volumes []yaml.Node
+		if err := node.Decode(&volumes); err != nil {
+			return err
+		}
+		for _, volume := range volumes {
+			if volume.Kind != yaml.ScalarNode {
+				c.warn(path, "the long syntax is not supported")
+				continue
+			}
+			conf.Volumes = append(conf.Volumes, volume.Value)
+		}
+	case "network_mode":
+		return node.Decode(&conf.Network)
+	case "user":
+		return node.Decode(&conf.User)
+	case "container_name":
+		return node.Decode(&conf.Name)
+	case "pull_policy":
+		switch node.Value {
+		case "always":
+			conf.Pull = container.PullAlways
+		case "never":
+			conf.Pull = container.PullNever
+		case "missing", "if_not_present":
+			conf.Pull = container.PullMissing
+		default:
+			c.warn(path, "pull policy %s not supported", node.Value)
+		}
+	}
+	return nil
+}
+
+// decodeCommand returns the exec form of a command, or its shell form.
+func decodeCommand(node *yaml.Node) ([]string, string, error) {
+	if node.Kind == yaml.ScalarNode {
+		return nil, node.Value, nil
+	}
+	var args []string
+	err := node.Decode(&args)
+	return args, "", err
+}
+
+func decodeComposeDuration(node *yaml.Node) (time.Duration, error) {
+	d, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration '%s'", node.Value)
+	}
+	return d, nil
+}
+
+func decodeComposeSeconds(node *yaml.Node) (int, error) {
+	d, err := decodeComposeDuration(node)
+	return durationSeconds(d), err
+}
+
+// durationSeconds rounds up to whole seconds: a sub-second duration isn't zero.
+func durationSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
+
+// splitShellWords splits a command line as a POSIX shell would, without
+// expansions: words are separated by blanks, and quotes and backslashes escape.
+func splitShellWords(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote in '%s'", line)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// shellJoin quotes the arguments that need it, so that the shell splits the
+// line back into them.
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\n'\"\\$`&|;<>()*?[]#~!{}") {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,61 @@
+package loader
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+const (
+	// procfileBasePort and procfilePortStep assign PORT as foreman does: 5000
+	// to the first process, 5100 to the second, and so on.
+	procfileBasePort = 5000
+	procfilePortStep = 100
+)
+
+var procfileLine = regexp.MustCompile(`^([A-Za-z0-9_-]+):\s*(.+)$`)
+
+// importProcfile converts the name: command lines of a Procfile into
+// processes. Every process gets its own PORT. The commands expand their
+// variables in the shell, so the project disables the environment expansion.
+func importProcfile(data []byte) (*types.Project, []string, error) {
+	project := &types.Project{
+		Version:             "0.5",
+		Processes:           types.Processes{},
+		DisableEnvExpansion: true,
+	}
+	var warnings []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		match := procfileLine.FindStringSubmatch(line)
+		if match == nil {
+			warnings = append(warnings, fmt.Sprintf("line %d: not a 'name: command' entry, skipped", lineNum))
+			continue
+		}
+		name, cmd := match[1], strings.TrimSpace(match[2])
+		if _, ok := project.Processes[name]; ok {
+			return nil, nil, fmt.Errorf("line %d: process %s is defined twice", lineNum, name)
+		}
+		port := procfileBasePort + procfilePortStep*len(project.Processes)
+		project.Processes[name] = types.ProcessConfig{
+			Command:     cmd,
+			Environment: types.Environment{"PORT=" + strconv.Itoa(port)},
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(project.Processes) == 0 {
+		return nil, nil, fmt.Errorf("no processes found in the Procfile")
+	}
+	return project, warnings, nil
+}
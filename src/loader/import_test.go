@@ -0,0 +1,341 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/container"
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		file string
+		data string
+		want string
+	}{
+		{"Procfile", "web: ./web", FormatProcfile},
+		{"/app/Procfile.dev", "web: ./web", FormatProcfile},
+		{"docker-compose.yml", "services:\n  web:\n    image: nginx\n", FormatDockerCompose},
+		{"compose.yaml", "services:\n  web:\n    image: nginx\n", FormatDockerCompose},
+		{"compose.yaml", "processes:\n  web:\n    command: ./web\n", FormatProcessCompose},
+		{"process-compose.yaml", "services: {}\nprocesses: {}\n", FormatProcessCompose},
+		{"process-compose.yaml", "- not a mapping", FormatProcessCompose},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			if got := DetectFormat(tt.file, []byte(tt.data)); got != tt.want {
+				t.Errorf("DetectFormat() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitShellWords(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{"npm run dev", []string{"npm", "run", "dev"}, false},
+		{`sh -c "echo 'hi there'"`, []string{"sh", "-c", "echo 'hi there'"}, false},
+		{`echo a\ b ''`, []string{"echo", "a b", ""}, false},
+		{`echo "unterminated`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := splitShellWords(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitShellWords() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("splitShellWords() = %q, want %q", got, tt.want)
+			}
+			if !tt.wantErr {
+				if back, _ := splitShellWords(shellJoin(got)); !slices.Equal(back, got) {
+					t.Errorf("shellJoin() = %q doesn't split back into %q", shellJoin(got), got)
+				}
+			}
+		})
+	}
+}
+
+func TestImportProcfile(t *testing.T) {
+	project, warnings, err := importProcfile([]byte("# comment\nweb: bundle exec puma -p $PORT\n\nworker:  bundle exec sidekiq\nnot an entry\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "line 5:") {
+		t.Errorf("warnings = %q, want the invalid line", warnings)
+	}
+	web, worker := project.Processes["web"], project.Processes["worker"]
+	if web.Command != "bundle exec puma -p $PORT" || !slices.Equal(web.Environment, []string{"PORT=5000"}) {
+		t.Errorf("web = %+v", web)
+	}
+	if worker.Command != "bundle exec sidekiq" || !slices.Equal(worker.Environment, []string{"PORT=5100"}) {
+		t.Errorf("worker = %+v", worker)
+	}
+	if !project.DisableEnvExpansion {
+		t.Error("the environment expansion is enabled")
+	}
+
+	if _, _, err = importProcfile([]byte("web: a\nweb: b\n")); err == nil {
+		t.Error("importProcfile() accepted a duplicate process")
+	}
+	if _, _, err = importProcfile([]byte("# empty\n")); err == nil {
+		t.Error("importProcfile() accepted a Procfile without processes")
+	}
+}
+
+const testComposeFile = `
+name: shop
+services:
+  db:
+    image: postgres:16
+    container_name: shop-db
+    environment:
+      POSTGRES_PASSWORD: secret
+      POSTGRES_PORT: 5432
+      FROM_HOST:
+    ports: ["5432:5432", {target: 80}]
+    volumes: [pgdata:/var/lib/postgresql/data]
+    pull_policy: always
+    healthcheck:
+      test: ["CMD", "pg_isready", "-U", "postgres"]
+    command: postgres -c "log_statement=all"
+  api:
+    build:
+      context: ./api
+      dockerfile: Dockerfile.dev
+    command: npm run dev
+    env_file: [.env, {path: .env.local}]
+    environment: [NODE_ENV=development, FROM_HOST]
+    depends_on:
+      db:
+        condition: service_healthy
+      migrate:
+        condition: service_completed_successfully
+    restart: on-failure:3
+    deploy:
+      replicas: 2
+      restart_policy: {condition: any}
+    stop_signal: SIGINT
+    stop_grace_period: 1m
+    ports: ["8080:8080"]
+    healthcheck:
+      test: ["CMD", "curl", "-f", "localhost:8080"]
+      interval: 5s
+      timeout: 500ms
+      retries: 5
+      start_period: 10s
+  migrate:
+    build: ./api
+    entrypoint: ["npm", "run"]
+    command: ["migrate", "--env", "dev env"]
+    depends_on: [db]
+    healthcheck:
+      disable: true
+  worker:
+    image: busybox
+    deploy:
+      restart_policy: {condition: on-failure, max_attempts: 2, delay: 5s}
+    healthcheck:
+      test: ["NONE"]
+networks:
+  back: {}
+`
+
+func TestImportDockerCompose(t *testing.T) {
+	project, warnings, err := importDockerCompose([]byte(testComposeFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project.Name != "shop" {
+		t.Errorf("name = %s, want shop", project.Name)
+	}
+
+	db := project.Processes["db"]
+	if db.Container == nil {
+		t.Fatal("db doesn't run as a container")
+	}
+	wantContainer := types.ContainerConfig{
+		Image:       "postgres:16",
+		Name:        "shop-db",
+		Command:     []string{"postgres", "-c", "log_statement=all"},
+		Ports:       []string{"5432:5432"},
+		Volumes:     []string{"pgdata:/var/lib/postgresql/data"},
+		Environment: types.Environment{"POSTGRES_PASSWORD=secret", "POSTGRES_PORT=5432"},
+		Pull:        container.PullAlways,
+	}
+	if !reflect.DeepEqual(*db.Container, wantContainer) {
+		t.Errorf("db container = %+v, want %+v", *db.Container, wantContainer)
+	}
+	if db.ReadinessProbe != nil {
+		t.Errorf("db readiness probe = %+v, want none: it would run on the host", db.ReadinessProbe)
+	}
+
+	api := project.Processes["api"]
+	if api.Container != nil || api.Command != "npm run dev" || api.WorkingDir != "./api" {
+		t.Errorf("api = %+v, want a local process in its build context", api)
+	}
+	probe := api.ReadinessProbe
+	if probe == nil || probe.Exec.Command != "curl -f localhost:8080" || probe.PeriodSeconds != 5 || probe.TimeoutSeconds != 1 ||
+		probe.FailureThreshold != 5 || probe.InitialDelay != 10 {
+		t.Errorf("api readiness probe = %+v", probe)
+	}
+	if api.EnvFile != "../.env" || !slices.Equal(api.Environment, []string{"NODE_ENV=development"}) {
+		t.Errorf("api env_file = %s, environment = %q", api.EnvFile, api.Environment)
+	}
+	if api.DependsOn["db"].Condition != types.ProcessConditionStarted ||
+		api.DependsOn["migrate"].Condition != types.ProcessConditionCompletedSuccessfully {
+		t.Errorf("api depends_on = %+v", api.DependsOn)
+	}
+	if api.RestartPolicy.Restart != types.RestartPolicyOnFailure || api.RestartPolicy.MaxRestarts != 3 || api.Replicas != 2 {
+		t.Errorf("api restart = %+v, replicas = %d, want restart to take precedence over deploy", api.RestartPolicy, api.Replicas)
+	}
+	if api.ShutDownParams.Signal != 2 || api.ShutDownParams.ShutDownTimeout != 60 {
+		t.Errorf("api shutdown = %+v", api.ShutDownParams)
+	}
+
+	migrate := project.Processes["migrate"]
+	if !slices.Equal(migrate.Entrypoint, []string{"npm", "run", "migrate", "--env", "dev env"}) || migrate.ReadinessProbe != nil {
+		t.Errorf("migrate = %+v", migrate)
+	}
+	if migrate.DependsOn["db"].Condition != types.ProcessConditionStarted {
+		t.Errorf("migrate depends_on = %+v", migrate.DependsOn)
+	}
+
+	worker := project.Processes["worker"]
+	if worker.RestartPolicy.Restart != types.RestartPolicyOnFailure || worker.RestartPolicy.MaxRestarts != 2 ||
+		worker.RestartPolicy.BackoffSeconds != 5 || worker.ReadinessProbe != nil {
+		t.Errorf("worker = %+v", worker)
+	}
+
+	wantWarnings := []string{
+		"networks: not supported",
+		"services.api.build.dockerfile: not supported, the service runs locally from its build context",
+		"services.api.env_file: only the first env file is supported, ignored .env.local",
+		"services.api.ports: not supported for services that run locally",
+		"services.db.environment.FROM_HOST: passing a variable from the host is not supported",
+		"services.db.healthcheck: not supported for services that run as containers",
+		"services.db.ports: the long syntax is not supported",
+		"services.api.depends_on.db: db has no health check, waiting for service_started instead",
+	}
+	if !slices.Equal(warnings, wantWarnings) {
+		t.Errorf("warnings =\n%s\nwant\n%s", strings.Join(warnings, "\n"), strings.Join(wantWarnings, "\n"))
+	}
+}
+
+func TestImportDockerCompose_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"no image", "services:\n  web:\n    command: ./web\n"},
+		{"local without command", "services:\n  web:\n    build: .\n"},
+		{"unknown condition", "services:\n  web:\n    image: nginx\n    depends_on:\n      db:\n        condition: service_ready\n"},
+		{"unknown restart", "services:\n  web:\n    image: nginx\n    restart: sometimes\n"},
+		{"invalid duration", "services:\n  web:\n    image: nginx\n    stop_grace_period: soon\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := importDockerCompose([]byte(tt.data)); err == nil {
+				t.Error("importDockerCompose() succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestLoad_Procfile(t *testing.T) {
+	dir := t.TempDir()
+	procfile := filepath.Join(dir, "Procfile")
+	if err := os.WriteFile(procfile, []byte("web: echo $PORT\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	envFile := filepath.Join(dir, ".env")
+	if err := os.WriteFile(envFile, []byte("DATABASE_URL=postgres://localhost\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	project, err := Load(&LoaderOptions{
+		FileNames:        []string{procfile},
+		EnvFileNames:     []string{envFile},
+		IsInternalLoader: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	web, ok := project.Processes["web"]
+	if !ok || web.Command != "echo $PORT" {
+		t.Errorf("web = %+v, want the Procfile command unexpanded", web)
+	}
+	if got := project.DotEnvVars["DATABASE_URL"]; got != "postgres://localhost" {
+		t.Errorf("DotEnvVars = %v, want the .env of the Procfile", project.DotEnvVars)
+	}
+}
+
+func Test_autoDiscoverComposeFile_Import(t *testing.T) {
+	const dockerCompose = "services:\n  db:\n    image: postgres\n"
+	const processCompose = "processes:\n  web:\n    command: ./web\n"
+	tests := []struct {
+		name      string
+		files     map[string]string
+		wantHint  string
+		wantFiles []string
+	}{
+		{
+			name:     "Procfile",
+			files:    map[string]string{"Procfile": "web: ./web\n"},
+			wantHint: "-f Procfile",
+		},
+		{
+			name:     "docker compose.yaml",
+			files:    map[string]string{"compose.yaml": dockerCompose},
+			wantHint: "-f compose.yaml",
+		},
+		{
+			name:      "docker compose.yaml next to process-compose.yaml",
+			files:     map[string]string{"compose.yaml": dockerCompose, "process-compose.yaml": processCompose},
+			wantFiles: []string{"process-compose.yaml"},
+		},
+		{
+			name:      "docker compose.override.yml",
+			files:     map[string]string{"process-compose.yaml": processCompose, "compose.override.yml": dockerCompose},
+			wantFiles: []string{"process-compose.yaml"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, data := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			opts := &LoaderOptions{workingDir: dir}
+			err := autoDiscoverComposeFile(opts)
+			if tt.wantHint != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantHint) {
+					t.Errorf("autoDiscoverComposeFile() error = %v, want a hint to import with %s", err, tt.wantHint)
+				}
+				if len(opts.FileNames) != 0 {
+					t.Errorf("autoDiscoverComposeFile() imported %v", opts.FileNames)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, file := range opts.FileNames {
+				got = append(got, filepath.Base(file))
+			}
+			if !slices.Equal(got, tt.wantFiles) {
+				t.Errorf("autoDiscoverComposeFile() files = %v, want %v", got, tt.wantFiles)
+			}
+		})
+	}
+}
@@ -62,6 +62,7 @@ func Load(opts *LoaderOptions) (*types.Project, error) {
 	// check to fire on entries the loader itself inserted on the prior pass.
 	mergedProject.FileNames = fileNames
 	mergedProject.EnvFileNames = opts.EnvFileNames
+	mergedProject.ActiveProfiles = opts.Profiles
 	mergedProject.IsTuiDisabled = opts.isTuiDisabled || mergedProject.IsTuiDisabled
 	mergedProject.IsOrderedShutdown = opts.isOrderedShutdown || mergedProject.IsOrderedShutdown
 	// If DryRun is set to validate the config, then force IsStrict to true:
@@ -82,7 +83,9 @@ func Load(opts *LoaderOptions) (*types.Project, error) {
 		disableProcsInEnv,
 	)
 	err = applyWithErr(mergedProject,
+		func(p *types.Project) error { return assignPorts(p, opts.assignedPorts) },
 		renderTemplates,
+		func(p *types.Project) error { return evaluateEnabledIf(p, opts.Profiles) },
 	)
 	if err != nil {
 		return nil, err
@@ -95,10 +98,13 @@ func Load(opts *LoaderOptions) (*types.Project, error) {
 		validateLogLevel,
 		validateProcessConfig,
 		validateProcessEnvFileExists,
+		validateSecrets,
+		validateProjectHooks,
 		validateNoCircularDependencies,
 		validateShellConfig,
 		validatePlatformCompatibility,
 		validateHealthDependencyHasHealthCheck,
+		validateDependencyConditions,
 		validateDependencyIsEnabled,
 		validateNoIncompatibleHealthChecks,
 		validateScheduledProcessScaling,
@@ -136,7 +142,7 @@ func loadExtendProject(p *types.Project, opts *LoaderOptions, file string, index
 }
 
 func admitProcesses(opts *LoaderOptions, p *types.Project) *types.Project {
-	admitter.ApplyToProject(p, opts.admitters)
+	admitter.ApplyToProject(p, opts.GetAdmitters())
 	return p
 }
 
@@ -157,6 +163,17 @@ func loadProjectFromFile(inputFile string, opts *LoaderOptions) (*types.Project,
 		// .env is optional we don't care if it errors
 		dotEnvVars, _ = godotenv.Read(opts.EnvFileNames...)
 	}
+
+	format := DetectFormat(inputFile, yamlFile)
+	if format == FormatProcfile {
+		// Procfile commands expand their variables in the shell.
+		project, err := importProjectFromFile(inputFile, yamlFile, opts)
+		if err != nil {
+			return nil, err
+		}
+		project.DotEnvVars = dotEnvVars
+		return project, nil
+	}
 	expanderFn := func(name string) string {
 		val, ok := dotEnvVars[name]
 		if ok {
@@ -175,6 +192,15 @@ func loadProjectFromFile(inputFile string, opts *LoaderOptions) (*types.Project,
 	}
 	temp = strings.ReplaceAll(temp, envEscaped, "$")
 
+	if format == FormatDockerCompose {
+		project, err := importProjectFromFile(inputFile, []byte(temp), opts)
+		if err != nil {
+			return nil, err
+		}
+		project.DotEnvVars = dotEnvVars
+		return project, nil
+	}
+
 	project := &types.Project{
 		LogLength: defaultLogLength,
 	}
@@ -200,6 +226,24 @@ func loadProjectFromFile(inputFile string, opts *LoaderOptions) (*types.Project,
 	return project, nil
 }
 
+// importProjectFromFile converts a docker-compose file or a Procfile, and
+// reports the settings it doesn't support.
+func importProjectFromFile(inputFile string, data []byte, opts *LoaderOptions) (*types.Project, error) {
+	project, warnings, err := importProject(inputFile, data)
+	if err != nil {
+		if opts.IsInternalLoader {
+			return nil, err
+		}
+		log.Fatal().Err(err).Msgf("Failed to import %s", inputFile)
+	}
+	for _, warning := range warnings {
+		log.Warn().Msgf("%s: %s", inputFile, warning)
+	}
+	project.LogLength = defaultLogLength
+	log.Info().Msgf("Imported project from %s", inputFile)
+	return project, nil
+}
+
 func findFiles(names []string, pwd string) []string {
 	candidates := []string{}
 	for _, n := range names {
@@ -227,6 +271,21 @@ var DefaultOverrideFileNames = []string{
 	"process-compose.override.yaml",
 }
 
+// splitImportFiles separates the process-compose files from the files that
+// would be imported, such as a docker-compose compose.yaml. A file that can't
+// be read is left to the loader to report.
+func splitImportFiles(files []string) (native, imports []string) {
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err == nil && DetectFormat(file, data) != FormatProcessCompose {
+			imports = append(imports, file)
+			continue
+		}
+		native = append(native, file)
+	}
+	return native, imports
+}
+
 func autoDiscoverComposeFile(opts *LoaderOptions) error {
 	if len(opts.FileNames) > 0 {
 		return nil
@@ -235,7 +294,7 @@ func autoDiscoverComposeFile(opts *LoaderOptions) error {
 	if err != nil {
 		return err
 	}
-	candidates := findFiles(DefaultFileNames, pwd)
+	candidates, imports := splitImportFiles(findFiles(DefaultFileNames, pwd))
 	if len(candidates) > 0 {
 		if len(candidates) > 1 {
 			log.Warn().Msgf("Found multiple config files with supported names: %s", strings.Join(candidates, ", "))
@@ -243,7 +302,10 @@ func autoDiscoverComposeFile(opts *LoaderOptions) error {
 		}
 		opts.FileNames = append(opts.FileNames, candidates[0])
 
-		overrides := findFiles(DefaultOverrideFileNames, pwd)
+		overrides, skipped := splitImportFiles(findFiles(DefaultOverrideFileNames, pwd))
+		for _, file := range skipped {
+			log.Warn().Msgf("Skipping %s: not a process-compose file, use -f to import it", file)
+		}
 		if len(overrides) > 0 {
 			if len(overrides) > 1 {
 				log.Warn().Msgf("Found multiple override files with supported names: %s", strings.Join(overrides, ", "))
@@ -253,5 +315,11 @@ func autoDiscoverComposeFile(opts *LoaderOptions) error {
 		}
 		return nil
 	}
+	// Importing is explicit: a docker-compose file or a Procfile is rarely meant
+	// to be run with process-compose just because it is there.
+	imports = append(imports, findFiles(DefaultImportFileNames, pwd)...)
+	if len(imports) > 0 {
+		return fmt.Errorf("no config files found in %s, run 'process-compose -f %s up' to import %s", pwd, filepath.Base(imports[0]), imports[0])
+	}
 	return fmt.Errorf("no config files found in %s", pwd)
 }
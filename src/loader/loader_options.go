@@ -5,12 +5,14 @@ import (
 	"github.com/f1bonacc1/process-compose/src/types"
 	"os"
 	"path/filepath"
+	"slices"
 )
 
 type LoaderOptions struct {
 	workingDir        string
 	FileNames         []string
 	EnvFileNames      []string
+	Profiles          []string
 	IsInternalLoader  bool
 	projects          []*types.Project
 	admitters         []admitter.Admitter
@@ -18,14 +20,23 @@ type LoaderOptions struct {
 	isTuiDisabled     bool
 	DryRun            bool
 	isOrderedShutdown bool
+	assignedPorts     map[string]map[string]int
+	// requestedProcesses are admitted whatever their profiles.
+	requestedProcesses []string
 }
 
 func (o *LoaderOptions) AddAdmitter(adm ...admitter.Admitter) {
 	o.admitters = append(o.admitters, adm...)
 }
 
+// GetAdmitters returns the admitters of the options, followed by the admitter
+// of the profiles.
 func (o *LoaderOptions) GetAdmitters() []admitter.Admitter {
-	return o.admitters
+	profileAdmitter := &admitter.ProfileAdmitter{
+		Profiles:  o.Profiles,
+		Requested: o.requestedProcesses,
+	}
+	return append(slices.Clip(o.admitters), profileAdmitter)
 }
 
 func (o *LoaderOptions) getWorkingDir() (string, error) {
@@ -56,3 +67,14 @@ func (o *LoaderOptions) WithOrderedShutdown(enabled bool) {
 	o.isOrderedShutdown = enabled
 }
 
+// WithAssignedPorts keeps the ports assigned to the processes of a running
+// project, by replica name, when it is reloaded.
+func (o *LoaderOptions) WithAssignedPorts(ports map[string]map[string]int) {
+	o.assignedPorts = ports
+}
+
+// WithRequestedProcesses admits the processes, requested on the command line,
+// even if none of their profiles is active.
+func (o *LoaderOptions) WithRequestedProcesses(names []string) {
+	o.requestedProcesses = names
+}
@@ -238,7 +238,7 @@ func assignExecutableAndArgs(p *types.Project) {
 }
 
 func renderTemplates(p *types.Project) error {
-	tpl := templater.New(p.Vars)
+	tpl := templater.New(p.TemplateVars())
 	for name, proc := range p.Processes {
 		tpl.RenderProcess(&proc)
 
@@ -0,0 +1,80 @@
+package loader
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sort"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+// assignPorts resolves the declared ports of every process. previous holds the
+// ports assigned before a reload, by replica name, so that auto-assigned ports
+// don't change when the project is reloaded.
+func assignPorts(p *types.Project, previous map[string]map[string]int) error {
+	var listeners []io.Closer
+	// Keep the free ports bound until all of them are picked, so that no two
+	// processes are assigned the same one.
+	defer func() {
+		for _, l := range listeners {
+			_ = l.Close()
+		}
+	}()
+	names := make([]string, 0, len(p.Processes))
+	for name := range p.Processes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		proc := p.Processes[name]
+		held, err := assignProcessPorts(&proc, previous[name])
+		listeners = append(listeners, held...)
+		if err != nil {
+			return err
+		}
+		p.Processes[name] = proc
+	}
+	return nil
+}
+
+// AssignProcessPorts resolves the declared ports of a single process, such as
+// a new replica. A port of 0 is assigned a free port, unless previous already
+// holds one, and a fixed port is offset by the replica number so that the
+// replicas of a process don't collide.
+func AssignProcessPorts(proc *types.ProcessConfig, previous map[string]int) error {
+	held, err := assignProcessPorts(proc, previous)
+	for _, l := range held {
+		_ = l.Close()
+	}
+	return err
+}
+
+func assignProcessPorts(proc *types.ProcessConfig, previous map[string]int) ([]io.Closer, error) {
+	if len(proc.Ports) == 0 {
+		proc.AssignedPorts = nil
+		return nil, nil
+	}
+	var held []io.Closer
+	assigned := make(map[string]int, len(proc.Ports))
+	for name, port := range proc.Ports {
+		switch {
+		case port > 0:
+			if port+proc.ReplicaNum > 65535 {
+				return held, fmt.Errorf("port '%s' of process %s is out of range: %d + replica %d exceeds 65535", name, proc.ReplicaName, port, proc.ReplicaNum)
+			}
+			assigned[name] = port + proc.ReplicaNum
+		case previous[name] > 0:
+			assigned[name] = previous[name]
+		default:
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				return held, fmt.Errorf("failed to assign port '%s' of process %s: %w", name, proc.ReplicaName, err)
+			}
+			held = append(held, l)
+			assigned[name] = l.Addr().(*net.TCPAddr).Port
+		}
+	}
+	proc.AssignedPorts = assigned
+	return held, nil
+}
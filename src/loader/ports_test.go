@@ -0,0 +1,148 @@
+package loader
+
+import (
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/health"
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func Test_assignPorts(t *testing.T) {
+	project := &types.Project{
+		Processes: types.Processes{
+			"api": {
+				Name:        "api",
+				ReplicaName: "api",
+				Ports:       types.PortsConfig{"http": 0, "metrics": 9100},
+			},
+			"web-0": {
+				Name:        "web",
+				ReplicaName: "web-0",
+				Replicas:    2,
+				Ports:       types.PortsConfig{"http": 8080},
+			},
+			"web-1": {
+				Name:        "web",
+				ReplicaName: "web-1",
+				ReplicaNum:  1,
+				Replicas:    2,
+				Ports:       types.PortsConfig{"http": 8080},
+			},
+			"worker": {
+				Name:        "worker",
+				ReplicaName: "worker",
+				Ports:       types.PortsConfig{"debug": 0},
+			},
+			"db": {
+				Name:          "db",
+				ReplicaName:   "db",
+				AssignedPorts: map[string]int{"stale": 1},
+			},
+		},
+	}
+	previous := map[string]map[string]int{"worker": {"debug": 40000}}
+	if err := assignPorts(project, previous); err != nil {
+		t.Fatalf("assignPorts() error = %v", err)
+	}
+
+	api := project.Processes["api"].AssignedPorts
+	if api["http"] <= 0 || api["http"] > 65535 {
+		t.Errorf("api http port = %d, want a free port", api["http"])
+	}
+	if api["metrics"] != 9100 {
+		t.Errorf("api metrics port = %d, want 9100", api["metrics"])
+	}
+	if got := project.Processes["web-0"].AssignedPorts["http"]; got != 8080 {
+		t.Errorf("web-0 http port = %d, want 8080", got)
+	}
+	if got := project.Processes["web-1"].AssignedPorts["http"]; got != 8081 {
+		t.Errorf("web-1 http port = %d, want 8081", got)
+	}
+	if got := project.Processes["worker"].AssignedPorts["debug"]; got != 40000 {
+		t.Errorf("worker debug port = %d, want the previous 40000", got)
+	}
+	if got := project.Processes["db"].AssignedPorts; got != nil {
+		t.Errorf("db assigned ports = %v, want none", got)
+	}
+}
+
+func Test_assignPorts_Unique(t *testing.T) {
+	project := &types.Project{Processes: types.Processes{}}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		project.Processes[name] = types.ProcessConfig{
+			Name:        name,
+			ReplicaName: name,
+			Ports:       types.PortsConfig{"http": 0, "grpc": 0},
+		}
+	}
+	if err := assignPorts(project, nil); err != nil {
+		t.Fatalf("assignPorts() error = %v", err)
+	}
+	seen := map[int]string{}
+	for name, proc := range project.Processes {
+		for portName, port := range proc.AssignedPorts {
+			if other, ok := seen[port]; ok {
+				t.Errorf("port %d assigned to both %s and %s.%s", port, other, name, portName)
+			}
+			seen[port] = name + "." + portName
+		}
+	}
+}
+
+func TestAssignProcessPorts_OutOfRange(t *testing.T) {
+	proc := &types.ProcessConfig{
+		Name:        "web",
+		ReplicaName: "web-2",
+		ReplicaNum:  2,
+		Replicas:    3,
+		Ports:       types.PortsConfig{"http": 65534},
+	}
+	if err := AssignProcessPorts(proc, nil); err == nil {
+		t.Errorf("AssignProcessPorts() assigned %v, want an out of range error", proc.AssignedPorts)
+	}
+	proc.ReplicaNum = 1
+	if err := AssignProcessPorts(proc, nil); err != nil || proc.AssignedPorts["http"] != 65535 {
+		t.Errorf("AssignProcessPorts() = %v, %v, want http=65535", proc.AssignedPorts, err)
+	}
+}
+
+func Test_renderTemplates_Ports(t *testing.T) {
+	project := &types.Project{
+		Processes: types.Processes{
+			"api": {
+				Name:          "api",
+				ReplicaName:   "api",
+				Command:       "api --port {{ .ports.http }}",
+				AssignedPorts: map[string]int{"http": 9000},
+				ReadinessProbe: &health.Probe{
+					HttpGet: &health.HttpProbe{Host: "localhost", Port: "http"},
+				},
+				LivenessProbe: &health.Probe{
+					TcpSocket: &health.TcpSocketProbe{Port: "{{ .ports.http }}"},
+				},
+			},
+			"web": {
+				Name:        "web",
+				ReplicaName: "web",
+				Command:     "web",
+				Environment: []string{"API_URL=http://localhost:{{ .process_ports.api.http }}"},
+			},
+		},
+	}
+	if err := renderTemplates(project); err != nil {
+		t.Fatalf("renderTemplates() error = %v", err)
+	}
+	api := project.Processes["api"]
+	if api.Command != "api --port 9000" {
+		t.Errorf("api command = %q", api.Command)
+	}
+	if api.ReadinessProbe.HttpGet.NumPort != 9000 {
+		t.Errorf("readiness probe port = %d, want the named port 9000", api.ReadinessProbe.HttpGet.NumPort)
+	}
+	if api.LivenessProbe.TcpSocket.NumPort != 9000 {
+		t.Errorf("liveness probe port = %d, want 9000", api.LivenessProbe.TcpSocket.NumPort)
+	}
+	if env := project.Processes["web"].Environment[0]; env != "API_URL=http://localhost:9000" {
+		t.Errorf("web environment = %q", env)
+	}
+}
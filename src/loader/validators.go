@@ -142,6 +142,34 @@ func validateHealthDependencyHasHealthCheck(p *types.Project) error {
 	return nil
 }
 
+// validateDependencyConditions rejects dependencies that cannot be waited on:
+// a port or file condition without its target, or a malformed timeout.
+func validateDependencyConditions(p *types.Project) error {
+	for procName, proc := range p.Processes {
+		for depName, dep := range proc.DependsOn {
+			var errStr string
+			timeout, timeoutErr := dep.GetTimeoutDuration()
+			switch {
+			case dep.Condition == types.ProcessConditionPortOpen && (dep.Port <= 0 || dep.Port > 65535):
+				errStr = fmt.Sprintf("port open dependency on '%s' in '%s' requires a valid port", depName, procName)
+			case dep.Condition == types.ProcessConditionFileExists && dep.Path == "":
+				errStr = fmt.Sprintf("file exists dependency on '%s' in '%s' requires a path", depName, procName)
+			case timeoutErr != nil:
+				errStr = fmt.Sprintf("dependency on '%s' in '%s' has an invalid timeout '%s': %v", depName, procName, dep.Timeout, timeoutErr)
+			case timeout < 0:
+				errStr = fmt.Sprintf("dependency on '%s' in '%s' has a negative timeout", depName, procName)
+			case !dep.OnTimeout.IsValid():
+				errStr = fmt.Sprintf("dependency on '%s' in '%s' has an invalid on_timeout '%s' (expected fail, skip or continue)", depName, procName, dep.OnTimeout)
+			default:
+				continue
+			}
+			log.Error().Msg(errStr)
+			return errors.New(errStr)
+		}
+	}
+	return nil
+}
+
 func validateNoIncompatibleHealthChecks(p *types.Project) error {
 	for procName, proc := range p.Processes {
 		if proc.ReadinessProbe != nil && proc.ReadyLogLine != "" {
@@ -364,6 +392,26 @@ func validateMCPConfig(p *types.Project) error {
 	return nil
 }
 
+func validateSecrets(p *types.Project) error {
+	if err := p.Secrets.Validate(); err != nil {
+		if p.IsStrict {
+			return err
+		}
+		log.Error().Err(err).Msg("Secrets configuration invalid")
+	}
+	return nil
+}
+
+func validateProjectHooks(p *types.Project) error {
+	if err := p.Hooks.Validate(); err != nil {
+		if p.IsStrict {
+			return err
+		}
+		log.Error().Err(err).Msg("Project hooks configuration invalid")
+	}
+	return nil
+}
+
 func validateProcessEnvFileExists(p *types.Project) error {
 	for procName, proc := range p.Processes {
 		if proc.EnvFile != "" {
@@ -247,3 +247,75 @@ func Test_validateProject(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateDependencyConditions(t *testing.T) {
+	tests := []struct {
+		name    string
+		dep     types.ProcessDependency
+		wantErr bool
+	}{
+		{
+			name: "port open",
+			dep:  types.ProcessDependency{Condition: types.ProcessConditionPortOpen, Port: 5432},
+		},
+		{
+			name:    "port open without port",
+			dep:     types.ProcessDependency{Condition: types.ProcessConditionPortOpen},
+			wantErr: true,
+		},
+		{
+			name:    "port out of range",
+			dep:     types.ProcessDependency{Condition: types.ProcessConditionPortOpen, Port: 70000},
+			wantErr: true,
+		},
+		{
+			name: "file exists",
+			dep:  types.ProcessDependency{Condition: types.ProcessConditionFileExists, Path: "build/app"},
+		},
+		{
+			name:    "file exists without path",
+			dep:     types.ProcessDependency{Condition: types.ProcessConditionFileExists},
+			wantErr: true,
+		},
+		{
+			name: "timeout with action",
+			dep: types.ProcessDependency{
+				Condition: types.ProcessConditionHealthy,
+				Timeout:   "1m",
+				OnTimeout: types.DependencyTimeoutSkip,
+			},
+		},
+		{
+			name:    "negative timeout",
+			dep:     types.ProcessDependency{Condition: types.ProcessConditionStarted, Timeout: "-1s"},
+			wantErr: true,
+		},
+		{
+			name:    "malformed timeout",
+			dep:     types.ProcessDependency{Condition: types.ProcessConditionStarted, Timeout: "30"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid action",
+			dep:     types.ProcessDependency{Condition: types.ProcessConditionStarted, OnTimeout: "retry"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &types.Project{
+				Processes: types.Processes{
+					"db": {Name: "db", Command: "echo"},
+					"app": {
+						Name:      "app",
+						Command:   "echo",
+						DependsOn: types.DependsOnConfig{"db": tt.dep},
+					},
+				},
+			}
+			if err := validateDependencyConditions(p); (err != nil) != tt.wantErr {
+				t.Errorf("validateDependencyConditions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
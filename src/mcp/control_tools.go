@@ -136,7 +136,7 @@ func (s *Server) registerProjectControlTools() {
 
 	s.addTool(
 		mcp.NewTool(controlToolPrefix+"project_dependency_graph",
-			mcp.WithDescription("Return the project dependency graph: each node carries the process name, current status, readiness, and a depends_on map of upstream processes with their startup conditions (process_started, process_healthy, process_completed, process_log_ready). Useful for diagnosing why a process is stuck Pending."),
+			mcp.WithDescription("Return the project dependency graph: each node carries the process name, current status, readiness, and a depends_on map of upstream processes with their startup conditions (process_started, process_healthy, process_completed, process_log_ready, process_port_open, file_exists). Useful for diagnosing why a process is stuck Pending."),
 		),
 		s.handleProjectDependencyGraph,
 	)
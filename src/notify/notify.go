@@ -0,0 +1,234 @@
+// Package notify sends desktop notifications: through the freedesktop D-Bus
+// notification service when there is one, or through the terminal otherwise.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type Method string
+
+const (
+	// MethodAuto uses D-Bus when available, and the best terminal method
+	// otherwise.
+	MethodAuto Method = "auto"
+	// MethodDBus calls org.freedesktop.Notifications.Notify.
+	MethodDBus Method = "dbus"
+	// MethodOSC9 writes an OSC 9 sequence (iTerm2, WezTerm, kitty, ghostty).
+	MethodOSC9 Method = "osc9"
+	// MethodOSC777 writes an OSC 777 sequence (urxvt, foot).
+	MethodOSC777 Method = "osc777"
+	// MethodBell rings the terminal bell.
+	MethodBell Method = "bell"
+	// MethodNone disables the notifications.
+	MethodNone Method = "none"
+)
+
+// Bell is the sequence written by MethodBell.
+const Bell = "\007"
+
+const (
+	// DefaultMinInterval is the minimum time between two notifications of the
+	// same kind about the same process.
+	DefaultMinInterval = 30 * time.Second
+	dbusTimeout        = 5 * time.Second
+	// expireTimeout is how long the notification server displays the
+	// notification, in milliseconds.
+	expireTimeout = 5000
+	appName       = "process-compose"
+)
+
+func ParseMethod(method string) (Method, error) {
+	switch m := Method(strings.ToLower(method)); m {
+	case "":
+		return MethodAuto, nil
+	case MethodAuto, MethodDBus, MethodOSC9, MethodOSC777, MethodBell, MethodNone:
+		return m, nil
+	}
+	return MethodNone, fmt.Errorf("unknown notification method '%s': expected one of auto, dbus, osc9, osc777, bell, none", method)
+}
+
+// Notifier sends rate limited notifications. It is safe for concurrent use,
+// and a nil *Notifier sends nothing.
+type Notifier struct {
+	method      Method
+	minInterval time.Duration
+	out         io.Writer
+	sendDBus    func(ctx context.Context, title, body string) error
+
+	mtx     sync.Mutex
+	lastKey map[string]time.Time
+	now     func() time.Time
+}
+
+// New returns a notifier that writes its terminal notifications to out. A
+// minInterval of 0 uses DefaultMinInterval.
+func New(method Method, minInterval time.Duration, out io.Writer) *Notifier {
+	if minInterval <= 0 {
+		minInterval = DefaultMinInterval
+	}
+	n := &Notifier{
+		method:      method,
+		minInterval: minInterval,
+		out:         out,
+		sendDBus:    sendDBus,
+		lastKey:     map[string]time.Time{},
+		now:         time.Now,
+	}
+	if method == MethodAuto {
+		n.method = detectMethod()
+	}
+	return n
+}
+
+// Method returns the method the notifications are sent with.
+func (n *Notifier) Method() Method {
+	if n == nil {
+		return MethodNone
+	}
+	return n.method
+}
+
+// Notify sends a notification, unless one with the same key was sent less than
+// the minimum interval ago. It reports whether the notification was sent. D-Bus
+// notifications are sent in the background.
+func (n *Notifier) Notify(key, title, body string) bool {
+	if n == nil || n.method == MethodNone || !n.allow(key) {
+		return false
+	}
+	if n.method != MethodDBus {
+		n.writeTerminal(n.method, title, body)
+		return true
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), dbusTimeout)
+		defer cancel()
+		if err := n.sendDBus(ctx, title, body); err != nil {
+			log.Err(err).Msg("Failed to send a D-Bus notification, falling back to the terminal")
+			n.writeTerminal(terminalMethod(), title, body)
+		}
+	}()
+	return true
+}
+
+func (n *Notifier) allow(key string) bool {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	now := n.now()
+	if last, ok := n.lastKey[key]; ok && now.Sub(last) < n.minInterval {
+		log.Debug().Msgf("Notification %s dropped: sent %v ago", key, now.Sub(last))
+		return false
+	}
+	n.lastKey[key] = now
+	return true
+}
+
+func (n *Notifier) writeTerminal(method Method, title, body string) {
+	title, body = sanitize(title), sanitize(body)
+	var seq string
+	switch method {
+	case MethodOSC9:
+		seq = fmt.Sprintf("\033]9;%s: %s\007", title, body)
+	case MethodOSC777:
+		seq = fmt.Sprintf("\033]777;notify;%s;%s\007", strings.ReplaceAll(title, ";", ","), body)
+	default:
+		seq = Bell
+	}
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	if _, err := io.WriteString(n.out, seq); err != nil {
+		log.Err(err).Msg("Failed to write a terminal notification")
+	}
+}
+
+// sanitize drops the control characters, which would end the escape sequence
+// early.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\t':
+			return ' '
+		case r < 0x20 || r == 0x7f:
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// detectMethod picks D-Bus when a session bus and gdbus are available, and the
+// terminal method otherwise.
+func detectMethod() Method {
+	if hasDBus() {
+		return MethodDBus
+	}
+	return terminalMethod()
+}
+
+func hasDBus() bool {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		return false
+	}
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return false
+	}
+	_, err := exec.LookPath("gdbus")
+	return err == nil
+}
+
+// terminalMethod guesses the notification sequence understood by the terminal
+// from its environment, and settles for the bell.
+func terminalMethod() Method {
+	term := os.Getenv("TERM")
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "ghostty":
+		return MethodOSC9
+	}
+	switch {
+	case strings.Contains(term, "kitty"):
+		return MethodOSC9
+	case strings.HasPrefix(term, "rxvt"), strings.HasPrefix(term, "foot"):
+		return MethodOSC777
+	}
+	return MethodBell
+}
+
+// sendDBus calls the Notify method of the freedesktop notification service
+// with gdbus.
+func sendDBus(ctx context.Context, title, body string) error {
+	args := []string{
+		"call", "--session",
+		"--dest", "org.freedesktop.Notifications",
+		"--object-path", "/org/freedesktop/Notifications",
+		"--method", "org.freedesktop.Notifications.Notify",
+		gvariantString(appName), // app_name
+		"0",                     // replaces_id
+		gvariantString(""),      // app_icon
+		gvariantString(title),   // summary
+		gvariantString(body),    // body
+		"@as []",                // actions
+		"@a{sv} {}",             // hints
+		fmt.Sprint(expireTimeout),
+	}
+	out, err := exec.CommandContext(ctx, "gdbus", args...).CombinedOutput()
+	if err != nil {
+		return errors.Join(err, errors.New(strings.TrimSpace(string(out))))
+	}
+	return nil
+}
+
+// gvariantString quotes s in the GVariant text format parsed by gdbus.
+func gvariantString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
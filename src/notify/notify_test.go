@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestNotifier(method Method, out *bytes.Buffer) (*Notifier, *time.Time) {
+	now := time.Now()
+	n := New(method, 10*time.Second, out)
+	n.now = func() time.Time { return now }
+	return n, &now
+}
+
+func TestNotifyTerminal(t *testing.T) {
+	tests := []struct {
+		method Method
+		title  string
+		body   string
+		want   string
+	}{
+		{MethodOSC9, "pc: web", "is ready", "\033]9;pc: web: is ready\007"},
+		{MethodOSC777, "pc; web", "is\nready", "\033]777;notify;pc, web;is ready\007"},
+		{MethodBell, "pc: web", "is ready", "\007"},
+		{MethodOSC9, "pc\033]0;x", "ok", "\033]9;pc]0;x: ok\007"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.method), func(t *testing.T) {
+			var out bytes.Buffer
+			n, _ := newTestNotifier(tt.method, &out)
+			if !n.Notify("web:ready", tt.title, tt.body) {
+				t.Fatal("notification not sent")
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifyRateLimit(t *testing.T) {
+	var out bytes.Buffer
+	n, now := newTestNotifier(MethodBell, &out)
+	steps := []struct {
+		after time.Duration
+		key   string
+		want  bool
+	}{
+		{0, "web:failed", true},
+		{0, "db:failed", true}, // processes failing together are all reported
+		{100 * time.Millisecond, "api:failed", true},
+		{time.Second, "db:failed", false}, // per key limit
+		{2 * time.Second, "web:ready", true},
+		{30 * time.Second, "db:failed", true},
+	}
+	for i, step := range steps {
+		*now = now.Add(step.after)
+		if got := n.Notify(step.key, "title", "body"); got != step.want {
+			t.Errorf("step %d: Notify(%s) = %v, want %v", i, step.key, got, step.want)
+		}
+	}
+}
+
+func TestNotifyDBusFallback(t *testing.T) {
+	var out bytes.Buffer
+	n, _ := newTestNotifier(MethodDBus, &out)
+	t.Setenv("TERM_PROGRAM", "")
+	t.Setenv("TERM", "xterm")
+	sent := make(chan struct{})
+	n.sendDBus = func(ctx context.Context, title, body string) error {
+		defer close(sent)
+		return context.DeadlineExceeded
+	}
+	n.Notify("web:failed", "title", "body")
+	<-sent
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		n.mtx.Lock()
+		got := out.String()
+		n.mtx.Unlock()
+		if got == "\007" {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("the terminal bell was not rung after the D-Bus failure")
+}
+
+func TestNilNotifier(t *testing.T) {
+	var n *Notifier
+	if n.Notify("key", "title", "body") {
+		t.Error("a nil notifier sent a notification")
+	}
+	if n.Method() != MethodNone {
+		t.Errorf("Method() = %s, want %s", n.Method(), MethodNone)
+	}
+}
+
+func TestParseMethod(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Method
+		wantErr bool
+	}{
+		{"", MethodAuto, false},
+		{"DBus", MethodDBus, false},
+		{"osc777", MethodOSC777, false},
+		{"none", MethodNone, false},
+		{"growl", MethodNone, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMethod(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseMethod(%q) = %s, %v, want %s, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestGvariantString(t *testing.T) {
+	if got, want := gvariantString("say \"hi\"\\\nbye"), `"say \"hi\"\\\nbye"`; got != want {
+		t.Errorf("gvariantString() = %s, want %s", got, want)
+	}
+}
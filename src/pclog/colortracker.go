@@ -45,3 +45,17 @@ func stringToInt(s string) int {
 	// Convert the hash to an integer
 	return int(hash.Sum32())
 }
+
+// ColorizeLevel colors a log line by the severity of its level. Lines with an
+// info or unknown level are returned unchanged.
+func ColorizeLevel(level, line string) string {
+	switch level {
+	case LogLevelError, LogLevelFatal:
+		return color.RedString("%s", line)
+	case LogLevelWarn:
+		return color.YellowString("%s", line)
+	case LogLevelDebug, LogLevelTrace:
+		return color.HiBlackString("%s", line)
+	}
+	return line
+}
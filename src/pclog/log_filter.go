@@ -0,0 +1,158 @@
+package pclog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// logFilterOperators are tried in order, so that ">=" is not read as ">".
+var logFilterOperators = []string{">=", "<=", "!=", "=", ">", "<"}
+
+type logCondition struct {
+	key   string
+	op    string
+	value string
+	// rank of the value of a level condition.
+	rank int
+}
+
+// LogFilter selects the log records that meet all of its conditions, such as
+// "level>=warn request_id=abc". The level is compared by severity, the
+// message and fields by value. Fields also support ordering operators when
+// both sides are numbers ("status>=500").
+type LogFilter struct {
+	expr       string
+	conditions []logCondition
+}
+
+// ParseLogFilter parses a whitespace separated list of conditions. An empty
+// expression returns a nil filter, which selects every line.
+func ParseLogFilter(expr string) (*LogFilter, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, nil
+	}
+	filter := &LogFilter{expr: expr}
+	for _, term := range strings.Fields(expr) {
+		cond, err := parseLogCondition(term)
+		if err != nil {
+			return nil, err
+		}
+		filter.conditions = append(filter.conditions, cond)
+	}
+	return filter, nil
+}
+
+func parseLogCondition(term string) (logCondition, error) {
+	for _, op := range logFilterOperators {
+		key, value, found := strings.Cut(term, op)
+		if !found {
+			continue
+		}
+		if key == "" || value == "" {
+			return logCondition{}, fmt.Errorf("invalid log filter %q: expected key%svalue", term, op)
+		}
+		cond := logCondition{key: key, op: op, value: value}
+		switch key {
+		case "level":
+			cond.value, cond.rank = NormalizeLogLevel(value)
+			if cond.rank < 0 {
+				return logCondition{}, fmt.Errorf("invalid log filter %q: unknown level %q", term, value)
+			}
+		case "message", "msg":
+			if op != "=" && op != "!=" {
+				return logCondition{}, fmt.Errorf("invalid log filter %q: the message only supports = and !=", term)
+			}
+		}
+		return cond, nil
+	}
+	return logCondition{}, fmt.Errorf("invalid log filter %q: expected key=value or level>=value", term)
+}
+
+// String returns the expression the filter was parsed from.
+func (f *LogFilter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.expr
+}
+
+// Match reports whether a log record meets all the conditions of the filter.
+// ok is false for a line that could not be parsed, which only a nil filter
+// selects.
+func (f *LogFilter) Match(rec *LogRecord, ok bool) bool {
+	if f == nil {
+		return true
+	}
+	if !ok {
+		return false
+	}
+	for i := range f.conditions {
+		if !f.conditions[i].match(rec) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchLine parses a line with parser and matches the result.
+func (f *LogFilter) MatchLine(parser *LogParser, line string) bool {
+	if f == nil {
+		return true
+	}
+	rec, ok := parser.Parse(line)
+	return f.Match(&rec, ok)
+}
+
+func (c *logCondition) match(rec *LogRecord) bool {
+	switch c.key {
+	case "level":
+		_, rank := NormalizeLogLevel(rec.Level)
+		if rank < 0 {
+			return c.op == "!="
+		}
+		return compare(c.op, rank-c.rank)
+	case "message", "msg":
+		return (rec.Message == c.value) == (c.op == "=")
+	}
+	value, found := rec.Fields[c.key]
+	switch c.op {
+	case "=":
+		return found && value == c.value
+	case "!=":
+		return !found || value != c.value
+	}
+	got, err := strconv.ParseFloat(value, 64)
+	if !found || err != nil {
+		return false
+	}
+	want, err := strconv.ParseFloat(c.value, 64)
+	if err != nil {
+		return false
+	}
+	switch {
+	case got < want:
+		return compare(c.op, -1)
+	case got > want:
+		return compare(c.op, 1)
+	}
+	return compare(c.op, 0)
+}
+
+// compare applies op to the sign of a three way comparison.
+func compare(op string, cmp int) bool {
+	switch op {
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	case "!=":
+		return cmp != 0
+	}
+	return cmp == 0
+}
@@ -0,0 +1,83 @@
+package pclog
+
+import (
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestParseLogFilter_Invalid(t *testing.T) {
+	tests := []string{
+		"level",
+		"level>=",
+		"=abc",
+		"level>=loud",
+		"message>hello",
+	}
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := ParseLogFilter(expr); err == nil {
+				t.Errorf("ParseLogFilter(%q) expected an error", expr)
+			}
+		})
+	}
+}
+
+func TestLogFilter_MatchLine(t *testing.T) {
+	parser, err := NewLogParser(types.LogFormatLogfmt, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const (
+		debugLine = `level=debug msg=polling`
+		infoLine  = `level=info msg="request done" request_id=abc status=200`
+		warnLine  = `level=warning msg=slow request_id=abc status=200`
+		errorLine = `level=error msg=failed request_id=xyz status=503`
+		plainLine = `goroutine 1 [running]:`
+	)
+	lines := []string{debugLine, infoLine, warnLine, errorLine, plainLine}
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"", lines},
+		{"level>=warn", []string{warnLine, errorLine}},
+		{"level<info", []string{debugLine}},
+		{"level=err", []string{errorLine}},
+		{"level!=debug", []string{infoLine, warnLine, errorLine}},
+		{"request_id=abc", []string{infoLine, warnLine}},
+		{"request_id!=abc", []string{debugLine, errorLine}},
+		{"level>=warn request_id=abc", []string{warnLine}},
+		{"status>=500", []string{errorLine}},
+		{"status<300", []string{infoLine, warnLine}},
+		{"msg=slow", []string{warnLine}},
+		{"message!=polling level<=info", []string{infoLine}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			filter, err := ParseLogFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseLogFilter() error = %v", err)
+			}
+			if filter.String() != tt.expr {
+				t.Errorf("String() = %q, want %q", filter.String(), tt.expr)
+			}
+			var got []string
+			for _, line := range lines {
+				if filter.MatchLine(parser, line) {
+					got = append(got, line)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("matched %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("matched %q, want %q", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
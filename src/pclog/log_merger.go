@@ -0,0 +1,111 @@
+package pclog
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// LogLine is a log line with the time it was written to the log buffer.
+type LogLine struct {
+	Time    time.Time
+	Message string
+}
+
+// MergedLogLine is a log line of one of the processes of a LogMerger.
+type MergedLogLine struct {
+	LogLine
+	ProcessName string
+}
+
+// LogMerger interleaves the logs of several processes into a single stream,
+// ordered by the time the lines were received. The buffered lines of every
+// process are merged when the merger starts, and new lines are emitted as they
+// are written.
+type LogMerger struct {
+	mx      sync.Mutex
+	tail    int
+	onLine  func(MergedLogLine)
+	started bool
+	pending []MergedLogLine
+}
+
+// NewLogMerger returns a merger that calls onLine for every line, with up to
+// tail buffered lines of each process. onLine is never called concurrently.
+func NewLogMerger(tail int, onLine func(MergedLogLine)) *LogMerger {
+	return &LogMerger{
+		tail:   tail,
+		onLine: onLine,
+	}
+}
+
+// Observer returns the observer to subscribe to the logs of a process.
+func (m *LogMerger) Observer(name string) LogObserver {
+	return &mergerObserver{
+		merger:   m,
+		name:     name,
+		uniqueId: GenerateUniqueID(10),
+	}
+}
+
+// Start emits the lines received so far ordered by time, and every line that
+// is received from now on. It is called once all the observers are subscribed.
+func (m *LogMerger) Start() {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	slices.SortStableFunc(m.pending, func(a, b MergedLogLine) int {
+		return a.Time.Compare(b.Time)
+	})
+	for _, line := range m.pending {
+		m.onLine(line)
+	}
+	m.pending = nil
+	m.started = true
+}
+
+func (m *LogMerger) add(name string, lines []LogLine) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	for _, line := range lines {
+		merged := MergedLogLine{LogLine: line, ProcessName: name}
+		if m.started {
+			m.onLine(merged)
+		} else {
+			m.pending = append(m.pending, merged)
+		}
+	}
+}
+
+type mergerObserver struct {
+	merger   *LogMerger
+	name     string
+	uniqueId string
+}
+
+func (o *mergerObserver) WriteString(line string) (n int, err error) {
+	o.merger.add(o.name, []LogLine{{Time: time.Now(), Message: line}})
+	return len(line), nil
+}
+
+// SetLines receives the buffered lines of a log without timestamps, such as a
+// remote one. They are merged as if they were received now.
+func (o *mergerObserver) SetLines(lines []string) {
+	now := time.Now()
+	timed := make([]LogLine, len(lines))
+	for i, line := range lines {
+		timed[i] = LogLine{Time: now, Message: line}
+	}
+	o.merger.add(o.name, timed)
+}
+
+func (o *mergerObserver) SetTimedLines(lines []LogLine) {
+	o.merger.add(o.name, lines)
+}
+
+func (o *mergerObserver) GetTailLength() int {
+	return o.merger.tail
+}
+
+func (o *mergerObserver) GetUniqueID() string {
+	return o.uniqueId
+}
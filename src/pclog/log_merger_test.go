@@ -0,0 +1,62 @@
+package pclog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLogMerger(t *testing.T) {
+	web := NewLogBuffer(10)
+	db := NewLogBuffer(10)
+	web.Write("web 1")
+	time.Sleep(time.Millisecond)
+	db.Write("db 1")
+	time.Sleep(time.Millisecond)
+	web.Write("web 2")
+	time.Sleep(time.Millisecond)
+	db.Write("db 2")
+
+	var got []string
+	merger := NewLogMerger(10, func(line MergedLogLine) {
+		got = append(got, line.ProcessName+": "+line.Message)
+	})
+	webObserver := merger.Observer("web")
+	dbObserver := merger.Observer("db")
+	web.GetLogsAndSubscribe(webObserver)
+	// Written before the merger starts, after the buffered lines of db.
+	web.Write("web 3")
+	db.GetLogsAndSubscribe(dbObserver)
+	merger.Start()
+	db.Write("db 3")
+	web.Write("web 4")
+
+	web.UnSubscribe(webObserver)
+	db.UnSubscribe(dbObserver)
+	web.Write("web 5")
+
+	want := []string{"web: web 1", "db: db 1", "web: web 2", "db: db 2", "web: web 3", "db: db 3", "web: web 4"}
+	if len(got) != len(want) {
+		t.Fatalf("merged %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("merged %q, want %q", got, want)
+		}
+	}
+}
+
+func TestLogMerger_Tail(t *testing.T) {
+	web := NewLogBuffer(10)
+	for _, line := range []string{"1", "2", "3"} {
+		web.Write(line)
+	}
+	var got []string
+	merger := NewLogMerger(2, func(line MergedLogLine) {
+		got = append(got, line.Message)
+	})
+	web.GetLogsAndSubscribe(merger.Observer("web"))
+	merger.Start()
+	if len(got) != 2 || got[0] != "2" || got[1] != "3" {
+		t.Errorf("merged %q, want the last 2 lines", got)
+	}
+}
@@ -0,0 +1,253 @@
+package pclog
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+// Log levels, from the least to the most severe.
+const (
+	LogLevelTrace = "trace"
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+	LogLevelFatal = "fatal"
+)
+
+var logLevels = []string{LogLevelTrace, LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError, LogLevelFatal}
+
+// logLevelAliases maps the level names used by common logging libraries to
+// the levels above.
+var logLevelAliases = map[string]string{
+	"trc":         LogLevelTrace,
+	"dbg":         LogLevelDebug,
+	"inf":         LogLevelInfo,
+	"information": LogLevelInfo,
+	"notice":      LogLevelInfo,
+	"wrn":         LogLevelWarn,
+	"warning":     LogLevelWarn,
+	"err":         LogLevelError,
+	"ftl":         LogLevelFatal,
+	"panic":       LogLevelFatal,
+	"crit":        LogLevelFatal,
+	"critical":    LogLevelFatal,
+	"alert":       LogLevelFatal,
+	"emerg":       LogLevelFatal,
+}
+
+var (
+	jsonLevelKeys   = []string{"level", "lvl", "severity"}
+	jsonMessageKeys = []string{"msg", "message"}
+)
+
+// LogRecord is a log line parsed according to the log_format of its process.
+type LogRecord struct {
+	Level   string            `json:"level,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+// NormalizeLogLevel maps a level as logged by a process to one of the LogLevel
+// constants, and returns its rank in logLevels. Numeric levels follow the
+// pino and bunyan convention (30 is info). An unknown level is returned in
+// lower case with a rank of -1.
+func NormalizeLogLevel(level string) (string, int) {
+	level = strings.ToLower(strings.TrimSpace(level))
+	if alias, ok := logLevelAliases[level]; ok {
+		level = alias
+	} else if n, err := strconv.Atoi(level); err == nil && n >= 10 {
+		level = logLevels[min(n/10-1, len(logLevels)-1)]
+	}
+	for rank, name := range logLevels {
+		if name == level {
+			return level, rank
+		}
+	}
+	return level, -1
+}
+
+// LogParser extracts a LogRecord from the log lines of a process.
+type LogParser struct {
+	format  string
+	pattern *regexp.Regexp
+}
+
+// NewLogParser returns the parser for a log_format, and its log_regex for
+// the regex format. An empty format returns a nil parser, which parses
+// nothing.
+func NewLogParser(format, pattern string) (*LogParser, error) {
+	parser := &LogParser{format: format}
+	switch format {
+	case "":
+		return nil, nil
+	case types.LogFormatJSON, types.LogFormatLogfmt:
+	case types.LogFormatRegex:
+		if pattern == "" {
+			return nil, fmt.Errorf("log_format %s requires a log_regex", format)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log_regex: %w", err)
+		}
+		parser.pattern = re
+	default:
+		return nil, fmt.Errorf("unknown log_format %q", format)
+	}
+	return parser, nil
+}
+
+// Parse parses a log line. It reports false for a line that is not in the
+// format of the parser, such as a stack trace or a banner.
+func (p *LogParser) Parse(line string) (LogRecord, bool) {
+	if p == nil {
+		return LogRecord{}, false
+	}
+	switch p.format {
+	case types.LogFormatJSON:
+		return parseJSONRecord(line)
+	case types.LogFormatLogfmt:
+		return parseLogfmtRecord(line)
+	case types.LogFormatRegex:
+		return p.parseRegexRecord(line)
+	}
+	return LogRecord{}, false
+}
+
+func parseJSONRecord(line string) (LogRecord, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "{") {
+		return LogRecord{}, false
+	}
+	var values map[string]any
+	if err := json.Unmarshal([]byte(line), &values); err != nil {
+		return LogRecord{}, false
+	}
+	fields := make(map[string]string, len(values))
+	for key, value := range values {
+		fields[key] = jsonFieldString(value)
+	}
+	return newLogRecord(fields, jsonLevelKeys, jsonMessageKeys), true
+}
+
+func jsonFieldString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return "null"
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+}
+
+func parseLogfmtRecord(line string) (LogRecord, bool) {
+	fields, ok := parseLogfmt(line)
+	if !ok {
+		return LogRecord{}, false
+	}
+	return newLogRecord(fields, jsonLevelKeys, jsonMessageKeys), true
+}
+
+// parseLogfmt splits a line of key=value pairs, where values containing spaces
+// are quoted. It reports false unless the line has at least one pair.
+func parseLogfmt(line string) (map[string]string, bool) {
+	fields := map[string]string{}
+	hasPair := false
+	for i := 0; i < len(line); {
+		if line[i] == ' ' || line[i] == '\t' {
+			i++
+			continue
+		}
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' && line[i] != '\t' {
+			i++
+		}
+		key := line[start:i]
+		if i >= len(line) || line[i] != '=' {
+			fields[key] = ""
+			continue
+		}
+		i++ // '='
+		var value string
+		if i < len(line) && line[i] == '"' {
+			start = i
+			for i++; i < len(line) && line[i] != '"'; i++ {
+				if line[i] == '\\' {
+					i++
+				}
+			}
+			i = min(i+1, len(line))
+			quoted := line[start:i]
+			unquoted, err := strconv.Unquote(quoted)
+			if err != nil {
+				unquoted = strings.Trim(quoted, `"`)
+			}
+			value = unquoted
+		} else {
+			start = i
+			for i < len(line) && line[i] != ' ' && line[i] != '\t' {
+				i++
+			}
+			value = line[start:i]
+		}
+		if key != "" {
+			fields[key] = value
+			hasPair = true
+		}
+	}
+	return fields, hasPair
+}
+
+func (p *LogParser) parseRegexRecord(line string) (LogRecord, bool) {
+	match := p.pattern.FindStringSubmatch(line)
+	if match == nil {
+		return LogRecord{}, false
+	}
+	fields := map[string]string{}
+	for i, name := range p.pattern.SubexpNames() {
+		if name != "" {
+			fields[name] = match[i]
+		}
+	}
+	return newLogRecord(fields, []string{"level"}, []string{"message", "msg"}), true
+}
+
+// newLogRecord moves the level and message out of the parsed fields.
+func newLogRecord(fields map[string]string, levelKeys, messageKeys []string) LogRecord {
+	var rec LogRecord
+	if key, ok := firstKey(fields, levelKeys); ok {
+		rec.Level, _ = NormalizeLogLevel(fields[key])
+		delete(fields, key)
+	}
+	if key, ok := firstKey(fields, messageKeys); ok {
+		rec.Message = fields[key]
+		delete(fields, key)
+	}
+	if len(fields) > 0 {
+		rec.Fields = fields
+	}
+	return rec
+}
+
+func firstKey(fields map[string]string, keys []string) (string, bool) {
+	for _, key := range keys {
+		if _, ok := fields[key]; ok {
+			return key, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,150 @@
+package pclog
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestNormalizeLogLevel(t *testing.T) {
+	tests := []struct {
+		level     string
+		wantLevel string
+		wantRank  int
+	}{
+		{"INFO", LogLevelInfo, 2},
+		{" Warning ", LogLevelWarn, 3},
+		{"ERR", LogLevelError, 4},
+		{"panic", LogLevelFatal, 5},
+		{"10", LogLevelTrace, 0},
+		{"30", LogLevelInfo, 2},
+		{"60", LogLevelFatal, 5},
+		{"99", LogLevelFatal, 5},
+		{"verbose", "verbose", -1},
+		{"5", "5", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			level, rank := NormalizeLogLevel(tt.level)
+			if level != tt.wantLevel || rank != tt.wantRank {
+				t.Errorf("NormalizeLogLevel(%q) = %q, %d, want %q, %d", tt.level, level, rank, tt.wantLevel, tt.wantRank)
+			}
+		})
+	}
+}
+
+func TestLogParser_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		pattern string
+		line    string
+		want    LogRecord
+		wantOk  bool
+	}{
+		{
+			name:   "json",
+			format: types.LogFormatJSON,
+			line:   `{"level":"WARN","msg":"slow query","duration":1.5,"cached":false,"user":{"id":7}}`,
+			want: LogRecord{
+				Level:   LogLevelWarn,
+				Message: "slow query",
+				Fields:  map[string]string{"duration": "1.5", "cached": "false", "user": `{"id":7}`},
+			},
+			wantOk: true,
+		},
+		{
+			name:   "json numeric level",
+			format: types.LogFormatJSON,
+			line:   `{"level":50,"message":"boom"}`,
+			want:   LogRecord{Level: LogLevelError, Message: "boom"},
+			wantOk: true,
+		},
+		{
+			name:   "json not an object",
+			format: types.LogFormatJSON,
+			line:   "panic: runtime error",
+		},
+		{
+			name:   "logfmt",
+			format: types.LogFormatLogfmt,
+			line:   `time=2026-01-01T00:00:00Z level=info msg="request done" status=200 path=/api`,
+			want: LogRecord{
+				Level:   LogLevelInfo,
+				Message: "request done",
+				Fields:  map[string]string{"time": "2026-01-01T00:00:00Z", "status": "200", "path": "/api"},
+			},
+			wantOk: true,
+		},
+		{
+			name:   "logfmt escaped quote",
+			format: types.LogFormatLogfmt,
+			line:   `msg="say \"hi\"" debug`,
+			want:   LogRecord{Message: `say "hi"`, Fields: map[string]string{"debug": ""}},
+			wantOk: true,
+		},
+		{
+			name:   "logfmt without pairs",
+			format: types.LogFormatLogfmt,
+			line:   "Starting server",
+		},
+		{
+			name:    "regex",
+			format:  types.LogFormatRegex,
+			pattern: `^\[(?P<level>\w+)\] (?P<component>\w+): (?P<message>.*)$`,
+			line:    "[ERROR] db: connection refused",
+			want: LogRecord{
+				Level:   LogLevelError,
+				Message: "connection refused",
+				Fields:  map[string]string{"component": "db"},
+			},
+			wantOk: true,
+		},
+		{
+			name:    "regex no match",
+			format:  types.LogFormatRegex,
+			pattern: `^\[(?P<level>\w+)\] (?P<message>.*)$`,
+			line:    "    at main.go:12",
+		},
+		{
+			name:   "no format",
+			format: "",
+			line:   `{"level":"info"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := NewLogParser(tt.format, tt.pattern)
+			if err != nil {
+				t.Fatalf("NewLogParser() error = %v", err)
+			}
+			got, ok := parser.Parse(tt.line)
+			if ok != tt.wantOk {
+				t.Fatalf("Parse() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLogParser_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		pattern string
+	}{
+		{"regex without pattern", types.LogFormatRegex, ""},
+		{"invalid pattern", types.LogFormatRegex, "("},
+		{"unknown format", "xml", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewLogParser(tt.format, tt.pattern); err == nil {
+				t.Error("NewLogParser() expected an error")
+			}
+		})
+	}
+}
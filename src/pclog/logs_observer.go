@@ -6,3 +6,11 @@ type LogObserver interface {
 	GetTailLength() int
 	GetUniqueID() string
 }
+
+// TimedLogObserver is a LogObserver that is subscribed with the time each of
+// the buffered lines was written, so that the logs of several processes can be
+// merged.
+type TimedLogObserver interface {
+	LogObserver
+	SetTimedLines(lines []LogLine)
+}
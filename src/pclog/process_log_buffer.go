@@ -9,6 +9,7 @@ import (
 type ProcessLogBuffer struct {
 	mxBuf          sync.Mutex
 	buffer         []string // fixed-size ring buffer
+	times          []time.Time
 	size           int      // capacity
 	head           int      // next write index
 	count          int      // items stored (0..size)
@@ -26,6 +27,7 @@ func NewLogBuffer(size int) *ProcessLogBuffer {
 	return &ProcessLogBuffer{
 		size:      size,
 		buffer:    make([]string, size),
+		times:     make([]time.Time, size),
 		observers: map[string]LogObserver{},
 	}
 }
@@ -41,9 +43,11 @@ func (b *ProcessLogBuffer) GetLastWriteTime() time.Time {
 }
 
 func (b *ProcessLogBuffer) Write(message string) {
-	b.lastWriteNano.Store(time.Now().UnixNano())
+	now := time.Now()
+	b.lastWriteNano.Store(now.UnixNano())
 	b.mxBuf.Lock()
 	b.buffer[b.head] = message
+	b.times[b.head] = now
 	b.head = (b.head + 1) % b.size
 	if b.count < b.size {
 		b.count++
@@ -68,8 +72,33 @@ func (b *ProcessLogBuffer) GetLogRange(endOffset, limit int) []string {
 	b.mxBuf.Lock()
 	defer b.mxBuf.Unlock()
 
+	firstIdx, count := b.logRange(endOffset, limit)
+	result := make([]string, count)
+	for i := range count {
+		result[i] = b.buffer[(firstIdx+i)%b.size]
+	}
+	return result
+}
+
+// GetTimedLogRange is GetLogRange with the time each line was written.
+func (b *ProcessLogBuffer) GetTimedLogRange(endOffset, limit int) []LogLine {
+	b.mxBuf.Lock()
+	defer b.mxBuf.Unlock()
+
+	firstIdx, count := b.logRange(endOffset, limit)
+	result := make([]LogLine, count)
+	for i := range count {
+		idx := (firstIdx + i) % b.size
+		result[i] = LogLine{Time: b.times[idx], Message: b.buffer[idx]}
+	}
+	return result
+}
+
+// logRange returns the ring index of the first line of a range and its length.
+// Must be called with mxBuf held.
+func (b *ProcessLogBuffer) logRange(endOffset, limit int) (int, int) {
 	if b.count == 0 {
-		return []string{}
+		return 0, 0
 	}
 
 	if endOffset < 0 {
@@ -81,7 +110,7 @@ func (b *ProcessLogBuffer) GetLogRange(endOffset, limit int) []string {
 
 	available := b.count - endOffset
 	if available <= 0 {
-		return []string{}
+		return 0, 0
 	}
 
 	if limit <= 0 {
@@ -91,16 +120,11 @@ func (b *ProcessLogBuffer) GetLogRange(endOffset, limit int) []string {
 		limit = available
 	}
 
-	result := make([]string, limit)
 	// Start of the logical buffer (oldest element)
 	start := (b.head - b.count + b.size) % b.size
 	// Skip to the first element we want: offset from end means we skip the last endOffset items,
 	// and we want the last `limit` items of the remaining.
-	firstIdx := (start + available - limit) % b.size
-	for i := range limit {
-		result[i] = b.buffer[(firstIdx+i)%b.size]
-	}
-	return result
+	return (start + available - limit) % b.size, limit
 }
 
 func (b *ProcessLogBuffer) GetLogLength() int {
@@ -110,6 +134,14 @@ func (b *ProcessLogBuffer) GetLogLength() int {
 }
 
 func (b *ProcessLogBuffer) GetLogsAndSubscribe(observer LogObserver) {
+	if timed, ok := observer.(TimedLogObserver); ok {
+		lines := b.GetTimedLogRange(0, observer.GetTailLength())
+		b.mxObs.Lock()
+		defer b.mxObs.Unlock()
+		timed.SetTimedLines(lines)
+		b.observers[observer.GetUniqueID()] = observer
+		return
+	}
 	lines := b.GetLogRange(0, observer.GetTailLength())
 	b.mxObs.Lock()
 	defer b.mxObs.Unlock()
@@ -123,6 +155,19 @@ func (b *ProcessLogBuffer) Subscribe(observer LogObserver) {
 	b.observers[observer.GetUniqueID()] = observer
 }
 
+// Follow calls onLine for every line written from now on, until the returned
+// function is called.
+func (b *ProcessLogBuffer) Follow(onLine func(line string)) func() {
+	connector := NewConnector(func([]string) {}, func(line string) (int, error) {
+		onLine(line)
+		return len(line), nil
+	}, 0)
+	b.Subscribe(connector)
+	return func() {
+		b.UnSubscribe(connector)
+	}
+}
+
 func (b *ProcessLogBuffer) UnSubscribe(observer LogObserver) {
 	b.mxObs.Lock()
 	defer b.mxObs.Unlock()
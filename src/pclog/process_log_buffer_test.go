@@ -277,6 +277,23 @@ func TestSubscribeAndUnSubscribe(t *testing.T) {
 	}
 }
 
+func TestFollow(t *testing.T) {
+	buffer := NewLogBuffer(10)
+	buffer.Write("before")
+
+	var lines []string
+	unfollow := buffer.Follow(func(line string) {
+		lines = append(lines, line)
+	})
+	buffer.Write("hello")
+	unfollow()
+	buffer.Write("world")
+
+	if len(lines) != 1 || lines[0] != "hello" {
+		t.Fatalf("follower should only have received 'hello', got %v", lines)
+	}
+}
+
 func TestGetLogsAndSubscribe(t *testing.T) {
 	buffer := NewLogBuffer(10)
 	for i := range 5 {
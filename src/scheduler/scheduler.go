@@ -29,7 +29,11 @@ type ScheduleEntry struct {
 	Config       *types.ScheduleConfig
 	Job          gocron.Job
 	RunningCount int
-	mutex        sync.Mutex
+	// Runs and Skipped count the due runs that started and those that did
+	// not.
+	Runs    int
+	Skipped int
+	mutex   sync.Mutex
 }
 
 // New creates a new Scheduler.
@@ -126,6 +130,7 @@ func (s *Scheduler) runScheduledProcess(name string, entry *ScheduleEntry) {
 	entry.mutex.Lock()
 	maxConcurrent := entry.Config.GetMaxConcurrent()
 	if entry.RunningCount >= maxConcurrent {
+		entry.Skipped++
 		entry.mutex.Unlock()
 		log.Debug().Msgf("Skipping scheduled run of %s: max concurrent (%d) reached", name, maxConcurrent)
 		return
@@ -140,9 +145,31 @@ func (s *Scheduler) runScheduledProcess(name string, entry *ScheduleEntry) {
 	}()
 
 	log.Info().Msgf("Starting scheduled process: %s", name)
-	if err := s.starter.StartProcess(name); err != nil {
+	err := s.starter.StartProcess(name)
+	if err != nil {
 		log.Error().Err(err).Msgf("Failed to start scheduled process %s", name)
 	}
+	entry.mutex.Lock()
+	if err != nil {
+		entry.Skipped++
+	} else {
+		entry.Runs++
+	}
+	entry.mutex.Unlock()
+}
+
+// GetRunCounts returns how many scheduled runs of a process started and how
+// many were skipped.
+func (s *Scheduler) GetRunCounts(name string) (runs, skipped int) {
+	s.mutex.RLock()
+	entry, ok := s.schedules[name]
+	s.mutex.RUnlock()
+	if !ok {
+		return 0, 0
+	}
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+	return entry.Runs, entry.Skipped
 }
 
 // GetNextRunTime returns the next scheduled run time for a process.
@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -273,3 +274,30 @@ func TestScheduler_PauseResume(t *testing.T) {
 		t.Error("GetNextRunTime() should return non-nil for resumed process")
 	}
 }
+
+func TestScheduler_GetRunCounts(t *testing.T) {
+	mock := &mockProcessStarter{}
+	s, _ := New(mock)
+	_ = s.AddProcess("counted", &types.ScheduleConfig{Interval: "1h"})
+	entry := s.schedules["counted"]
+
+	s.runScheduledProcess("counted", entry)
+	s.runScheduledProcess("counted", entry)
+
+	// A run due while max_concurrent is reached is skipped.
+	entry.RunningCount = entry.Config.GetMaxConcurrent()
+	s.runScheduledProcess("counted", entry)
+	entry.RunningCount = 0
+
+	// So is a run that fails to start.
+	mock.startError = errors.New("already running")
+	s.runScheduledProcess("counted", entry)
+
+	runs, skipped := s.GetRunCounts("counted")
+	if runs != 2 || skipped != 2 {
+		t.Errorf("GetRunCounts() = %d, %d, want 2, 2", runs, skipped)
+	}
+	if runs, skipped = s.GetRunCounts("unknown"); runs != 0 || skipped != 0 {
+		t.Errorf("GetRunCounts() for an unscheduled process = %d, %d, want 0, 0", runs, skipped)
+	}
+}
@@ -0,0 +1,133 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/joho/godotenv"
+)
+
+// fetchTimeout bounds every provider. It leaves time for tools such as pass,
+// which may prompt for the passphrase of the GPG key.
+const fetchTimeout = 30 * time.Second
+
+// provider fetches the value of a secret.
+type provider func(ctx context.Context, secret *types.SecretConfig) (string, error)
+
+var providers = map[string]provider{
+	types.SecretProviderFile:    fetchFile,
+	types.SecretProviderSops:    fetchSops,
+	types.SecretProviderAge:     fetchAge,
+	types.SecretProviderPass:    fetchPass,
+	types.SecretProviderKeyring: fetchKeyring,
+	types.SecretProviderCommand: fetchCommand,
+}
+
+func fetch(ctx context.Context, secret *types.SecretConfig) (string, error) {
+	if err := secret.Validate(); err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+	return providers[secret.Provider](ctx, secret)
+}
+
+// fetchFile reads the whole file, or a single variable of a dotenv file.
+func fetchFile(_ context.Context, secret *types.SecretConfig) (string, error) {
+	if secret.Key != "" {
+		vars, err := godotenv.Read(secret.Path)
+		if err != nil {
+			return "", err
+		}
+		value, ok := vars[secret.Key]
+		if !ok {
+			return "", fmt.Errorf("%s has no variable %s", secret.Path, secret.Key)
+		}
+		return value, nil
+	}
+	data, err := os.ReadFile(secret.Path)
+	if err != nil {
+		return "", err
+	}
+	return trimNewline(string(data)), nil
+}
+
+func fetchSops(ctx context.Context, secret *types.SecretConfig) (string, error) {
+	args := []string{"--decrypt"}
+	if secret.Key != "" {
+		extract := secret.Key
+		if !strings.HasPrefix(extract, "[") {
+			extract = fmt.Sprintf("[%q]", extract)
+		}
+		args = append(args, "--extract", extract)
+	}
+	return runTool(ctx, "sops", append(args, secret.Path)...)
+}
+
+func fetchAge(ctx context.Context, secret *types.SecretConfig) (string, error) {
+	return runTool(ctx, "age", "--decrypt", "--identity", secret.Identity, secret.Path)
+}
+
+// fetchPass returns the first line of a password store entry, which holds the
+// password by convention.
+func fetchPass(ctx context.Context, secret *types.SecretConfig) (string, error) {
+	out, err := runTool(ctx, "pass", "show", secret.Path)
+	if err != nil {
+		return "", err
+	}
+	password, _, _ := strings.Cut(out, "\n")
+	return password, nil
+}
+
+// fetchKeyring reads a generic password from the keychain on macOS, and from
+// the Secret Service (GNOME Keyring, KWallet) elsewhere.
+func fetchKeyring(ctx context.Context, secret *types.SecretConfig) (string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return runTool(ctx, "security", "find-generic-password", "-s", secret.Service, "-a", secret.Account, "-w")
+	case "windows":
+		return "", errors.New("the keyring provider is not supported on windows")
+	}
+	return runTool(ctx, "secret-tool", "lookup", "service", secret.Service, "account", secret.Account)
+}
+
+func fetchCommand(ctx context.Context, secret *types.SecretConfig) (string, error) {
+	out, err := command.BuildCommandContext(ctx, secret.Command).Output()
+	if err != nil {
+		return "", commandError(err)
+	}
+	return trimNewline(string(out)), nil
+}
+
+func runTool(ctx context.Context, name string, args ...string) (string, error) {
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, commandError(err))
+	}
+	return trimNewline(string(out)), nil
+}
+
+// commandError adds the error output of a failed command, which usually
+// explains the failure better than its exit code.
+func commandError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if stderr := bytes.TrimSpace(exitErr.Stderr); len(stderr) > 0 {
+			return fmt.Errorf("%w: %s", err, stderr)
+		}
+	}
+	return err
+}
+
+func trimNewline(s string) string {
+	return strings.TrimRight(s, "\r\n")
+}
@@ -0,0 +1,120 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+// minRedactedLength is the length below which a value is too likely to occur
+// in ordinary log lines to be masked in them.
+const minRedactedLength = 4
+
+// Resolved holds the values of the secrets of a project. The values are only
+// handed to the processes: everything displayed to the user goes through
+// Redact or RedactEnv. A nil Resolved holds no secrets.
+type Resolved struct {
+	values   map[string]string
+	redactor *strings.Replacer
+}
+
+// Resolve fetches the value of every secret. The secrets that could not be
+// fetched are reported in the returned error, the others are resolved.
+func Resolve(ctx context.Context, secrets types.Secrets) (*Resolved, error) {
+	if len(secrets) == 0 {
+		return nil, nil
+	}
+	values := make(map[string]string, len(secrets))
+	var errs []error
+	for _, name := range secrets.Names() {
+		secret := secrets[name]
+		value, err := fetch(ctx, &secret)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to resolve secret %s: %w", name, err))
+			continue
+		}
+		values[name] = value
+	}
+	return newResolved(values), errors.Join(errs...)
+}
+
+func newResolved(values map[string]string) *Resolved {
+	masked := make([]string, 0, len(values))
+	for _, value := range values {
+		if len(value) >= minRedactedLength {
+			masked = append(masked, value)
+		}
+	}
+	// Mask the longest values first, so that a secret containing another one
+	// is not partially revealed.
+	sort.Slice(masked, func(i, j int) bool { return len(masked[i]) > len(masked[j]) })
+	pairs := make([]string, 0, 2*len(masked))
+	for _, value := range masked {
+		pairs = append(pairs, value, types.RedactedValue)
+	}
+	return &Resolved{
+		values:   values,
+		redactor: strings.NewReplacer(pairs...),
+	}
+}
+
+// Names returns the environment variables of the resolved secrets, sorted.
+func (r *Resolved) Names() []string {
+	if r == nil {
+		return nil
+	}
+	names := make([]string, 0, len(r.values))
+	for name := range r.values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Env returns the secrets as NAME=value environment entries.
+func (r *Resolved) Env() []string {
+	names := r.Names()
+	env := make([]string, 0, len(names))
+	for _, name := range names {
+		env = append(env, name+"="+r.values[name])
+	}
+	return env
+}
+
+// RedactedEnv returns the secrets as NAME=****** environment entries.
+func (r *Resolved) RedactedEnv() []string {
+	var env []string
+	for _, name := range r.Names() {
+		env = append(env, name+"="+types.RedactedValue)
+	}
+	return env
+}
+
+// Redact masks the secret values found in s.
+func (r *Resolved) Redact(s string) string {
+	if r == nil {
+		return s
+	}
+	return r.redactor.Replace(s)
+}
+
+// RedactEnv returns a copy of env where the entries set to a secret value are
+// masked.
+func (r *Resolved) RedactEnv(env []string) []string {
+	if r == nil {
+		return env
+	}
+	redacted := make([]string, len(env))
+	for i, entry := range env {
+		name, value, _ := strings.Cut(entry, "=")
+		if secret, ok := r.values[name]; ok && value == secret {
+			entry = name + "=" + types.RedactedValue
+		}
+		redacted[i] = entry
+	}
+	return redacted
+}
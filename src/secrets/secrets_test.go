@@ -0,0 +1,117 @@
+package secrets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("s3cr3t-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	envFile := filepath.Join(dir, "secrets.env")
+	if err := os.WriteFile(envFile, []byte("DB_PASSWORD=hunter22\nOTHER=x\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		secret  types.SecretConfig
+		want    string
+		wantErr bool
+	}{
+		{"file", types.SecretConfig{Provider: types.SecretProviderFile, Path: tokenFile}, "s3cr3t-token", false},
+		{"dotenv key", types.SecretConfig{Provider: types.SecretProviderFile, Path: envFile, Key: "DB_PASSWORD"}, "hunter22", false},
+		{"missing key", types.SecretConfig{Provider: types.SecretProviderFile, Path: envFile, Key: "NOPE"}, "", true},
+		{"missing file", types.SecretConfig{Provider: types.SecretProviderFile, Path: filepath.Join(dir, "nope")}, "", true},
+		{"command", types.SecretConfig{Provider: types.SecretProviderCommand, Command: "echo from-command"}, "from-command", false},
+		{"failing command", types.SecretConfig{Provider: types.SecretProviderCommand, Command: "exit 3"}, "", true},
+		{"invalid", types.SecretConfig{Provider: types.SecretProviderPass}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if runtime.GOOS == "windows" && tt.secret.Provider == types.SecretProviderCommand {
+				t.Skip("uses a POSIX shell")
+			}
+			resolved, err := Resolve(context.Background(), types.Secrets{"SECRET": tt.secret})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var want []string
+			if !tt.wantErr {
+				want = []string{"SECRET=" + tt.want}
+			}
+			if got := resolved.Env(); !slices.Equal(got, want) {
+				t.Errorf("Env() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestResolve_Partial(t *testing.T) {
+	resolved, err := Resolve(context.Background(), types.Secrets{
+		"GOOD": {Provider: types.SecretProviderCommand, Command: "echo good"},
+		"BAD":  {Provider: types.SecretProviderFile, Path: filepath.Join(t.TempDir(), "nope")},
+	})
+	if err == nil {
+		t.Fatal("Resolve() should report the failed secret")
+	}
+	if got := resolved.Names(); !slices.Equal(got, []string{"GOOD"}) {
+		t.Errorf("Names() = %q, want [GOOD]", got)
+	}
+}
+
+func TestResolved_Redact(t *testing.T) {
+	resolved := newResolved(map[string]string{
+		"TOKEN":   "abcd1234",
+		"LONGER":  "abcd1234-suffix",
+		"SHORT":   "ab",
+		"PASSWRD": "hunter22",
+	})
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"token=abcd1234 ok", "token=****** ok"},
+		{"long abcd1234-suffix", "long ******"},
+		{"ab is too short to mask", "ab is too short to mask"},
+		{"hunter22 and abcd1234", "****** and ******"},
+		{"nothing to hide", "nothing to hide"},
+	}
+	for _, tt := range tests {
+		if got := resolved.Redact(tt.line); got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	env := []string{"TOKEN=abcd1234", "TOKEN=overridden", "PATH=/bin", "SHORT=ab"}
+	want := []string{"TOKEN=******", "TOKEN=overridden", "PATH=/bin", "SHORT=******"}
+	if got := resolved.RedactEnv(env); !slices.Equal(got, want) {
+		t.Errorf("RedactEnv() = %q, want %q", got, want)
+	}
+	if got := resolved.RedactedEnv(); !slices.Equal(got, []string{"LONGER=******", "PASSWRD=******", "SHORT=******", "TOKEN=******"}) {
+		t.Errorf("RedactedEnv() = %q", got)
+	}
+}
+
+func TestResolved_Nil(t *testing.T) {
+	var resolved *Resolved
+	if got := resolved.Redact("line"); got != "line" {
+		t.Errorf("Redact() = %q, want line", got)
+	}
+	if got := resolved.Env(); len(got) != 0 {
+		t.Errorf("Env() = %q, want empty", got)
+	}
+	env := []string{"A=1"}
+	if got := resolved.RedactEnv(env); !slices.Equal(got, env) {
+		t.Errorf("RedactEnv() = %q, want %q", got, env)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"maps"
+	"strconv"
 	"text/template"
 
 	"github.com/f1bonacc1/process-compose/src/health"
@@ -30,6 +31,9 @@ func (t *Templater) RenderProcess(proc *types.ProcessConfig) {
 	}
 	proc.OriginalConfig = string(procConf)
 	proc.Vars["PC_REPLICA_NUM"] = proc.ReplicaNum
+	if len(proc.AssignedPorts) > 0 {
+		proc.Vars[types.TemplateVarPorts] = proc.AssignedPorts
+	}
 	if !proc.DisableCommandRendering {
 		proc.Command = t.RenderWithExtraVars(proc.Command, proc.Vars)
 	}
@@ -41,8 +45,56 @@ func (t *Templater) RenderProcess(proc *types.ProcessConfig) {
 		proc.Environment[i] = t.RenderWithExtraVars(envVar, proc.Vars)
 	}
 
+	proc.Container = t.renderContainer(proc.Container, proc)
 	t.renderProbe(proc.ReadinessProbe, proc)
 	t.renderProbe(proc.LivenessProbe, proc)
+	if !proc.DisableCommandRendering {
+		proc.Hooks.PreStart = t.renderHook(proc.Hooks.PreStart, proc)
+		proc.Hooks.PostStart = t.renderHook(proc.Hooks.PostStart, proc)
+		proc.Hooks.PreStop = t.renderHook(proc.Hooks.PreStop, proc)
+		proc.Hooks.PostStop = t.renderHook(proc.Hooks.PostStop, proc)
+	}
+}
+
+// renderHook returns a rendered copy of the hook: the replicas of a process
+// share its hooks.
+func (t *Templater) renderHook(hook *types.HookConfig, procConf *types.ProcessConfig) *types.HookConfig {
+	if hook == nil {
+		return nil
+	}
+	rendered := *hook
+	rendered.Command = t.RenderWithExtraVars(hook.Command, procConf.Vars)
+	return &rendered
+}
+
+// renderContainer returns a rendered copy of the container: the replicas of a
+// process share it.
+func (t *Templater) renderContainer(conf *types.ContainerConfig, procConf *types.ProcessConfig) *types.ContainerConfig {
+	if conf == nil {
+		return nil
+	}
+	rendered := *conf
+	rendered.Image = t.RenderWithExtraVars(conf.Image, procConf.Vars)
+	rendered.Name = t.RenderWithExtraVars(conf.Name, procConf.Vars)
+	rendered.Ports = t.renderList(conf.Ports, procConf)
+	rendered.Volumes = t.renderList(conf.Volumes, procConf)
+	rendered.Environment = t.renderList(conf.Environment, procConf)
+	if !procConf.DisableCommandRendering {
+		rendered.Command = t.renderList(conf.Command, procConf)
+		rendered.Entrypoint = t.renderList(conf.Entrypoint, procConf)
+	}
+	return &rendered
+}
+
+func (t *Templater) renderList(list []string, procConf *types.ProcessConfig) []string {
+	if list == nil {
+		return nil
+	}
+	rendered := make([]string, len(list))
+	for i, item := range list {
+		rendered[i] = t.RenderWithExtraVars(item, procConf.Vars)
+	}
+	return rendered
 }
 
 func (t *Templater) renderProbe(probe *health.Probe, procConf *types.ProcessConfig) {
@@ -56,11 +108,27 @@ func (t *Templater) renderProbe(probe *health.Probe, procConf *types.ProcessConf
 		probe.HttpGet.Path = t.RenderWithExtraVars(probe.HttpGet.Path, procConf.Vars)
 		probe.HttpGet.Host = t.RenderWithExtraVars(probe.HttpGet.Host, procConf.Vars)
 		probe.HttpGet.Scheme = t.RenderWithExtraVars(probe.HttpGet.Scheme, procConf.Vars)
-		probe.HttpGet.Port = t.RenderWithExtraVars(probe.HttpGet.Port, procConf.Vars)
+		probe.HttpGet.Port = namedPort(t.RenderWithExtraVars(probe.HttpGet.Port, procConf.Vars), procConf)
+	} else if probe.TcpSocket != nil {
+		probe.TcpSocket.Host = t.RenderWithExtraVars(probe.TcpSocket.Host, procConf.Vars)
+		probe.TcpSocket.Port = namedPort(t.RenderWithExtraVars(probe.TcpSocket.Port, procConf.Vars), procConf)
+	} else if probe.Grpc != nil {
+		probe.Grpc.Host = t.RenderWithExtraVars(probe.Grpc.Host, procConf.Vars)
+		probe.Grpc.Port = namedPort(t.RenderWithExtraVars(probe.Grpc.Port, procConf.Vars), procConf)
+		probe.Grpc.Service = t.RenderWithExtraVars(probe.Grpc.Service, procConf.Vars)
 	}
 	probe.ValidateAndSetDefaults()
 }
 
+// namedPort resolves a probe port that refers to one of the ports of the
+// process by name.
+func namedPort(port string, procConf *types.ProcessConfig) string {
+	if assigned, ok := procConf.AssignedPorts[port]; ok {
+		return strconv.Itoa(assigned)
+	}
+	return port
+}
+
 func (t *Templater) Render(str string) string {
 	return t.render(str, nil)
 }
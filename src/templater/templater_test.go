@@ -134,3 +134,23 @@ func TestTemplater_DisableCommandRendering(t *testing.T) {
 		}
 	})
 }
+
+func TestTemplater_RenderHooks(t *testing.T) {
+	hook := &types.HookConfig{Command: "migrate --replica {{.PC_REPLICA_NUM}}"}
+	for i := range 2 {
+		procConf := &types.ProcessConfig{
+			ReplicaNum: i,
+			Hooks:      types.ProcessHooks{PreStart: hook},
+		}
+		templater := New(types.Vars{})
+		templater.RenderProcess(procConf)
+
+		expected := fmt.Sprintf("migrate --replica %d", i)
+		if procConf.Hooks.PreStart.Command != expected {
+			t.Errorf("Expected hook command %q but got %q", expected, procConf.Hooks.PreStart.Command)
+		}
+	}
+	if hook.Command != "migrate --replica {{.PC_REPLICA_NUM}}" {
+		t.Errorf("The shared hook was rendered in place: %q", hook.Command)
+	}
+}
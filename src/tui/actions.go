@@ -23,6 +23,8 @@ const (
 	ActionProcessStart     = ActionName("process_start")
 	ActionProcessScale     = ActionName("process_scale")
 	ActionProcessInfo      = ActionName("process_info")
+	ActionProcessResources = ActionName("process_resources")
+	ActionProcessTree      = ActionName("process_tree")
 	ActionProcessSignal    = ActionName("process_signal")
 	ActionProcessStop      = ActionName("process_stop")
 	ActionProcessRestart   = ActionName("process_restart")
@@ -32,6 +34,7 @@ const (
 	ActionLogFindNext      = ActionName("find_next")
 	ActionLogFindPrev      = ActionName("find_prev")
 	ActionLogFindExit      = ActionName("find_exit")
+	ActionLogFilter        = ActionName("log_filter")
 	ActionNsFilter         = ActionName("ns_filter")
 	ActionHideDisabled     = ActionName("hide_disabled")
 	ActionProcFilter       = ActionName("proc_filter")
@@ -48,6 +51,8 @@ const (
 	ActionDependencyGraph  = ActionName("dependency_graph")
 	ActionNamespaceOps     = ActionName("namespace_ops")
 	ActionCommandPalette   = ActionName("command_palette")
+	ActionLogPin           = ActionName("log_pin")
+	ActionLogPanes         = ActionName("log_panes")
 )
 
 var defaultShortcuts = map[ActionName]tcell.Key{
@@ -58,6 +63,8 @@ var defaultShortcuts = map[ActionName]tcell.Key{
 	ActionLogSelection:     tcell.KeyCtrlS,
 	ActionProcessScale:     tcell.KeyF2,
 	ActionProcessInfo:      tcell.KeyF3,
+	ActionProcessResources: tcell.KeyCtrlU,
+	ActionProcessTree:      tcell.KeyRune,
 	ActionProcessSignal:    tcell.KeyCtrlX,
 	ActionProcessStart:     tcell.KeyF7,
 	ActionProcessStop:      tcell.KeyF9,
@@ -68,6 +75,7 @@ var defaultShortcuts = map[ActionName]tcell.Key{
 	ActionLogFindNext:      tcell.KeyCtrlN,
 	ActionLogFindPrev:      tcell.KeyCtrlP,
 	ActionLogFindExit:      tcell.KeyEsc,
+	ActionLogFilter:        tcell.KeyRune,
 	ActionNsFilter:         tcell.KeyCtrlG,
 	ActionHideDisabled:     tcell.KeyCtrlD,
 	ActionProcFilter:       tcell.KeyRune,
@@ -84,14 +92,18 @@ var defaultShortcuts = map[ActionName]tcell.Key{
 	ActionLogPrettyPrint:   tcell.KeyRune,
 	ActionNamespaceOps:     tcell.KeyRune,
 	ActionCommandPalette:   tcell.KeyRune,
+	ActionLogPin:           tcell.KeyCtrlO,
+	ActionLogPanes:         tcell.KeyCtrlW,
 }
 
 var defaultShortcutsRunes = map[ActionName]rune{
 	ActionProcFilter:     '/',
 	ActionMarkLog:        'm',
 	ActionLogPrettyPrint: 'p',
+	ActionLogFilter:      'f',
 	ActionNamespaceOps:   'n',
 	ActionCommandPalette: ':',
+	ActionProcessTree:    't',
 }
 
 var generalActionsOrder = []ActionName{
@@ -109,14 +121,19 @@ var logActionsOrder = []ActionName{
 	ActionLogPrettyPrint,
 	ActionLogSelection,
 	ActionLogFind,
+	ActionLogFilter,
 	ActionClearLog,
 	ActionMarkLog,
+	ActionLogPin,
+	ActionLogPanes,
 }
 
 var procActionsOrder = []ActionName{
 	ActionProcFilter,
 	ActionProcessScale,
 	ActionProcessInfo,
+	ActionProcessResources,
+	ActionProcessTree,
 	ActionProcessSignal,
 	ActionProcessStart,
 	ActionProcessScreen,
@@ -262,6 +279,19 @@ func (s *ShortCuts) runKeyAction(key tcell.Key, event *tcell.EventKey) *tcell.Ev
 	return event
 }
 
+// isActionKey reports whether the event is the shortcut of the action, for
+// dialogs running actions on their own selection.
+func (s *ShortCuts) isActionKey(actName ActionName, event *tcell.EventKey) bool {
+	act, found := s.ShortCutKeys[actName]
+	if !found {
+		return false
+	}
+	if act.rune != 0 {
+		return event.Key() == tcell.KeyRune && event.Rune() == act.rune
+	}
+	return event.Key() == act.key
+}
+
 func parseShortCuts(sc *ShortCuts) {
 	for actionName, action := range sc.ShortCutKeys {
 		if len(action.ShortCut) == 1 {
@@ -360,6 +390,12 @@ func newShortCuts() *ShortCuts {
 			ActionProcessInfo: {
 				Description: "Info",
 			},
+			ActionProcessResources: {
+				Description: "Resource History",
+			},
+			ActionProcessTree: {
+				Description: "Process Tree",
+			},
 			ActionProcessSignal: {
 				Description: "Send Signal",
 			},
@@ -393,6 +429,9 @@ func newShortCuts() *ShortCuts {
 			ActionLogFindExit: {
 				Description: "Exit Search",
 			},
+			ActionLogFilter: {
+				Description: "Filter Log",
+			},
 			ActionNsFilter: {
 				Description: "Select Namespace",
 			},
@@ -444,6 +483,15 @@ func newShortCuts() *ShortCuts {
 			ActionCommandPalette: {
 				Description: "Command Palette",
 			},
+			ActionLogPin: {
+				Description: "Pin/Unpin Process Log",
+			},
+			ActionLogPanes: {
+				ToggleDescription: map[bool]string{
+					true:  "Tile Logs",
+					false: "Single Log",
+				},
+			},
 		},
 	}
 	if len(availableSignalOptions()) == 0 {
@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/f1bonacc1/process-compose/src/notify"
+	"github.com/gdamore/tcell/v2"
+)
+
+// sendDesktopNotification notifies the desktop of an event of a monitored
+// process, so that it is noticed while the terminal is in the background.
+func (pv *pcView) sendDesktopNotification(ev monitorEvent) {
+	var key, body string
+	switch ev.kind {
+	case monitorEventActivity:
+		key, body = "activity", "produced new output"
+	case monitorEventSilence:
+		key, body = "silence", "went silent"
+	case monitorEventFailed:
+		key, body = "failed", fmt.Sprintf("failed with exit code %d", ev.state.ExitCode)
+	case monitorEventReady:
+		key, body = "ready", "is ready"
+	default:
+		return
+	}
+	pv.notifier.Notify(ev.name+":"+key, "process-compose: "+ev.name, body)
+}
+
+// screenWriter queues the terminal notifications until the next draw, which
+// writes them to the screen. Writing them to stdout instead would interleave
+// them with the output of a draw in progress.
+type screenWriter struct {
+	mtx     sync.Mutex
+	pending []string
+}
+
+func (w *screenWriter) Write(p []byte) (int, error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.pending = append(w.pending, string(p))
+	return len(p), nil
+}
+
+// flush writes the queued notifications to the screen. It is called after each
+// draw, on the UI goroutine.
+func (w *screenWriter) flush(screen tcell.Screen) {
+	w.mtx.Lock()
+	pending := w.pending
+	w.pending = nil
+	w.mtx.Unlock()
+	for _, seq := range pending {
+		if seq == notify.Bell {
+			_ = screen.Beep()
+			continue
+		}
+		if tty, ok := screen.Tty(); ok {
+			_, _ = tty.Write([]byte(seq))
+		}
+	}
+}
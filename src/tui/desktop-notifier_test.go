@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/notify"
+	"github.com/gdamore/tcell/v2"
+)
+
+type fakeTty struct {
+	tcell.Tty
+	out strings.Builder
+}
+
+func (t *fakeTty) Write(p []byte) (int, error) {
+	return t.out.Write(p)
+}
+
+type fakeScreen struct {
+	tcell.Screen
+	tty   *fakeTty
+	beeps int
+}
+
+func (s *fakeScreen) Beep() error {
+	s.beeps++
+	return nil
+}
+
+func (s *fakeScreen) Tty() (tcell.Tty, bool) {
+	return s.tty, true
+}
+
+func TestScreenWriter(t *testing.T) {
+	w := &screenWriter{}
+	n := notify.New(notify.MethodOSC9, 0, w)
+	n.Notify("web:ready", "pc: web", "is ready")
+
+	screen := &fakeScreen{tty: &fakeTty{}}
+	if screen.tty.out.Len() != 0 {
+		t.Fatal("the notification was written before the draw")
+	}
+	w.flush(screen)
+	if got, want := screen.tty.out.String(), "\033]9;pc: web: is ready\007"; got != want {
+		t.Errorf("screen got %q, want %q", got, want)
+	}
+
+	_, _ = w.Write([]byte(notify.Bell))
+	w.flush(screen)
+	w.flush(screen)
+	if screen.beeps != 1 || strings.Contains(screen.tty.out.String(), notify.Bell+notify.Bell) {
+		t.Errorf("beeps = %d, tty = %q, want one beep through the screen", screen.beeps, screen.tty.out.String())
+	}
+}
@@ -1,52 +1,88 @@
 package tui
 
 import (
+	"context"
 	"fmt"
-
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/f1bonacc1/process-compose/src/types"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
+// graphHealthWidth leaves room for the health of a process, which is only
+// known once its readiness probe has run.
+const graphHealthWidth = len(", Not Ready")
+
+// graphProcessActions are the process actions available from the graph, with
+// the same shortcuts as in the process table.
+var graphProcessActions = []ActionName{ActionProcessStart, ActionProcessStop, ActionProcessRestart}
+
 type graphDialog struct {
 	*tview.TreeView
 	root     *tview.TreeNode
 	closeFn  func()
 	maxWidth int
+	states   map[string]types.ProcessState
+}
+
+// graphNodeRef is the reference of a tree node: the process it shows and the
+// condition of the dependency leading to it, if any.
+type graphNodeRef struct {
+	name      string
+	condition string
 }
 
-// newGraphDialog creates a dependency graph dialog using tview.TreeView
-func newGraphDialog(closeFn func()) *graphDialog {
+// newGraphDialog creates a dependency graph dialog using tview.TreeView. The
+// logs function is called with the selected process to show its logs, and run
+// with the process action whose shortcut was pressed.
+func newGraphDialog(shortcuts *ShortCuts, logs func(name string), run func(action ActionName, name string), closeFn func()) *graphDialog {
 	root := tview.NewTreeNode("Root").SetSelectable(false)
 	tree := tview.NewTreeView().
 		SetRoot(root).
 		SetTopLevel(1)
-	tree.SetBorder(true).SetTitle("Dependencies (Esc to close)")
+	hints := []string{"Enter Expand", "l Logs"}
+	for _, action := range graphProcessActions {
+		hints = append(hints, shortcuts.ShortCutKeys[action].ShortCut+" "+shortcuts.ShortCutKeys[action].Description)
+	}
+	title := "Dependencies (" + strings.Join(hints, ", ") + ", Esc to close)"
+	tree.SetBorder(true).SetTitle(title)
+	g := &graphDialog{TreeView: tree, root: root, closeFn: closeFn, maxWidth: len(title) - graphHealthWidth - 4, states: map[string]types.ProcessState{}}
 
 	// Handle node selection to toggle expansion
 	tree.SetSelectedFunc(func(node *tview.TreeNode) {
-		node.SetExpanded(!node.IsExpanded())
-		text := node.GetText()
-		if strings.HasPrefix(text, "▾ ") {
-			node.SetText(strings.Replace(text, "▾ ", "▸ ", 1))
-		} else if strings.HasPrefix(text, "▸ ") {
-			node.SetText(strings.Replace(text, "▸ ", "▾ ", 1))
+		if len(node.GetChildren()) == 0 {
+			return
 		}
+		node.SetExpanded(!node.IsExpanded())
+		g.setNodeText(node)
 	})
 
-	// Handle input to close dialog
 	tree.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		if event.Key() == tcell.KeyEsc {
 			closeFn()
 			return nil
 		}
+		name := g.selectedProcess()
+		if name == "" {
+			return event
+		}
+		if event.Key() == tcell.KeyRune && event.Rune() == 'l' {
+			logs(name)
+			return nil
+		}
+		for _, action := range graphProcessActions {
+			if shortcuts.isActionKey(action, event) {
+				run(action, name)
+				return nil
+			}
+		}
 		return event
 	})
 
-	return &graphDialog{TreeView: tree, root: root, closeFn: closeFn}
+	return g
 }
 
 // buildTree populates the tree from dependency graph with status colors, traversing Leaves -> Dependencies
@@ -54,9 +90,8 @@ func (g *graphDialog) buildTree(graph *types.DependencyGraph, states *types.Proc
 	g.root.ClearChildren()
 
 	// Index states for faster lookup
-	statesMap := make(map[string]types.ProcessState)
 	for _, s := range states.States {
-		statesMap[s.Name] = s
+		g.states[s.Name] = s
 	}
 
 	// Add leaf processes (top-level apps/processes that others don't depend on)
@@ -67,8 +102,8 @@ func (g *graphDialog) buildTree(graph *types.DependencyGraph, states *types.Proc
 	sort.Strings(leafNames)
 
 	for _, leafName := range leafNames {
-		node := g.createProcessNode(leafName, statesMap, "")
-		g.addDependencies(node, leafName, graph, statesMap, 0)
+		node := g.createProcessNode(leafName, "")
+		g.addDependencies(node, leafName, graph, 0)
 		g.root.AddChild(node)
 	}
 
@@ -82,43 +117,79 @@ func (g *graphDialog) buildTree(graph *types.DependencyGraph, states *types.Proc
 // GetWidth returns the calculated maximum width of the tree content plus some padding
 func (g *graphDialog) GetWidth() int {
 	// Add padding for borders and icons
-	return g.maxWidth + 8
+	return g.maxWidth + graphHealthWidth + 8
 }
 
-// createProcessNode creates a tree node with status-colored text
-func (g *graphDialog) createProcessNode(name string, states map[string]types.ProcessState, condition string) *tview.TreeNode {
-	node := tview.NewTreeNode(name)
-	text := name
-	if state, ok := states[name]; ok {
-		node.SetColor(statusColor(state.Status))
-		text = fmt.Sprintf("%s [%s]", name, state.Status)
+// updateStates recolors the nodes of the processes whose state changed. A
+// process depended on by several others is shown, and updated, once per
+// dependent.
+func (g *graphDialog) updateStates(states map[string]types.ProcessState) {
+	for name, state := range states {
+		g.states[name] = state
 	}
-	if condition != "" {
-		text += fmt.Sprintf(" <%s>", condition)
+	g.root.Walk(func(node, _ *tview.TreeNode) bool {
+		if ref, ok := node.GetReference().(graphNodeRef); ok {
+			if _, changed := states[ref.name]; changed {
+				g.setNodeText(node)
+			}
+		}
+		return true
+	})
+}
+
+func (g *graphDialog) selectedProcess() string {
+	if node := g.GetCurrentNode(); node != nil {
+		if ref, ok := node.GetReference().(graphNodeRef); ok {
+			return ref.name
+		}
 	}
-	node.SetText(tview.Escape(text))
-	node.SetReference(name)
-	node.SetExpanded(true)
+	return ""
+}
+
+// createProcessNode creates a tree node with status-colored text
+func (g *graphDialog) createProcessNode(name string, condition string) *tview.TreeNode {
+	node := tview.NewTreeNode("").
+		SetReference(graphNodeRef{name: name, condition: condition}).
+		SetExpanded(true)
+	g.setNodeText(node)
 
 	// Base maxWidth on text length. Indentation is added in addDependencies.
-	if len(text) > g.maxWidth {
-		g.maxWidth = len(text)
+	if len(node.GetText()) > g.maxWidth {
+		g.maxWidth = len(node.GetText())
 	}
 
 	return node
 }
 
+// setNodeText sets the text and the color of the node from the state of its
+// process.
+func (g *graphDialog) setNodeText(node *tview.TreeNode) {
+	ref, ok := node.GetReference().(graphNodeRef)
+	if !ok {
+		return
+	}
+	text := ref.name
+	if state, ok := g.states[ref.name]; ok {
+		node.SetColor(graphNodeColor(state))
+		status := state.Status
+		if state.HasHealthProbe && state.Health != types.ProcessHealthUnknown {
+			status += ", " + state.Health
+		}
+		text = fmt.Sprintf("%s [%s]", ref.name, status)
+	}
+	if ref.condition != "" {
+		text += fmt.Sprintf(" <%s>", ref.condition)
+	}
+	node.SetText(treeNodeText(len(node.GetChildren()) > 0, node.IsExpanded(), tview.Escape(text)))
+}
+
 // addDependencies recursively adds dependencies to the tree
-func (g *graphDialog) addDependencies(parentNode *tview.TreeNode, parentName string, graph *types.DependencyGraph, states map[string]types.ProcessState, level int) {
+func (g *graphDialog) addDependencies(parentNode *tview.TreeNode, parentName string, graph *types.DependencyGraph, level int) {
 	node, exists := graph.AllNodes[parentName]
 	if !exists {
 		return
 	}
 
-	if len(node.DependsOn) > 0 {
-		parentNode.SetText("▾ " + parentNode.GetText())
-	}
-
 	var depNames []string
 	for name := range node.DependsOn {
 		depNames = append(depNames, name)
@@ -128,7 +199,7 @@ func (g *graphDialog) addDependencies(parentNode *tview.TreeNode, parentName str
 	for _, depName := range depNames {
 		link := node.DependsOn[depName]
 		condition := link.Type
-		childNode := g.createProcessNode(depName, states, condition)
+		childNode := g.createProcessNode(depName, condition)
 
 		// Update maxWidth with indentation (tview default is 2 characters per level for TreeView)
 		indent := (level + 1) * 2
@@ -136,23 +207,172 @@ func (g *graphDialog) addDependencies(parentNode *tview.TreeNode, parentName str
 			g.maxWidth = len(childNode.GetText()) + indent
 		}
 
-		g.addDependencies(childNode, depName, graph, states, level+1)
+		g.addDependencies(childNode, depName, graph, level+1)
 		parentNode.AddChild(childNode)
 	}
+	g.setNodeText(parentNode)
+}
+
+// graphNodeColor returns the color of a process from its status, or from its
+// health while it runs without being ready.
+func graphNodeColor(state types.ProcessState) tcell.Color {
+	if state.IsRunning && state.HasHealthProbe && state.Health == types.ProcessHealthNotReady {
+		return tcell.ColorOrange
+	}
+	return statusColor(state.Status)
 }
 
 // statusColor returns tcell.Color based on process status
 func statusColor(status string) tcell.Color {
 	switch status {
-	case "Running":
+	case types.ProcessStateRunning:
 		return tcell.ColorGreen
-	case "Completed":
+	case types.ProcessStateCompleted:
 		return tcell.ColorBlue
-	case "Error", "Failed":
+	case types.ProcessStateError, types.ProcessStateOOMKilled, types.ProcessStateCrashLoopBackOff:
 		return tcell.ColorRed
-	case "Pending", "Launching":
+	case types.ProcessStatePending, types.ProcessStateLaunching, types.ProcessStateRestarting:
 		return tcell.ColorYellow
 	default:
 		return tcell.ColorWhite
 	}
 }
+
+// graphObserver collects the state changes shown by the graph dialog. Notify
+// is called under the lock of the broadcaster, so it only keeps the latest
+// state of each process and wakes the update loop up.
+type graphObserver struct {
+	mtx     sync.Mutex
+	pending map[string]types.ProcessState
+	wake    chan struct{}
+}
+
+func newGraphObserver() *graphObserver {
+	return &graphObserver{
+		pending: map[string]types.ProcessState{},
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Notify implements types.StateObserver.
+func (o *graphObserver) Notify(ev types.ProcessStateEvent) {
+	o.mtx.Lock()
+	o.pending[ev.State.Name] = ev.State
+	o.mtx.Unlock()
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// UniqueID implements types.StateObserver.
+func (o *graphObserver) UniqueID() string {
+	return "tui-graph"
+}
+
+func (o *graphObserver) take() map[string]types.ProcessState {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	states := o.pending
+	o.pending = map[string]types.ProcessState{}
+	return states
+}
+
+func (pv *pcView) showGraphDialog() {
+	graph, err := pv.project.GetDependencyGraph()
+	if err != nil {
+		pv.showError(fmt.Sprintf("Failed to get dependency graph: %v", err))
+		return
+	}
+
+	states, err := pv.project.GetProcessesState()
+	if err != nil {
+		pv.showError(fmt.Sprintf("Failed to get process states: %v", err))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	observer := newGraphObserver()
+	closeDialog := func() {
+		cancel()
+		pv.project.UnregisterStateObserver(observer)
+		pv.pages.RemovePage(PageDialog)
+	}
+	logs := func(name string) {
+		closeDialog()
+		pv.selectTableProcess(name)
+		if pv.isInteractive(name) {
+			pv.appView.SetFocus(pv.termView)
+		} else {
+			pv.appView.SetFocus(pv.logsText)
+		}
+	}
+	dialog := newGraphDialog(pv.shortcuts, logs, func(action ActionName, name string) {
+		pv.runGraphAction(action, name, closeDialog)
+	}, func() {
+		closeDialog()
+		pv.appView.SetFocus(pv.procTable)
+	})
+	dialog.buildTree(graph, states)
+
+	width := min(dialog.GetWidth(), 120)
+	pv.showDialog(dialog.TreeView, width, 30)
+
+	go pv.updateGraphDialog(ctx, dialog, observer)
+	pv.project.RegisterStateObserver(observer)
+}
+
+// updateGraphDialog applies the state changes to the dialog until it is
+// closed.
+func (pv *pcView) updateGraphDialog(ctx context.Context, dialog *graphDialog, observer *graphObserver) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-observer.wake:
+			states := observer.take()
+			pv.appView.QueueUpdateDraw(func() {
+				dialog.updateStates(states)
+			})
+		}
+	}
+}
+
+// runGraphAction runs a process action on a process of the graph. The dialog
+// is closed before showing an error, or running a foreground process which
+// takes the whole screen.
+func (pv *pcView) runGraphAction(action ActionName, name string, closeDialog func()) {
+	switch action {
+	case ActionProcessStart:
+		info, err := pv.project.GetProcessInfo(name)
+		if err != nil {
+			closeDialog()
+			pv.showError(err.Error())
+			return
+		}
+		if info.IsForeground {
+			closeDialog()
+			pv.runForeground(info)
+			return
+		}
+		go func() {
+			if err := pv.project.StartProcess(name); err != nil {
+				pv.appView.QueueUpdateDraw(func() {
+					closeDialog()
+					pv.showError(err.Error())
+				})
+			}
+		}()
+	case ActionProcessStop:
+		go pv.handleProcessStopped(name)
+	case ActionProcessRestart:
+		go func() {
+			if err := pv.project.RestartProcess(name); err != nil {
+				pv.appView.QueueUpdateDraw(func() {
+					closeDialog()
+					pv.showError(err.Error())
+				})
+			}
+		}()
+	}
+}
@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestGraphDialogUpdateStates(t *testing.T) {
+	graph := types.BuildDependencyGraph(types.Processes{
+		"db":  {Name: "db", ReplicaName: "db"},
+		"api": {Name: "api", ReplicaName: "api", DependsOn: types.DependsOnConfig{"db": {Condition: types.ProcessConditionHealthy}}},
+		"web": {Name: "web", ReplicaName: "web", DependsOn: types.DependsOnConfig{"db": {Condition: types.ProcessConditionStarted}}},
+	})
+	states := &types.ProcessesState{States: []types.ProcessState{
+		{Name: "api", Status: types.ProcessStatePending},
+		{Name: "db", Status: types.ProcessStatePending},
+		{Name: "web", Status: types.ProcessStatePending},
+	}}
+	d := newGraphDialog(newShortCuts(), func(string) {}, func(ActionName, string) {}, func() {})
+	d.buildTree(graph, states)
+
+	observer := newGraphObserver()
+	observer.Notify(types.ProcessStateEvent{State: types.ProcessState{Name: "db", Status: types.ProcessStatePending}})
+	observer.Notify(types.ProcessStateEvent{State: types.ProcessState{
+		Name: "db", Status: types.ProcessStateRunning, IsRunning: true, HasHealthProbe: true, Health: types.ProcessHealthNotReady,
+	}})
+	d.updateStates(observer.take())
+	if len(observer.take()) != 0 {
+		t.Error("expected the pending states to be taken")
+	}
+
+	var texts []string
+	d.GetRoot().Walk(func(node, _ *tview.TreeNode) bool {
+		if ref, ok := node.GetReference().(graphNodeRef); ok && ref.name == "db" {
+			texts = append(texts, node.GetText())
+			if node.GetColor() != tcell.ColorOrange {
+				t.Errorf("expected a running but not ready process to be orange, got %v", node.GetColor())
+			}
+		}
+		return true
+	})
+	want := []string{"  " + tview.Escape("db [Running, Not Ready] <healthy>"), "  " + tview.Escape("db [Running, Not Ready] <started>")}
+	if len(texts) != len(want) {
+		t.Fatalf("expected db to be shown %d times, got %v", len(want), texts)
+	}
+	for i := range want {
+		if texts[i] != want[i] {
+			t.Errorf("expected %q, got %q", want[i], texts[i])
+		}
+	}
+}
+
+func TestGraphDialogKeys(t *testing.T) {
+	graph := types.BuildDependencyGraph(types.Processes{
+		"db":  {Name: "db", ReplicaName: "db"},
+		"api": {Name: "api", ReplicaName: "api", DependsOn: types.DependsOnConfig{"db": {}}},
+	})
+	tests := []struct {
+		name       string
+		event      *tcell.EventKey
+		wantLogs   string
+		wantAction ActionName
+	}{
+		{
+			name:     "logs",
+			event:    tcell.NewEventKey(tcell.KeyRune, 'l', tcell.ModNone),
+			wantLogs: "api",
+		},
+		{
+			name:       "restart",
+			event:      tcell.NewEventKey(tcell.KeyCtrlR, 0, tcell.ModCtrl),
+			wantAction: ActionProcessRestart,
+		},
+		{
+			name:       "stop",
+			event:      tcell.NewEventKey(tcell.KeyF9, 0, tcell.ModNone),
+			wantAction: ActionProcessStop,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs string
+			var action ActionName
+			d := newGraphDialog(newShortCuts(), func(name string) {
+				logs = name
+			}, func(act ActionName, name string) {
+				if name != "api" {
+					t.Errorf("expected the action to run on api, got %s", name)
+				}
+				action = act
+			}, func() {})
+			d.buildTree(graph, &types.ProcessesState{})
+			if d.GetInputCapture()(tt.event) != nil {
+				t.Error("expected the event to be handled")
+			}
+			if logs != tt.wantLogs || action != tt.wantAction {
+				t.Errorf("expected logs %q and action %q, got %q and %q", tt.wantLogs, tt.wantAction, logs, action)
+			}
+		})
+	}
+}
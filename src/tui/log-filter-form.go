@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func (pv *pcView) showLogFilter() {
+	const fieldWidth = 50
+	f := tview.NewForm()
+	f.SetCancelFunc(func() {
+		pv.pages.RemovePage(PageDialog)
+	})
+	f.SetItemPadding(1)
+	f.SetBorder(true)
+	f.SetButtonsAlign(tview.AlignCenter)
+	f.SetTitle("Filter Log")
+	f.AddInputField("Filter", pv.logsText.getLogFilter().String(), fieldWidth, nil, nil)
+	f.AddTextView("", "e.g. level>=warn request_id=abc, empty to clear", fieldWidth, 1, true, false)
+	filterFunc := func() {
+		expr := f.GetFormItem(0).(*tview.InputField).GetText()
+		if err := pv.setLogFilter(expr); err != nil {
+			f.SetTitle(err.Error())
+			return
+		}
+		pv.pages.RemovePage(PageDialog)
+		pv.updateHelpTextView()
+	}
+	f.AddButton("Filter", filterFunc)
+	f.AddButton("Cancel", func() {
+		pv.pages.RemovePage(PageDialog)
+	})
+	f.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter:
+			filterFunc()
+		case tcell.KeyEsc:
+			pv.pages.RemovePage(PageDialog)
+		default:
+			return event
+		}
+		return nil
+	})
+	f.SetFocus(0)
+	pv.styleForm(f)
+	// Display and focus the dialog
+	pv.pages.AddPage(PageDialog, createDialogPage(f, fieldWidth+20, 11), true, true)
+	pv.appView.SetFocus(f)
+}
@@ -6,7 +6,9 @@ import (
 	"time"
 
 	"github.com/f1bonacc1/glippy"
+	"github.com/f1bonacc1/process-compose/src/pclog"
 	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
 	"github.com/rs/zerolog/log"
 )
 
@@ -63,6 +65,11 @@ func (pv *pcView) followLog(name string) {
 		return
 	}
 	pv.logsText.useAnsi = !config.DisableAnsiColors
+	parser, err := pclog.NewLogParser(config.LogFormat, config.LogRegex)
+	if err != nil {
+		log.Err(err).Msgf("invalid log format of process %s", name)
+	}
+	pv.logsText.setLogParser(parser)
 	if err = pv.project.GetLogsAndSubscribe(name, pv.logsText); err != nil {
 		pv.attentionMessage(fmt.Sprintf("Couldn't subscribe to the process logs: %s", err.Error()), 5*time.Second, true)
 		return
@@ -120,13 +127,39 @@ func (pv *pcView) createLogSelectionTextArea() {
 }
 
 func (pv *pcView) getLogTitle(name string) string {
-	if pv.logsText.isSearchActive() {
-		return fmt.Sprintf("Find: %s [%d of %d] - %s", pv.logsText.getSearchTerm(), pv.logsText.getCurrentSearchIndex()+1, pv.logsText.getTotalSearchCount(), name)
+	return logTitle(pv.logsText, name)
+}
+
+func logTitle(l *LogView, name string) string {
+	if filter := l.getLogFilter(); filter != nil {
+		name = fmt.Sprintf("%s [Filter: %s]", name, tview.Escape(filter.String()))
+	}
+	if l.isSearchActive() {
+		return fmt.Sprintf("Find: %s [%d of %d] - %s", l.getSearchTerm(), l.getCurrentSearchIndex()+1, l.getTotalSearchCount(), name)
 	} else {
 		return name
 	}
 }
 
+// setLogFilter applies a log filter to the logs of the selected process, and
+// reloads them.
+func (pv *pcView) setLogFilter(expr string) error {
+	filter, err := pclog.ParseLogFilter(expr)
+	if err != nil {
+		return err
+	}
+	name := pv.getSelectedProcName()
+	pv.exitSearch()
+	pv.unFollowLog()
+	pv.logsText.setLogFilter(filter)
+	pv.followLog(name)
+	if !pv.logFollow {
+		pv.unFollowLog()
+	}
+	pv.logsText.SetTitle(pv.getLogTitle(name))
+	return nil
+}
+
 func (pv *pcView) truncateLog() {
 	name := pv.getSelectedProcName()
 	err := pv.project.TruncateProcessLogs(name)
@@ -0,0 +1,313 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/app"
+	"github.com/f1bonacc1/process-compose/src/pclog"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	minLogPanes = 2
+	maxLogPanes = 6
+)
+
+// logPane shows the logs of a pinned process in the tiled layout. Each pane
+// follows and searches its logs independently of the others.
+type logPane struct {
+	name     string
+	project  app.IProject
+	logView  *LogView
+	termView *TerminalView
+	follow   bool
+}
+
+// logPaneCell is the position of a pane in the tiled layout.
+type logPaneCell struct {
+	row, col, colSpan int
+}
+
+func (p *logPane) primitive() tview.Primitive {
+	if p.termView != nil {
+		return p.termView
+	}
+	return p.logView
+}
+
+func (p *logPane) startFollow() {
+	if p.logView == nil {
+		return
+	}
+	p.logView.resetSearch()
+	p.follow = true
+	p.logView.Clear()
+	if err := p.project.GetLogsAndSubscribe(p.name, p.logView); err != nil {
+		log.Err(err).Msgf("failed to follow the logs of %s", p.name)
+		return
+	}
+	p.logView.ScrollToEnd()
+	p.logView.SetTitle(p.title())
+}
+
+func (p *logPane) stopFollow() {
+	if p.logView == nil || !p.follow {
+		return
+	}
+	p.follow = false
+	if err := p.project.UnSubscribeLogger(p.name, p.logView); err != nil {
+		log.Err(err).Msg("failed to unfollow log")
+	}
+	p.logView.Flush()
+	p.logView.SetTitle(p.title())
+}
+
+func (p *logPane) toggleFollow() {
+	if p.follow {
+		p.stopFollow()
+	} else {
+		p.startFollow()
+	}
+}
+
+func (p *logPane) close() {
+	p.stopFollow()
+	if p.termView != nil {
+		p.termView.Stop()
+	}
+}
+
+// syncPty attaches the terminal of the pane to the PTY of the process, which
+// changes when the process is started or restarted.
+func (p *logPane) syncPty() {
+	if p.termView == nil {
+		return
+	}
+	ptyFile := p.project.GetProcessPty(p.name)
+	if ptyFile != nil && ptyFile != p.termView.getPty() {
+		p.termView.SetPty(ptyFile)
+	}
+}
+
+func (p *logPane) title() string {
+	if p.logView == nil {
+		return p.name
+	}
+	if p.follow || p.logView.isSearchActive() {
+		return logTitle(p.logView, p.name)
+	}
+	return logTitle(p.logView, p.name+" (paused)")
+}
+
+func (p *logPane) truncateLog() {
+	if err := p.project.TruncateProcessLogs(p.name); err != nil {
+		log.Err(err).Msgf("failed to truncate process %s logs", p.name)
+	}
+}
+
+// logPanesLayout returns the rows and columns of the tiled layout of n panes,
+// and the position of each pane. Up to three panes are placed side by side,
+// more are split in two rows, the last pane spanning the free columns.
+func logPanesLayout(n int) (rows, cols int, cells []logPaneCell) {
+	if n <= 0 {
+		return 0, 0, nil
+	}
+	cols = n
+	if n > 3 {
+		cols = (n + 1) / 2
+	}
+	rows = (n + cols - 1) / cols
+	cells = make([]logPaneCell, n)
+	for i := range cells {
+		cells[i] = logPaneCell{row: i / cols, col: i % cols, colSpan: 1}
+	}
+	cells[n-1].colSpan = rows*cols - n + 1
+	return rows, cols, cells
+}
+
+func (pv *pcView) newLogPane(name string) (*logPane, error) {
+	info, err := pv.project.GetProcessInfo(name)
+	if err != nil {
+		return nil, err
+	}
+	pane := &logPane{
+		name:    name,
+		project: pv.project,
+	}
+	if info.IsInteractive {
+		pane.termView = newTerminalPane(pv.termView)
+		pane.termView.SetOnEscape(pv.changeFocus)
+		pane.termView.SetOnFocus(func() { pv.updateHelpTextView() })
+		pane.termView.SetOnBlur(func() { pv.updateHelpTextView() })
+		pane.termView.SetTitle(name)
+		pane.syncPty()
+	} else {
+		pane.logView = NewLogView(pv.project.GetLogLength())
+		pane.logView.useAnsi = !info.DisableAnsiColors
+		parser, err := pclog.NewLogParser(info.LogFormat, info.LogRegex)
+		if err != nil {
+			log.Err(err).Msgf("invalid log format of process %s", name)
+		}
+		pane.logView.setLogParser(parser)
+		pane.logView.setTruncator(pane)
+		pane.startFollow()
+	}
+	if pv.styles != nil {
+		pv.setLogPaneStyle(pane, pv.styles)
+	}
+	return pane, nil
+}
+
+// setLogPanes pins the logs of the processes, skipping the ones that are not
+// in the project.
+func (pv *pcView) setLogPanes(names []string, isTiled bool) {
+	for _, pane := range pv.logPanes {
+		pane.close()
+	}
+	pv.logPanes = nil
+	for _, name := range names {
+		if len(pv.logPanes) == maxLogPanes {
+			break
+		}
+		if pv.logPaneIndex(name) >= 0 {
+			continue
+		}
+		pane, err := pv.newLogPane(name)
+		if err != nil {
+			log.Debug().Err(err).Msgf("Not pinning the logs of %s", name)
+			continue
+		}
+		pv.logPanes = append(pv.logPanes, pane)
+	}
+	pv.isLogTiled = isTiled
+}
+
+func (pv *pcView) logPaneIndex(name string) int {
+	return slices.IndexFunc(pv.logPanes, func(p *logPane) bool {
+		return p.name == name
+	})
+}
+
+func (pv *pcView) logPaneNames() []string {
+	names := make([]string, len(pv.logPanes))
+	for i, pane := range pv.logPanes {
+		names[i] = pane.name
+	}
+	return names
+}
+
+// togglePinLog pins the logs of the selected process, or unpins them.
+func (pv *pcView) togglePinLog() {
+	name := pv.getSelectedProcName()
+	if name == "" {
+		return
+	}
+	if i := pv.logPaneIndex(name); i >= 0 {
+		pv.logPanes[i].close()
+		pv.logPanes = slices.Delete(pv.logPanes, i, i+1)
+		pv.attentionMessage(fmt.Sprintf("Unpinned %s logs", name), 2*time.Second, false)
+	} else {
+		if len(pv.logPanes) == maxLogPanes {
+			pv.attentionMessage(fmt.Sprintf("At most %d logs can be pinned", maxLogPanes), 3*time.Second, true)
+			return
+		}
+		pane, err := pv.newLogPane(name)
+		if err != nil {
+			pv.showError(err.Error())
+			return
+		}
+		pv.logPanes = append(pv.logPanes, pane)
+		pv.attentionMessage(fmt.Sprintf("Pinned %s logs (%d of %d)", name, len(pv.logPanes), maxLogPanes), 2*time.Second, false)
+	}
+	if len(pv.logPanes) < minLogPanes {
+		pv.isLogTiled = false
+	}
+	pv.saveTuiState()
+	pv.redrawGrid()
+	pv.updateHelpTextView()
+}
+
+// toggleLogPanes switches between the logs of the selected process and the
+// tiled logs of the pinned processes.
+func (pv *pcView) toggleLogPanes() {
+	if !pv.isLogTiled && len(pv.logPanes) < minLogPanes {
+		pin := pv.shortcuts.ShortCutKeys[ActionLogPin].ShortCut
+		pv.attentionMessage(fmt.Sprintf("Pin at least %d process logs with %s to tile them", minLogPanes, pin), 3*time.Second, true)
+		return
+	}
+	pv.isLogTiled = !pv.isLogTiled
+	pv.saveTuiState()
+	pv.redrawGrid()
+	pv.updateHelpTextView()
+}
+
+func (pv *pcView) isLogPanesShown() bool {
+	return pv.isLogTiled && len(pv.logPanes) >= minLogPanes
+}
+
+func (pv *pcView) createLogPanesGrid() tview.Primitive {
+	rows, cols, cells := logPanesLayout(len(pv.logPanes))
+	pv.logPanesGrid.Clear()
+	pv.logPanesGrid.SetRows(make([]int, rows)...)
+	pv.logPanesGrid.SetColumns(make([]int, cols)...)
+	for i, pane := range pv.logPanes {
+		cell := cells[i]
+		pv.logPanesGrid.AddItem(pane.primitive(), cell.row, cell.col, 1, cell.colSpan, 0, 0, false)
+	}
+	return pv.logPanesGrid
+}
+
+// focusedLogPane returns the pane of logs holding the focus, or nil.
+// The panes of interactive processes handle their own keys.
+func (pv *pcView) focusedLogPane() *logPane {
+	if !pv.isLogPanesShown() {
+		return nil
+	}
+	for _, pane := range pv.logPanes {
+		if pane.logView != nil && pane.logView.HasFocus() {
+			return pane
+		}
+	}
+	return nil
+}
+
+// changeLogPaneFocus moves the focus to the next pane, and from the last pane
+// back to the processes table.
+func (pv *pcView) changeLogPaneFocus() {
+	next := slices.IndexFunc(pv.logPanes, func(p *logPane) bool {
+		return p.primitive().HasFocus()
+	}) + 1
+	if next == len(pv.logPanes) {
+		if pv.scrSplitState != LogFull {
+			pv.appView.SetFocus(pv.procTable)
+			pv.updateHelpTextView()
+			return
+		}
+		next = 0
+	}
+	pv.appView.SetFocus(pv.logPanes[next].primitive())
+	pv.updateHelpTextView()
+}
+
+func (pv *pcView) updateLogPanes(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug().Msg("Log panes monitoring canceled")
+			return
+		case <-time.After(300 * time.Millisecond):
+			pv.appView.QueueUpdateDraw(func() {
+				for _, pane := range pv.logPanes {
+					if pane.logView != nil {
+						pane.logView.Flush()
+					}
+					pane.syncPty()
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLogPanesLayout(t *testing.T) {
+	tests := []struct {
+		name      string
+		panes     int
+		wantRows  int
+		wantCols  int
+		wantCells []logPaneCell
+	}{
+		{
+			name:  "no panes",
+			panes: 0,
+		},
+		{
+			name:      "side by side",
+			panes:     2,
+			wantRows:  1,
+			wantCols:  2,
+			wantCells: []logPaneCell{{0, 0, 1}, {0, 1, 1}},
+		},
+		{
+			name:      "three columns",
+			panes:     3,
+			wantRows:  1,
+			wantCols:  3,
+			wantCells: []logPaneCell{{0, 0, 1}, {0, 1, 1}, {0, 2, 1}},
+		},
+		{
+			name:      "two by two",
+			panes:     4,
+			wantRows:  2,
+			wantCols:  2,
+			wantCells: []logPaneCell{{0, 0, 1}, {0, 1, 1}, {1, 0, 1}, {1, 1, 1}},
+		},
+		{
+			name:      "last pane spans the free column",
+			panes:     5,
+			wantRows:  2,
+			wantCols:  3,
+			wantCells: []logPaneCell{{0, 0, 1}, {0, 1, 1}, {0, 2, 1}, {1, 0, 1}, {1, 1, 2}},
+		},
+		{
+			name:      "three by two",
+			panes:     6,
+			wantRows:  2,
+			wantCols:  3,
+			wantCells: []logPaneCell{{0, 0, 1}, {0, 1, 1}, {0, 2, 1}, {1, 0, 1}, {1, 1, 1}, {1, 2, 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, cols, cells := logPanesLayout(tt.panes)
+			if rows != tt.wantRows || cols != tt.wantCols {
+				t.Errorf("logPanesLayout() = %dx%d, want %dx%d", rows, cols, tt.wantRows, tt.wantCols)
+			}
+			if !slices.Equal(cells, tt.wantCells) {
+				t.Errorf("logPanesLayout() cells = %v, want %v", cells, tt.wantCells)
+			}
+		})
+	}
+}
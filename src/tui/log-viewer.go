@@ -46,6 +46,8 @@ type LogView struct {
 	searchIndex            int
 	totalSearchCount       int
 	truncator              truncator
+	parser                 *pclog.LogParser
+	filter                 *pclog.LogFilter
 }
 
 func NewLogView(maxLines int) *LogView {
@@ -68,6 +70,11 @@ func NewLogView(maxLines int) *LogView {
 }
 
 func (l *LogView) WriteString(line string) (n int, err error) {
+	rec, parsed := l.parser.Parse(line)
+	if !l.filter.Match(&rec, parsed) {
+		return len(line), nil
+	}
+	color := levelColor(rec.Level)
 	if l.prettyPrintJson {
 		line = l.tryPrettyPrintJson(line)
 	}
@@ -79,15 +86,34 @@ func (l *LogView) WriteString(line string) (n int, err error) {
 			// Remove the clear sequence and process remaining text
 			line = clearScreenPattern.ReplaceAllString(line, "")
 		}
+		if color != "" {
+			return fmt.Fprintf(l.buffer, "[%s]%s[-:-:-]\n", color, escapeForAnsiWriter(line))
+		}
 		return l.buffer.WriteString(escapeForAnsiWriter(line) + "\n")
 	}
-	if strings.Contains(strings.ToLower(line), "error") {
-		return fmt.Fprintf(l.buffer, "[deeppink]%s[-:-:-]\n", tview.Escape(line))
+	if color == "" && !parsed && strings.Contains(strings.ToLower(line), "error") {
+		color = "deeppink"
+	}
+	if color != "" {
+		return fmt.Fprintf(l.buffer, "[%s]%s[-:-:-]\n", color, tview.Escape(line))
 	} else {
 		return fmt.Fprintf(l.buffer, "%s\n", tview.Escape(line))
 	}
 }
 
+// levelColor returns the color of the lines with a parsed log level, or an
+// empty string to keep the colors of the line.
+func levelColor(level string) string {
+	switch level {
+	case pclog.LogLevelError, pclog.LogLevelFatal:
+		return "deeppink"
+	case pclog.LogLevelWarn:
+		return "yellow"
+	case pclog.LogLevelDebug, pclog.LogLevelTrace:
+		return "gray"
+	}
+	return ""
+}
 
 // escapeForAnsiWriter escapes tview-style tags in text that also contains ANSI
 // escape sequences. tview.Escape cannot be used here for two reasons:
@@ -254,6 +280,18 @@ func (l *LogView) AddMark() {
 	fmt.Fprintf(l.buffer, "%s\n", mark)
 }
 
+func (l *LogView) setLogParser(parser *pclog.LogParser) {
+	l.parser = parser
+}
+
+func (l *LogView) setLogFilter(filter *pclog.LogFilter) {
+	l.filter = filter
+}
+
+func (l *LogView) getLogFilter() *pclog.LogFilter {
+	return l.filter
+}
+
 func (l *LogView) setTruncator(t truncator) {
 	l.truncator = t
 }
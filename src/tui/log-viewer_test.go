@@ -6,6 +6,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/f1bonacc1/process-compose/src/pclog"
+	"github.com/f1bonacc1/process-compose/src/types"
 	"github.com/rivo/tview"
 )
 
@@ -152,3 +154,43 @@ func TestEscapeForAnsiWriterRendering(t *testing.T) {
 		})
 	}
 }
+
+func TestLogViewStructuredLogs(t *testing.T) {
+	parser, err := pclog.NewLogParser(types.LogFormatJSON, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter, err := pclog.ParseLogFilter("level>=warn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		useAnsi bool
+		filter  *pclog.LogFilter
+		line    string
+		want    string
+	}{
+		{"error is colored", false, nil, `{"level":"error","msg":"failed"}`, "[deeppink]{\"level\":\"error\",\"msg\":\"failed\"}[-:-:-]\n"},
+		{"warn is colored with ansi", true, nil, `{"level":"warn","msg":"slow"}`, "[yellow]{\"level\":\"warn\",\"msg\":\"slow\"}[-:-:-]\n"},
+		{"info keeps its colors", false, nil, `{"level":"info","msg":"no error"}`, "{\"level\":\"info\",\"msg\":\"no error\"}\n"},
+		{"unparsed error line", false, nil, "error: boom", "[deeppink]error: boom[-:-:-]\n"},
+		{"filtered out", false, filter, `{"level":"info","msg":"ok"}`, ""},
+		{"unparsed line filtered out", false, filter, "started", ""},
+		{"filter match", false, filter, `{"level":"fatal","msg":"down"}`, "[deeppink]{\"level\":\"fatal\",\"msg\":\"down\"}[-:-:-]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLogView(100)
+			l.useAnsi = tt.useAnsi
+			l.setLogParser(parser)
+			l.setLogFilter(tt.filter)
+			if _, err := l.WriteString(tt.line); err != nil {
+				t.Fatalf("WriteString() error = %v", err)
+			}
+			if got := l.buffer.String(); got != tt.want {
+				t.Errorf("WriteString() wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -30,7 +30,10 @@ func (pv *pcView) createGrid() {
 
 	name := pv.getSelectedProcName()
 	pv.currentProcInteractive = false
-	if name != "" && pv.isInteractive(name) {
+	if pv.isLogPanesShown() {
+		pv.termView.Stop()
+		logPrimitive = pv.createLogPanesGrid()
+	} else if name != "" && pv.isInteractive(name) {
 		pv.currentProcInteractive = true
 		logPrimitive = pv.termView
 		// Ensure PTY is set
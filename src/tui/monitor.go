@@ -9,6 +9,23 @@ import (
 
 const defaultSilenceThreshold = 5 * time.Second
 
+type monitorEventKind int
+
+const (
+	monitorEventActivity monitorEventKind = iota
+	monitorEventSilence
+	monitorEventFailed
+	monitorEventReady
+)
+
+// monitorEvent is a change worth a desktop notification: a monitored process
+// got an activity or silence notification, failed, or became ready.
+type monitorEvent struct {
+	name  string
+	kind  monitorEventKind
+	state types.ProcessState
+}
+
 type processMonitorState struct {
 	monitorType           types.MonitorFor
 	silenceThreshold      time.Duration
@@ -18,6 +35,11 @@ type processMonitorState struct {
 	maxLineAtSilence      int64     // MaxLogicalLine when silence was last detected
 	silenceAcknowledged   bool      // true after user focused a silence-monitored process
 	hasNotification       bool
+	// observed is set once the state below holds the process state of a
+	// previous update, so that failures and readiness are reported on change.
+	observed      bool
+	lastStatus    string
+	lastReadyTime time.Time
 }
 
 type processMonitor struct {
@@ -90,67 +112,110 @@ func (m *processMonitor) onProcessFocused(name string) bool {
 
 // updateNotifications checks all monitored processes and updates notification state.
 // processStates provides the current state (including LastActivityTime and IsRunning).
-func (m *processMonitor) updateNotifications(processStates []types.ProcessState) {
+// It returns the notifications raised by this update, and the failures and
+// readiness changes of the monitored processes, whether they are focused or not.
+func (m *processMonitor) updateNotifications(processStates []types.ProcessState) []monitorEvent {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	var events []monitorEvent
 	for i := range processStates {
 		ps := &processStates[i]
 		ms, ok := m.states[ps.Name]
 		if !ok {
 			continue
 		}
-
-		// Always keep max logical line current, even for focused processes.
-		// This ensures onProcessFocused sees up-to-date values.
-		if ms.monitorType == types.MonitorForSilence {
-			ms.lastSeenMaxLine = ps.MaxLogicalLine
+		if kind, changed := ms.observeLifecycle(ps); changed {
+			events = append(events, monitorEvent{name: ps.Name, kind: kind, state: *ps})
 		}
-
-		if ms.unfocusedSince.IsZero() {
-			continue
+		hadNotification := ms.hasNotification
+		ms.updateNotification(ps)
+		if ms.hasNotification && !hadNotification {
+			kind := monitorEventActivity
+			if ms.monitorType == types.MonitorForSilence {
+				kind = monitorEventSilence
+			}
+			events = append(events, monitorEvent{name: ps.Name, kind: kind, state: *ps})
 		}
+	}
+	return events
+}
 
-		// Clear notification when process is no longer running
-		if !ps.IsRunning {
-			ms.hasNotification = false
-			continue
-		}
+// observeLifecycle records the status and ready time of the process, and
+// reports whether it just failed or became ready.
+func (ms *processMonitorState) observeLifecycle(ps *types.ProcessState) (monitorEventKind, bool) {
+	var readyTime time.Time
+	if ps.ProcessReadyTime != nil {
+		readyTime = *ps.ProcessReadyTime
+	}
+	observed, lastStatus, lastReadyTime := ms.observed, ms.lastStatus, ms.lastReadyTime
+	ms.observed, ms.lastStatus, ms.lastReadyTime = true, ps.Status, readyTime
+	if !observed {
+		return 0, false
+	}
+	failed := ps.Status == types.ProcessStateError ||
+		(ps.Status == types.ProcessStateCompleted && !ps.IsExitCodeSuccess())
+	if failed && ps.Status != lastStatus {
+		return monitorEventFailed, true
+	}
+	if ps.IsRunning && !readyTime.IsZero() && !readyTime.Equal(lastReadyTime) {
+		return monitorEventReady, true
+	}
+	return 0, false
+}
 
-		if ms.hasNotification {
-			continue
-		}
+// updateNotification raises the activity or silence notification of an
+// unfocused process.
+func (ms *processMonitorState) updateNotification(ps *types.ProcessState) {
+	// Always keep max logical line current, even for focused processes.
+	// This ensures onProcessFocused sees up-to-date values.
+	if ms.monitorType == types.MonitorForSilence {
+		ms.lastSeenMaxLine = ps.MaxLogicalLine
+	}
 
-		var lastActivity time.Time
-		if ps.LastActivityTime != nil {
-			lastActivity = *ps.LastActivityTime
-		}
+	if ms.unfocusedSince.IsZero() {
+		return
+	}
+
+	// Clear notification when process is no longer running
+	if !ps.IsRunning {
+		ms.hasNotification = false
+		return
+	}
 
-		switch ms.monitorType {
-		case types.MonitorForActivity:
-			// Notify if new output appeared since unfocus
-			if lastActivity.After(ms.lastActivityAtUnfocus) {
+	if ms.hasNotification {
+		return
+	}
+
+	var lastActivity time.Time
+	if ps.LastActivityTime != nil {
+		lastActivity = *ps.LastActivityTime
+	}
+
+	switch ms.monitorType {
+	case types.MonitorForActivity:
+		// Notify if new output appeared since unfocus
+		if lastActivity.After(ms.lastActivityAtUnfocus) {
+			ms.hasNotification = true
+		}
+	case types.MonitorForSilence:
+		// Reset acknowledged only when the max logical line advanced
+		// past what it was at the last silence detection. Phantom writes
+		// (prompt redraws, status updates) operate in-place and don't
+		// advance the logical line. Only real new output does.
+		if ms.silenceAcknowledged && ms.maxLineAtSilence > 0 &&
+			ms.lastSeenMaxLine > ms.maxLineAtSilence {
+			ms.silenceAcknowledged = false
+		}
+		// Check if process has been silent for longer than threshold
+		if !lastActivity.IsZero() && time.Since(lastActivity) > ms.silenceThreshold {
+			if !ms.silenceAcknowledged {
+				ms.maxLineAtSilence = ms.lastSeenMaxLine
 				ms.hasNotification = true
-			}
-		case types.MonitorForSilence:
-			// Reset acknowledged only when the max logical line advanced
-			// past what it was at the last silence detection. Phantom writes
-			// (prompt redraws, status updates) operate in-place and don't
-			// advance the logical line. Only real new output does.
-			if ms.silenceAcknowledged && ms.maxLineAtSilence > 0 &&
-				ms.lastSeenMaxLine > ms.maxLineAtSilence {
-				ms.silenceAcknowledged = false
-			}
-			// Check if process has been silent for longer than threshold
-			if !lastActivity.IsZero() && time.Since(lastActivity) > ms.silenceThreshold {
-				if !ms.silenceAcknowledged {
-					ms.maxLineAtSilence = ms.lastSeenMaxLine
-					ms.hasNotification = true
-				} else if ms.maxLineAtSilence == 0 {
-					// Lazy init: user focused before any data was observed,
-					// so onProcessUnfocused couldn't set a baseline.
-					ms.maxLineAtSilence = ms.lastSeenMaxLine
-				}
+			} else if ms.maxLineAtSilence == 0 {
+				// Lazy init: user focused before any data was observed,
+				// so onProcessUnfocused couldn't set a baseline.
+				ms.maxLineAtSilence = ms.lastSeenMaxLine
 			}
 		}
 	}
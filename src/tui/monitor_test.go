@@ -389,3 +389,71 @@ func TestMonitorNotificationSticksUntilFocused(t *testing.T) {
 		t.Error("notification should persist across multiple updates")
 	}
 }
+
+func TestMonitorEvents(t *testing.T) {
+	m := newProcessMonitor()
+	m.initProcess("proc1", types.MonitorForActivity, 0)
+	m.onProcessFocused("proc1")
+
+	// The first update only records the state, even of a failed process
+	states := []types.ProcessState{
+		{Name: "proc1", Status: types.ProcessStateError},
+	}
+	if events := m.updateNotifications(states); len(events) != 0 {
+		t.Fatalf("first update should not raise events, got %+v", events)
+	}
+
+	// Readiness is reported although the process is focused
+	ready := time.Now()
+	states = []types.ProcessState{
+		{Name: "proc1", Status: types.ProcessStateRunning, IsRunning: true, ProcessReadyTime: &ready},
+	}
+	events := m.updateNotifications(states)
+	if len(events) != 1 || events[0].kind != monitorEventReady {
+		t.Fatalf("expected a ready event, got %+v", events)
+	}
+	if events = m.updateNotifications(states); len(events) != 0 {
+		t.Fatalf("ready should be reported once, got %+v", events)
+	}
+
+	// A successful completion is not a failure
+	states = []types.ProcessState{
+		{Name: "proc1", Status: types.ProcessStateCompleted},
+	}
+	if events = m.updateNotifications(states); len(events) != 0 {
+		t.Fatalf("successful completion should not raise events, got %+v", events)
+	}
+
+	states = []types.ProcessState{
+		{Name: "proc1", Status: types.ProcessStateRunning, IsRunning: true},
+	}
+	m.updateNotifications(states)
+	states = []types.ProcessState{
+		{Name: "proc1", Status: types.ProcessStateCompleted, ExitCode: 2},
+	}
+	events = m.updateNotifications(states)
+	if len(events) != 1 || events[0].kind != monitorEventFailed || events[0].state.ExitCode != 2 {
+		t.Fatalf("expected a failed event, got %+v", events)
+	}
+	if events = m.updateNotifications(states); len(events) != 0 {
+		t.Fatalf("failure should be reported once, got %+v", events)
+	}
+}
+
+func TestMonitorActivityEvent(t *testing.T) {
+	m := newProcessMonitor()
+	m.initProcess("proc1", types.MonitorForActivity, 0)
+
+	now := time.Now()
+	states := []types.ProcessState{
+		{Name: "proc1", Status: types.ProcessStateRunning, IsRunning: true, LastActivityTime: &now},
+	}
+	events := m.updateNotifications(states)
+	if len(events) != 1 || events[0].kind != monitorEventActivity {
+		t.Fatalf("expected an activity event, got %+v", events)
+	}
+	// The notification sticks, but is reported once
+	if events = m.updateNotifications(states); len(events) != 0 {
+		t.Fatalf("activity should be reported once, got %+v", events)
+	}
+}
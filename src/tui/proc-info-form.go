@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"strings"
@@ -29,13 +30,15 @@ func (pv *pcView) createProcInfoForm(info *types.ProcessConfig, state *types.Pro
 	if state != nil && state.NextRunTime != nil {
 		f.AddInputField("Next Run:", state.NextRunTime.Format(time.RFC1123), 0, nil, nil)
 	}
-	addDropDownIfNotEmpty("Environment:", info.Environment, f)
+	addDropDownIfNotEmpty("Environment:", append(slices.Clone(info.Environment), info.SecretEnvironment...), f)
 	addCSVIfNotEmpty("Depends On:", mapKeysToSlice(info.DependsOn), f)
 	if ports != nil {
 		addCSVIfNotEmpty("TCP Ports:", ports.TcpPorts, f)
 		addCSVIfNotEmpty("UDP Ports:", ports.UdpPorts, f)
+		addStringIfNotEmpty("Assigned Ports:", ports.FormatAssigned(), f)
 	}
 	addWatchInfo(info.Watch, state, f)
+	addLimitsInfo(state, f)
 	f.AddCheckbox("Is Disabled:", info.Disabled, nil)
 	f.AddCheckbox("Is Daemon:", info.IsDaemon, nil)
 	f.AddCheckbox("Is TTY:", info.IsTty, nil)
@@ -77,6 +80,31 @@ func addWatchInfo(watch *types.WatchConfig, state *types.ProcessState, f *tview.
 	}
 }
 
+// addLimitsInfo describes the resource limits in effect. They come from the
+// state rather than the config: when cgroups are not delegated only a subset
+// of the configured limits is enforced, and that subset is what matters.
+func addLimitsInfo(state *types.ProcessState, f *tview.Form) {
+	if state == nil || state.Limits == nil {
+		return
+	}
+	f.AddInputField("Limits Enforcer:", state.Limits.Enforcer, 0, nil, nil)
+	if state.Limits.MemoryMax > 0 {
+		f.AddInputField("Memory Limit:", byteCountIEC(state.Limits.MemoryMax), 0, nil, nil)
+	}
+	if state.Limits.CPUQuota > 0 {
+		f.AddInputField("CPU Quota:", fmt.Sprintf("%.0f%%", state.Limits.CPUQuota), 0, nil, nil)
+	}
+	if state.Limits.PidsMax > 0 {
+		f.AddInputField("Pids Limit:", fmt.Sprint(state.Limits.PidsMax), 0, nil, nil)
+	}
+	if state.Limits.NoFile > 0 {
+		f.AddInputField("Open Files Limit:", fmt.Sprint(state.Limits.NoFile), 0, nil, nil)
+	}
+	if state.OOMKills > 0 {
+		f.AddInputField("OOM Kills:", fmt.Sprint(state.OOMKills), 0, nil, nil)
+	}
+}
+
 // watchPathsSummary renders each watched root together with its filters, so the
 // dialog can answer "why did this path not trigger" and not merely "what is
 // watched".
@@ -82,7 +82,9 @@ func (pv *pcView) execute(info *types.ProcessConfig) error {
 	}(cancel)
 
 	cmd := exec.CommandContext(ctx, info.Executable, info.Args...)
-	cmd.Env = pv.project.GetFullProcessEnvironment(info)
+	// The foreground process needs the secret values, which
+	// GetFullProcessEnvironment redacts.
+	cmd.Env = pv.project.GetProcessLaunchEnvironment(info)
 	log.Debug().Str("exec", info.Executable).Strs("args", info.Args).Msg("running start")
 	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
 	err := cmd.Run()
@@ -25,12 +25,20 @@ type tableRowValues struct {
 	status    string
 	age       string
 	mem       string
+	memTrend  string
 	cpu       string
+	cpuTrend  string
 	health    string
 	restarts  string
 	exitCode  string
 }
 
+const (
+	// resourceTrendWindow is the resource usage shown by the trend columns.
+	resourceTrendWindow = time.Minute
+	resourceTrendWidth  = 10
+)
+
 var (
 	DetachOnSuccessMessage = "All processes started successfully, detached from TUI"
 )
@@ -71,7 +79,9 @@ func (pv *pcView) fillTableData() {
 	}
 
 	// Update activity/silence monitor notifications
-	pv.monitor.updateNotifications(states.States)
+	for _, ev := range pv.monitor.updateNotifications(states.States) {
+		pv.sendDesktopNotification(ev)
+	}
 
 	// Report watch-triggered restarts, coalesced so a burst stays readable.
 	if msg := pv.watchNotifier.observe(states.States, time.Now()); msg != "" {
@@ -170,7 +180,9 @@ func setRowValues(procTable *tview.Table, row int, rowVals tableRowValues) {
 	procTable.SetCell(row, int(ProcessStateAge), tview.NewTableCell(rowVals.age).SetAlign(tview.AlignLeft).SetExpansion(1).SetTextColor(rowVals.fgColor))
 	procTable.SetCell(row, int(ProcessStateHealth), tview.NewTableCell(rowVals.health).SetAlign(tview.AlignLeft).SetExpansion(1).SetTextColor(rowVals.fgColor))
 	procTable.SetCell(row, int(ProcessStateMem), tview.NewTableCell(rowVals.mem).SetAlign(tview.AlignLeft).SetExpansion(1).SetTextColor(rowVals.fgColor))
+	procTable.SetCell(row, int(ProcessStateMemTrend), tview.NewTableCell(rowVals.memTrend).SetAlign(tview.AlignLeft).SetExpansion(1).SetTextColor(rowVals.fgColor))
 	procTable.SetCell(row, int(ProcessStateCPU), tview.NewTableCell(rowVals.cpu).SetAlign(tview.AlignLeft).SetExpansion(1).SetTextColor(rowVals.fgColor))
+	procTable.SetCell(row, int(ProcessStateCPUTrend), tview.NewTableCell(rowVals.cpuTrend).SetAlign(tview.AlignLeft).SetExpansion(1).SetTextColor(rowVals.fgColor))
 	procTable.SetCell(row, int(ProcessStateRestarts), tview.NewTableCell(rowVals.restarts).SetAlign(tview.AlignRight).SetExpansion(0).SetTextColor(rowVals.fgColor))
 	procTable.SetCell(row, int(ProcessStateExit), tview.NewTableCell(rowVals.exitCode).SetAlign(tview.AlignRight).SetExpansion(0).SetTextColor(rowVals.fgColor))
 }
@@ -193,7 +205,7 @@ func (pv *pcView) onTableSelectionChange(_, _ int) {
 	}
 	pv.logsText.resetSearch()
 	pv.updateHelpTextView()
-	pv.logsText.SetTitle(name)
+	pv.logsText.SetTitle(pv.getLogTitle(name))
 	pv.unFollowLog()
 	pv.followLog(name)
 	if !pv.logFollow {
@@ -255,7 +267,9 @@ func (pv *pcView) createProcTable() *tview.Table {
 		ProcessStateAge:       "AGE(A)",
 		ProcessStateHealth:    "HEALTH(H)",
 		ProcessStateMem:       "MEM(M)",
+		ProcessStateMemTrend:  "MEM TREND",
 		ProcessStateCPU:       "CPU(U)",
+		ProcessStateCPUTrend:  "CPU TREND",
 		ProcessStateRestarts:  "RESTARTS(R)",
 		ProcessStateExit:      "EXIT CODE(E)",
 	}
@@ -337,11 +351,29 @@ func (pv *pcView) refreshTableState() {
 		log.Err(err).Msg("failed to get processes state")
 		return
 	}
+	// The history is only used for the trends, a failure doesn't block the states.
+	histories, err := pv.project.GetResourceHistory("", time.Now().Add(-resourceTrendWindow))
+	if err != nil {
+		log.Err(err).Msg("failed to get resource history")
+	}
 	pv.procStatesMtx.Lock()
 	pv.procStates = states
+	pv.resourceHistories = make(map[string][]types.ResourceSample, len(histories))
+	for _, h := range histories {
+		pv.resourceHistories[h.Name] = h.Samples
+	}
 	pv.procStatesMtx.Unlock()
 }
 
+// getResourceTrend returns the resource usage of the process over the trend
+// window, one bucket per sparkline character.
+func (pv *pcView) getResourceTrend(name string) []resourceBucket {
+	pv.procStatesMtx.Lock()
+	samples := pv.resourceHistories[name]
+	pv.procStatesMtx.Unlock()
+	return resourceBuckets(samples, time.Now(), resourceTrendWindow, resourceTrendWidth)
+}
+
 func (pv *pcView) updateProcStates(ctx context.Context) {
 	pv.refreshTableState()
 	ticker := time.NewTicker(pv.refreshRate)
@@ -455,6 +487,8 @@ func (pv *pcView) getIconForState(state types.ProcessState) (string, tcell.Color
 		return "✘", pv.styles.ProcTable().FgError.Color()
 	case types.ProcessStateError:
 		return "✘", pv.styles.ProcTable().FgError.Color()
+	case types.ProcessStateCrashLoopBackOff:
+		return "●", pv.styles.ProcTable().FgError.Color()
 	case types.ProcessStateDisabled,
 		types.ProcessStateForeground:
 		return "◯", pv.styles.ProcTable().FgPending.Color()
@@ -494,6 +528,16 @@ func getStrForMem(mem int64, running bool) string {
 	return byteCountIEC(mem)
 }
 
+// getStrForMemWithLimit appends the enforced memory limit, if any, so that a
+// process closing in on it stands out.
+func getStrForMemWithLimit(state types.ProcessState) string {
+	mem := getStrForMem(state.Mem, state.IsRunning)
+	if state.Limits == nil || state.Limits.MemoryMax == 0 || mem == types.PlaceHolderValue {
+		return mem
+	}
+	return mem + " / " + byteCountIEC(state.Limits.MemoryMax)
+}
+
 func getStrForCPU(cpu float64, running bool) string {
 	if !running {
 		return types.PlaceHolderValue
@@ -540,6 +584,7 @@ func (pv *pcView) getTableRowValues(state types.ProcessState) tableRowValues {
 		}
 	}
 
+	trend := pv.getResourceTrend(state.Name)
 	return tableRowValues{
 		icon:      icon,
 		iconColor: color,
@@ -550,8 +595,10 @@ func (pv *pcView) getTableRowValues(state types.ProcessState) tableRowValues {
 		status:    types.DisplayProcessStatus(state),
 		age:       state.SystemTime,
 		health:    state.Health,
-		mem:       getStrForMem(state.Mem, state.IsRunning),
+		mem:       getStrForMemWithLimit(state),
+		memTrend:  sparkline(bucketValues(trend, func(b resourceBucket) float64 { return b.mem })),
 		cpu:       getStrForCPU(state.CPU, state.IsRunning),
+		cpuTrend:  sparkline(bucketValues(trend, func(b resourceBucket) float64 { return b.cpu })),
 		restarts:  getStrForRestarts(state.Restarts),
 		exitCode:  getStrForExitCode(state),
 	}
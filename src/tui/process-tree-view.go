@@ -0,0 +1,197 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+)
+
+// processTreeDialog shows the OS processes spawned by a process, directly or
+// not. The tree is refreshed while the dialog is open, keeping the collapsed
+// nodes and the selection.
+type processTreeDialog struct {
+	*tview.TreeView
+	name      string
+	collapsed map[int]bool
+}
+
+// newProcessTreeDialog creates the dialog of the process tree. The signal
+// function is called with the selected PID.
+func newProcessTreeDialog(name string, signal func(pid int), closeFn func()) *processTreeDialog {
+	d := &processTreeDialog{
+		TreeView:  tview.NewTreeView(),
+		name:      name,
+		collapsed: map[int]bool{},
+	}
+	d.SetRoot(tview.NewTreeNode(name).SetSelectable(false)).SetTopLevel(1)
+	d.SetBorder(true).SetTitle(name + " Process Tree (Enter Expand, s Signal, Esc to close)")
+	d.SetSelectedFunc(func(node *tview.TreeNode) {
+		if pid, ok := node.GetReference().(int); ok && len(node.GetChildren()) > 0 {
+			node.SetExpanded(!node.IsExpanded())
+			d.collapsed[pid] = !node.IsExpanded()
+			node.SetText(treeNodeText(true, node.IsExpanded(), strings.TrimLeft(node.GetText(), "▾▸ ")))
+		}
+	})
+	d.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEsc:
+			closeFn()
+		case event.Rune() == 's':
+			if node := d.GetCurrentNode(); node != nil {
+				if pid, ok := node.GetReference().(int); ok {
+					signal(pid)
+				}
+			}
+		default:
+			return event
+		}
+		return nil
+	})
+	return d
+}
+
+// update rebuilds the tree. A nil tree means that the process is not running.
+func (d *processTreeDialog) update(tree *types.ProcessTree, styles processTreeStyles) {
+	selected := -1
+	if node := d.GetCurrentNode(); node != nil {
+		if pid, ok := node.GetReference().(int); ok {
+			selected = pid
+		}
+	}
+	root := d.GetRoot()
+	root.ClearChildren()
+	if tree == nil {
+		root.AddChild(tview.NewTreeNode(d.name + " is not running").SetSelectable(false).SetColor(styles.pending))
+		return
+	}
+	var current *tview.TreeNode
+	var add func(parent *tview.TreeNode, n *types.ProcessTreeNode)
+	add = func(parent *tview.TreeNode, n *types.ProcessTreeNode) {
+		expanded := !d.collapsed[n.Pid]
+		node := tview.NewTreeNode(treeNodeText(len(n.Children) > 0, expanded, formatProcessTreeNode(n))).
+			SetReference(n.Pid).
+			SetExpanded(expanded).
+			SetColor(styles.fg)
+		if len(n.TcpPorts)+len(n.UdpPorts) > 0 {
+			node.SetColor(styles.listening)
+		}
+		if n.Pid == selected || current == nil {
+			current = node
+		}
+		for i := range n.Children {
+			add(node, &n.Children[i])
+		}
+		parent.AddChild(node)
+	}
+	add(root, &tree.Root)
+	d.SetCurrentNode(current)
+}
+
+// treeNodeText prefixes the text of a node with its expansion state. Leaves
+// are indented by as much to keep the columns aligned.
+func treeNodeText(hasChildren, expanded bool, text string) string {
+	switch {
+	case !hasChildren:
+		return "  " + text
+	case expanded:
+		return "▾ " + text
+	default:
+		return "▸ " + text
+	}
+}
+
+func formatProcessTreeNode(n *types.ProcessTreeNode) string {
+	ports := ""
+	for _, port := range n.TcpPorts {
+		ports += fmt.Sprintf(" :%d", port)
+	}
+	for _, port := range n.UdpPorts {
+		ports += fmt.Sprintf(" :%d/udp", port)
+	}
+	return tview.Escape(fmt.Sprintf("%-7d %6s %10s  %s%s", n.Pid,
+		getStrForCPU(n.CPU, true), getStrForMem(n.Mem, true), n.Command, ports))
+}
+
+type processTreeStyles struct {
+	fg, listening, pending tcell.Color
+}
+
+func (pv *pcView) showProcessTreeDialog() {
+	name := pv.getSelectedProcName()
+	if name == "" {
+		return
+	}
+	pv.openProcessTreeDialog(name)
+}
+
+func (pv *pcView) openProcessTreeDialog(name string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	closeDialog := func() {
+		cancel()
+		pv.pages.RemovePage(PageDialog)
+	}
+	signal := func(pid int) {
+		closeDialog()
+		pv.showSignalOptions(fmt.Sprintf("Send Signal to PID %d", pid), func(option processSignalOption) {
+			go pv.handleDescendantSignaled(name, pid, option)
+		}, func() {
+			pv.pages.RemovePage(PageDialog)
+			pv.openProcessTreeDialog(name)
+		})
+	}
+	dialog := newProcessTreeDialog(name, signal, func() {
+		closeDialog()
+		pv.appView.SetFocus(pv.procTable)
+	})
+	dialog.SetBackgroundColor(pv.styles.BgColor())
+	dialog.SetBorderColor(pv.styles.BorderColor())
+	dialog.SetTitleColor(pv.styles.Body().SecondaryTextColor.Color())
+	styles := processTreeStyles{
+		fg:        pv.styles.ProcTable().FgColor.Color(),
+		listening: pv.styles.ProcTable().FgCompleted.Color(),
+		pending:   pv.styles.ProcTable().FgPending.Color(),
+	}
+	go pv.updateProcessTreeDialog(ctx, dialog, styles)
+	pv.showDialog(dialog, -4, 30)
+}
+
+func (pv *pcView) updateProcessTreeDialog(ctx context.Context, dialog *processTreeDialog, styles processTreeStyles) {
+	refresh := func() {
+		tree, err := pv.project.GetProcessTree(dialog.name)
+		if err != nil {
+			log.Debug().Err(err).Msgf("failed to get the process tree of %s", dialog.name)
+		}
+		pv.appView.QueueUpdateDraw(func() {
+			dialog.update(tree, styles)
+		})
+	}
+	refresh()
+	ticker := time.NewTicker(pv.refreshRate)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refresh()
+		}
+	}
+}
+
+func (pv *pcView) handleDescendantSignaled(name string, pid int, option processSignalOption) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go pv.showAttentionMessage(ctx, fmt.Sprintf("Sending %s to PID %d of %s", option.Name, pid, name), time.Second, false)
+	pv.showAutoProgress(ctx, time.Second)
+	err := pv.project.SignalProcessTree(name, pid, option.Signal)
+	cancel()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to send signal")
+		pv.showError(err.Error())
+	}
+}
@@ -16,13 +16,23 @@ type processSignalOption struct {
 }
 
 func (pv *pcView) showSignalDialog() {
+	name := pv.getSelectedProcName()
+	pv.showSignalOptions("Send Signal to "+name, func(option processSignalOption) {
+		go pv.handleProcessSignaled(name, option)
+	}, func() {
+		pv.pages.RemovePage(PageDialog)
+	})
+}
+
+// showSignalOptions lists the signals to choose from. The dialog is closed
+// with done, then the chosen signal is passed to send.
+func (pv *pcView) showSignalOptions(title string, send func(option processSignalOption), done func()) {
 	options := availableSignalOptions()
 	if len(options) == 0 {
 		pv.showError("Sending signals from the TUI is not supported on this platform")
 		return
 	}
 
-	name := pv.getSelectedProcName()
 	list := tview.NewList()
 
 	const scList = "123456789abcdefghijklmnopqrstuvwyzABCDEGHIJKLMNOPQRSTUVWXYZ"
@@ -40,21 +50,17 @@ func (pv *pcView) showSignalDialog() {
 			current = i
 		}
 		list.AddItem(label, secondaryText, r, func() {
-			pv.pages.RemovePage(PageDialog)
-			go pv.handleProcessSignaled(name, opt)
+			done()
+			send(opt)
 		})
 		if len(secondaryText) > maxLblLen {
 			maxLblLen = len(secondaryText)
 		}
 	}
-	list.AddItem(cancelLbl, "Select to close", 'x', func() {
-		pv.pages.RemovePage(PageDialog)
-	})
+	list.AddItem(cancelLbl, "Select to close", 'x', done)
 	list.SetCurrentItem(current)
-	list.SetDoneFunc(func() {
-		pv.pages.RemovePage(PageDialog)
-	})
-	list.SetBorder(true).SetTitle("Send Signal to " + name)
+	list.SetDoneFunc(done)
+	list.SetBorder(true).SetTitle(title)
 
 	footer := tview.NewTextView().
 		SetDynamicColors(true).
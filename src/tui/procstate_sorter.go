@@ -20,9 +20,11 @@ const (
 	ProcessStateAge       ColumnID = 5
 	ProcessStateHealth    ColumnID = 6
 	ProcessStateMem       ColumnID = 7
-	ProcessStateCPU       ColumnID = 8
-	ProcessStateRestarts  ColumnID = 9
-	ProcessStateExit      ColumnID = 10
+	ProcessStateMemTrend  ColumnID = 8
+	ProcessStateCPU       ColumnID = 9
+	ProcessStateCPUTrend  ColumnID = 10
+	ProcessStateRestarts  ColumnID = 11
+	ProcessStateExit      ColumnID = 12
 )
 
 var columnNames = map[ColumnID]string{
@@ -0,0 +1,198 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/app"
+	"github.com/f1bonacc1/process-compose/src/config"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+)
+
+// resourceWindows are the periods the resources dialog can chart, the longest
+// one being all the history kept by the runner.
+var resourceWindows = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute}
+
+// resourcesDialog charts the CPU and memory usage, and the restarts, of a
+// process over the last minutes. It is redrawn as new samples are taken.
+type resourcesDialog struct {
+	*tview.Box
+	name     string
+	project  app.IProject
+	styles   *config.Styles
+	closeFn  func()
+	update   func(func())
+	windowIx atomic.Int32
+	samples  []types.ResourceSample
+	end      time.Time
+}
+
+// newResourcesDialog creates the dialog of the process resources. The update
+// function applies the fetched samples on the UI goroutine.
+func newResourcesDialog(name string, project app.IProject, styles *config.Styles, update func(func()), closeFn func()) *resourcesDialog {
+	d := &resourcesDialog{
+		Box:     tview.NewBox(),
+		name:    name,
+		project: project,
+		styles:  styles,
+		closeFn: closeFn,
+		update:  update,
+	}
+	d.windowIx.Store(1)
+	d.SetBorder(true)
+	d.setTitle()
+	d.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEsc:
+			d.closeFn()
+		case event.Key() == tcell.KeyLeft || event.Rune() == '-':
+			d.setWindow(int(d.windowIx.Load()) - 1)
+		case event.Key() == tcell.KeyRight || event.Rune() == '+':
+			d.setWindow(int(d.windowIx.Load()) + 1)
+		default:
+			return event
+		}
+		return nil
+	})
+	return d
+}
+
+func (d *resourcesDialog) window() time.Duration {
+	return resourceWindows[d.windowIx.Load()]
+}
+
+func (d *resourcesDialog) setTitle() {
+	d.SetTitle(fmt.Sprintf("%s Resources - Last %s (←/→ Period, Esc to close)", d.name, formatWindow(d.window())))
+}
+
+func (d *resourcesDialog) setWindow(ix int) {
+	if ix < 0 || ix >= len(resourceWindows) {
+		return
+	}
+	d.windowIx.Store(int32(ix))
+	d.setTitle()
+	go d.refresh(d.window())
+}
+
+// refresh fetches the samples of the charted period, unless the period was
+// changed in the meantime.
+func (d *resourcesDialog) refresh(window time.Duration) {
+	end := time.Now()
+	histories, err := d.project.GetResourceHistory(d.name, end.Add(-window))
+	if err != nil {
+		log.Err(err).Msgf("failed to get the resource history of %s", d.name)
+		return
+	}
+	var samples []types.ResourceSample
+	if len(histories) > 0 {
+		samples = histories[0].Samples
+	}
+	d.update(func() {
+		if d.window() == window {
+			d.end, d.samples = end, samples
+		}
+	})
+}
+
+func (d *resourcesDialog) Draw(screen tcell.Screen) {
+	d.DrawForSubclass(screen, d)
+	x, y, width, height := d.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	for i, line := range d.render(width, height) {
+		tview.Print(screen, line, x, y+i, width, tview.AlignLeft, d.styles.FgColor())
+	}
+}
+
+// render lays out the CPU and memory charts, each under a caption, followed by
+// the restarts row and the time axis.
+func (d *resourcesDialog) render(width, height int) []string {
+	if len(d.samples) == 0 {
+		return []string{"No samples yet"}
+	}
+	buckets := resourceBuckets(d.samples, d.end, d.window(), width)
+	cpu := bucketValues(buckets, func(b resourceBucket) float64 { return b.cpu })
+	mem := bucketValues(buckets, func(b resourceBucket) float64 { return b.mem })
+	last := d.samples[len(d.samples)-1]
+	chartHeight := max((height-5)/2, 1)
+
+	captionColor := d.styles.Body().SecondaryTextColor.String()
+	chartColor := d.styles.ProcTable().FgColor.String()
+	var lines []string
+	addChart := func(caption string, values []float64) {
+		lines = append(lines, fmt.Sprintf("[%s]%s[-]", captionColor, caption))
+		for _, row := range barChart(values, chartHeight, maxValue(values)) {
+			lines = append(lines, fmt.Sprintf("[%s]%s[-]", chartColor, row))
+		}
+	}
+	addChart(fmt.Sprintf("CPU  now %.1f%%  max %.1f%%", last.CPU, maxValue(cpu)), cpu)
+	addChart(fmt.Sprintf("MEM  now %s  max %s", byteCountIEC(last.Mem), byteCountIEC(int64(maxValue(mem)))), mem)
+
+	restarts := d.samples[0].Restarts
+	restartsRow := make([]rune, len(buckets))
+	for i, b := range buckets {
+		restartsRow[i] = ' '
+		if !math.IsNaN(b.cpu) && b.restarts > restarts {
+			restartsRow[i] = '▲'
+			restarts = b.restarts
+		}
+	}
+	lines = append(lines,
+		fmt.Sprintf("[%s]RESTARTS  total %d  in period %d[-]", captionColor, last.Restarts, last.Restarts-d.samples[0].Restarts),
+		fmt.Sprintf("[%s]%s[-]", d.styles.ProcTable().FgWarning.String(), string(restartsRow)),
+		timeAxis(d.window(), width),
+	)
+	return lines
+}
+
+// timeAxis labels the start and the end of the charted period.
+func timeAxis(window time.Duration, width int) string {
+	start := "-" + formatWindow(window)
+	const end = "now"
+	return fmt.Sprintf("%-*s%s", max(width-len(end), len(start)), start, end)
+}
+
+func formatWindow(window time.Duration) string {
+	return fmt.Sprintf("%dm", int(window.Minutes()))
+}
+
+func (pv *pcView) showResourcesDialog() {
+	name := pv.getSelectedProcName()
+	if name == "" {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	update := func(f func()) { pv.appView.QueueUpdateDraw(f) }
+	dialog := newResourcesDialog(name, pv.project, pv.styles, update, func() {
+		cancel()
+		pv.pages.RemovePage(PageDialog)
+		pv.appView.SetFocus(pv.procTable)
+	})
+	dialog.SetBackgroundColor(pv.styles.BgColor())
+	dialog.SetBorderColor(pv.styles.BorderColor())
+	dialog.SetTitleColor(pv.styles.Body().SecondaryTextColor.Color())
+	go pv.updateResourcesDialog(ctx, dialog)
+	// a chart needs most of the screen width
+	pv.showDialog(dialog, -4, 30)
+}
+
+func (pv *pcView) updateResourcesDialog(ctx context.Context, dialog *resourcesDialog) {
+	dialog.refresh(dialog.window())
+	ticker := time.NewTicker(pv.refreshRate)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			dialog.refresh(dialog.window())
+		}
+	}
+}
@@ -15,20 +15,34 @@ func (pv *pcView) showSearch() {
 	f.SetBorder(true)
 	f.SetButtonsAlign(tview.AlignCenter)
 	f.SetTitle("Search Log")
-	f.AddInputField("Search For", pv.logsText.getSearchTerm(), fieldWidth, nil, nil)
+	pane := pv.focusedLogPane()
+	logView := pv.logsText
+	if pane != nil {
+		logView = pane.logView
+	}
+	f.AddInputField("Search For", logView.getSearchTerm(), fieldWidth, nil, nil)
 	f.AddCheckbox("Case Sensitive", false, nil)
 	f.AddCheckbox("Regex", false, nil)
 	searchFunc := func() {
 		searchTerm := f.GetFormItem(0).(*tview.InputField).GetText()
 		caseSensitive := f.GetFormItem(1).(*tview.Checkbox).IsChecked()
 		isRegex := f.GetFormItem(2).(*tview.Checkbox).IsChecked()
-		pv.stopFollowLog()
-		if err := pv.logsText.searchString(searchTerm, isRegex, caseSensitive); err != nil {
+		if pane != nil {
+			pane.stopFollow()
+		} else {
+			pv.stopFollowLog()
+		}
+		if err := logView.searchString(searchTerm, isRegex, caseSensitive); err != nil {
 			f.SetTitle(err.Error())
 			return
 		}
 		pv.pages.RemovePage(PageDialog)
-		pv.logsText.SetTitle(pv.getLogTitle(pv.getSelectedProcName()))
+		if pane != nil {
+			pane.logView.SetTitle(pane.title())
+			pv.appView.SetFocus(pane.logView)
+		} else {
+			pv.logsText.SetTitle(pv.getLogTitle(pv.getSelectedProcName()))
+		}
 		pv.updateHelpTextView()
 	}
 	f.AddButton("Search", searchFunc)
@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// resourceBucket averages the resource samples taken during a slice of time.
+// A bucket without samples has a NaN usage.
+type resourceBucket struct {
+	cpu      float64
+	mem      float64
+	restarts int
+}
+
+// resourceBuckets spreads the samples of the window ending at end into n
+// buckets of the same duration, oldest first.
+func resourceBuckets(samples []types.ResourceSample, end time.Time, window time.Duration, n int) []resourceBucket {
+	buckets := make([]resourceBucket, n)
+	counts := make([]int, n)
+	start := end.Add(-window)
+	for _, s := range samples {
+		if s.Time.Before(start) || s.Time.After(end) {
+			continue
+		}
+		i := min(int(s.Time.Sub(start)*time.Duration(n)/window), n-1)
+		buckets[i].cpu += s.CPU
+		buckets[i].mem += float64(s.Mem)
+		buckets[i].restarts = max(buckets[i].restarts, s.Restarts)
+		counts[i]++
+	}
+	for i := range buckets {
+		if counts[i] == 0 {
+			buckets[i].cpu, buckets[i].mem = math.NaN(), math.NaN()
+			continue
+		}
+		buckets[i].cpu /= float64(counts[i])
+		buckets[i].mem /= float64(counts[i])
+	}
+	return buckets
+}
+
+func bucketValues(buckets []resourceBucket, value func(b resourceBucket) float64) []float64 {
+	values := make([]float64, len(buckets))
+	for i, b := range buckets {
+		values[i] = value(b)
+	}
+	return values
+}
+
+// maxValue returns the largest value, ignoring the NaN ones.
+func maxValue(values []float64) float64 {
+	top := 0.0
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			top = max(top, v)
+		}
+	}
+	return top
+}
+
+// sparkline renders the values as a line of bars scaled from 0 to the largest
+// value. NaN values, with no sample, are left blank.
+func sparkline(values []float64) string {
+	top := maxValue(values)
+	var sb strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			sb.WriteRune(' ')
+		case top <= 0:
+			sb.WriteRune(sparkBlocks[0])
+		default:
+			level := int(v / top * float64(len(sparkBlocks)-1))
+			sb.WriteRune(sparkBlocks[min(max(level, 0), len(sparkBlocks)-1)])
+		}
+	}
+	return sb.String()
+}
+
+// barChart renders the values as vertical bars of height rows, scaled from 0
+// to top. The rows are returned from the top one down.
+func barChart(values []float64, height int, top float64) []string {
+	rows := make([][]rune, height)
+	for r := range rows {
+		rows[r] = []rune(strings.Repeat(" ", len(values)))
+	}
+	if top <= 0 {
+		return toStrings(rows)
+	}
+	for col, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		// the height of the bar in eighths of a row
+		eighths := int(math.Round(min(v/top, 1) * float64(height*len(sparkBlocks))))
+		for r := height - 1; r >= 0 && eighths > 0; r-- {
+			level := min(eighths, len(sparkBlocks))
+			rows[r][col] = sparkBlocks[level-1]
+			eighths -= level
+		}
+	}
+	return toStrings(rows)
+}
+
+func toStrings(rows [][]rune) []string {
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = string(row)
+	}
+	return lines
+}
@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestSparkline(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name   string
+		values []float64
+		want   string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:   "scaled to the largest value",
+			values: []float64{0, 1, 2, 3, 4, 5, 6, 7},
+			want:   "▁▂▃▄▅▆▇█",
+		},
+		{
+			name:   "idle",
+			values: []float64{0, 0, 0},
+			want:   "▁▁▁",
+		},
+		{
+			name:   "missing samples",
+			values: []float64{nan, 2, nan, 4},
+			want:   " ▄ █",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sparkline(tt.values); got != tt.want {
+				t.Errorf("sparkline() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBarChart(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		height int
+		top    float64
+		want   []string
+	}{
+		{
+			name:   "partial rows",
+			values: []float64{0, 1, 2, 4},
+			height: 2,
+			top:    4,
+			want:   []string{"   █", " ▄██"},
+		},
+		{
+			name:   "clipped to the top",
+			values: []float64{8},
+			height: 1,
+			top:    4,
+			want:   []string{"█"},
+		},
+		{
+			name:   "no usage",
+			values: []float64{0, 0},
+			height: 1,
+			top:    0,
+			want:   []string{"  "},
+		},
+		{
+			name:   "missing samples",
+			values: []float64{math.NaN(), 4},
+			height: 1,
+			top:    4,
+			want:   []string{" █"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := barChart(tt.values, tt.height, tt.top); !slices.Equal(got, tt.want) {
+				t.Errorf("barChart() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceBuckets(t *testing.T) {
+	end := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	samples := []types.ResourceSample{
+		{Time: end.Add(-2 * time.Minute), CPU: 50, Mem: 100},
+		{Time: end.Add(-50 * time.Second), CPU: 10, Mem: 100, Restarts: 1},
+		{Time: end.Add(-45 * time.Second), CPU: 30, Mem: 300, Restarts: 1},
+		{Time: end, CPU: 5, Mem: 50, Restarts: 2},
+	}
+	buckets := resourceBuckets(samples, end, time.Minute, 3)
+	if len(buckets) != 3 {
+		t.Fatalf("resourceBuckets() returned %d buckets, want 3", len(buckets))
+	}
+	if buckets[0].cpu != 20 || buckets[0].mem != 200 || buckets[0].restarts != 1 {
+		t.Errorf("first bucket = %+v, want the average of the samples in the period", buckets[0])
+	}
+	if !math.IsNaN(buckets[1].cpu) || !math.IsNaN(buckets[1].mem) {
+		t.Errorf("second bucket = %+v, want no usage", buckets[1])
+	}
+	if buckets[2].cpu != 5 || buckets[2].restarts != 2 {
+		t.Errorf("last bucket = %+v, want the sample at the end", buckets[2])
+	}
+}
@@ -62,6 +62,23 @@ func (pv *pcView) setLogViewStyle(s *config.Styles) {
 	pv.logsTextArea.SetTitleColor(s.Body().SecondaryTextColor.Color())
 	pv.logsTextArea.SetBackgroundColor(s.BgColor())
 	pv.logsTextArea.SetTextStyle(tcell.StyleDefault.Background(s.BgColor()).Foreground(s.FgColor()))
+
+	pv.logPanesGrid.SetBackgroundColor(s.BgColor())
+	for _, pane := range pv.logPanes {
+		pv.setLogPaneStyle(pane, s)
+	}
+}
+
+func (pv *pcView) setLogPaneStyle(pane *logPane, s *config.Styles) {
+	if pane.termView != nil {
+		pane.termView.SetTitleColor(s.Body().SecondaryTextColor.Color())
+		pane.termView.SetBorderColor(s.BorderColor())
+		return
+	}
+	pane.logView.SetBorderColor(s.BorderColor())
+	pane.logView.SetTitleColor(s.Body().SecondaryTextColor.Color())
+	pane.logView.SetBackgroundColor(s.BgColor())
+	pane.logView.SetTextColor(s.FgColor())
 }
 
 func (pv *pcView) setHelpTextStyles(s *config.Styles) {
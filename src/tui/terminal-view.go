@@ -62,7 +62,8 @@ type TerminalView struct {
 	term          *AnsiTerminal
 	terminals     map[*os.File]*AnsiTerminal
 	activeReaders map[*os.File]bool // tracks PTYs with running readPty goroutines
-	lock          sync.Mutex
+	panePtys      map[*os.File]bool // tracks PTYs shown in the log panes
+	lock          *sync.Mutex
 	isRunning     bool
 	width         int
 	height        int
@@ -73,6 +74,7 @@ type TerminalView struct {
 	onFocus       func()
 	onBlur        func()
 	isScrolling   bool
+	isPane        bool
 
 	// Selection state
 	isSelecting        bool
@@ -91,6 +93,8 @@ func NewTerminalView(app *tview.Application) *TerminalView {
 		term:          NewAnsiTerminal(80, 24),
 		terminals:     make(map[*os.File]*AnsiTerminal),
 		activeReaders: make(map[*os.File]bool),
+		panePtys:      make(map[*os.File]bool),
+		lock:          &sync.Mutex{},
 		width:         80,
 		height:        24,
 		exitKey:       tcell.KeyCtrlA,
@@ -100,6 +104,27 @@ func NewTerminalView(app *tview.Application) *TerminalView {
 	return tv
 }
 
+// newTerminalPane creates a view of the terminals of owner, for a log pane.
+// The pane shares the terminal states and the PTY readers of owner, so a PTY
+// is never read twice and its history is the same in both views.
+func newTerminalPane(owner *TerminalView) *TerminalView {
+	tv := &TerminalView{
+		Box:           tview.NewBox().SetBorder(true),
+		app:           owner.app,
+		term:          NewAnsiTerminal(80, 24),
+		terminals:     owner.terminals,
+		activeReaders: owner.activeReaders,
+		panePtys:      owner.panePtys,
+		lock:          owner.lock,
+		width:         80,
+		height:        24,
+		exitKey:       owner.exitKey,
+		isPane:        true,
+	}
+	tv.SetTitleAlign(tview.AlignCenter)
+	return tv
+}
+
 func (t *TerminalView) SetOnEscape(handler func()) {
 	t.onEscape = handler
 }
@@ -184,6 +209,12 @@ func (t *TerminalView) SetPty(ptyFile *os.File) {
 		t.isRunning = false
 	}
 
+	if t.isPane {
+		delete(t.panePtys, t.pty)
+		if ptyFile != nil {
+			t.panePtys[ptyFile] = true
+		}
+	}
 	t.pty = ptyFile
 	if ptyFile != nil {
 		// Get current actual dimensions from the Box FIRST. If the pane is not
@@ -253,6 +284,12 @@ func (t *TerminalView) SetPty(ptyFile *os.File) {
 	}
 }
 
+func (t *TerminalView) getPty() *os.File {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.pty
+}
+
 // EnsureDraining starts a background reader for ptyFile if one is not already
 // running, so an interactive process's PTY is drained continuously even while
 // its pane is unfocused. Without this, once the kernel PTY buffer (~16KB on
@@ -351,8 +388,8 @@ func (t *TerminalView) readPty(ptyFile *os.File, term *AnsiTerminal) {
 
 			if n > 0 {
 				term.Write(buf[:n])
-				// Only trigger draw if this is the active PTY
-				return false, t.pty == ptyFile
+				// Only trigger draw if this is the active PTY or a pinned one
+				return false, t.pty == ptyFile || t.panePtys[ptyFile]
 			}
 			// Prevent spin loop on 0-byte reads
 			time.Sleep(10 * time.Millisecond)
@@ -683,6 +720,9 @@ func (t *TerminalView) Stop() {
 	t.isRunning = false
 	// Do NOT clear terminal state here, as we want to persist it in the map
 	// t.term = NewAnsiTerminal(t.width, t.height)
+	if t.isPane {
+		delete(t.panePtys, t.pty)
+	}
 	t.pty = nil
 }
 
@@ -1,6 +1,10 @@
 package tui
 
-import "time"
+import (
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/notify"
+)
 
 type Option func(view *pcView) error
 
@@ -60,6 +64,14 @@ func WithDetachOnSuccess(detachOnSuccess bool) Option {
 	}
 }
 
+// WithLogPanes pins the logs of the processes, and tiles them if isTiled.
+func WithLogPanes(processes []string, isTiled bool) Option {
+	return func(view *pcView) error {
+		view.setLogPanes(processes, isTiled)
+		return nil
+	}
+}
+
 func LoadExtraShortCutsPaths(paths []string) Option {
 	return func(view *pcView) error {
 		for _, path := range paths {
@@ -69,3 +81,14 @@ func LoadExtraShortCutsPaths(paths []string) Option {
 		return nil
 	}
 }
+
+// WithNotifications sends desktop notifications for the events of the monitored
+// processes, at most one per process and event every minInterval.
+func WithNotifications(method notify.Method, minInterval time.Duration) Option {
+	return func(view *pcView) error {
+		out := &screenWriter{}
+		view.appView.SetAfterDrawFunc(out.flush)
+		view.notifier = notify.New(method, minInterval, out)
+		return nil
+	}
+}
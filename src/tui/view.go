@@ -13,6 +13,7 @@ import (
 
 	"github.com/f1bonacc1/process-compose/src/client"
 	"github.com/f1bonacc1/process-compose/src/config"
+	"github.com/f1bonacc1/process-compose/src/notify"
 	"github.com/f1bonacc1/process-compose/src/types"
 	"github.com/f1bonacc1/process-compose/src/updater"
 	"github.com/gdamore/tcell/v2"
@@ -55,6 +56,9 @@ type pcView struct {
 	appView                *tview.Application
 	logsText               *LogView
 	termView               *TerminalView
+	logPanes               []*logPane
+	logPanesGrid           *tview.Grid
+	isLogTiled             bool
 	statusText             *tview.TextView
 	helpFooter             *tview.Flex
 	pages                  *tview.Pages
@@ -79,6 +83,7 @@ type pcView struct {
 	refreshRate            time.Duration
 	cancelFn               context.CancelFunc
 	cancelLogFn            context.CancelFunc
+	cancelPanesFn          context.CancelFunc
 	cancelSigFn            context.CancelFunc
 	ctxApp                 context.Context
 	cancelAppFn            context.CancelFunc
@@ -97,11 +102,13 @@ type pcView struct {
 	detachOnSuccess        bool
 	procStatesMtx          sync.Mutex
 	procStates             *types.ProcessesState
+	resourceHistories      map[string][]types.ResourceSample
 	attentionMessages      chan attentionMessage
 	attentionCancel        context.CancelFunc
 	errTuiStartup          error
 	monitor                *processMonitor
 	watchNotifier          *watchNotifier
+	notifier               *notify.Notifier
 	prevSelectedProc       string
 }
 
@@ -118,6 +125,7 @@ func newPcView(project app.IProject) *pcView {
 		procCountCell:  tview.NewTableCell(""),
 		procMemCpuCell: tview.NewTableCell(""),
 		mainGrid:       tview.NewGrid(),
+		logPanesGrid:   tview.NewGrid(),
 		logsTextArea:   tview.NewTextArea(),
 		logSelect:      false,
 		project:        project,
@@ -132,7 +140,7 @@ func newPcView(project app.IProject) *pcView {
 		// configuration
 		shortcuts:         newShortCuts(),
 		themes:            config.NewThemes(),
-		settings:          config.NewSettings(),
+		settings:          config.NewSettings().Load(),
 		attentionMessages: make(chan attentionMessage, 10),
 	}
 	pv.termView = NewTerminalView(pv.appView)
@@ -216,11 +224,20 @@ func (pv *pcView) setShortCutsActions() {
 		pv.redrawGrid()
 		pv.updateHelpTextView()
 	})
-	pv.shortcuts.setAction(ActionFollowLog, pv.toggleLogFollow)
+	pv.shortcuts.setAction(ActionFollowLog, func() {
+		if pane := pv.focusedLogPane(); pane != nil {
+			pane.toggleFollow()
+			pv.updateHelpTextView()
+			return
+		}
+		pv.toggleLogFollow()
+	})
 	pv.shortcuts.setAction(ActionWrapLog, func() {
-		pv.logsText.ToggleWrap()
+		pv.activeLogView().ToggleWrap()
 		pv.updateHelpTextView()
 	})
+	pv.shortcuts.setAction(ActionLogPin, pv.togglePinLog)
+	pv.shortcuts.setAction(ActionLogPanes, pv.toggleLogPanes)
 	pv.shortcuts.setAction(ActionLogPrettyPrint, func() {
 		pv.logsText.TogglePrettyPrint()
 		// Re-render current logs with new setting
@@ -249,20 +266,37 @@ func (pv *pcView) setShortCutsActions() {
 	})
 	pv.shortcuts.setAction(ActionProcessScale, pv.showScale)
 	pv.shortcuts.setAction(ActionProcessInfo, pv.showInfo)
+	pv.shortcuts.setAction(ActionProcessResources, pv.showResourcesDialog)
+	pv.shortcuts.setAction(ActionProcessTree, pv.showProcessTreeDialog)
 	if len(availableSignalOptions()) > 0 {
 		pv.shortcuts.setAction(ActionProcessSignal, pv.showSignalDialog)
 	}
 	pv.shortcuts.setAction(ActionLogFind, pv.showSearch)
+	pv.shortcuts.setAction(ActionLogFilter, pv.showLogFilter)
 	pv.shortcuts.setAction(ActionLogFindNext, func() {
+		if pane := pv.focusedLogPane(); pane != nil {
+			pane.logView.SearchNext()
+			pane.logView.SetTitle(pane.title())
+			return
+		}
 		pv.logsText.SearchNext()
 		pv.logsText.SetTitle(pv.getLogTitle(pv.getSelectedProcName()))
 	})
 	pv.shortcuts.setAction(ActionLogFindPrev, func() {
+		if pane := pv.focusedLogPane(); pane != nil {
+			pane.logView.SearchPrev()
+			pane.logView.SetTitle(pane.title())
+			return
+		}
 		pv.logsText.SearchPrev()
 		pv.logsText.SetTitle(pv.getLogTitle(pv.getSelectedProcName()))
 	})
 	pv.shortcuts.setAction(ActionLogFindExit, func() {
-		if pv.logsText.isSearchActive() {
+		if pane := pv.focusedLogPane(); pane != nil && pane.logView.isSearchActive() {
+			pane.logView.resetSearch()
+			pane.logView.SetTitle(pane.title())
+			pv.updateHelpTextView()
+		} else if pv.logsText.isSearchActive() {
 			pv.exitSearch()
 		} else if pv.procRegex != nil {
 			pv.resetProcessSearch()
@@ -289,11 +323,16 @@ func (pv *pcView) setShortCutsActions() {
 		pv.redrawGrid()
 	})
 	pv.shortcuts.setAction(ActionClearLog, func() {
+		if pane := pv.focusedLogPane(); pane != nil {
+			pane.logView.Clear()
+			pane.truncateLog()
+			return
+		}
 		pv.logsText.Clear()
 		pv.truncateLog()
 	})
 	pv.shortcuts.setAction(ActionMarkLog, func() {
-		pv.logsText.AddMark()
+		pv.activeLogView().AddMark()
 	})
 	pv.shortcuts.setAction(ActionEditProcess, func() {
 		pv.editSelectedProcess()
@@ -348,8 +387,8 @@ func (pv *pcView) loadThemes() {
 
 func (pv *pcView) onAppKey(event *tcell.EventKey) *tcell.EventKey {
 	if event.Key() == tcell.KeyCtrlC {
-		if pv.termView.HasFocus() && pv.termView.isRunning {
-			handler := pv.termView.InputHandler()
+		if term := pv.focusedTerminal(); term != nil && term.isRunning {
+			handler := term.InputHandler()
 			handler(event, nil)
 			return nil
 		}
@@ -361,7 +400,7 @@ func (pv *pcView) onAppKey(event *tcell.EventKey) *tcell.EventKey {
 }
 
 func (pv *pcView) onMainGridKey(event *tcell.EventKey) *tcell.EventKey {
-	if pv.termView.HasFocus() {
+	if pv.focusedTerminal() != nil {
 		return event
 	}
 	switch event.Key() {
@@ -371,7 +410,7 @@ func (pv *pcView) onMainGridKey(event *tcell.EventKey) *tcell.EventKey {
 		}
 		pv.shortcuts.ShortCutKeys[ActionLogSelection].actionFn()
 	case pv.shortcuts.ShortCutKeys[ActionLogFindExit].key:
-		if !pv.logsText.isSearchActive() && pv.procRegex == nil {
+		if !pv.activeLogView().isSearchActive() && pv.procRegex == nil {
 			return event
 		}
 		pv.shortcuts.ShortCutKeys[ActionLogFindExit].actionFn()
@@ -384,7 +423,7 @@ func (pv *pcView) onMainGridKey(event *tcell.EventKey) *tcell.EventKey {
 			}
 			pv.shortcuts.ShortCutKeys[ActionLogSelection].actionFn()
 		case pv.shortcuts.ShortCutKeys[ActionLogFindExit].rune:
-			if !pv.logsText.isSearchActive() && pv.procRegex == nil {
+			if !pv.activeLogView().isSearchActive() && pv.procRegex == nil {
 				return event
 			}
 			pv.shortcuts.ShortCutKeys[ActionLogFindExit].actionFn()
@@ -469,29 +508,6 @@ func (pv *pcView) showInfo() {
 	pv.showDialog(form, 0, 5+form.GetFormItemCount()*2)
 }
 
-func (pv *pcView) showGraphDialog() {
-	graph, err := pv.project.GetDependencyGraph()
-	if err != nil {
-		pv.showError(fmt.Sprintf("Failed to get dependency graph: %v", err))
-		return
-	}
-
-	states, err := pv.project.GetProcessesState()
-	if err != nil {
-		pv.showError(fmt.Sprintf("Failed to get process states: %v", err))
-		return
-	}
-
-	dialog := newGraphDialog(func() {
-		pv.pages.RemovePage(PageDialog)
-		pv.appView.SetFocus(pv.procTable)
-	})
-	dialog.buildTree(graph, states)
-
-	width := min(dialog.GetWidth(), 120)
-	pv.showDialog(dialog.TreeView, width, 30)
-}
-
 func (pv *pcView) handleShutDown() {
 	if pv.project.IsRemote() {
 		pv.attentionMessage("Detaching...", 0, true)
@@ -537,17 +553,21 @@ func (pv *pcView) recreateHelpDialog() {
 func (pv *pcView) updateHelpTextView() {
 	logScrBool := pv.scrSplitState != LogFull
 	procScrBool := pv.scrSplitState != ProcFull
+	logView, logFollow := pv.logsText, pv.logFollow
+	if pane := pv.focusedLogPane(); pane != nil {
+		logView, logFollow = pane.logView, pane.follow
+	}
 	pv.helpFooter.Clear()
 	defer pv.addFooterLinks()
-	if pv.termView.HasFocus() {
+	if term := pv.focusedTerminal(); term != nil {
 		exitKey := pv.shortcuts.ShortCutKeys[ActionTermExit].ShortCut
 		pv.shortcuts.addCustomButton(exitKey+",Esc", "Exit Interactive", pv.helpFooter)
-		if pv.termView.HasSelection() {
+		if term.HasSelection() {
 			pv.shortcuts.addCustomButton("Enter", "Copy Selection", pv.helpFooter)
 		}
 		return
 	}
-	if pv.logsText.isSearchActive() {
+	if logView.isSearchActive() {
 		pv.shortcuts.addButton(ActionLogFind, pv.helpFooter)
 		pv.shortcuts.addButton(ActionLogFindNext, pv.helpFooter)
 		pv.shortcuts.addButton(ActionLogFindPrev, pv.helpFooter)
@@ -560,12 +580,15 @@ func (pv *pcView) updateHelpTextView() {
 	pv.shortcuts.addButton(ActionHelp, pv.helpFooter)
 	pv.shortcuts.addCategory("LOGS:", pv.helpFooter)
 	pv.shortcuts.addToggleButton(ActionLogScreen, pv.helpFooter, logScrBool)
-	pv.shortcuts.addToggleButton(ActionFollowLog, pv.helpFooter, !pv.logFollow)
-	pv.shortcuts.addToggleButton(ActionWrapLog, pv.helpFooter, !pv.logsText.IsWrapOn())
+	pv.shortcuts.addToggleButton(ActionFollowLog, pv.helpFooter, !logFollow)
+	pv.shortcuts.addToggleButton(ActionWrapLog, pv.helpFooter, !logView.IsWrapOn())
 	if config.IsLogSelectionOn() {
 		pv.shortcuts.addToggleButton(ActionLogSelection, pv.helpFooter, !pv.logSelect)
 	}
 	pv.shortcuts.addButton(ActionLogFind, pv.helpFooter)
+	if len(pv.logPanes) >= minLogPanes {
+		pv.shortcuts.addToggleButton(ActionLogPanes, pv.helpFooter, !pv.isLogTiled)
+	}
 	pv.shortcuts.addCategory("PROCESS:", pv.helpFooter)
 	pv.shortcuts.addButton(ActionProcessScale, pv.helpFooter)
 	pv.shortcuts.addButton(ActionProcessInfo, pv.helpFooter)
@@ -605,6 +628,10 @@ func (pv *pcView) saveTuiState() {
 	pv.settings.Sort.By = columnNames[pv.stateSorter.sortByColumn]
 	pv.settings.Sort.IsReversed = !pv.stateSorter.isAsc
 	pv.settings.Theme = pv.styles.GetStyleName()
+	pv.settings.LogPanes = config.LogPanes{
+		Processes: pv.logPaneNames(),
+		IsTiled:   pv.isLogTiled,
+	}
 	err := pv.settings.Save()
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to save settings")
@@ -670,6 +697,10 @@ func (pv *pcView) halt() {
 		pv.cancelSigFn()
 		pv.cancelSigFn = nil
 	}
+	if pv.cancelPanesFn != nil {
+		pv.cancelPanesFn()
+		pv.cancelPanesFn = nil
+	}
 }
 
 // Resume restarts the app event loop.
@@ -677,17 +708,24 @@ func (pv *pcView) resume() {
 	var ctxTbl context.Context
 	var ctxLog context.Context
 	var ctxSig context.Context
+	var ctxPanes context.Context
 	ctxTbl, pv.cancelFn = context.WithCancel(context.Background())
 	ctxLog, pv.cancelLogFn = context.WithCancel(context.Background())
 	ctxSig, pv.cancelSigFn = context.WithCancel(context.Background())
+	ctxPanes, pv.cancelPanesFn = context.WithCancel(context.Background())
 
 	go pv.updateProcStates(ctxTbl)
 	go pv.updateTable(ctxTbl)
 	go pv.updateLogs(ctxLog)
+	go pv.updateLogPanes(ctxPanes)
 	go setSignal(ctxSig)
 }
 
 func (pv *pcView) changeFocus() {
+	if pv.isLogPanesShown() && pv.scrSplitState != ProcFull {
+		pv.changeLogPaneFocus()
+		return
+	}
 	if pv.procTable.HasFocus() {
 		name := pv.getSelectedProcName()
 		if pv.isInteractive(name) {
@@ -757,6 +795,28 @@ func Stop() {
 	}
 }
 
+// focusedTerminal returns the terminal view holding the focus, or nil.
+func (pv *pcView) focusedTerminal() *TerminalView {
+	if pv.termView.HasFocus() {
+		return pv.termView
+	}
+	for _, pane := range pv.logPanes {
+		if pane.termView != nil && pane.termView.HasFocus() {
+			return pane.termView
+		}
+	}
+	return nil
+}
+
+// activeLogView returns the logs the log actions apply to: the focused pane
+// of the tiled layout, or the logs of the selected process.
+func (pv *pcView) activeLogView() *LogView {
+	if pane := pv.focusedLogPane(); pane != nil {
+		return pane.logView
+	}
+	return pv.logsText
+}
+
 func (pv *pcView) isInteractive(name string) bool {
 	info, err := pv.project.GetProcessInfo(name)
 	if err != nil {
@@ -0,0 +1,150 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/container"
+	"github.com/f1bonacc1/process-compose/src/health"
+)
+
+// ContainerConfig runs the process as a container of a Docker or Podman
+// compatible engine, instead of a local command.
+type ContainerConfig struct {
+	Image string `yaml:"image" json:"image"`
+	// Name is the name of the container. It defaults to pc-<project>-<process>.
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	// Command and Entrypoint override the CMD and ENTRYPOINT of the image.
+	Command    []string `yaml:"command,omitempty" json:"command,omitempty"`
+	Entrypoint []string `yaml:"entrypoint,omitempty" json:"entrypoint,omitempty"`
+	// Ports are published as [[host_ip:]host_port:]container_port[/protocol].
+	Ports []string `yaml:"ports,omitempty" json:"ports,omitempty"`
+	// Volumes are bind mounts or named volumes, as source:target[:options].
+	// Relative sources are relative to the working_dir of the process.
+	Volumes     []string    `yaml:"volumes,omitempty" json:"volumes,omitempty"`
+	Environment Environment `yaml:"environment,omitempty" json:"environment,omitempty"`
+	Network     string      `yaml:"network,omitempty" json:"network,omitempty"`
+	User        string      `yaml:"user,omitempty" json:"user,omitempty"`
+	// Pull is the image pull policy: missing (default), always or never.
+	Pull string `yaml:"pull,omitempty" json:"pull,omitempty" jsonschema:"enum=missing,enum=always,enum=never"`
+	// Host is the engine socket, e.g. unix:///run/user/1000/podman/podman.sock.
+	// It defaults to DOCKER_HOST, then to the Docker and the Podman sockets.
+	Host string `yaml:"host,omitempty" json:"host,omitempty"`
+}
+
+// ContainerPort is a published port of a container.
+type ContainerPort struct {
+	HostIP        string
+	HostPort      string
+	ContainerPort string
+	Protocol      string
+}
+
+// Key returns the port as the engine API names it: 80/tcp.
+func (p ContainerPort) Key() string {
+	return p.ContainerPort + "/" + p.Protocol
+}
+
+// ParseContainerPort parses [[host_ip:]host_port:]container_port[/protocol].
+// A port without host port is published on a random host port.
+func ParseContainerPort(spec string) (ContainerPort, error) {
+	port := ContainerPort{Protocol: "tcp"}
+	rest := spec
+	if i := strings.LastIndex(rest, "/"); i >= 0 {
+		port.Protocol = strings.ToLower(rest[i+1:])
+		rest = rest[:i]
+		if port.Protocol != "tcp" && port.Protocol != "udp" && port.Protocol != "sctp" {
+			return port, fmt.Errorf("invalid port '%s': unknown protocol '%s'", spec, port.Protocol)
+		}
+	}
+	if i := strings.LastIndex(rest, ":"); i >= 0 {
+		port.ContainerPort = rest[i+1:]
+		rest = rest[:i]
+		if j := strings.LastIndex(rest, ":"); j >= 0 {
+			port.HostIP = strings.Trim(rest[:j], "[]")
+			port.HostPort = rest[j+1:]
+		} else {
+			port.HostPort = rest
+		}
+	} else {
+		port.ContainerPort = rest
+	}
+	if !isPortNumber(port.ContainerPort) {
+		return port, fmt.Errorf("invalid port '%s': invalid container port '%s'", spec, port.ContainerPort)
+	}
+	if port.HostPort != "" && !isPortNumber(port.HostPort) {
+		return port, fmt.Errorf("invalid port '%s': invalid host port '%s'", spec, port.HostPort)
+	}
+	if port.HostIP != "" && net.ParseIP(port.HostIP) == nil {
+		return port, fmt.Errorf("invalid port '%s': invalid host IP '%s'", spec, port.HostIP)
+	}
+	return port, nil
+}
+
+func isPortNumber(s string) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n > 0 && n <= 65535
+}
+
+func (c *ContainerConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if strings.TrimSpace(c.Image) == "" {
+		return errors.New("container.image is required")
+	}
+	switch c.Pull {
+	case "", container.PullMissing, container.PullAlways, container.PullNever:
+	default:
+		return fmt.Errorf("invalid container.pull '%s': expected missing, always or never", c.Pull)
+	}
+	for _, spec := range c.Ports {
+		if _, err := ParseContainerPort(spec); err != nil {
+			return fmt.Errorf("container.ports: %w", err)
+		}
+	}
+	if c.Host != "" {
+		if _, err := container.NewClient(c.Host); err != nil {
+			return err
+		}
+	}
+	for _, volume := range c.Volumes {
+		if source, target, _ := strings.Cut(volume, ":"); source == "" || target == "" {
+			return fmt.Errorf("invalid container volume '%s': expected source:target[:options]", volume)
+		}
+	}
+	return nil
+}
+
+// validateContainer checks that the process settings make sense for a
+// container.
+func (p *ProcessConfig) validateContainer() error {
+	if p.Container == nil {
+		return nil
+	}
+	if err := p.Container.Validate(); err != nil {
+		return err
+	}
+	switch {
+	case p.Command != "" || len(p.Entrypoint) > 0:
+		return errors.New("command and entrypoint can't be used with container, use container.command and container.entrypoint")
+	case p.IsDaemon, p.IsTty, p.IsInteractive, p.IsElevated, p.IsForeground:
+		return errors.New("is_daemon, is_tty, is_interactive, is_elevated and is_foreground can't be used with container")
+	case p.Resources != nil:
+		return errors.New("resources can't be used with container: the engine manages the container cgroup")
+	case p.Container.Name != "" && p.Replicas > 1:
+		return errors.New("container.name can't be used with replicas: the replicas need distinct containers")
+	case p.Hooks != (ProcessHooks{}):
+		return errors.New("hooks can't be used with container: they would run on the host, not in the container")
+	case hasExecProbe(p.ReadinessProbe), hasExecProbe(p.LivenessProbe):
+		return errors.New("exec probes can't be used with container: they would run on the host, not in the container; use http_get, tcp_socket or grpc")
+	}
+	return nil
+}
+
+func hasExecProbe(probe *health.Probe) bool {
+	return probe != nil && probe.Exec != nil
+}
@@ -0,0 +1,68 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/health"
+)
+
+func TestParseContainerPort(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    ContainerPort
+		wantErr bool
+	}{
+		{"80", ContainerPort{ContainerPort: "80", Protocol: "tcp"}, false},
+		{"8080:80", ContainerPort{HostPort: "8080", ContainerPort: "80", Protocol: "tcp"}, false},
+		{"127.0.0.1:8080:80", ContainerPort{HostIP: "127.0.0.1", HostPort: "8080", ContainerPort: "80", Protocol: "tcp"}, false},
+		{"[::1]:8080:80", ContainerPort{HostIP: "::1", HostPort: "8080", ContainerPort: "80", Protocol: "tcp"}, false},
+		{"5353:53/udp", ContainerPort{HostPort: "5353", ContainerPort: "53", Protocol: "udp"}, false},
+		{"127.0.0.1::80", ContainerPort{HostIP: "127.0.0.1", ContainerPort: "80", Protocol: "tcp"}, false},
+		{"80/icmp", ContainerPort{}, true},
+		{"http", ContainerPort{}, true},
+		{"70000:80", ContainerPort{}, true},
+		{"host:8080:80", ContainerPort{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseContainerPort(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseContainerPort() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseContainerPort() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessConfig_ValidateContainer(t *testing.T) {
+	tests := []struct {
+		name    string
+		proc    ProcessConfig
+		wantErr bool
+	}{
+		{"no container", ProcessConfig{Command: "sleep 1"}, false},
+		{"image", ProcessConfig{Container: &ContainerConfig{Image: "nginx:1.27", Ports: []string{"8080:80"}, Volumes: []string{"./html:/usr/share/nginx/html:ro"}}}, false},
+		{"no image", ProcessConfig{Container: &ContainerConfig{}}, true},
+		{"invalid pull", ProcessConfig{Container: &ContainerConfig{Image: "nginx", Pull: "sometimes"}}, true},
+		{"invalid port", ProcessConfig{Container: &ContainerConfig{Image: "nginx", Ports: []string{"http"}}}, true},
+		{"invalid volume", ProcessConfig{Container: &ContainerConfig{Image: "nginx", Volumes: []string{"/data"}}}, true},
+		{"invalid host", ProcessConfig{Container: &ContainerConfig{Image: "nginx", Host: "ssh://remote"}}, true},
+		{"command", ProcessConfig{Command: "nginx", Container: &ContainerConfig{Image: "nginx"}}, true},
+		{"tty", ProcessConfig{IsTty: true, Container: &ContainerConfig{Image: "nginx"}}, true},
+		{"resources", ProcessConfig{Resources: &ResourceLimits{}, Container: &ContainerConfig{Image: "nginx"}}, true},
+		{"name with replicas", ProcessConfig{Replicas: 2, Container: &ContainerConfig{Image: "nginx", Name: "web"}}, true},
+		{"hook", ProcessConfig{Hooks: ProcessHooks{PostStart: &HookConfig{Command: "./migrate"}}, Container: &ContainerConfig{Image: "postgres"}}, true},
+		{"exec readiness probe", ProcessConfig{ReadinessProbe: &health.Probe{Exec: &health.ExecProbe{Command: "pg_isready"}}, Container: &ContainerConfig{Image: "postgres"}}, true},
+		{"exec liveness probe", ProcessConfig{LivenessProbe: &health.Probe{Exec: &health.ExecProbe{Command: "pg_isready"}}, Container: &ContainerConfig{Image: "postgres"}}, true},
+		{"tcp probe", ProcessConfig{ReadinessProbe: &health.Probe{TcpSocket: &health.TcpSocketProbe{Port: "5432"}}, Container: &ContainerConfig{Image: "postgres"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.proc.validateContainer(); (err != nil) != tt.wantErr {
+				t.Errorf("validateContainer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package types
+
+import (
+	"errors"
+	"io"
+)
+
+// ExecRequest is a command to run in the context of a process: with its
+// environment, env_file and working directory.
+type ExecRequest struct {
+	// Command is the executable and its arguments. The executable is looked up
+	// in the PATH of the process environment.
+	Command []string `json:"command"`
+	// Tty runs the command in a pseudo-terminal of Size.
+	Tty  bool         `json:"tty,omitempty"`
+	Size TerminalSize `json:"size,omitzero"`
+}
+
+type TerminalSize struct {
+	Rows uint16 `json:"rows"`
+	Cols uint16 `json:"cols"`
+}
+
+// ExecStreams connects a command run with an ExecRequest. Stderr is not used
+// with a TTY: the output of the terminal goes to Stdout. Resize, when set,
+// receives the terminal size changes.
+type ExecStreams struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	Resize <-chan TerminalSize
+}
+
+func (r *ExecRequest) Validate() error {
+	if len(r.Command) == 0 || r.Command[0] == "" {
+		return errors.New("a command is required")
+	}
+	return nil
+}
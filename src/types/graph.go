@@ -67,6 +67,10 @@ func BuildDependencyGraph(processes Processes) *DependencyGraph {
 				condition = "started"
 			case ProcessConditionLogReady:
 				condition = "log_ready"
+			case ProcessConditionPortOpen:
+				condition = "port_open"
+			case ProcessConditionFileExists:
+				condition = "file_exists"
 			}
 
 			if depNode, exists := graph.AllNodes[depName]; exists {
@@ -0,0 +1,196 @@
+package types
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+// Layout of the SVG rendering, in pixels. The text is assumed to be monospace.
+const (
+	svgMargin    = 20
+	svgCharWidth = 8
+	svgPadding   = 12
+	svgNodeH     = 40
+	svgRowGap    = 20
+	svgColumnGap = 140
+)
+
+// ToDOT outputs the dependency graph in Graphviz DOT format. Nodes are filled
+// by status and edges are labeled with the dependency condition.
+func (g *DependencyGraph) ToDOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph dependencies {\n")
+	sb.WriteString("    rankdir=LR;\n")
+	sb.WriteString("    node [shape=box, style=\"rounded,filled\", fontname=\"monospace\"];\n")
+	names := g.sortedNames()
+	for _, name := range names {
+		node := g.AllNodes[name]
+		fmt.Fprintf(&sb, "    %q [label=%q, fillcolor=%q, color=%q];\n",
+			name, name+"\n"+nodeStatusText(node), statusFillColor(node.Status), healthBorderColor(node.IsReady))
+	}
+	for _, name := range names {
+		node := g.AllNodes[name]
+		for _, dep := range sortedDependencies(node) {
+			fmt.Fprintf(&sb, "    %q -> %q [label=%q];\n", name, dep, node.DependsOn[dep].Type)
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// ToSVG renders the dependency graph as a standalone SVG image, laid out from
+// left to right like the DOT and Mermaid outputs: a process is placed to the
+// left of everything it depends on.
+func (g *DependencyGraph) ToSVG() string {
+	names := g.sortedNames()
+	columns := g.columns()
+
+	textLen := 0
+	for _, name := range names {
+		textLen = max(textLen, len(name), len(nodeStatusText(g.AllNodes[name])))
+	}
+	nodeW := textLen*svgCharWidth + 2*svgPadding
+
+	type point struct{ x, y int }
+	positions := make(map[string]point, len(names))
+	rows := 0
+	for col, column := range columns {
+		for row, name := range column {
+			positions[name] = point{
+				x: svgMargin + col*(nodeW+svgColumnGap),
+				y: svgMargin + row*(svgNodeH+svgRowGap),
+			}
+		}
+		rows = max(rows, len(column))
+	}
+	width := 2*svgMargin + max(len(columns)*(nodeW+svgColumnGap)-svgColumnGap, 0)
+	height := 2*svgMargin + max(rows*(svgNodeH+svgRowGap)-svgRowGap, 0)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="monospace" font-size="13">`+"\n",
+		width, height, width, height)
+	sb.WriteString(`  <defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z" fill="#555"/></marker></defs>` + "\n")
+	sb.WriteString(`  <rect width="100%" height="100%" fill="white"/>` + "\n")
+	for _, name := range names {
+		node := g.AllNodes[name]
+		from := positions[name]
+		for _, dep := range sortedDependencies(node) {
+			to := positions[dep]
+			x1, y1 := from.x+nodeW, from.y+svgNodeH/2
+			x2, y2 := to.x, to.y+svgNodeH/2
+			mid := (x1 + x2) / 2
+			fmt.Fprintf(&sb, `  <path d="M %d %d C %d %d, %d %d, %d %d" fill="none" stroke="#555" marker-end="url(#arrow)"/>`+"\n",
+				x1, y1, mid, y1, mid, y2, x2, y2)
+			fmt.Fprintf(&sb, `  <text x="%d" y="%d" text-anchor="middle" fill="#555" font-size="11">%s</text>`+"\n",
+				mid, (y1+y2)/2-4, html.EscapeString(node.DependsOn[dep].Type))
+		}
+	}
+	for _, name := range names {
+		node := g.AllNodes[name]
+		pos := positions[name]
+		fmt.Fprintf(&sb, `  <g class="process" data-process="%s"><title>%s</title>`+"\n", html.EscapeString(name), html.EscapeString(name))
+		fmt.Fprintf(&sb, `    <rect x="%d" y="%d" width="%d" height="%d" rx="6" fill="%s" stroke="%s" stroke-width="2"/>`+"\n",
+			pos.x, pos.y, nodeW, svgNodeH, statusFillColor(node.Status), healthBorderColor(node.IsReady))
+		fmt.Fprintf(&sb, `    <text x="%d" y="%d" text-anchor="middle" font-weight="bold">%s</text>`+"\n",
+			pos.x+nodeW/2, pos.y+17, html.EscapeString(name))
+		fmt.Fprintf(&sb, `    <text x="%d" y="%d" text-anchor="middle" font-size="11">%s</text>`+"\n",
+			pos.x+nodeW/2, pos.y+32, html.EscapeString(nodeStatusText(node)))
+		sb.WriteString("  </g>\n")
+	}
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+// columns splits the nodes into the columns of the SVG layout. A process is
+// placed one column to the left of its furthest dependency, so that every
+// edge points to the right. Each column is sorted by name.
+func (g *DependencyGraph) columns() [][]string {
+	depth := make(map[string]int, len(g.AllNodes))
+	var visit func(name string, path map[string]bool) int
+	visit = func(name string, path map[string]bool) int {
+		if d, ok := depth[name]; ok {
+			return d
+		}
+		d := 0
+		path[name] = true
+		if node, ok := g.AllNodes[name]; ok {
+			for dep := range node.DependsOn {
+				// A cycle is rejected at load time, guard against it anyway
+				if !path[dep] {
+					d = max(d, visit(dep, path)+1)
+				}
+			}
+		}
+		delete(path, name)
+		depth[name] = d
+		return d
+	}
+	maxDepth := 0
+	for _, name := range g.sortedNames() {
+		maxDepth = max(maxDepth, visit(name, map[string]bool{}))
+	}
+	if len(depth) == 0 {
+		return nil
+	}
+	columns := make([][]string, maxDepth+1)
+	for _, name := range g.sortedNames() {
+		col := maxDepth - depth[name]
+		columns[col] = append(columns[col], name)
+	}
+	return columns
+}
+
+func (g *DependencyGraph) sortedNames() []string {
+	names := make([]string, 0, len(g.AllNodes))
+	for name := range g.AllNodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedDependencies(node *DependencyNode) []string {
+	deps := make([]string, 0, len(node.DependsOn))
+	for dep := range node.DependsOn {
+		deps = append(deps, dep)
+	}
+	sort.Strings(deps)
+	return deps
+}
+
+// nodeStatusText is the status of the node, followed by its health when the
+// process has a readiness probe.
+func nodeStatusText(node *DependencyNode) string {
+	if node.IsReady == "" || node.IsReady == ProcessHealthUnknown {
+		return node.Status
+	}
+	return node.Status + ", " + node.IsReady
+}
+
+func statusFillColor(status string) string {
+	switch status {
+	case ProcessStateRunning, ProcessStateLaunched:
+		return "#c8e6c9"
+	case ProcessStateCompleted:
+		return "#bbdefb"
+	case ProcessStateError, ProcessStateOOMKilled, ProcessStateCrashLoopBackOff:
+		return "#ffcdd2"
+	case ProcessStatePending, ProcessStateLaunching, ProcessStateRestarting, ProcessStateScheduled:
+		return "#fff9c4"
+	default:
+		return "#eeeeee"
+	}
+}
+
+func healthBorderColor(health string) string {
+	switch health {
+	case ProcessHealthReady:
+		return "#2e7d32"
+	case ProcessHealthNotReady:
+		return "#c62828"
+	default:
+		return "#616161"
+	}
+}
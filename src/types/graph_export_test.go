@@ -0,0 +1,113 @@
+package types
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func exportTestGraph() *DependencyGraph {
+	processes := Processes{
+		"postgres": {Name: "postgres", ReplicaName: "postgres"},
+		"redis":    {Name: "redis", ReplicaName: "redis"},
+		"api": {Name: "api", ReplicaName: "api", DependsOn: DependsOnConfig{
+			"postgres": {Condition: ProcessConditionHealthy},
+			"redis":    {Condition: ProcessConditionStarted},
+		}},
+		"web": {Name: "web", ReplicaName: "web", DependsOn: DependsOnConfig{
+			"api":      {Condition: ProcessConditionLogReady},
+			"postgres": {Condition: ProcessConditionStarted},
+		}},
+	}
+	graph := BuildDependencyGraph(processes)
+	graph.AllNodes["api"].Status = ProcessStateRunning
+	graph.AllNodes["api"].IsReady = ProcessHealthNotReady
+	graph.AllNodes["postgres"].Status = ProcessStateRunning
+	graph.AllNodes["postgres"].IsReady = ProcessHealthReady
+	return graph
+}
+
+func TestDependencyGraph_ToDOT(t *testing.T) {
+	dot := exportTestGraph().ToDOT()
+
+	tests := []string{
+		"digraph dependencies {",
+		`"api" -> "postgres" [label="healthy"];`,
+		`"api" -> "redis" [label="started"];`,
+		`"web" -> "api" [label="log_ready"];`,
+		`"api" [label="api\nRunning, Not Ready", fillcolor="#c8e6c9", color="#c62828"];`,
+		`"redis" [label="redis\nPending", fillcolor="#fff9c4", color="#616161"];`,
+	}
+	for _, want := range tests {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT output should contain %s, got:\n%s", want, dot)
+		}
+	}
+}
+
+func TestDependencyGraph_Columns(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph *DependencyGraph
+		want  [][]string
+	}{
+		{
+			name:  "empty",
+			graph: NewDependencyGraph(),
+			want:  nil,
+		},
+		{
+			name:  "layered",
+			graph: exportTestGraph(),
+			want:  [][]string{{"web"}, {"api"}, {"postgres", "redis"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.graph.columns(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("columns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDependencyGraph_ToSVG(t *testing.T) {
+	graph := exportTestGraph()
+	svg := graph.ToSVG()
+
+	dec := xml.NewDecoder(strings.NewReader(svg))
+	rects, paths := 0, 0
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("SVG output is not well formed: %v\n%s", err, svg)
+		}
+		if el, ok := tok.(xml.StartElement); ok {
+			switch el.Name.Local {
+			case "rect":
+				rects++
+			case "path":
+				paths++
+			}
+		}
+	}
+	// the background, and a box per process
+	if rects != 1+len(graph.AllNodes) {
+		t.Errorf("expected %d rects, got %d", 1+len(graph.AllNodes), rects)
+	}
+	// the arrow marker, and an edge per dependency
+	if paths != 1+4 {
+		t.Errorf("expected %d paths, got %d", 1+4, paths)
+	}
+	for _, want := range []string{">healthy<", ">log_ready<", ">Running, Not Ready<"} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG output should contain %s", want)
+		}
+	}
+}
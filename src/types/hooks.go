@@ -0,0 +1,70 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	HookPreStart  = "pre_start"
+	HookPostStart = "post_start"
+	HookPreStop   = "pre_stop"
+	HookPostStop  = "post_stop"
+
+	// EnvVarExitCode holds the exit code of the process in the post_stop hook.
+	EnvVarExitCode = "PC_EXIT_CODE"
+	// DefaultHookTimeout is the timeout of a hook without timeout_seconds.
+	DefaultHookTimeout = 60
+)
+
+// ProcessHooks are the commands run around the lifecycle of a process, with
+// its environment and working directory.
+type ProcessHooks struct {
+	// PreStart runs before every launch of the process. The process does not
+	// start when it fails.
+	PreStart *HookConfig `yaml:"pre_start,omitempty" json:"preStart,omitempty"`
+	// PostStart runs once the process is ready, or right after its launch when
+	// it has no readiness probe or ready_log_line.
+	PostStart *HookConfig `yaml:"post_start,omitempty" json:"postStart,omitempty"`
+	// PreStop runs before the process is stopped, ahead of its shutdown
+	// command or signal.
+	PreStop *HookConfig `yaml:"pre_stop,omitempty" json:"preStop,omitempty"`
+	// PostStop runs after every exit of the process, with PC_EXIT_CODE set.
+	PostStop *HookConfig `yaml:"post_stop,omitempty" json:"postStop,omitempty"`
+}
+
+type HookConfig struct {
+	Command        string `yaml:"command" json:"command"`
+	TimeoutSeconds int    `yaml:"timeout_seconds,omitempty" json:"timeoutSeconds,omitempty"`
+}
+
+// Timeout returns the timeout of the hook in seconds.
+func (h *HookConfig) Timeout() int {
+	if h.TimeoutSeconds > 0 {
+		return h.TimeoutSeconds
+	}
+	return DefaultHookTimeout
+}
+
+func (h ProcessHooks) Validate() error {
+	for _, hook := range []struct {
+		name string
+		conf *HookConfig
+	}{
+		{HookPreStart, h.PreStart},
+		{HookPostStart, h.PostStart},
+		{HookPreStop, h.PreStop},
+		{HookPostStop, h.PostStop},
+	} {
+		if hook.conf == nil {
+			continue
+		}
+		if strings.TrimSpace(hook.conf.Command) == "" {
+			return fmt.Errorf("hooks.%s requires a command", hook.name)
+		}
+		if hook.conf.TimeoutSeconds < 0 {
+			return fmt.Errorf("invalid hooks.%s timeout_seconds %d: must not be negative", hook.name, hook.conf.TimeoutSeconds)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package types
+
+import "testing"
+
+func TestProcessHooksValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		hooks   ProcessHooks
+		wantErr bool
+	}{
+		{"empty", ProcessHooks{}, false},
+		{"all", ProcessHooks{
+			PreStart:  &HookConfig{Command: "migrate"},
+			PostStart: &HookConfig{Command: "seed", TimeoutSeconds: 5},
+			PreStop:   &HookConfig{Command: "drain"},
+			PostStop:  &HookConfig{Command: "cleanup"},
+		}, false},
+		{"missing command", ProcessHooks{PostStop: &HookConfig{Command: " "}}, true},
+		{"negative timeout", ProcessHooks{PreStart: &HookConfig{Command: "migrate", TimeoutSeconds: -1}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.hooks.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProjectHooksValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		hooks   ProjectHooks
+		wantErr bool
+	}{
+		{"empty", ProjectHooks{}, false},
+		{"command and webhook", ProjectHooks{
+			OnReady:    []ProjectHook{{Command: "notify-send up"}},
+			OnFailure:  []ProjectHook{{Webhook: &WebhookConfig{URL: "http://localhost:9000/hook"}}},
+			OnShutdown: []ProjectHook{{Command: "notify-send down", TimeoutSeconds: 5}},
+		}, false},
+		{"neither", ProjectHooks{OnReady: []ProjectHook{{}}}, true},
+		{"both", ProjectHooks{OnReady: []ProjectHook{{Command: "x", Webhook: &WebhookConfig{URL: "http://localhost"}}}}, true},
+		{"invalid url", ProjectHooks{OnFailure: []ProjectHook{{Webhook: &WebhookConfig{URL: "localhost:9000"}}}}, true},
+		{"negative timeout", ProjectHooks{OnShutdown: []ProjectHook{{Command: "x", TimeoutSeconds: -1}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.hooks.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
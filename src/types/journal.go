@@ -0,0 +1,82 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// JournalEventStatus records a process status transition.
+	JournalEventStatus = "status"
+	// JournalEventHealth records a readiness transition.
+	JournalEventHealth = "health"
+	// JournalEventExit records a process exit, including the ones followed by
+	// a restart.
+	JournalEventExit = "exit"
+	// JournalEventProbeFailure records a failed readiness or liveness check.
+	JournalEventProbeFailure = "probe_failure"
+	// JournalEventWatchTrigger records a file change that restarts a process.
+	JournalEventWatchTrigger = "watch_trigger"
+	// JournalEventRestart records a restart and what caused it.
+	JournalEventRestart = "restart"
+	// JournalEventHookFailure records a failed lifecycle hook.
+	JournalEventHookFailure = "hook_failure"
+)
+
+// JournalEvent is a single entry of the event journal.
+type JournalEvent struct {
+	// Seq increases by one with every event recorded to the journal, across
+	// process-compose runs.
+	Seq      uint64            `json:"seq"`
+	Time     time.Time         `json:"time"`
+	Process  string            `json:"process"`
+	Type     string            `json:"type"`
+	Status   string            `json:"status,omitempty"`
+	Health   string            `json:"health,omitempty"`
+	ExitCode *int              `json:"exit_code,omitempty"`
+	Reason   string            `json:"reason,omitempty"`
+	Details  map[string]string `json:"details,omitempty"`
+}
+
+// JournalEvents is the response of a journal query.
+type JournalEvents struct {
+	Events []JournalEvent `json:"data"`
+}
+
+// JournalQuery selects events from the journal. Zero fields do not filter.
+type JournalQuery struct {
+	Process string
+	Since   time.Time
+	// AfterSeq returns only the events recorded after the one with this
+	// sequence number.
+	AfterSeq uint64
+	// Limit keeps only the most recent events.
+	Limit int
+}
+
+// Matches reports whether ev is selected by the query, ignoring Limit.
+func (q *JournalQuery) Matches(ev *JournalEvent) bool {
+	if q.Process != "" && ev.Process != q.Process {
+		return false
+	}
+	if !q.Since.IsZero() && ev.Time.Before(q.Since) {
+		return false
+	}
+	return ev.Seq > q.AfterSeq
+}
+
+// ParseJournalSince accepts either a duration relative to now ("90m") or an
+// RFC 3339 timestamp.
+func ParseJournalSince(since string, now time.Time) (time.Time, error) {
+	if since == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(since); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %q: expected a duration or an RFC 3339 time", since)
+	}
+	return t, nil
+}
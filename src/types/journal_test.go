@@ -0,0 +1,31 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseJournalSince(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		since   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"", time.Time{}, false},
+		{"90m", now.Add(-90 * time.Minute), false},
+		{"2025-12-31T08:30:00Z", time.Date(2025, 12, 31, 8, 30, 0, 0, time.UTC), false},
+		{"yesterday", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.since, func(t *testing.T) {
+			got, err := ParseJournalSince(tt.since, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseJournalSince() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseJournalSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
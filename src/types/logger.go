@@ -35,3 +35,10 @@ type LoggerConfig struct {
 	// FlushEachLine flushes the logger on each line
 	FlushEachLine bool `yaml:"flush_each_line,omitempty" json:"flushEachLine,omitempty"`
 }
+
+// Structured log formats of the log_format process setting.
+const (
+	LogFormatJSON   = "json"
+	LogFormatLogfmt = "logfmt"
+	LogFormatRegex  = "regex"
+)
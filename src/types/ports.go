@@ -0,0 +1,82 @@
+package types
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// TemplateVarPorts is the template variable with the ports of the rendered
+	// process: {{ .ports.http }}.
+	TemplateVarPorts = "ports"
+	// TemplateVarProcessPorts is the template variable with the ports of every
+	// process in the project: {{ .process_ports.api.http }}.
+	TemplateVarProcessPorts = "process_ports"
+	// EnvPortPrefix prefixes the environment variables of the assigned ports:
+	// PC_PORT_HTTP.
+	EnvPortPrefix = "PC_PORT_"
+)
+
+// portNamePattern limits port names to the ones usable in templates and
+// environment variable names.
+var portNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// PortsConfig declares the ports of a process by name. A port of 0 is assigned
+// a free port when the project is loaded.
+type PortsConfig map[string]int
+
+func (p PortsConfig) Validate() error {
+	for name, port := range p {
+		if !portNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid port name '%s': expected letters, digits and underscores", name)
+		}
+		if port < 0 || port > 65535 {
+			return fmt.Errorf("invalid port %d for '%s': ports must be in the range 0-65535", port, name)
+		}
+	}
+	return nil
+}
+
+// PortEnvName returns the environment variable of an assigned port.
+func PortEnvName(name string) string {
+	return EnvPortPrefix + strings.ToUpper(name)
+}
+
+// FormatAssigned lists the assigned ports as name=port, sorted by name.
+func (p *ProcessPorts) FormatAssigned() string {
+	names := slices.Sorted(maps.Keys(p.Assigned))
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf("%s=%d", name, p.Assigned[name])
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// TemplateVars returns the project vars with the assigned ports of each
+// process, by replica name, as the process_ports variable. extra adds the
+// processes that are not in the project yet, such as new replicas.
+func (p *Project) TemplateVars(extra ...ProcessConfig) Vars {
+	ports := map[string]map[string]int{}
+	for name, proc := range p.Processes {
+		if len(proc.AssignedPorts) > 0 {
+			ports[name] = proc.AssignedPorts
+		}
+	}
+	for _, proc := range extra {
+		if len(proc.AssignedPorts) > 0 {
+			ports[proc.ReplicaName] = proc.AssignedPorts
+		}
+	}
+	if len(ports) == 0 {
+		return p.Vars
+	}
+	vars := maps.Clone(p.Vars)
+	if vars == nil {
+		vars = Vars{}
+	}
+	vars[TemplateVarProcessPorts] = ports
+	return vars
+}
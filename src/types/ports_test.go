@@ -0,0 +1,72 @@
+package types
+
+import "testing"
+
+func TestPortsConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		ports   PortsConfig
+		wantErr bool
+	}{
+		{"empty", nil, false},
+		{"auto and fixed", PortsConfig{"http": 0, "grpc_port": 50051}, false},
+		{"negative", PortsConfig{"http": -1}, true},
+		{"out of range", PortsConfig{"http": 65536}, true},
+		{"dash in name", PortsConfig{"http-port": 0}, true},
+		{"leading digit", PortsConfig{"1http": 0}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.ports.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProjectTemplateVars(t *testing.T) {
+	project := &Project{
+		Vars: Vars{"ENV": "dev"},
+		Processes: Processes{
+			"api": {AssignedPorts: map[string]int{"http": 9000}},
+			"db":  {},
+		},
+	}
+	vars := project.TemplateVars()
+	ports, ok := vars[TemplateVarProcessPorts].(map[string]map[string]int)
+	if !ok || ports["api"]["http"] != 9000 || len(ports) != 1 {
+		t.Errorf("process_ports = %v, want the ports of api", vars[TemplateVarProcessPorts])
+	}
+	if vars["ENV"] != "dev" {
+		t.Errorf("project vars lost: %v", vars)
+	}
+	if _, ok := project.Vars[TemplateVarProcessPorts]; ok {
+		t.Error("TemplateVars() modified the project vars")
+	}
+	if got := PortEnvName("http"); got != "PC_PORT_HTTP" {
+		t.Errorf("PortEnvName() = %q", got)
+	}
+}
+
+func TestProjectTemplateVars_Extra(t *testing.T) {
+	project := &Project{
+		Processes: Processes{
+			"web-0": {ReplicaName: "web-0", AssignedPorts: map[string]int{"http": 8080}},
+		},
+	}
+	vars := project.TemplateVars(ProcessConfig{ReplicaName: "web-1", AssignedPorts: map[string]int{"http": 8081}})
+	ports, _ := vars[TemplateVarProcessPorts].(map[string]map[string]int)
+	if ports["web-0"]["http"] != 8080 || ports["web-1"]["http"] != 8081 {
+		t.Errorf("process_ports = %v, want web-0 and the new web-1", ports)
+	}
+	if _, ok := project.Processes["web-1"]; ok {
+		t.Error("TemplateVars() added the extra process to the project")
+	}
+}
+
+func TestProcessPortsFormatAssigned(t *testing.T) {
+	ports := &ProcessPorts{Assigned: map[string]int{"metrics": 9100, "http": 8080}}
+	if got, want := ports.FormatAssigned(), "http=8080, metrics=9100"; got != want {
+		t.Errorf("FormatAssigned() = %q, want %q", got, want)
+	}
+}
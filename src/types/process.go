@@ -1,10 +1,12 @@
 package types
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"os"
 	"reflect"
+	"regexp"
 	"slices"
 	"strings"
 	"time"
@@ -32,6 +34,11 @@ type (
 		Entrypoint              []string            `yaml:"entrypoint,omitempty" json:"entrypoint,omitempty"`
 		LogLocation             string              `yaml:"log_location,omitempty" json:"logLocation,omitempty"`
 		LoggerConfig            *LoggerConfig       `yaml:"log_configuration,omitempty" json:"loggerConfig,omitempty"`
+		LogFormat               string              `yaml:"log_format,omitempty" json:"logFormat,omitempty" jsonschema:"enum=json,enum=logfmt,enum=regex"`
+		LogRegex                string              `yaml:"log_regex,omitempty" json:"logRegex,omitempty"`
+		Ports                   PortsConfig         `yaml:"ports,omitempty" json:"ports,omitempty"`
+		AssignedPorts           map[string]int      `yaml:"-" json:"assignedPorts,omitempty"`
+		SecretEnvironment       Environment         `yaml:"-" json:"secretEnvironment,omitempty"`
 		Environment             Environment         `yaml:"environment,omitempty" json:"environment,omitempty"`
 		EnvFile                 string              `yaml:"env_file,omitempty" json:"envFile,omitempty"`
 		RestartPolicy           RestartPolicyConfig `yaml:"availability,omitempty" json:"restartPolicy"`
@@ -40,9 +47,11 @@ type (
 		ReadinessProbe          *health.Probe       `yaml:"readiness_probe,omitempty" json:"readinessProbe,omitempty"`
 		ReadyLogLine            string              `yaml:"ready_log_line,omitempty" json:"readyLogLine,omitempty"`
 		ShutDownParams          ShutDownParams      `yaml:"shutdown,omitempty" json:"shutDownParams"`
+		Hooks                   ProcessHooks        `yaml:"hooks,omitempty" json:"hooks,omitzero"`
 		DisableAnsiColors       bool                `yaml:"disable_ansi_colors,omitempty" json:"disableAnsiColors,omitempty"`
 		WorkingDir              string              `yaml:"working_dir,omitempty" json:"workingDir,omitempty"`
 		Namespace               Namespaces          `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+		Profiles                []string            `yaml:"profiles,omitempty" json:"profiles,omitempty"`
 		Replicas                int                 `yaml:"replicas,omitempty" json:"replicas,omitempty"`
 		Extensions              map[string]any      `yaml:",inline" json:"extensions,omitempty"`
 		Description             string              `yaml:"description,omitempty" json:"description,omitempty"`
@@ -53,6 +62,7 @@ type (
 		IsInteractive           bool                `yaml:"is_interactive,omitempty" json:"isInteractive,omitempty"`
 		LaunchTimeout           int                 `yaml:"launch_timeout_seconds,omitempty" json:"launchTimeout,omitempty"`
 		IsDisabled              string              `yaml:"is_disabled,omitempty" json:"isDisabled,omitempty"`
+		EnabledIf               string              `yaml:"enabled_if,omitempty" json:"enabledIf,omitempty"`
 		DisableDotEnv           bool                `yaml:"is_dotenv_disabled,omitempty" json:"disableDotEnv,omitempty"`
 		OriginalConfig          string              `yaml:"original_config,omitempty" json:"originalConfig,omitempty"`
 		ReplicaNum              int                 `yaml:"replica_num,omitempty" json:"replicaNum,omitempty"`
@@ -67,6 +77,8 @@ type (
 		MonitorFor              MonitorFor          `yaml:"monitor_for,omitempty" json:"monitorFor,omitempty" jsonschema:"type=string,enum=none,enum=activity,enum=silence"`
 		MonitorSilenceThreshold time.Duration       `yaml:"monitor_silence_threshold,omitempty" json:"monitorSilenceThreshold,omitempty"`
 		SuccessExitCodes        []int               `yaml:"success_exit_codes,omitempty" json:"successExitCodes,omitempty"`
+		Resources               *ResourceLimits     `yaml:"resources,omitempty" json:"resources,omitempty"`
+		Container               *ContainerConfig    `yaml:"container,omitempty" json:"container,omitempty"`
 	}
 )
 
@@ -135,6 +147,8 @@ func (p *ProcessConfig) Compare(another *ProcessConfig) bool {
 		p.IsDaemon != another.IsDaemon ||
 		p.Command != another.Command ||
 		p.LogLocation != another.LogLocation ||
+		p.LogFormat != another.LogFormat ||
+		p.LogRegex != another.LogRegex ||
 		p.ReadyLogLine != another.ReadyLogLine ||
 		p.DisableAnsiColors != another.DisableAnsiColors ||
 		p.EnvFile != another.EnvFile ||
@@ -161,6 +175,7 @@ func (p *ProcessConfig) compareCompositeFields(another *ProcessConfig) bool {
 		{p.LivenessProbe, another.LivenessProbe},
 		{p.ReadinessProbe, another.ReadinessProbe},
 		{p.ShutDownParams, another.ShutDownParams},
+		{p.Hooks, another.Hooks},
 		{p.Vars, another.Vars},
 		{p.Extensions, another.Extensions},
 		{p.DependsOn, another.DependsOn},
@@ -169,6 +184,10 @@ func (p *ProcessConfig) compareCompositeFields(another *ProcessConfig) bool {
 		{p.Args, another.Args},
 		{p.Watch, another.Watch},
 		{p.SuccessExitCodes, another.SuccessExitCodes},
+		{p.Resources, another.Resources},
+		{p.Ports, another.Ports},
+		{p.Container, another.Container},
+		{p.Profiles, another.Profiles},
 	}
 	for _, field := range composites {
 		if !reflect.DeepEqual(field.a, field.b) {
@@ -212,6 +231,24 @@ func (p *ProcessConfig) ValidateProcessConfig() error {
 			return fmt.Errorf("invalid success_exit_codes value %d in process '%s': exit codes must be in the range 0-255", code, p.Name)
 		}
 	}
+	if err := p.Resources.Validate(); err != nil {
+		return fmt.Errorf("process '%s': %w", p.Name, err)
+	}
+	if err := p.RestartPolicy.Validate(); err != nil {
+		return fmt.Errorf("process '%s': %w", p.Name, err)
+	}
+	if err := p.validateLogFormat(); err != nil {
+		return fmt.Errorf("process '%s': %w", p.Name, err)
+	}
+	if err := p.Ports.Validate(); err != nil {
+		return fmt.Errorf("process '%s': %w", p.Name, err)
+	}
+	if err := p.Hooks.Validate(); err != nil {
+		return fmt.Errorf("process '%s': %w", p.Name, err)
+	}
+	if err := p.validateContainer(); err != nil {
+		return fmt.Errorf("process '%s': %w", p.Name, err)
+	}
 	if p.ShutDownParams.SendKeys != "" && !p.IsInteractive && !p.IsTty {
 		return fmt.Errorf("process '%s': shutdown.send_keys requires is_interactive (or is_tty)", p.Name)
 	}
@@ -228,6 +265,22 @@ func (p *ProcessConfig) ValidateProcessConfig() error {
 	return nil
 }
 
+func (p *ProcessConfig) validateLogFormat() error {
+	switch p.LogFormat {
+	case "", LogFormatJSON, LogFormatLogfmt:
+		return nil
+	case LogFormatRegex:
+		if p.LogRegex == "" {
+			return errors.New("log_format regex requires a log_regex")
+		}
+		if _, err := regexp.Compile(p.LogRegex); err != nil {
+			return fmt.Errorf("invalid log_regex: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown log_format %q: expected json, logfmt or regex", p.LogFormat)
+}
+
 func compareStructs(a, b any) []string {
 	var differences []string
 	aValue := reflect.ValueOf(a)
@@ -316,12 +369,41 @@ type ProcessState struct {
 	// ProcessEndTime is the wall-clock time the process ended (completed,
 	// errored, terminated, or was skipped).
 	ProcessEndTime *time.Time `json:"process_end_time,omitempty"`
+	// Limits are the resource limits in effect for the current incarnation,
+	// nil when the process has no `resources` block.
+	Limits *EffectiveLimits `json:"limits,omitempty"`
+	// OOMKills counts how many times the cgroup OOM killer fired for this
+	// process, across restarts. OOMKilled reports whether the last exit was
+	// one of them.
+	OOMKills  int  `json:"oom_kills,omitempty"`
+	OOMKilled bool `json:"oom_killed,omitempty"`
+	// ProbeStats counts the outcome of every readiness and liveness check,
+	// across restarts.
+	ProbeStats ProbeStats `json:"probe_stats,omitzero"`
+	// ScheduledRuns counts the runs started by the process schedule.
+	// ScheduledRunsSkipped counts the ones that were due but did not start,
+	// because max_concurrent was reached or the start failed.
+	ScheduledRuns        int `json:"scheduled_runs,omitempty"`
+	ScheduledRunsSkipped int `json:"scheduled_runs_skipped,omitempty"`
+	// FailedHook names the lifecycle hook that failed last, and HookError
+	// tells why. Both are cleared when that hook succeeds again.
+	FailedHook string `json:"failed_hook,omitempty"`
+	HookError  string `json:"hook_error,omitempty"`
+}
+
+type ProbeStats struct {
+	ReadinessSuccesses int64 `json:"readiness_successes"`
+	ReadinessFailures  int64 `json:"readiness_failures"`
+	LivenessSuccesses  int64 `json:"liveness_successes"`
+	LivenessFailures   int64 `json:"liveness_failures"`
 }
 
 type ProcessPorts struct {
 	Name     string   `json:"name"`
 	TcpPorts []uint16 `json:"tcp_ports"`
 	UdpPorts []uint16 `json:"udp_ports"`
+	// Assigned are the ports declared by the process, by name.
+	Assigned map[string]int `json:"assigned,omitempty"`
 }
 
 type ProcessesState struct {
@@ -335,6 +417,10 @@ type ProcessStateEvent struct {
 	// Snapshot is true for events emitted as part of the initial replay on
 	// subscribe, false for live transitions.
 	Snapshot bool `json:"snapshot,omitempty"`
+	// Stopped is true for the final event of a process that was stopped
+	// (by the user, a restart, a scale down or the project shutdown), rather
+	// than one that ended on its own.
+	Stopped bool `json:"stopped,omitempty"`
 	// State is a self-contained copy of the process state at the moment of
 	// the event.
 	State ProcessState `json:"state"`
@@ -410,7 +496,8 @@ func (p *ProcessState) IsReadyReason() (bool, string) {
 		p.Status != ProcessStateCompleted &&
 		p.Status != ProcessStateSkipped &&
 		p.Status != ProcessStateDisabled &&
-		p.Status != ProcessStateRestarting {
+		p.Status != ProcessStateRestarting &&
+		p.Status != ProcessStateCrashLoopBackOff {
 		return false, fmt.Sprintf("status is %s", p.Status)
 	} else if p.Status == ProcessStateDisabled {
 		return true, "process is disabled"
@@ -489,6 +576,11 @@ const (
 	ProcessStateError       = "Error"
 	ProcessStateScheduled   = "Scheduled"
 	ProcessStateWatching    = "Watching"
+	ProcessStateOOMKilled   = "OOMKilled"
+
+	// ProcessStateCrashLoopBackOff is a process waiting to restart after it
+	// failed too often within the crash loop window.
+	ProcessStateCrashLoopBackOff = "CrashLoopBackOff"
 )
 
 // Display a process status for the UI.
@@ -510,6 +602,9 @@ func DisplayProcessStatus(state ProcessState) string {
 	if state.IsWatched && state.IsWatchIdle() {
 		return ProcessStateWatching
 	}
+	if state.Status == ProcessStateCompleted && state.OOMKilled {
+		return ProcessStateOOMKilled
+	}
 	if state.Status == ProcessStateCompleted && !state.IsExitCodeSuccess() {
 		return "Failed"
 	}
@@ -528,6 +623,61 @@ type RestartPolicyConfig struct {
 	MaxRestarts    int           `yaml:"max_restarts,omitempty" json:"maxRestarts,omitempty"`
 	ExitOnEnd      bool          `yaml:"exit_on_end,omitempty" json:"exitOnEnd,omitempty"`
 	ExitOnSkipped  bool          `yaml:"exit_on_skipped,omitempty" json:"exitOnSkipped,omitempty"`
+	// BackoffMultiplier grows the backoff with every consecutive restart.
+	BackoffMultiplier float64 `yaml:"backoff_multiplier,omitempty" json:"backoffMultiplier,omitempty"`
+	// MaxBackoffSeconds caps the growing backoff. Defaults to
+	// DefaultMaxBackoffSeconds.
+	MaxBackoffSeconds int `yaml:"max_backoff_seconds,omitempty" json:"maxBackoffSeconds,omitempty"`
+	// BackoffJitter randomly spreads the backoff by up to this fraction of it,
+	// in either direction, so that processes failing together do not restart
+	// in lockstep.
+	BackoffJitter float64 `yaml:"backoff_jitter,omitempty" json:"backoffJitter,omitempty"`
+	// BackoffResetSeconds resets the backoff once the process ran for that
+	// long before exiting. Zero never resets it.
+	BackoffResetSeconds int              `yaml:"backoff_reset_seconds,omitempty" json:"backoffResetSeconds,omitempty"`
+	CrashLoop           *CrashLoopConfig `yaml:"crash_loop,omitempty" json:"crashLoop,omitempty"`
+}
+
+// DefaultMaxBackoffSeconds caps the backoff of a process with a
+// BackoffMultiplier and no MaxBackoffSeconds.
+const DefaultMaxBackoffSeconds = 300
+
+// Backoff returns the delay before a restart that follows attempt consecutive
+// restarts. random, in [0, 1), picks the jitter.
+func (r *RestartPolicyConfig) Backoff(attempt int, random float64) time.Duration {
+	backoff := float64(max(r.BackoffSeconds, 1))
+	if r.BackoffMultiplier > 1 {
+		maxBackoff := r.MaxBackoffSeconds
+		if maxBackoff <= 0 {
+			maxBackoff = DefaultMaxBackoffSeconds
+		}
+		backoff = min(backoff*math.Pow(r.BackoffMultiplier, float64(attempt)), float64(maxBackoff))
+	}
+	if r.BackoffJitter > 0 {
+		backoff *= 1 + r.BackoffJitter*(2*random-1)
+	}
+	return time.Duration(backoff * float64(time.Second))
+}
+
+// Validate checks the backoff and crash loop settings.
+func (r *RestartPolicyConfig) Validate() error {
+	if r.BackoffMultiplier != 0 && r.BackoffMultiplier < 1 {
+		return fmt.Errorf("invalid backoff_multiplier value %v: must be at least 1", r.BackoffMultiplier)
+	}
+	if r.BackoffJitter < 0 || r.BackoffJitter > 1 {
+		return fmt.Errorf("invalid backoff_jitter value %v: must be between 0 and 1", r.BackoffJitter)
+	}
+	if r.CrashLoop != nil && (r.CrashLoop.MaxFailures <= 0 || r.CrashLoop.WindowSeconds <= 0) {
+		return errors.New("crash_loop requires positive max_failures and window_seconds")
+	}
+	return nil
+}
+
+// CrashLoopConfig detects a process that keeps failing: once it failed
+// MaxFailures times within WindowSeconds, it is crash looping.
+type CrashLoopConfig struct {
+	MaxFailures   int `yaml:"max_failures" json:"maxFailures"`
+	WindowSeconds int `yaml:"window_seconds" json:"windowSeconds"`
 }
 
 type ShutDownParams struct {
@@ -552,6 +702,10 @@ const (
 	ProcessConditionStarted
 	// ProcessConditionLogReady is the type for waiting until a process has printed a predefined log line
 	ProcessConditionLogReady
+	// ProcessConditionPortOpen is the type for waiting until a TCP port accepts connections.
+	ProcessConditionPortOpen
+	// ProcessConditionFileExists is the type for waiting until a file is created.
+	ProcessConditionFileExists
 )
 
 func (c *ProcessCondition) UnmarshalYAML(node *yaml.Node) error {
@@ -570,6 +724,10 @@ func (c *ProcessCondition) UnmarshalYAML(node *yaml.Node) error {
 		*c = ProcessConditionStarted
 	case "process_log_ready":
 		*c = ProcessConditionLogReady
+	case "process_port_open":
+		*c = ProcessConditionPortOpen
+	case "file_exists":
+		*c = ProcessConditionFileExists
 	default:
 		return fmt.Errorf("invalid process dependency condition: %q", value)
 	}
@@ -588,6 +746,10 @@ func (c ProcessCondition) MarshalYAML() (any, error) {
 		return "process_started", nil
 	case ProcessConditionLogReady:
 		return "process_log_ready", nil
+	case ProcessConditionPortOpen:
+		return "process_port_open", nil
+	case ProcessConditionFileExists:
+		return "file_exists", nil
 	default:
 		return nil, fmt.Errorf("invalid process condition: %d", c)
 	}
@@ -637,8 +799,62 @@ func (m MonitorFor) MarshalYAML() (any, error) {
 type DependsOnConfig map[string]ProcessDependency
 
 type ProcessDependency struct {
-	Condition  ProcessCondition `yaml:",omitempty" json:"condition,omitempty" jsonschema:"type=string,enum=process_started,enum=process_healthy,enum=process_completed,enum=process_completed_successfully,enum=process_log_ready"`
-	Extensions map[string]any   `yaml:",inline" json:"extensions,omitempty"`
+	Condition ProcessCondition `yaml:",omitempty" json:"condition,omitempty" jsonschema:"type=string,enum=process_started,enum=process_healthy,enum=process_completed,enum=process_completed_successfully,enum=process_log_ready,enum=process_port_open,enum=file_exists"`
+	// Host and Port are the address awaited by the process_port_open
+	// condition. Host defaults to localhost.
+	Host string `yaml:"host,omitempty" json:"host,omitempty"`
+	Port int    `yaml:"port,omitempty" json:"port,omitempty"`
+	// Path is the file awaited by the file_exists condition. A relative path is
+	// resolved against the working directory of the dependent process.
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+	// Timeout bounds the wait for the condition, e.g. "30s". An empty timeout
+	// waits forever.
+	Timeout    string                  `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	OnTimeout  DependencyTimeoutAction `yaml:"on_timeout,omitempty" json:"onTimeout,omitempty" jsonschema:"type=string,enum=fail,enum=skip,enum=continue"`
+	Extensions map[string]any          `yaml:",inline" json:"extensions,omitempty"`
+}
+
+// GetTimeout returns the timeout of the wait for the condition, or 0 to wait
+// forever, including for a malformed value. Use GetTimeoutDuration to surface
+// a parse error; the loader validates it so that a bad value is reported at
+// load time.
+func (d ProcessDependency) GetTimeout() time.Duration {
+	timeout, err := d.GetTimeoutDuration()
+	if err != nil || timeout < 0 {
+		return 0
+	}
+	return timeout
+}
+
+// GetTimeoutDuration parses Timeout, reporting a malformed value as an error.
+func (d ProcessDependency) GetTimeoutDuration() (time.Duration, error) {
+	if d.Timeout == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(d.Timeout)
+}
+
+// DependencyTimeoutAction is what happens to a process when one of its
+// dependencies does not meet its condition within the timeout.
+type DependencyTimeoutAction string
+
+const (
+	// DependencyTimeoutFail fails the process without running it (default).
+	DependencyTimeoutFail DependencyTimeoutAction = "fail"
+	// DependencyTimeoutSkip skips the process, as for any other unmet dependency.
+	DependencyTimeoutSkip DependencyTimeoutAction = "skip"
+	// DependencyTimeoutContinue ignores the dependency and goes on.
+	DependencyTimeoutContinue DependencyTimeoutAction = "continue"
+)
+
+// IsValid checks if the timeout action is valid. The empty action is the
+// default, fail.
+func (a DependencyTimeoutAction) IsValid() bool {
+	switch a {
+	case "", DependencyTimeoutFail, DependencyTimeoutSkip, DependencyTimeoutContinue:
+		return true
+	}
+	return false
 }
 
 const (
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -176,6 +177,26 @@ func TestProcessStateIsReady(t *testing.T) {
 			},
 			isReady: false,
 		},
+		{
+			name: "crash loop back-off, exit failed, no health probe",
+			p: &ProcessState{
+				Status:         ProcessStateCrashLoopBackOff,
+				HasHealthProbe: false,
+				Health:         ProcessHealthUnknown,
+				ExitCode:       1,
+			},
+			isReady: false,
+		},
+		{
+			name: "crash loop back-off, exit ok, no health probe",
+			p: &ProcessState{
+				Status:         ProcessStateCrashLoopBackOff,
+				HasHealthProbe: false,
+				Health:         ProcessHealthUnknown,
+				ExitCode:       0,
+			},
+			isReady: true,
+		},
 		{
 			name: "terminating, no health probe",
 			p: &ProcessState{
@@ -406,6 +427,8 @@ func TestProcessConditionMarshalYAML(t *testing.T) {
 		{ProcessConditionHealthy, "process_healthy"},
 		{ProcessConditionStarted, "process_started"},
 		{ProcessConditionLogReady, "process_log_ready"},
+		{ProcessConditionPortOpen, "process_port_open"},
+		{ProcessConditionFileExists, "file_exists"},
 	}
 
 	for _, tt := range tests {
@@ -522,6 +545,16 @@ func TestDisplayProcessStatus(t *testing.T) {
 			},
 			expected: "Failed",
 		},
+		{
+			name: "completed after an OOM kill",
+			state: ProcessState{
+				Status:    ProcessStateCompleted,
+				IsRunning: false,
+				ExitCode:  137,
+				OOMKilled: true,
+			},
+			expected: ProcessStateOOMKilled,
+		},
 	}
 
 	for _, tt := range tests {
@@ -602,3 +635,109 @@ func TestValidateProcessConfigSendKeys(t *testing.T) {
 		t.Errorf("ValidateProcessConfig() expected error for send_keys without is_interactive, got nil")
 	}
 }
+
+func TestValidateProcessConfigLogFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		pattern string
+		wantErr bool
+	}{
+		{"none", "", "", false},
+		{"json", LogFormatJSON, "", false},
+		{"logfmt", LogFormatLogfmt, "", false},
+		{"regex", LogFormatRegex, `^(?P<level>\w+) (?P<message>.*)$`, false},
+		{"regex without pattern", LogFormatRegex, "", true},
+		{"invalid pattern", LogFormatRegex, "(", true},
+		{"unknown format", "xml", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &ProcessConfig{Name: "p", LogFormat: tt.format, LogRegex: tt.pattern}
+			if err := p.ValidateProcessConfig(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateProcessConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRestartPolicyBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RestartPolicyConfig
+		attempt int
+		random  float64
+		want    time.Duration
+	}{
+		{"default", RestartPolicyConfig{}, 3, 0.5, time.Second},
+		{"constant", RestartPolicyConfig{BackoffSeconds: 2}, 3, 0.5, 2 * time.Second},
+		{"first attempt", RestartPolicyConfig{BackoffSeconds: 2, BackoffMultiplier: 2}, 0, 0.5, 2 * time.Second},
+		{"exponential", RestartPolicyConfig{BackoffSeconds: 2, BackoffMultiplier: 2}, 3, 0.5, 16 * time.Second},
+		{"capped", RestartPolicyConfig{BackoffSeconds: 2, BackoffMultiplier: 2, MaxBackoffSeconds: 10}, 3, 0.5, 10 * time.Second},
+		{"default cap", RestartPolicyConfig{BackoffMultiplier: 2}, 100, 0.5, DefaultMaxBackoffSeconds * time.Second},
+		{"jitter low", RestartPolicyConfig{BackoffSeconds: 10, BackoffJitter: 0.2}, 0, 0, 8 * time.Second},
+		{"jitter high", RestartPolicyConfig{BackoffSeconds: 10, BackoffJitter: 0.2}, 0, 1, 12 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Backoff(tt.attempt, tt.random); got != tt.want {
+				t.Errorf("Backoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestartPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RestartPolicyConfig
+		wantErr bool
+	}{
+		{"empty", RestartPolicyConfig{}, false},
+		{"valid", RestartPolicyConfig{BackoffMultiplier: 2, BackoffJitter: 0.1, CrashLoop: &CrashLoopConfig{MaxFailures: 5, WindowSeconds: 60}}, false},
+		{"shrinking backoff", RestartPolicyConfig{BackoffMultiplier: 0.5}, true},
+		{"jitter above 1", RestartPolicyConfig{BackoffJitter: 1.5}, true},
+		{"crash loop without window", RestartPolicyConfig{CrashLoop: &CrashLoopConfig{MaxFailures: 5}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProcessDependency_GetTimeout(t *testing.T) {
+	tests := []struct {
+		timeout string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"30s", 30 * time.Second, false},
+		{"1m30s", 90 * time.Second, false},
+		{"30", 0, true},
+		{"-1s", 0, false},
+	}
+	for _, tt := range tests {
+		dep := ProcessDependency{Timeout: tt.timeout}
+		if _, err := dep.GetTimeoutDuration(); (err != nil) != tt.wantErr {
+			t.Errorf("GetTimeoutDuration(%q) error = %v, wantErr %v", tt.timeout, err, tt.wantErr)
+		}
+		if got := dep.GetTimeout(); got != tt.want {
+			t.Errorf("GetTimeout(%q) = %v, want %v", tt.timeout, got, tt.want)
+		}
+	}
+
+	// The timeout survives the JSON round trip of OriginalConfig and the API
+	// as it was written.
+	data, err := json.Marshal(ProcessDependency{Timeout: "30s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dep ProcessDependency
+	if err = json.Unmarshal(data, &dep); err != nil || dep.Timeout != "30s" {
+		t.Errorf("round trip of %s = %+v, %v", data, dep, err)
+	}
+}
@@ -0,0 +1,33 @@
+package types
+
+// ProcessTreeNode is an OS process, together with the processes it spawned.
+type ProcessTreeNode struct {
+	Pid     int    `json:"pid"`
+	Command string `json:"command"`
+	// Mem is the resident memory in bytes, and CPU the percentage of a core.
+	// Both are -1 when the usage of the OS process can't be read.
+	Mem      int64             `json:"mem"`
+	CPU      float64           `json:"cpu"`
+	TcpPorts []uint16          `json:"tcp_ports,omitempty"`
+	UdpPorts []uint16          `json:"udp_ports,omitempty"`
+	Children []ProcessTreeNode `json:"children,omitempty"`
+}
+
+// ProcessTree is the tree of the OS processes of a process, rooted at its PID.
+type ProcessTree struct {
+	Name string          `json:"name"`
+	Root ProcessTreeNode `json:"root"`
+}
+
+// Walk calls fn for the node and each of its descendants, depth first. The
+// depth of the node is 0.
+func (n *ProcessTreeNode) Walk(fn func(node *ProcessTreeNode, depth int)) {
+	n.walk(fn, 0)
+}
+
+func (n *ProcessTreeNode) walk(fn func(node *ProcessTreeNode, depth int), depth int) {
+	fn(n, depth)
+	for i := range n.Children {
+		n.Children[i].walk(fn, depth+1)
+	}
+}
@@ -13,11 +13,13 @@ func _() {
 	_ = x[ProcessConditionHealthy-2]
 	_ = x[ProcessConditionStarted-3]
 	_ = x[ProcessConditionLogReady-4]
+	_ = x[ProcessConditionPortOpen-5]
+	_ = x[ProcessConditionFileExists-6]
 }
 
-const _ProcessCondition_name = "ProcessConditionCompletedProcessConditionCompletedSuccessfullyProcessConditionHealthyProcessConditionStartedProcessConditionLogReady"
+const _ProcessCondition_name = "ProcessConditionCompletedProcessConditionCompletedSuccessfullyProcessConditionHealthyProcessConditionStartedProcessConditionLogReadyProcessConditionPortOpenProcessConditionFileExists"
 
-var _ProcessCondition_index = [...]uint8{0, 25, 62, 85, 108, 132}
+var _ProcessCondition_index = [...]uint8{0, 25, 62, 85, 108, 132, 156, 182}
 
 func (i ProcessCondition) String() string {
 	idx := int(i) - 0
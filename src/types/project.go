@@ -26,9 +26,12 @@ type Project struct {
 	IsTuiDisabled       bool                 `yaml:"is_tui_disabled,omitempty"`
 	ExtendsProject      string               `yaml:"extends,omitempty"`
 	EnvCommands         EnvCmd               `yaml:"env_cmds,omitempty"`
+	Secrets             Secrets              `yaml:"secrets,omitempty"`
+	Hooks               ProjectHooks         `yaml:"hooks,omitempty"`
 	IsOrderedShutdown   bool                 `yaml:"ordered_shutdown,omitempty"`
 	FileNames           []string             `yaml:"file_names,omitempty"`
 	EnvFileNames        []string             `yaml:"env_file_names,omitempty"`
+	ActiveProfiles      []string             `yaml:"active_profiles,omitempty"`
 	DotEnvVars          map[string]string    `yaml:"dot_env_vars,omitempty"`
 	Extensions          map[string]any       `yaml:",inline"`
 	MCPServer           *MCPServerConfig     `yaml:"mcp_server,omitempty"`
@@ -0,0 +1,109 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	ProjectHookOnReady    = "on_ready"
+	ProjectHookOnFailure  = "on_failure"
+	ProjectHookOnShutdown = "on_shutdown"
+
+	// EnvVarHook holds the name of the project hook that runs a command.
+	EnvVarHook = "PC_HOOK"
+)
+
+// ProjectHooks are the actions taken on project events:
+//   - OnReady once every process is ready,
+//   - OnFailure when a process fails for good: it ended in error, or completed
+//     with a failing exit code and won't be restarted,
+//   - OnShutdown when the project starts shutting down.
+type ProjectHooks struct {
+	OnReady    []ProjectHook `yaml:"on_ready,omitempty" json:"onReady,omitempty"`
+	OnFailure  []ProjectHook `yaml:"on_failure,omitempty" json:"onFailure,omitempty"`
+	OnShutdown []ProjectHook `yaml:"on_shutdown,omitempty" json:"onShutdown,omitempty"`
+}
+
+// ProjectHook runs a command, or calls a webhook. Both receive the
+// ProjectHookEvent as JSON: on the command's stdin, or as the request body.
+type ProjectHook struct {
+	Command        string         `yaml:"command,omitempty" json:"command,omitempty"`
+	Webhook        *WebhookConfig `yaml:"webhook,omitempty" json:"webhook,omitempty"`
+	TimeoutSeconds int            `yaml:"timeout_seconds,omitempty" json:"timeoutSeconds,omitempty"`
+}
+
+type WebhookConfig struct {
+	URL     string            `yaml:"url" json:"url"`
+	Method  string            `yaml:"method,omitempty" json:"method,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+}
+
+// ProjectHookEvent is the payload of the project hooks.
+type ProjectHookEvent struct {
+	Hook    string    `json:"hook"`
+	Project string    `json:"project,omitempty"`
+	Time    time.Time `json:"time"`
+	// Event is the process state event that triggered the hook. It is not set
+	// for on_shutdown.
+	Event *ProcessStateEvent `json:"event,omitempty"`
+}
+
+// Get returns the actions of a project hook.
+func (h ProjectHooks) Get(hook string) []ProjectHook {
+	switch hook {
+	case ProjectHookOnReady:
+		return h.OnReady
+	case ProjectHookOnFailure:
+		return h.OnFailure
+	case ProjectHookOnShutdown:
+		return h.OnShutdown
+	}
+	return nil
+}
+
+// IsEmpty reports whether no project hook is configured.
+func (h ProjectHooks) IsEmpty() bool {
+	return len(h.OnReady) == 0 && len(h.OnFailure) == 0 && len(h.OnShutdown) == 0
+}
+
+func (h ProjectHooks) Validate() error {
+	var errs []error
+	for _, name := range []string{ProjectHookOnReady, ProjectHookOnFailure, ProjectHookOnShutdown} {
+		for i, hook := range h.Get(name) {
+			if err := hook.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("hooks.%s[%d]: %w", name, i, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *ProjectHook) Validate() error {
+	hasCommand := strings.TrimSpace(h.Command) != ""
+	if hasCommand == (h.Webhook != nil) {
+		return errors.New("exactly one of command or webhook is required")
+	}
+	if h.TimeoutSeconds < 0 {
+		return fmt.Errorf("invalid timeout_seconds %d: must not be negative", h.TimeoutSeconds)
+	}
+	if h.Webhook == nil {
+		return nil
+	}
+	u, err := url.Parse(h.Webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook url '%s': expected an http or https URL", h.Webhook.URL)
+	}
+	return nil
+}
+
+// Timeout returns the timeout of the hook in seconds.
+func (h *ProjectHook) Timeout() int {
+	if h.TimeoutSeconds > 0 {
+		return h.TimeoutSeconds
+	}
+	return DefaultHookTimeout
+}
@@ -0,0 +1,25 @@
+package types
+
+import "time"
+
+// ResourceSample is the resource usage of a process at a point in time.
+type ResourceSample struct {
+	Time time.Time `json:"time"`
+	// Mem is the resident memory in bytes, and CPU the percentage of a core
+	// used since the previous sample.
+	Mem      int64   `json:"mem"`
+	CPU      float64 `json:"cpu"`
+	Restarts int     `json:"restarts"`
+}
+
+// ResourceHistory is the resource usage of a process over time, oldest
+// sample first.
+type ResourceHistory struct {
+	Name    string           `json:"name"`
+	Samples []ResourceSample `json:"samples"`
+}
+
+// ResourceHistories is the response of a resource history query.
+type ResourceHistories struct {
+	Histories []ResourceHistory `json:"data"`
+}
@@ -0,0 +1,112 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// LimitsEnforcerCgroup - limits are enforced by a dedicated cgroup v2 leaf.
+	LimitsEnforcerCgroup = "cgroup"
+	// LimitsEnforcerRlimit - cgroups are not delegated to process-compose, so
+	// only the limits expressible with setrlimit are enforced.
+	LimitsEnforcerRlimit = "rlimit"
+	// LimitsEnforcerNone - resource limits are not supported on this platform.
+	LimitsEnforcerNone = "none"
+)
+
+// ResourceLimits caps what a process may consume.
+//
+// On Linux, with a cgroup v2 hierarchy delegated to process-compose, every
+// limit is enforced by placing the process in its own cgroup leaf. Otherwise
+// process-compose falls back to setrlimit, which can only enforce MemoryMax
+// (as an address space limit) and NoFile.
+type ResourceLimits struct {
+	// MemoryMax is the memory ceiling, e.g. "512M" or "2G". Suffixes are
+	// binary (K = 1024). A plain number is a byte count.
+	//
+	// This is a string rather than an integer for the same reasons
+	// WatchConfig.Debounce is: it survives the JSON round trip of
+	// OriginalConfig and is typed as a string in the generated schema.
+	MemoryMax string `yaml:"memory_max,omitempty" json:"memory_max,omitempty"`
+
+	// CPUQuota is the CPU bandwidth the process may use, as a percentage of a
+	// single CPU, e.g. "50%" or "200%" for two full CPUs.
+	CPUQuota string `yaml:"cpu_quota,omitempty" json:"cpu_quota,omitempty"`
+
+	// PidsMax caps the number of processes and threads in the process tree.
+	// Enforced by cgroups only.
+	PidsMax int `yaml:"pids_max,omitempty" json:"pids_max,omitempty"`
+
+	// NoFile caps the number of open file descriptors (RLIMIT_NOFILE).
+	NoFile uint64 `yaml:"nofile,omitempty" json:"nofile,omitempty"`
+}
+
+// EffectiveLimits reports the limits that were actually applied to the
+// running process, which may be fewer than configured when process-compose had
+// to fall back to setrlimit.
+type EffectiveLimits struct {
+	// Enforcer is one of LimitsEnforcerCgroup, LimitsEnforcerRlimit or
+	// LimitsEnforcerNone.
+	Enforcer  string  `json:"enforcer"`
+	MemoryMax int64   `json:"memory_max,omitempty"`
+	CPUQuota  float64 `json:"cpu_quota,omitempty"`
+	PidsMax   int     `json:"pids_max,omitempty"`
+	NoFile    uint64  `json:"nofile,omitempty"`
+}
+
+// IsEnabled reports whether this config limits anything.
+func (r *ResourceLimits) IsEnabled() bool {
+	return r != nil && (r.MemoryMax != "" || r.CPUQuota != "" || r.PidsMax > 0 || r.NoFile > 0)
+}
+
+// GetMemoryMaxBytes parses MemoryMax. It returns 0 when no memory limit is set.
+func (r *ResourceLimits) GetMemoryMaxBytes() (int64, error) {
+	if r == nil || r.MemoryMax == "" {
+		return 0, nil
+	}
+	value := strings.ToUpper(strings.TrimSpace(r.MemoryMax))
+	multiplier := int64(1)
+	if unit := strings.IndexAny(value, "KMGT"); unit >= 0 && unit == len(value)-1 {
+		for range strings.Index("KMGT", value[unit:]) + 1 {
+			multiplier *= 1024
+		}
+		value = value[:unit]
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid memory_max value '%s' (expected a size such as '512M' or '2G')", r.MemoryMax)
+	}
+	return n * multiplier, nil
+}
+
+// GetCPUQuotaPercent parses CPUQuota. It returns 0 when no CPU limit is set.
+func (r *ResourceLimits) GetCPUQuotaPercent() (float64, error) {
+	if r == nil || r.CPUQuota == "" {
+		return 0, nil
+	}
+	value := strings.TrimSuffix(strings.TrimSpace(r.CPUQuota), "%")
+	percent, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || percent <= 0 {
+		return 0, fmt.Errorf("invalid cpu_quota value '%s' (expected a percentage such as '50%%' or '200%%')", r.CPUQuota)
+	}
+	return percent, nil
+}
+
+// Validate reports the first malformed limit.
+func (r *ResourceLimits) Validate() error {
+	if r == nil {
+		return nil
+	}
+	if _, err := r.GetMemoryMaxBytes(); err != nil {
+		return err
+	}
+	if _, err := r.GetCPUQuotaPercent(); err != nil {
+		return err
+	}
+	if r.PidsMax < 0 {
+		return fmt.Errorf("invalid pids_max value %d", r.PidsMax)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package types
+
+import "testing"
+
+func TestResourceLimits_GetMemoryMaxBytes(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{name: "unset", value: "", want: 0},
+		{name: "plain bytes", value: "1048576", want: 1 << 20},
+		{name: "kilobytes", value: "64K", want: 64 << 10},
+		{name: "megabytes", value: "512M", want: 512 << 20},
+		{name: "lowercase gigabytes", value: "2g", want: 2 << 30},
+		{name: "terabytes", value: "1T", want: 1 << 40},
+		{name: "surrounding spaces", value: " 256M ", want: 256 << 20},
+		{name: "unknown unit", value: "512X", wantErr: true},
+		{name: "unit first", value: "M512", wantErr: true},
+		{name: "zero", value: "0", wantErr: true},
+		{name: "negative", value: "-1G", wantErr: true},
+		{name: "blank", value: "  ", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ResourceLimits{MemoryMax: tt.value}
+			got, err := r.GetMemoryMaxBytes()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetMemoryMaxBytes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetMemoryMaxBytes() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceLimits_GetCPUQuotaPercent(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{name: "unset", value: "", want: 0},
+		{name: "percent", value: "50%", want: 50},
+		{name: "more than one cpu", value: "250%", want: 250},
+		{name: "without percent sign", value: "75", want: 75},
+		{name: "zero", value: "0%", wantErr: true},
+		{name: "garbage", value: "half", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ResourceLimits{CPUQuota: tt.value}
+			got, err := r.GetCPUQuotaPercent()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetCPUQuotaPercent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetCPUQuotaPercent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceLimits_IsEnabled(t *testing.T) {
+	var nilLimits *ResourceLimits
+	if nilLimits.IsEnabled() {
+		t.Errorf("IsEnabled() = true for nil limits")
+	}
+	if (&ResourceLimits{}).IsEnabled() {
+		t.Errorf("IsEnabled() = true for empty limits")
+	}
+	if !(&ResourceLimits{NoFile: 1024}).IsEnabled() {
+		t.Errorf("IsEnabled() = false for nofile limit")
+	}
+}
+
+func TestValidateProcessConfigResources(t *testing.T) {
+	valid := &ResourceLimits{MemoryMax: "512M", CPUQuota: "50%", PidsMax: 64, NoFile: 1024}
+	if err := (&ProcessConfig{Name: "p", Resources: valid}).ValidateProcessConfig(); err != nil {
+		t.Errorf("ValidateProcessConfig() unexpected error for valid limits: %v", err)
+	}
+	if err := (&ProcessConfig{Name: "p", Resources: &ResourceLimits{MemoryMax: "lots"}}).ValidateProcessConfig(); err == nil {
+		t.Errorf("ValidateProcessConfig() expected error for malformed memory_max, got nil")
+	}
+	if err := (&ProcessConfig{Name: "p", Resources: &ResourceLimits{PidsMax: -1}}).ValidateProcessConfig(); err == nil {
+		t.Errorf("ValidateProcessConfig() expected error for negative pids_max, got nil")
+	}
+}
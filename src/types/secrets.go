@@ -0,0 +1,89 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Secret providers.
+const (
+	SecretProviderFile    = "file"
+	SecretProviderSops    = "sops"
+	SecretProviderAge     = "age"
+	SecretProviderPass    = "pass"
+	SecretProviderKeyring = "keyring"
+	SecretProviderCommand = "command"
+)
+
+// RedactedValue replaces the value of a secret wherever it is displayed.
+const RedactedValue = "******"
+
+// SecretConfig describes where the value of a secret is fetched from. The
+// fields used depend on the provider:
+//
+//   - file: path, and key to pick a variable from a dotenv file.
+//   - sops: path, and key to extract a single value from the decrypted file.
+//   - age: path and identity.
+//   - pass: path, the name of the password store entry.
+//   - keyring: service and account.
+//   - command: command, whose output is the value.
+type SecretConfig struct {
+	Provider string `yaml:"provider" json:"provider" jsonschema:"enum=file,enum=sops,enum=age,enum=pass,enum=keyring,enum=command"`
+	Path     string `yaml:"path,omitempty" json:"path,omitempty"`
+	Key      string `yaml:"key,omitempty" json:"key,omitempty"`
+	Identity string `yaml:"identity,omitempty" json:"identity,omitempty"`
+	Service  string `yaml:"service,omitempty" json:"service,omitempty"`
+	Account  string `yaml:"account,omitempty" json:"account,omitempty"`
+	Command  string `yaml:"command,omitempty" json:"command,omitempty"`
+}
+
+func (s *SecretConfig) Validate() error {
+	switch s.Provider {
+	case SecretProviderFile, SecretProviderSops, SecretProviderPass:
+		if s.Path == "" {
+			return fmt.Errorf("the %s provider requires a path", s.Provider)
+		}
+	case SecretProviderAge:
+		if s.Path == "" || s.Identity == "" {
+			return errors.New("the age provider requires a path and an identity")
+		}
+	case SecretProviderKeyring:
+		if s.Service == "" || s.Account == "" {
+			return errors.New("the keyring provider requires a service and an account")
+		}
+	case SecretProviderCommand:
+		if s.Command == "" {
+			return errors.New("the command provider requires a command")
+		}
+	case "":
+		return errors.New("missing secret provider")
+	default:
+		return fmt.Errorf("unknown secret provider '%s'", s.Provider)
+	}
+	return nil
+}
+
+// Secrets maps the environment variables injected into the processes to the
+// secrets that hold their values.
+type Secrets map[string]SecretConfig
+
+func (s Secrets) Validate() error {
+	for _, name := range s.Names() {
+		secret := s[name]
+		if err := secret.Validate(); err != nil {
+			return fmt.Errorf("invalid secret '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Names returns the environment variables of the secrets, sorted.
+func (s Secrets) Names() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
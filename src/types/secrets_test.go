@@ -0,0 +1,30 @@
+package types
+
+import "testing"
+
+func TestSecretsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		secrets Secrets
+		wantErr bool
+	}{
+		{"empty", nil, false},
+		{"file", Secrets{"TOKEN": {Provider: SecretProviderFile, Path: "token.txt"}}, false},
+		{"age", Secrets{"TOKEN": {Provider: SecretProviderAge, Path: "token.age", Identity: "key.txt"}}, false},
+		{"keyring", Secrets{"TOKEN": {Provider: SecretProviderKeyring, Service: "api", Account: "me"}}, false},
+		{"command", Secrets{"TOKEN": {Provider: SecretProviderCommand, Command: "vault read"}}, false},
+		{"missing provider", Secrets{"TOKEN": {Path: "token.txt"}}, true},
+		{"unknown provider", Secrets{"TOKEN": {Provider: "vault"}}, true},
+		{"sops without path", Secrets{"TOKEN": {Provider: SecretProviderSops}}, true},
+		{"age without identity", Secrets{"TOKEN": {Provider: SecretProviderAge, Path: "token.age"}}, true},
+		{"keyring without account", Secrets{"TOKEN": {Provider: SecretProviderKeyring, Service: "api"}}, true},
+		{"command without command", Secrets{"TOKEN": {Provider: SecretProviderCommand}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.secrets.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
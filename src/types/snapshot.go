@@ -0,0 +1,23 @@
+package types
+
+import "time"
+
+// ProjectSnapshot is the runtime state of a project that outlives Process
+// Compose: the changes made to the project while it ran, which the YAML
+// configuration doesn't know about. It is saved on shutdown and by
+// `project save-state`, and replayed by `up --restore-state`.
+type ProjectSnapshot struct {
+	SavedAt time.Time `json:"savedAt"`
+	// Replicas is the replica count of each process, by process name.
+	Replicas map[string]int `json:"replicas,omitempty"`
+	// Processes is the state of each process, by replica name.
+	Processes map[string]ProcessSnapshot `json:"processes,omitempty"`
+}
+
+type ProcessSnapshot struct {
+	Restarts int `json:"restarts,omitempty"`
+	// Stopped is set for the processes stopped by the user, including the
+	// ones stopped with their namespace. They are restored disabled, with
+	// their schedule paused.
+	Stopped bool `json:"stopped,omitempty"`
+}